@@ -114,13 +114,23 @@ func main() {
 		log.WithError(err).Fatalf("Could not load clusters")
 	}
 
-	pluginsRouter := plugins.Register(loadedClusters, cfg.Plugins)
+	pluginsRouter, registeredPlugins, err := plugins.Register(loadedClusters, cfg.Plugins)
+	if err != nil {
+		log.WithError(err).Fatalf("Could not register plugins")
+	}
 
 	// Initialize each component and start it in it's own goroutine, so that the main goroutine is only used as listener
 	// for terminal signals, to initialize the graceful shutdown of the components.
 	// The appServer is the kobs application server, which serves the React frontend and the health endpoint. The
 	// metrics server is used to serve the kobs metrics.
-	apiServer, err := api.New(loadedClusters, pluginsRouter, isDevelopment)
+	redactedConfig, err := cfg.Redact()
+	if err != nil {
+		log.WithError(err).Fatalf("Could not redact configuration")
+	}
+
+	apiConfig := api.NewConfig(redactedConfig)
+
+	apiServer, err := api.New(loadedClusters, pluginsRouter, apiConfig, isDevelopment)
 	if err != nil {
 		log.WithError(err).Fatalf("Could not create API server")
 	}
@@ -132,9 +142,13 @@ func main() {
 	}
 	go appServer.Start()
 
-	metricsServer := metrics.New()
+	metricsServer := metrics.New(loadedClusters, registeredPlugins)
 	go metricsServer.Start()
 
+	// When kobs receives a SIGHUP signal, we reload the configuration file and apply the new configuration to all
+	// plugins which are supporting a reload, so that e.g. the ClickHouse instances can be changed without a restart.
+	go reload(pluginsRouter, apiConfig)
+
 	// All components should be terminated gracefully. For that we are listen for the SIGINT and SIGTERM signals and try
 	// to gracefully shutdown the started kobs components. This ensures that established connections or tasks are not
 	// interrupted.
@@ -148,6 +162,39 @@ func main() {
 	metricsServer.Stop()
 	appServer.Stop()
 	apiServer.Stop()
+	loadedClusters.Close()
 
 	log.Infof("Shutdown kobs...")
 }
+
+// reload listens for SIGHUP signals. When a signal is received the configuration file is loaded again and passed to the
+// Reload method of the plugins router. If the configuration can not be loaded or is invalid, the current configuration
+// is kept. When the plugins were reloaded, the redacted configuration, which is returned by the /api/config endpoint, is
+// also replaced.
+func reload(pluginsRouter *plugins.Router, apiConfig *api.Config) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	for range hup {
+		log.WithFields(logrus.Fields{"config": configFile}).Infof("Reload configuration")
+
+		cfg, err := config.Load(configFile)
+		if err != nil {
+			log.WithError(err).WithFields(logrus.Fields{"config": configFile}).Errorf("Could not load configuration file")
+			continue
+		}
+
+		if err := pluginsRouter.Reload(cfg.Plugins); err != nil {
+			log.WithError(err).Errorf("Could not reload plugins")
+			continue
+		}
+
+		redactedConfig, err := cfg.Redact()
+		if err != nil {
+			log.WithError(err).Errorf("Could not redact configuration")
+			continue
+		}
+
+		apiConfig.Set(redactedConfig)
+	}
+}
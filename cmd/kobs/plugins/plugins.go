@@ -1,13 +1,23 @@
 package plugins
 
 import (
+	"fmt"
 	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/kobsio/kobs/pkg/api/clusters"
+	authContext "github.com/kobsio/kobs/pkg/api/middleware/auth/context"
+	"github.com/kobsio/kobs/pkg/api/middleware/errresponse"
+	"github.com/kobsio/kobs/pkg/api/middleware/timeout"
 	"github.com/kobsio/kobs/pkg/api/plugins/plugin"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
+	"github.com/sirupsen/logrus"
 
 	// Import all plugins, which should be used with the kobs instance. By default this are all first party plugins from
 	// the plugins folder.
@@ -31,61 +41,277 @@ import (
 	"github.com/kobsio/kobs/plugins/users"
 )
 
+var (
+	log = logrus.WithFields(logrus.Fields{"package": "plugins"})
+)
+
 // Config holds the configuration for all plugins. We have to add the configuration for all the imported plugins.
 type Config struct {
-	Applications  applications.Config  `json:"applications"`
-	Clickhouse    clickhouse.Config    `json:"clickhouse"`
-	Dashboards    dashboards.Config    `json:"dashboards"`
-	Elasticsearch elasticsearch.Config `json:"elasticsearch"`
-	Flux          flux.Config          `json:"flux"`
-	Grafana       grafana.Config       `json:"grafana"`
-	Istio         istio.Config         `json:"istio"`
-	Jaeger        jaeger.Config        `json:"jaeger"`
-	Kiali         kiali.Config         `json:"kiali"`
-	Opsgenie      opsgenie.Config      `json:"opsgenie"`
-	Prometheus    prometheus.Config    `json:"prometheus"`
-	Markdown      markdown.Config      `json:"markdown"`
-	Resources     resources.Config     `json:"resources"`
-	RSS           rss.Config           `json:"rss"`
-	Sonarqube     sonarqube.Config     `json:"sonarqube"`
-	SQL           sql.Config           `json:"sql"`
-	Teams         teams.Config         `json:"teams"`
-	Users         users.Config         `json:"users"`
+	Applications   applications.Config          `json:"applications"`
+	Clickhouse     clickhouse.Config            `json:"clickhouse"`
+	ClickhouseSets map[string]clickhouse.Config `json:"clickhouseSets"`
+	Dashboards     dashboards.Config            `json:"dashboards"`
+	Elasticsearch  elasticsearch.Config         `json:"elasticsearch"`
+	Flux           flux.Config                  `json:"flux"`
+	Grafana        grafana.Config               `json:"grafana"`
+	Istio          istio.Config                 `json:"istio"`
+	Jaeger         jaeger.Config                `json:"jaeger"`
+	Kiali          kiali.Config                 `json:"kiali"`
+	Opsgenie       opsgenie.Config              `json:"opsgenie"`
+	Prometheus     prometheus.Config            `json:"prometheus"`
+	Markdown       markdown.Config              `json:"markdown"`
+	Resources      resources.Config             `json:"resources"`
+	RSS            rss.Config                   `json:"rss"`
+	Sonarqube      sonarqube.Config             `json:"sonarqube"`
+	SQL            sql.Config                   `json:"sql"`
+	Teams          teams.Config                 `json:"teams"`
+	Users          users.Config                 `json:"users"`
+	Timeouts       map[string]string            `json:"timeouts"`
+	Routes         map[string]string            `json:"routes"`
+}
+
+// clickhouseName is the name of the default ClickHouse plugin set, which is configured via the clickhouse option.
+var clickhouseName = strings.TrimPrefix(clickhouse.Route, "/")
+
+// routeRe is the regular expression, which must be matched by a route configured via the routes option.
+var routeRe = regexp.MustCompile("^/[a-z0-9-]+$")
+
+// pluginRouter is the default route of a plugin together with the router of the plugin.
+type pluginRouter struct {
+	route  string
+	router chi.Router
+}
+
+// name returns the name of the plugin, which is the default route without the leading slash. The name is used to check
+// the permissions of a user, to get the configured timeout and to get the configured route of a plugin.
+func (p pluginRouter) name() string {
+	return strings.TrimPrefix(p.route, "/")
+}
+
+// getRoutes returns the routes, under which the plugins with the given names should be mounted. By default a plugin is
+// mounted under its name (e.g. "/clickhouse"), but it can be overwritten via the routes configuration, where the key is
+// the name of the plugin (e.g. "clickhouse") and the value is the new route (e.g. "/logs"). An error is returned, when a
+// configured plugin doesn't exist, when a route is invalid or when multiple plugins would use the same route.
+func getRoutes(configuredRoutes map[string]string, names []string) (map[string]string, error) {
+	existingNames := make(map[string]bool, len(names))
+	for _, name := range names {
+		if existingNames[name] {
+			return nil, fmt.Errorf("plugin %s is registered multiple times", name)
+		}
+		existingNames[name] = true
+	}
+
+	for name, route := range configuredRoutes {
+		if !existingNames[name] {
+			return nil, fmt.Errorf("plugin %s does not exist", name)
+		}
+
+		if !routeRe.MatchString(route) {
+			return nil, fmt.Errorf("route %s for plugin %s is invalid, it must match %s", route, name, routeRe.String())
+		}
+	}
+
+	routes := make(map[string]string, len(names))
+	usedRoutes := make(map[string]string, len(names))
+
+	for _, name := range names {
+		route := "/" + name
+		if configuredRoute, ok := configuredRoutes[name]; ok {
+			route = configuredRoute
+		}
+
+		if usedBy, ok := usedRoutes[route]; ok {
+			return nil, fmt.Errorf("route %s is used by the plugins %s and %s", route, usedBy, name)
+		}
+
+		usedRoutes[route] = name
+		routes[name] = route
+	}
+
+	return routes, nil
+}
+
+// getClickhouseSetNames returns the sorted names of the additional ClickHouse plugin sets. An error is returned, when a
+// set name is invalid or when the name of a ClickHouse instance is used multiple times, because the frontend identifies
+// an instance only by its name.
+func getClickhouseSetNames(defaultConfig clickhouse.Config, sets map[string]clickhouse.Config) ([]string, error) {
+	instanceNames := make(map[string]bool)
+	for _, cfg := range defaultConfig {
+		instanceNames[cfg.Name] = true
+	}
+
+	var names []string
+	for name, config := range sets {
+		if !routeRe.MatchString("/" + name) {
+			return nil, fmt.Errorf("name %s of the clickhouse set is invalid, it must match %s", name, routeRe.String())
+		}
+
+		for _, cfg := range config {
+			if instanceNames[cfg.Name] {
+				return nil, fmt.Errorf("clickhouse instance %s is configured multiple times", cfg.Name)
+			}
+			instanceNames[cfg.Name] = true
+		}
+
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
+
+// pluginAccess is a middleware, which checks if the user has access to the plugin with the given name. The permissions
+// are always checked via the name of the plugin and not via the route, so that the permissions of a team do not have
+// to be changed when the route of a plugin is changed.
+func pluginAccess(name string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, err := authContext.GetUser(r.Context())
+			if err != nil {
+				errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the plugin")
+				return
+			}
+
+			if !user.HasPluginAccess(name) {
+				errresponse.Render(w, r, nil, http.StatusForbidden, "Your are not allowed to access the plugin")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// getTimeout returns the configured timeout for the plugin with the given name. If no timeout is configured for the
+// plugin, 0 is returned, which means that no timeout is applied. If the timeout is invalid, an error is returned, so
+// that kobs fails to start instead of serving the plugin without a timeout.
+func getTimeout(timeouts map[string]string, name string) (time.Duration, error) {
+	value, ok := timeouts[name]
+	if !ok {
+		return 0, nil
+	}
+
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timeout for plugin %s: %w", name, err)
+	}
+
+	return timeout, nil
 }
 
 // Router implements the router for the plugins package. This only registeres one route which is used to return all the
 // configured plugins.
 type Router struct {
 	*chi.Mux
-	plugins *plugin.Plugins
+	plugins    *plugin.Plugins
+	clickhouse map[string]*clickhouse.Router
+	mutex      sync.RWMutex
+}
+
+// Reload applies the given configuration to all plugins, which are supporting a reload of their configuration. At the
+// moment this is only supported by the ClickHouse plugin. The configuration of all other plugins is ignored. The
+// plugins are locked during the reload, because the reload can update the metadata of the plugins. ClickHouse sets can
+// not be added or removed via a reload. The new configuration is only applied, when it is valid for all plugins.
+func (router *Router) Reload(config Config) error {
+	router.mutex.Lock()
+	defer router.mutex.Unlock()
+
+	if len(config.ClickhouseSets)+1 != len(router.clickhouse) {
+		return fmt.Errorf("clickhouse sets can not be added or removed")
+	}
+
+	for name := range config.ClickhouseSets {
+		if _, ok := router.clickhouse[name]; !ok || name == clickhouseName {
+			return fmt.Errorf("clickhouse set %s does not exist", name)
+		}
+	}
+
+	// All new ClickHouse instances are created before one of them is used, so that the configuration is either applied
+	// completely or not at all. When the instances for one set can not be created, the instances of all other sets are
+	// closed again.
+	configs := map[string]clickhouse.Config{clickhouseName: config.Clickhouse}
+	for name, cfg := range config.ClickhouseSets {
+		configs[name] = cfg
+	}
+
+	var pendingReloads []*clickhouse.PendingReload
+	for name, cfg := range configs {
+		pendingReload, err := router.clickhouse[name].PrepareReload(cfg)
+		if err != nil {
+			for _, p := range pendingReloads {
+				p.Discard()
+			}
+
+			return fmt.Errorf("could not reload clickhouse plugin %s: %w", name, err)
+		}
+
+		pendingReloads = append(pendingReloads, pendingReload)
+	}
+
+	for _, p := range pendingReloads {
+		p.Apply()
+	}
+
+	return nil
 }
 
 // getPlugins returns all registered plugin instances.
 func (router *Router) getPlugins(w http.ResponseWriter, r *http.Request) {
+	router.mutex.RLock()
+	defer router.mutex.RUnlock()
+
 	render.JSON(w, r, router.plugins)
 }
 
-// Register is used to register all api routes for plugins.
-func Register(clusters *clusters.Clusters, config Config) chi.Router {
-	router := Router{
-		chi.NewRouter(),
-		&plugin.Plugins{},
+// Register is used to register all api routes for plugins. It also returns all registered plugin instances, so that
+// they can be used in other components like the metrics server. An error is returned when the configured routes or
+// ClickHouse sets are invalid.
+func Register(clusters *clusters.Clusters, config Config) (*Router, *plugin.Plugins, error) {
+	router := &Router{
+		Mux:        chi.NewRouter(),
+		plugins:    &plugin.Plugins{},
+		clickhouse: make(map[string]*clickhouse.Router),
 	}
 
 	router.Get("/", router.getPlugins)
 
+	// Before the plugins are initialized, we have to get the routes for all plugins, because the ClickHouse plugin
+	// must know its route, so that the frontend can use the correct route for the requests. The route of a plugin can
+	// be overwritten via the routes configuration, the timeout and the permissions are still configured via the name
+	// of the plugin.
+	clickhouseSetNames, err := getClickhouseSetNames(config.Clickhouse, config.ClickhouseSets)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var names []string
+	for _, route := range []string{
+		resources.Route, applications.Route, teams.Route, users.Route, dashboards.Route, prometheus.Route,
+		elasticsearch.Route, clickhouse.Route, jaeger.Route, kiali.Route, istio.Route, grafana.Route, flux.Route,
+		opsgenie.Route, sonarqube.Route, sql.Route, markdown.Route, rss.Route,
+	} {
+		names = append(names, strings.TrimPrefix(route, "/"))
+	}
+	names = append(names, clickhouseSetNames...)
+
+	routes, err := getRoutes(config.Routes, names)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// Initialize all plugins
 	resourcesRouter := resources.Register(clusters, router.plugins, config.Resources)
-	applicationsRouter := applications.Register(clusters, router.plugins, config.Applications)
+	prometheusRouter, prometheusInstances := prometheus.Register(clusters, router.plugins, config.Prometheus)
+	applicationsRouter := applications.Register(clusters, router.plugins, config.Applications, prometheusInstances)
 	teamsRouter := teams.Register(clusters, router.plugins, config.Teams)
 	usersRouter := users.Register(clusters, router.plugins, config.Users)
 	dashboardsRouter := dashboards.Register(clusters, router.plugins, config.Dashboards)
-	prometheusRouter, prometheusInstances := prometheus.Register(clusters, router.plugins, config.Prometheus)
 	elasticsearchRouter := elasticsearch.Register(clusters, router.plugins, config.Elasticsearch)
-	clickhouseRouter, clickhouseInstances := clickhouse.Register(clusters, router.plugins, config.Clickhouse)
+	clickhouseRouter := clickhouse.Register(clusters, router.plugins, config.Clickhouse, routes[clickhouseName])
+	router.clickhouse[clickhouseName] = clickhouseRouter
 	jaegerRouter := jaeger.Register(clusters, router.plugins, config.Jaeger)
 	kialiRouter := kiali.Register(clusters, router.plugins, config.Kiali)
-	istioRouter := istio.Register(clusters, router.plugins, config.Istio, prometheusInstances, clickhouseInstances)
+	istioRouter := istio.Register(clusters, router.plugins, config.Istio, prometheusInstances, clickhouseRouter.GetInstanceByName)
 	grafanaRouter := grafana.Register(clusters, router.plugins, config.Grafana)
 	fluxRouter := flux.Register(clusters, router.plugins, config.Flux)
 	opsgenieRouter := opsgenie.Register(clusters, router.plugins, config.Opsgenie)
@@ -94,25 +320,48 @@ func Register(clusters *clusters.Clusters, config Config) chi.Router {
 	markdownRouter := markdown.Register(clusters, router.plugins, config.Markdown)
 	rssRouter := rss.Register(clusters, router.plugins, config.RSS)
 
-	// Register all plugins
-	router.Mount(resources.Route, resourcesRouter)
-	router.Mount(applications.Route, applicationsRouter)
-	router.Mount(teams.Route, teamsRouter)
-	router.Mount(users.Route, usersRouter)
-	router.Mount(dashboards.Route, dashboardsRouter)
-	router.Mount(prometheus.Route, prometheusRouter)
-	router.Mount(elasticsearch.Route, elasticsearchRouter)
-	router.Mount(clickhouse.Route, clickhouseRouter)
-	router.Mount(jaeger.Route, jaegerRouter)
-	router.Mount(kiali.Route, kialiRouter)
-	router.Mount(istio.Route, istioRouter)
-	router.Mount(grafana.Route, grafanaRouter)
-	router.Mount(flux.Route, fluxRouter)
-	router.Mount(opsgenie.Route, opsgenieRouter)
-	router.Mount(sonarqube.Route, sonarqubeRouter)
-	router.Mount(sql.Route, sqlRouter)
-	router.Mount(markdown.Route, markdownRouter)
-	router.Mount(rss.Route, rssRouter)
-
-	return router
+	// Register all plugins. If a timeout is configured for a plugin, the timeout is applied to all requests of the
+	// plugin. Each additional ClickHouse set is registered as its own plugin with the name of the set.
+	pluginRouters := []pluginRouter{
+		{resources.Route, resourcesRouter},
+		{applications.Route, applicationsRouter},
+		{teams.Route, teamsRouter},
+		{users.Route, usersRouter},
+		{dashboards.Route, dashboardsRouter},
+		{prometheus.Route, prometheusRouter},
+		{elasticsearch.Route, elasticsearchRouter},
+		{clickhouse.Route, clickhouseRouter},
+		{jaeger.Route, jaegerRouter},
+		{kiali.Route, kialiRouter},
+		{istio.Route, istioRouter},
+		{grafana.Route, grafanaRouter},
+		{flux.Route, fluxRouter},
+		{opsgenie.Route, opsgenieRouter},
+		{sonarqube.Route, sonarqubeRouter},
+		{sql.Route, sqlRouter},
+		{markdown.Route, markdownRouter},
+		{rss.Route, rssRouter},
+	}
+
+	for _, name := range clickhouseSetNames {
+		setRouter := clickhouse.Register(clusters, router.plugins, config.ClickhouseSets[name], routes[name])
+		router.clickhouse[name] = setRouter
+		pluginRouters = append(pluginRouters, pluginRouter{"/" + name, setRouter})
+	}
+
+	// The timeout is not applied to the streaming routes of a plugin, e.g. routes which are using Server-Sent Events.
+	streamingRoutes := map[string][]string{
+		resources.Route: resources.StreamingRoutes,
+	}
+
+	for _, p := range pluginRouters {
+		pluginTimeout, err := getTimeout(config.Timeouts, p.name())
+		if err != nil {
+			return nil, nil, err
+		}
+
+		router.With(pluginAccess(p.name()), timeout.Handler(pluginTimeout, streamingRoutes[p.route]...)).Mount(routes[p.name()], p.router)
+	}
+
+	return router, router.plugins, nil
 }
@@ -0,0 +1,102 @@
+package plugins
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	team "github.com/kobsio/kobs/pkg/api/apis/team/v1beta1"
+	authContext "github.com/kobsio/kobs/pkg/api/middleware/auth/context"
+	"github.com/kobsio/kobs/plugins/clickhouse"
+	"github.com/kobsio/kobs/plugins/clickhouse/pkg/instance"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRoutes(t *testing.T) {
+	names := []string{"clickhouse", "elasticsearch", "sql"}
+
+	for _, tc := range []struct {
+		name             string
+		names            []string
+		configuredRoutes map[string]string
+		expectedRoutes   map[string]string
+		expectError      bool
+	}{
+		{name: "default routes", names: names, configuredRoutes: nil, expectedRoutes: map[string]string{"clickhouse": "/clickhouse", "elasticsearch": "/elasticsearch", "sql": "/sql"}},
+		{name: "overwritten route", names: names, configuredRoutes: map[string]string{"clickhouse": "/logs"}, expectedRoutes: map[string]string{"clickhouse": "/logs", "elasticsearch": "/elasticsearch", "sql": "/sql"}},
+		{name: "swapped routes", names: names, configuredRoutes: map[string]string{"clickhouse": "/sql", "sql": "/clickhouse"}, expectedRoutes: map[string]string{"clickhouse": "/sql", "elasticsearch": "/elasticsearch", "sql": "/clickhouse"}},
+		{name: "additional clickhouse set", names: append(names, "logs"), configuredRoutes: map[string]string{"logs": "/audit-logs"}, expectedRoutes: map[string]string{"clickhouse": "/clickhouse", "elasticsearch": "/elasticsearch", "sql": "/sql", "logs": "/audit-logs"}},
+		{name: "duplicated route", names: names, configuredRoutes: map[string]string{"clickhouse": "/sql"}, expectError: true},
+		{name: "duplicated name", names: append(names, "sql"), expectError: true},
+		{name: "unknown plugin", names: names, configuredRoutes: map[string]string{"splunk": "/logs"}, expectError: true},
+		{name: "invalid route", names: names, configuredRoutes: map[string]string{"clickhouse": "/logs/clickhouse"}, expectError: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			actualRoutes, err := getRoutes(tc.configuredRoutes, tc.names)
+			if tc.expectError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.expectedRoutes, actualRoutes)
+			}
+		})
+	}
+}
+
+func TestGetClickhouseSetNames(t *testing.T) {
+	defaultConfig := clickhouse.Config{{Name: "clickhouse"}}
+
+	names, err := getClickhouseSetNames(defaultConfig, map[string]clickhouse.Config{"logs-b": {{Name: "clickhouse-b"}}, "logs-a": {{Name: "clickhouse-a"}}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"logs-a", "logs-b"}, names)
+
+	_, err = getClickhouseSetNames(defaultConfig, map[string]clickhouse.Config{"Logs": {{Name: "clickhouse-a"}}})
+	require.Error(t, err)
+
+	_, err = getClickhouseSetNames(defaultConfig, map[string]clickhouse.Config{"logs": {instance.Config{Name: "clickhouse"}}})
+	require.Error(t, err)
+}
+
+func TestGetTimeout(t *testing.T) {
+	timeouts := map[string]string{"prometheus": "30s", "clickhouse": "30"}
+
+	timeout, err := getTimeout(timeouts, "prometheus")
+	require.NoError(t, err)
+	require.Equal(t, 30*time.Second, timeout)
+
+	timeout, err = getTimeout(timeouts, "jaeger")
+	require.NoError(t, err)
+	require.Equal(t, time.Duration(0), timeout)
+
+	_, err = getTimeout(timeouts, "clickhouse")
+	require.Error(t, err)
+}
+
+func TestPluginAccess(t *testing.T) {
+	handler := pluginAccess("clickhouse")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for _, tc := range []struct {
+		name               string
+		plugins            []string
+		expectedStatusCode int
+	}{
+		{name: "plugin access", plugins: []string{"clickhouse"}, expectedStatusCode: http.StatusOK},
+		{name: "access to all plugins", plugins: []string{"*"}, expectedStatusCode: http.StatusOK},
+		{name: "no plugin access", plugins: []string{"logs"}, expectedStatusCode: http.StatusForbidden},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			user := authContext.User{Permissions: team.Permissions{Plugins: tc.plugins}}
+			req := httptest.NewRequest(http.MethodGet, "/logs/clickhouse", nil)
+			req = req.WithContext(context.WithValue(req.Context(), authContext.UserKey, user))
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			require.Equal(t, tc.expectedStatusCode, w.Code)
+		})
+	}
+}
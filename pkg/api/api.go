@@ -4,12 +4,15 @@ import (
 	"context"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/kobsio/kobs/pkg/api/clusters"
 	"github.com/kobsio/kobs/pkg/api/middleware/auth"
 	"github.com/kobsio/kobs/pkg/api/middleware/httplog"
 	"github.com/kobsio/kobs/pkg/api/middleware/metrics"
+	"github.com/kobsio/kobs/pkg/api/middleware/recoverer"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -20,19 +23,36 @@ import (
 )
 
 var (
-	log     = logrus.WithFields(logrus.Fields{"package": "api"})
-	address string
+	log             = logrus.WithFields(logrus.Fields{"package": "api"})
+	address         string
+	logSampleRate   uint64
+	logSampleRoutes []string
 )
 
 // init is used to define all flags, which are needed for the api server. We have to define the address, where the api
-// server is listen on.
+// server is listen on and the sampling options for the request logs.
 func init() {
 	defaultAddress := ":15220"
 	if os.Getenv("KOBS_API_ADDRESS") != "" {
 		defaultAddress = os.Getenv("KOBS_API_ADDRESS")
 	}
 
+	defaultLogSampleRate := uint64(1)
+	if os.Getenv("KOBS_API_LOG_SAMPLE_RATE") != "" {
+		parsedLogSampleRate, err := strconv.ParseUint(os.Getenv("KOBS_API_LOG_SAMPLE_RATE"), 10, 64)
+		if err == nil {
+			defaultLogSampleRate = parsedLogSampleRate
+		}
+	}
+
+	var defaultLogSampleRoutes []string
+	if os.Getenv("KOBS_API_LOG_SAMPLE_ROUTES") != "" {
+		defaultLogSampleRoutes = strings.Split(os.Getenv("KOBS_API_LOG_SAMPLE_ROUTES"), ",")
+	}
+
 	flag.StringVar(&address, "api.address", defaultAddress, "The address, where the API server is listen on.")
+	flag.Uint64Var(&logSampleRate, "api.log.sample-rate", defaultLogSampleRate, "Only log 1 in N requests for the routes defined via \"--api.log.sample-routes\". Failed requests are always logged.")
+	flag.StringSliceVar(&logSampleRoutes, "api.log.sample-routes", defaultLogSampleRoutes, "A list of route prefixes (e.g. \"/api/plugins/resources/resources\"), for which the request logs should be sampled.")
 }
 
 // Server implements the api server. The api server is used to serve the rest api for kobs.
@@ -72,7 +92,9 @@ func (s *Server) Stop() {
 // We exclude the health check from all middlewares, because the health check just returns 200. Therefore we do not need
 // our defined middlewares like request id, metrics, auth or loggin. This also makes it easier to analyze the logs in a
 // Kubernetes cluster where the health check is called every x seconds, because we generate less logs.
-func New(loadedClusters *clusters.Clusters, pluginsRouter chi.Router, isDevelopment bool) (*Server, error) {
+// The config contains the loaded configuration of kobs, where all secrets are already redacted. It is returned via the
+// /api/config endpoint for admins.
+func New(loadedClusters *clusters.Clusters, pluginsRouter chi.Router, config *Config, isDevelopment bool) (*Server, error) {
 	router := chi.NewRouter()
 
 	if isDevelopment {
@@ -89,14 +111,15 @@ func New(loadedClusters *clusters.Clusters, pluginsRouter chi.Router, isDevelopm
 
 	router.Route("/api", func(r chi.Router) {
 		r.Use(middleware.RequestID)
-		r.Use(middleware.Recoverer)
+		r.Use(recoverer.Handler())
 		r.Use(middleware.URLFormat)
 		r.Use(metrics.Metrics)
 		r.Use(auth.Handler(loadedClusters))
-		r.Use(httplog.NewStructuredLogger(log.Logger))
+		r.Use(httplog.NewStructuredLogger(log.Logger, httplog.WithSampling(logSampleRate, logSampleRoutes)))
 		r.Use(render.SetContentType(render.ContentTypeJSON))
 
 		r.Get("/user", auth.UserHandler)
+		r.Get("/config", configHandler(config))
 		r.Mount("/clusters", clusters.NewRouter(loadedClusters))
 		r.Mount("/plugins", pluginsRouter)
 	})
@@ -38,6 +38,7 @@ type ApplicationSpec struct {
 	Dependencies []Reference           `json:"dependencies,omitempty"`
 	Preview      *Preview              `json:"preview,omitempty"`
 	Dashboards   []dashboard.Reference `json:"dashboards,omitempty"`
+	Health       []HealthCheck         `json:"health,omitempty"`
 }
 
 type Link struct {
@@ -56,3 +57,11 @@ type Preview struct {
 	Title  string           `json:"title"`
 	Plugin dashboard.Plugin `json:"plugin"`
 }
+
+// HealthCheck is a PromQL expression, which is used to compute the health of an application. Like for a Prometheus
+// alerting rule the application is degraded when the expression returns a result.
+type HealthCheck struct {
+	Name       string `json:"name,omitempty"`
+	Prometheus string `json:"prometheus,omitempty"`
+	Query      string `json:"query"`
+}
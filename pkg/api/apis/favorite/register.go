@@ -0,0 +1,6 @@
+package favorite
+
+// GroupName is the group name used in this package.
+const (
+	GroupName = "kobs.io"
+)
@@ -0,0 +1,44 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Favorite is the Favorite CRD.
+type Favorite struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec FavoriteSpec `json:"spec,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// FavoriteList is the structure for a list of Favorite CRs.
+type FavoriteList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []Favorite `json:"items"`
+}
+
+// FavoriteSpec contains the pinned resources of a single user. Each user has exactly one Favorite CR, which is
+// identified by the user field.
+type FavoriteSpec struct {
+	User      string      `json:"user"`
+	Resources []Reference `json:"resources,omitempty"`
+}
+
+// Reference is a reference to a single pinned resource. The path and resource fields are the same as they are used for
+// the resources plugin (e.g. "/apis/apps/v1" and "deployments").
+type Reference struct {
+	Cluster   string `json:"cluster"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Path      string `json:"path"`
+	Resource  string `json:"resource"`
+	Title     string `json:"title,omitempty"`
+}
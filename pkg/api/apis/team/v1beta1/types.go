@@ -34,6 +34,7 @@ type TeamSpec struct {
 	Description string                `json:"description,omitempty"`
 	Links       []Link                `json:"links,omitempty"`
 	Logo        string                `json:"logo,omitempty"`
+	Groups      []string              `json:"groups,omitempty"`
 	Permissions Permissions           `json:"permissions,omitempty"`
 	Dashboards  []dashboard.Reference `json:"dashboards,omitempty"`
 }
@@ -185,6 +185,11 @@ func (in *TeamSpec) DeepCopyInto(out *TeamSpec) {
 		*out = make([]Link, len(*in))
 		copy(*out, *in)
 	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	in.Permissions.DeepCopyInto(&out.Permissions)
 	if in.Dashboards != nil {
 		in, out := &in.Dashboards, &out.Dashboards
@@ -0,0 +1,55 @@
+package cluster
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// Age contains the creation timestamp of an object in the RFC3339 format and the age of the object in the same format
+// as it is used by kubectl (e.g. "5d3h"). If an object doesn't have a valid creation timestamp, both fields are empty.
+type Age struct {
+	CreationTimestamp string `json:"creationTimestamp"`
+	Age               string `json:"age"`
+}
+
+// GetAge returns the creation timestamp and the age of the given object, relative to the given time.
+func GetAge(object map[string]interface{}, now time.Time) Age {
+	metadata, ok := object["metadata"].(map[string]interface{})
+	if !ok {
+		return Age{}
+	}
+
+	creationTimestamp, ok := metadata["creationTimestamp"].(string)
+	if !ok {
+		return Age{}
+	}
+
+	parsedCreationTimestamp, err := time.Parse(time.RFC3339, creationTimestamp)
+	if err != nil {
+		return Age{}
+	}
+
+	return Age{
+		CreationTimestamp: parsedCreationTimestamp.UTC().Format(time.RFC3339),
+		Age:               duration.HumanDuration(now.Sub(parsedCreationTimestamp)),
+	}
+}
+
+// GetAges returns the age for each item of the given list. The ages are returned in the same order as the items, so
+// that the age of an item can be found via its index. If the given object isn't a list (e.g. when a single resource
+// was requested), the age of the object itself is returned as the only element.
+func GetAges(list map[string]interface{}, now time.Time) []Age {
+	items, ok := list["items"].([]interface{})
+	if !ok {
+		return []Age{GetAge(list, now)}
+	}
+
+	ages := make([]Age, 0, len(items))
+	for _, item := range items {
+		object, _ := item.(map[string]interface{})
+		ages = append(ages, GetAge(object, now))
+	}
+
+	return ages
+}
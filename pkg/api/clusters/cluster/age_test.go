@@ -0,0 +1,41 @@
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAges(t *testing.T) {
+	now := time.Date(2021, 10, 10, 12, 0, 0, 0, time.UTC)
+	object := func(creationTimestamp interface{}) map[string]interface{} {
+		return map[string]interface{}{"metadata": map[string]interface{}{"name": "nginx", "creationTimestamp": creationTimestamp}}
+	}
+
+	t.Run("list", func(t *testing.T) {
+		list := map[string]interface{}{
+			"items": []interface{}{
+				object("2021-10-10T11:59:30Z"),
+				object("2021-10-05T09:00:00Z"),
+				object("invalid"),
+				object(nil),
+			},
+		}
+
+		require.Equal(t, []Age{
+			{CreationTimestamp: "2021-10-10T11:59:30Z", Age: "30s"},
+			{CreationTimestamp: "2021-10-05T09:00:00Z", Age: "5d3h"},
+			{},
+			{},
+		}, GetAges(list, now))
+	})
+
+	t.Run("single object", func(t *testing.T) {
+		require.Equal(t, []Age{{CreationTimestamp: "2021-10-10T10:00:00Z", Age: "4h"}}, GetAges(object("2021-10-10T12:00:00+02:00"), now.Add(2*time.Hour)))
+	})
+
+	t.Run("empty list", func(t *testing.T) {
+		require.Equal(t, []Age{}, GetAges(map[string]interface{}{"items": []interface{}{}}, now))
+	})
+}
@@ -0,0 +1,52 @@
+package cluster
+
+import (
+	"context"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// patchResourcesConcurrency is the maximum number of resources, which are patched in parallel by PatchResources.
+const patchResourcesConcurrency = 10
+
+// ResourceRef identifies a single resource in a cluster. The resource is identified by the namespace, the name, the
+// Kubernetes API path and the name of the resource type (e.g. "pods").
+type ResourceRef struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Path      string `json:"path"`
+	Resource  string `json:"resource"`
+}
+
+// PatchResources applies the same patch to all given resources. The resources are patched in parallel, but at most
+// patchResourcesConcurrency resources at the same time. The returned slice contains the error for each item at the
+// same index as the item, so that the caller can report the result for each resource. If a resource was patched
+// successfully the error is nil. Conflicts can be detected via apierrors.IsConflict.
+func (c *Cluster) PatchResources(ctx context.Context, items []ResourceRef, patchType types.PatchType, body []byte) []error {
+	errs := make([]error, len(items))
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, patchResourcesConcurrency)
+
+	for i, item := range items {
+		wg.Add(1)
+
+		go func(i int, item ResourceRef) {
+			defer wg.Done()
+
+			select {
+			case semaphore <- struct{}{}:
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				return
+			}
+			defer func() { <-semaphore }()
+
+			errs[i] = c.PatchResource(ctx, item.Namespace, item.Name, item.Path, item.Resource, patchType, body)
+		}(i, item)
+	}
+
+	wg.Wait()
+	return errs
+}
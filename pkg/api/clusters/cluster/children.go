@@ -0,0 +1,77 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+)
+
+// ChildResource identifies a resource, which should be searched for children of an object. The resource is identified
+// by the Kubernetes API path (e.g. "/apis/apps/v1") and the name of the resource (e.g. "replicasets").
+type ChildResource struct {
+	Path     string `json:"path"`
+	Resource string `json:"resource"`
+}
+
+// Children contains all objects of a resource, which are owned by an object.
+type Children struct {
+	Path     string                   `json:"path"`
+	Resource string                   `json:"resource"`
+	Items    []map[string]interface{} `json:"items"`
+}
+
+// GetChildren returns all objects of the given resources, which have an owner reference to the object with the given
+// uid. Since the Kubernetes API doesn't support to filter by owner references, we have to get a list of all objects
+// for each resource in the namespace and filter the objects afterwards. To build a tree (e.g. Deployment ->
+// ReplicaSets -> Pods), the caller has to call GetChildren again with the uids of the returned children.
+func (c *Cluster) GetChildren(ctx context.Context, namespace, uid string, resources []ChildResource) ([]Children, error) {
+	var children []Children
+
+	for _, resource := range resources {
+		res, _, err := c.GetResourcesWithParams(ctx, namespace, "", resource.Path, resource.Resource, url.Values{})
+		if err != nil {
+			return nil, err
+		}
+
+		var list struct {
+			Items []map[string]interface{} `json:"items"`
+		}
+		if err := json.Unmarshal(res, &list); err != nil {
+			return nil, err
+		}
+
+		children = append(children, Children{
+			Path:     resource.Path,
+			Resource: resource.Resource,
+			Items:    filterByOwner(list.Items, uid),
+		})
+	}
+
+	return children, nil
+}
+
+// filterByOwner returns all items, which have an owner reference with the given uid.
+func filterByOwner(items []map[string]interface{}, uid string) []map[string]interface{} {
+	filteredItems := []map[string]interface{}{}
+
+	for _, item := range items {
+		metadata, ok := item["metadata"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		ownerReferences, ok := metadata["ownerReferences"].([]interface{})
+		if !ok {
+			continue
+		}
+
+		for _, ownerReference := range ownerReferences {
+			if reference, ok := ownerReference.(map[string]interface{}); ok && reference["uid"] == uid {
+				filteredItems = append(filteredItems, item)
+				break
+			}
+		}
+	}
+
+	return filteredItems
+}
@@ -0,0 +1,34 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterByOwner(t *testing.T) {
+	item := func(name string, owners ...string) map[string]interface{} {
+		var ownerReferences []interface{}
+		for _, owner := range owners {
+			ownerReferences = append(ownerReferences, map[string]interface{}{"uid": owner})
+		}
+
+		metadata := map[string]interface{}{"name": name}
+		if ownerReferences != nil {
+			metadata["ownerReferences"] = ownerReferences
+		}
+
+		return map[string]interface{}{"metadata": metadata}
+	}
+
+	items := []map[string]interface{}{
+		item("nginx-1", "1234"),
+		item("nginx-2", "5678", "1234"),
+		item("redis-1", "5678"),
+		item("standalone"),
+		{"kind": "Pod"},
+	}
+
+	require.Equal(t, []map[string]interface{}{item("nginx-1", "1234"), item("nginx-2", "5678", "1234")}, filterByOwner(items, "1234"))
+	require.Equal(t, []map[string]interface{}{}, filterByOwner(items, "9999"))
+}
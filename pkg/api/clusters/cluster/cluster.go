@@ -4,60 +4,188 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math/rand"
 	"mime/multipart"
 	"net/http"
 	"net/url"
+	"os"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	application "github.com/kobsio/kobs/pkg/api/apis/application/v1beta1"
 	dashboard "github.com/kobsio/kobs/pkg/api/apis/dashboard/v1beta1"
+	savedquery "github.com/kobsio/kobs/pkg/api/apis/savedquery/v1beta1"
 	team "github.com/kobsio/kobs/pkg/api/apis/team/v1beta1"
 	user "github.com/kobsio/kobs/pkg/api/apis/user/v1beta1"
 	applicationClientsetVersioned "github.com/kobsio/kobs/pkg/api/clients/application/clientset/versioned"
 	dashboardClientsetVersioned "github.com/kobsio/kobs/pkg/api/clients/dashboard/clientset/versioned"
+	favoriteClientsetVersioned "github.com/kobsio/kobs/pkg/api/clients/favorite/clientset/versioned"
+	savedQueryClientsetVersioned "github.com/kobsio/kobs/pkg/api/clients/savedquery/clientset/versioned"
 	teamClientsetVersioned "github.com/kobsio/kobs/pkg/api/clients/team/clientset/versioned"
 	userClientsetVersioned "github.com/kobsio/kobs/pkg/api/clients/user/clientset/versioned"
 	"github.com/kobsio/kobs/pkg/api/clusters/cluster/copy"
+	"github.com/kobsio/kobs/pkg/api/clusters/cluster/diff"
 	"github.com/kobsio/kobs/pkg/api/clusters/cluster/terminal"
 
 	"github.com/gorilla/websocket"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/sirupsen/logrus"
+	flag "github.com/spf13/pflag"
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	apiruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/remotecommand"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
 )
 
 var (
-	log       = logrus.WithFields(logrus.Fields{"package": "clusters"})
-	slugifyRe = regexp.MustCompile("[^a-z0-9]+")
+	log                  = logrus.WithFields(logrus.Fields{"package": "clusters"})
+	slugifyRe            = regexp.MustCompile("[^a-z0-9]+")
+	crdDefaultColumns    bool
+	crdsRetryBase        time.Duration
+	crdsRetryMax         time.Duration
+	crdsTimeout          time.Duration
+	crdsConcurrency      int
+	cacheDurationOpenAPI time.Duration
+	jitterRand           = &lockedRand{rand: rand.New(rand.NewSource(time.Now().UnixNano()))}
+
+	activeWebSocketsMetric = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "kobs",
+		Name:      "active_websockets",
+		Help:      "Number of active WebSocket connections for log streams and terminals.",
+	}, []string{"type"})
+
+	errResourceVersionExpired = errors.New("resource version expired")
+	errWebSocketWrite         = errors.New("could not write to websocket")
+
+	// logContinuationMarker is appended to a log line, which was split across multiple WebSocket messages, because
+	// it exceeded the maximum message size.
+	logContinuationMarker = " \u21b5"
+
+	// ErrStreamReconnect is returned by StreamLogs, when the stream could not be recovered after an authentication
+	// failure. In this case the client was already told to reconnect via a StreamMessage.
+	ErrStreamReconnect = errors.New("stream could not be recovered, client should reconnect")
+
+	// ErrInvalidRegex is returned by GetLogs and GetLogsReader, when one of the given regular expressions can not be
+	// compiled, so that the caller can distinguish an invalid request from a failed request against the Kubernetes API.
+	ErrInvalidRegex = errors.New("invalid regex")
+
+	// ErrResponseTooLarge is returned by GetResourcesWithMaxSize, when the response of the Kubernetes API server exceeds
+	// the given maximum size.
+	ErrResponseTooLarge = errors.New("response too large")
 )
 
+// init is used to define all command-line flags for the cluster package.
+func init() {
+	defaultCRDDefaultColumns := true
+	if os.Getenv("KOBS_CLUSTERS_CRDS_DEFAULT_COLUMNS") != "" {
+		parsedCRDDefaultColumns, err := strconv.ParseBool(os.Getenv("KOBS_CLUSTERS_CRDS_DEFAULT_COLUMNS"))
+		if err == nil {
+			defaultCRDDefaultColumns = parsedCRDDefaultColumns
+		}
+	}
+
+	flag.BoolVar(&crdDefaultColumns, "clusters.crds.default-columns", defaultCRDDefaultColumns, "Add a default column for the age of a resource to all CRDs, which doesn't define additional printer columns.")
+
+	defaultCRDsRetryBase := 30 * time.Second
+	if os.Getenv("KOBS_CLUSTERS_CRDS_RETRY_BASE") != "" {
+		parsedCRDsRetryBase, err := time.ParseDuration(os.Getenv("KOBS_CLUSTERS_CRDS_RETRY_BASE"))
+		if err == nil {
+			defaultCRDsRetryBase = parsedCRDsRetryBase
+		}
+	}
+
+	defaultCRDsRetryMax := 10 * time.Minute
+	if os.Getenv("KOBS_CLUSTERS_CRDS_RETRY_MAX") != "" {
+		parsedCRDsRetryMax, err := time.ParseDuration(os.Getenv("KOBS_CLUSTERS_CRDS_RETRY_MAX"))
+		if err == nil {
+			defaultCRDsRetryMax = parsedCRDsRetryMax
+		}
+	}
+
+	defaultCRDsTimeout := 30 * time.Second
+	if os.Getenv("KOBS_CLUSTERS_CRDS_TIMEOUT") != "" {
+		parsedCRDsTimeout, err := time.ParseDuration(os.Getenv("KOBS_CLUSTERS_CRDS_TIMEOUT"))
+		if err == nil {
+			defaultCRDsTimeout = parsedCRDsTimeout
+		}
+	}
+
+	flag.DurationVar(&crdsRetryBase, "clusters.crds.retry-base", defaultCRDsRetryBase, "The initial duration to wait, before loading the CRDs of a cluster is retried.")
+	flag.DurationVar(&crdsRetryMax, "clusters.crds.retry-max", defaultCRDsRetryMax, "The maximum duration to wait, before loading the CRDs of a cluster is retried.")
+	flag.DurationVar(&crdsTimeout, "clusters.crds.timeout", defaultCRDsTimeout, "The timeout for a single request to load the CRDs of a cluster.")
+
+	defaultCRDsConcurrency := 5
+	if os.Getenv("KOBS_CLUSTERS_CRDS_CONCURRENCY") != "" {
+		parsedCRDsConcurrency, err := strconv.Atoi(os.Getenv("KOBS_CLUSTERS_CRDS_CONCURRENCY"))
+		if err == nil {
+			defaultCRDsConcurrency = parsedCRDsConcurrency
+		}
+	}
+
+	flag.IntVar(&crdsConcurrency, "clusters.crds.concurrency", defaultCRDsConcurrency, "The maximum number of clusters, which are loading their CRDs at the same time. A value of 0 disables the limit.")
+
+	defaultCacheDurationOpenAPI := 10 * time.Minute
+	if os.Getenv("KOBS_CLUSTERS_CACHE_DURATION_OPENAPI") != "" {
+		parsedCacheDurationOpenAPI, err := time.ParseDuration(os.Getenv("KOBS_CLUSTERS_CACHE_DURATION_OPENAPI"))
+		if err == nil {
+			defaultCacheDurationOpenAPI = parsedCacheDurationOpenAPI
+		}
+	}
+
+	flag.DurationVar(&cacheDurationOpenAPI, "clusters.cache-duration.openapi", defaultCacheDurationOpenAPI, "The duration, for how long the OpenAPI schema of a cluster, which is used to validate manifests, should be cached.")
+}
+
 // Cluster is a Kubernetes cluster. It contains all required fields to interact with the cluster and it's services.
 type Cluster struct {
 	cache                Cache
 	config               *rest.Config
 	clientset            *kubernetes.Clientset
+	streamConfig         *rest.Config
+	streamClientset      *kubernetes.Clientset
+	streamMutex          sync.RWMutex
+	tlsConfig            *TLSConfig
+	configLoader         ConfigLoader
 	applicationClientset *applicationClientsetVersioned.Clientset
 	teamClientset        *teamClientsetVersioned.Clientset
 	dashboardClientset   *dashboardClientsetVersioned.Clientset
 	userClientset        *userClientsetVersioned.Clientset
+	savedQueryClientset  *savedQueryClientsetVersioned.Clientset
+	favoriteClientset    *favoriteClientsetVersioned.Clientset
 	name                 string
+	displayName          string
 	crds                 []CRD
+	crdsMutex            sync.RWMutex
+	views                []CRDView
+	openAPI              openAPICache
+	info                 infoCache
+	kinds                kindsCache
+	cancel               context.CancelFunc
 }
 
+// ConfigLoader loads the rest config of a cluster again, e.g. by reading the Kubeconfig file. It is set by the provider
+// of a cluster and used to pick up rotated credentials.
+type ConfigLoader func() (*rest.Config, error)
+
 // CRD is the format of a Custom Resource Definition. Each CRD must contain a path and resource, which are used for the
 // API request to retrieve all CRs for a CRD. It also must contain a title (kind), an optional description, the scope of
 // the CRs (namespaced vs. cluster) and an optional list of columns with the fields, which should be shown in the
-// frontend table.
+// frontend table. If a custom view was configured for the CRD, the view is also added.
 type CRD struct {
 	Path        string      `json:"path"`
 	Resource    string      `json:"resource"`
@@ -65,6 +193,7 @@ type CRD struct {
 	Description string      `json:"description"`
 	Scope       string      `json:"scope"`
 	Columns     []CRDColumn `json:"columns,omitempty"`
+	View        *CRDView    `json:"view,omitempty"`
 }
 
 // CRDColumn is a single column for the CRD. A column has the same fields as the additionalPrinterColumns from the CRD
@@ -77,21 +206,251 @@ type CRDColumn struct {
 	Type        string `json:"type"`
 }
 
+// ResourceEvent is a single message, which is sent to the client when a user watches a list of resources. The first
+// event always has the type "LIST" and contains the complete list of resources. All following events are the "ADDED",
+// "MODIFIED" and "DELETED" events from the Kubernetes API, which contain the changed object.
+type ResourceEvent struct {
+	Type   string                 `json:"type"`
+	Object map[string]interface{} `json:"object"`
+}
+
+// ImageInfo contains the image of a container from the Pod spec and the resolved image from the Pod status. The type is
+// "container", "initContainer" or "ephemeralContainer". The digest is the part of the image id after the "@" sign.
+type ImageInfo struct {
+	Container string `json:"container"`
+	Type      string `json:"type"`
+	Image     string `json:"image"`
+	ImageID   string `json:"imageID"`
+	Digest    string `json:"digest"`
+}
+
+// ContainerStatus is the status of a single container of a Pod. The state is "running", "waiting" or "terminated" and
+// the reason and message are set for the waiting and terminated state. The last termination reason and exit code are
+// taken from the last state of the container, so that we can show why a container was restarted (e.g.
+// "CrashLoopBackOff: exit 137 (OOMKilled)").
+type ContainerStatus struct {
+	Container                 string `json:"container"`
+	Type                      string `json:"type"`
+	Ready                     bool   `json:"ready"`
+	RestartCount              int32  `json:"restartCount"`
+	State                     string `json:"state"`
+	Reason                    string `json:"reason,omitempty"`
+	Message                   string `json:"message,omitempty"`
+	ExitCode                  *int32 `json:"exitCode,omitempty"`
+	LastTerminationReason     string `json:"lastTerminationReason,omitempty"`
+	LastTerminationExitCode   *int32 `json:"lastTerminationExitCode,omitempty"`
+	LastTerminationFinishedAt string `json:"lastTerminationFinishedAt,omitempty"`
+}
+
+// ResourceEventWriter is used to send the events of a resource watch to the client. It is implemented by a WebSocket
+// connection, but can also be implemented by other transports like Server-Sent Events.
+type ResourceEventWriter interface {
+	WriteJSON(v interface{}) error
+}
+
+// watchEvent is the structure of a single event returned by the watch endpoint of the Kubernetes API. We keep the
+// object as raw message, because we have to decode it into a Status object for "ERROR" events.
+type watchEvent struct {
+	Type   string          `json:"type"`
+	Object json.RawMessage `json:"object"`
+}
+
 // Cache implements a simple caching layer, for the loaded manifest files. The goal of the caching layer is to return
-// the manifests faster to the user.
+// the manifests faster to the user. The cached namespaces are guarded by the namespacesMutex, because they are read by
+// the status endpoint, while they are refreshed by a request. The crdsLastFetch field is guarded by the crdsMutex of
+// the cluster.
 type Cache struct {
+	namespacesMutex     sync.RWMutex
 	namespaces          []string
 	namespacesLastFetch time.Time
+	crdsLastFetch       time.Time
 }
 
-// GetName returns the name of the cluster.
+// Status is the self-diagnostic status of a cluster. It contains if the CRDs of the cluster were loaded, the number of
+// loaded CRDs and the age of the cached namespaces.
+type Status struct {
+	Name               string `json:"name"`
+	Loaded             bool   `json:"loaded"`
+	CRDs               int    `json:"crds"`
+	CRDsLoadedAt       int64  `json:"crdsLoadedAt,omitempty"`
+	NamespacesCached   int    `json:"namespacesCached"`
+	NamespacesCacheAge string `json:"namespacesCacheAge,omitempty"`
+}
+
+// GetName returns the name of the cluster. The name is the slugified version of the configured name and is used in
+// all API requests.
 func (c *Cluster) GetName() string {
 	return c.name
 }
 
-// GetCRDs returns all CRDs of the cluster.
+// GetDisplayName returns the original (not slugified) name of the cluster, which should be shown to the user.
+func (c *Cluster) GetDisplayName() string {
+	return c.displayName
+}
+
+// GetStatus returns the status of the cluster, which can be used by operators to get a quick overview of the loaded
+// data.
+func (c *Cluster) GetStatus() Status {
+	c.crdsMutex.RLock()
+	defer c.crdsMutex.RUnlock()
+
+	c.cache.namespacesMutex.RLock()
+	defer c.cache.namespacesMutex.RUnlock()
+
+	status := Status{
+		Name:             c.name,
+		Loaded:           !c.cache.crdsLastFetch.IsZero(),
+		CRDs:             len(c.crds),
+		NamespacesCached: len(c.cache.namespaces),
+	}
+
+	if status.Loaded {
+		status.CRDsLoadedAt = c.cache.crdsLastFetch.Unix()
+	}
+
+	if !c.cache.namespacesLastFetch.IsZero() {
+		status.NamespacesCacheAge = time.Since(c.cache.namespacesLastFetch).Round(time.Second).String()
+	}
+
+	return status
+}
+
+// GetCRDs returns all CRDs of the cluster. If a custom view was configured for a CRD, the view is attached to the
+// returned CRD.
 func (c *Cluster) GetCRDs() []CRD {
-	return c.crds
+	c.crdsMutex.RLock()
+	defer c.crdsMutex.RUnlock()
+
+	if len(c.views) == 0 {
+		return c.crds
+	}
+
+	crds := make([]CRD, 0, len(c.crds))
+	for _, crd := range c.crds {
+		for i := range c.views {
+			if c.views[i].matches(crd) {
+				crd.View = &c.views[i]
+				break
+			}
+		}
+
+		crds = append(crds, crd)
+	}
+
+	return crds
+}
+
+// CRDsLoaded returns true, when the CRDs of the cluster were loaded. Since the CRDs are loaded asynchronously after
+// the cluster was created, this can be used to distinguish a cluster without CRDs from a cluster where the CRDs are
+// not loaded yet.
+func (c *Cluster) CRDsLoaded() bool {
+	c.crdsMutex.RLock()
+	defer c.crdsMutex.RUnlock()
+
+	return !c.cache.crdsLastFetch.IsZero()
+}
+
+// CRDList is a filtered and paginated list of CRDs. Next to the CRDs it contains the total number of CRDs, which are
+// matching the filter and if the returned list was truncated.
+type CRDList struct {
+	CRDs      []CRD `json:"crds"`
+	Total     int   `json:"total"`
+	Truncated bool  `json:"truncated"`
+}
+
+// FilterCRDs returns the CRDs, where the group (path), kind (title) or resource contains the given filter. The
+// comparison is case-insensitive. The matching CRDs are paginated via the offset and limit parameters, where a limit of
+// 0 returns all CRDs after the offset.
+func FilterCRDs(crds []CRD, filter string, offset, limit int) CRDList {
+	filter = strings.ToLower(filter)
+	filteredCRDs := []CRD{}
+
+	for _, crd := range crds {
+		if filter == "" || strings.Contains(strings.ToLower(crd.Path), filter) || strings.Contains(strings.ToLower(crd.Title), filter) || strings.Contains(strings.ToLower(crd.Resource), filter) {
+			filteredCRDs = append(filteredCRDs, crd)
+		}
+	}
+
+	total := len(filteredCRDs)
+
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > total {
+		offset = total
+	}
+
+	end := total
+	if limit > 0 && offset+limit < total {
+		end = offset + limit
+	}
+
+	return CRDList{
+		CRDs:      filteredCRDs[offset:end],
+		Total:     total,
+		Truncated: end < total,
+	}
+}
+
+// SetViews sets the custom views for the CRDs of the cluster. The views must be validated via the ValidateViews
+// function before they are set.
+func (c *Cluster) SetViews(views []CRDView) {
+	c.views = views
+}
+
+// SetConfigLoader sets the function, which is used to load the rest config of the cluster again, when a request fails
+// because the credentials for the cluster expired. It must be called before the cluster is used.
+func (c *Cluster) SetConfigLoader(configLoader ConfigLoader) {
+	c.configLoader = configLoader
+}
+
+// getStreamConfig returns the rest config without a timeout, which is used for long running operations.
+func (c *Cluster) getStreamConfig() *rest.Config {
+	c.streamMutex.RLock()
+	defer c.streamMutex.RUnlock()
+
+	return c.streamConfig
+}
+
+// getStreamClientset returns the clientset without a timeout, which is used for long running operations.
+func (c *Cluster) getStreamClientset() *kubernetes.Clientset {
+	c.streamMutex.RLock()
+	defer c.streamMutex.RUnlock()
+
+	return c.streamClientset
+}
+
+// reloadStreamClientset replaces the stream config and clientset. When the provider has set a config loader, the rest
+// config is loaded again, so that rotated credentials are picked up. Otherwise the clientset is only recreated from the
+// existing config.
+func (c *Cluster) reloadStreamClientset() error {
+	streamConfig := c.getStreamConfig()
+
+	if c.configLoader != nil {
+		restConfig, err := c.configLoader()
+		if err != nil {
+			return err
+		}
+
+		if err := c.tlsConfig.apply(restConfig); err != nil {
+			return err
+		}
+
+		streamConfig = rest.CopyConfig(restConfig)
+		streamConfig.Timeout = 0
+	}
+
+	streamClientset, err := kubernetes.NewForConfig(streamConfig)
+	if err != nil {
+		return err
+	}
+
+	c.streamMutex.Lock()
+	c.streamConfig = streamConfig
+	c.streamClientset = streamClientset
+	c.streamMutex.Unlock()
+
+	return nil
 }
 
 // GetClient returns a new client to perform CRUD operations on Kubernetes objects.
@@ -105,12 +464,16 @@ func (c *Cluster) GetClient(schema *apiruntime.Scheme) (client.Client, error) {
 // "caching" the namespaces. This means that if a new namespace is created in a cluster, this namespaces is only shown
 // after the configured cache duration.
 func (c *Cluster) GetNamespaces(ctx context.Context, cacheDuration time.Duration) ([]string, error) {
-	log.WithFields(logrus.Fields{"last fetch": c.cache.namespacesLastFetch}).Tracef("Last namespace fetch.")
+	c.cache.namespacesMutex.RLock()
+	cachedNamespaces, lastFetch := c.cache.namespaces, c.cache.namespacesLastFetch
+	c.cache.namespacesMutex.RUnlock()
 
-	if c.cache.namespacesLastFetch.After(time.Now().Add(-1 * cacheDuration)) {
+	log.WithFields(logrus.Fields{"last fetch": lastFetch}).Tracef("Last namespace fetch.")
+
+	if lastFetch.After(time.Now().Add(-1 * cacheDuration)) {
 		log.WithFields(logrus.Fields{"cluster": c.name}).Debugf("Return namespaces from cache.")
 
-		return c.cache.namespaces, nil
+		return cachedNamespaces, nil
 	}
 
 	namespaceList, err := c.clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
@@ -125,43 +488,268 @@ func (c *Cluster) GetNamespaces(ctx context.Context, cacheDuration time.Duration
 	}
 
 	log.WithFields(logrus.Fields{"cluster": c.name}).Debugf("Return namespaces from Kubernetes API.")
+	c.cache.namespacesMutex.Lock()
 	c.cache.namespaces = namespaces
 	c.cache.namespacesLastFetch = time.Now()
+	c.cache.namespacesMutex.Unlock()
 
 	return namespaces, nil
 }
 
+// NamespaceExists returns true when the given namespace exists in the cluster. We check the cached namespaces first and
+// only when the namespace isn't cached we get the namespace from the Kubernetes API, so that namespaces, which were
+// created after the namespaces were cached, are also found.
+func (c *Cluster) NamespaceExists(ctx context.Context, namespace string, cacheDuration time.Duration) (bool, error) {
+	namespaces, err := c.GetNamespaces(ctx, cacheDuration)
+	if err == nil {
+		for _, n := range namespaces {
+			if n == namespace {
+				return true, nil
+			}
+		}
+	}
+
+	_, err = c.clientset.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
 // GetResources returns a list for the given resource in the given namespace. The resource is identified by the
 // Kubernetes API path and the resource. The name is optional and can be used to get a single resource, instead of a
-// list of resources.
-func (c *Cluster) GetResources(ctx context.Context, namespace, name, path, resource, paramName, param string) ([]byte, error) {
+// list of resources. Next to the resources we also return all warnings from the Kubernetes API server (e.g. for
+// deprecated APIs), so that they can be shown to the user.
+// The returned objects are the live objects as they are returned by the Kubernetes API server, so that they include all
+// server-applied defaults. They must not be modified, so that users can debug defaulting and admission issues.
+func (c *Cluster) GetResources(ctx context.Context, namespace, name, path, resource, paramName, param string) ([]byte, []string, error) {
+	params := url.Values{}
+	if paramName != "" {
+		params.Set(paramName, param)
+	}
+
+	return c.GetResourcesWithParams(ctx, namespace, name, path, resource, params)
+}
+
+// GetResourcesWithParams works like GetResources, but instead of a single parameter it accepts a list of query
+// parameters, which are added to the request against the Kubernetes API server. This allows the usage of all list
+// options, like "limit", "continue", "resourceVersion" or "timeoutSeconds".
+func (c *Cluster) GetResourcesWithParams(ctx context.Context, namespace, name, path, resource string, params url.Values) ([]byte, []string, error) {
+	return c.GetResourcesWithMaxSize(ctx, namespace, name, path, resource, params, 0)
+}
+
+// GetResourcesWithMaxSize works like GetResourcesWithParams, but the response of the Kubernetes API server is read
+// until the given maximum size in bytes is reached. If the response is larger, the request is aborted and the
+// ErrResponseTooLarge error is returned, so that a large list is never loaded into memory completely. A maximum size of
+// 0 disables the limit.
+func (c *Cluster) GetResourcesWithMaxSize(ctx context.Context, namespace, name, path, resource string, params url.Values, maxSize int64) ([]byte, []string, error) {
+	return c.getResources(ctx, namespace, name, path, resource, params, maxSize, "")
+}
+
+// GetResourcesMetadata works like GetResourcesWithMaxSize, but the Kubernetes API server only returns the metadata of
+// the resources as PartialObjectMetadataList. This should be used, when only the name and namespace of the resources
+// are needed, because the response is much smaller than the list of the complete objects.
+func (c *Cluster) GetResourcesMetadata(ctx context.Context, namespace, path, resource string, maxSize int64) ([]byte, []string, error) {
+	return c.getResources(ctx, namespace, "", path, resource, url.Values{}, maxSize, "application/json;as=PartialObjectMetadataList;g=meta.k8s.io;v=v1,application/json")
+}
+
+// getResources implements GetResourcesWithMaxSize and GetResourcesMetadata. If the accept parameter is not empty, it is
+// used as Accept header for the request.
+func (c *Cluster) getResources(ctx context.Context, namespace, name, path, resource string, params url.Values, maxSize int64, accept string) ([]byte, []string, error) {
+	req := c.clientset.RESTClient().Get().AbsPath(path).Namespace(namespace).Resource(resource)
+	if accept != "" {
+		req = req.SetHeader("Accept", accept)
+	}
+
 	if name != "" {
-		if namespace != "" {
-			res, err := c.clientset.RESTClient().Get().AbsPath(path).Namespace(namespace).Resource(resource).Name(name).DoRaw(ctx)
-			if err != nil {
-				log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "name": name, "path": path, "resource": resource}).Errorf("GetResources")
-				return nil, err
-			}
+		req = req.Name(name)
+	}
 
-			return res, nil
+	for key, values := range params {
+		for _, value := range values {
+			req = req.Param(key, value)
 		}
+	}
 
-		res, err := c.clientset.RESTClient().Get().AbsPath(path).Resource(resource).Name(name).DoRaw(ctx)
-		if err != nil {
-			log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "name": name, "path": path, "resource": resource}).Errorf("GetResources")
-			return nil, err
-		}
+	var res []byte
+	var warnings []string
+	var err error
 
-		return res, nil
+	if maxSize > 0 {
+		res, warnings, err = doRawWithMaxSize(ctx, req, maxSize)
+	} else {
+		res, warnings, err = doRaw(ctx, req)
 	}
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "name": name, "path": path, "resource": resource, "params": params.Encode()}).Errorf("GetResources")
+		return nil, nil, err
+	}
+
+	return res, warnings, nil
+}
+
+// doRaw executes the given request and returns the raw response body and the text of all warning headers returned by
+// the Kubernetes API server.
+func doRaw(ctx context.Context, req *rest.Request) ([]byte, []string, error) {
+	result := req.Do(ctx)
 
-	res, err := c.clientset.RESTClient().Get().AbsPath(path).Namespace(namespace).Resource(resource).Param(paramName, param).DoRaw(ctx)
+	res, err := result.Raw()
 	if err != nil {
-		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "path": path, "resource": resource}).Errorf("GetResources")
-		return nil, err
+		return nil, nil, err
+	}
+
+	var warnings []string
+	for _, warning := range result.Warnings() {
+		warnings = append(warnings, warning.Text)
+	}
+
+	return res, warnings, nil
+}
+
+// warningsCollector is a warning handler for the Kubernetes client, which collects the text of all warning headers.
+type warningsCollector struct {
+	warnings []string
+}
+
+func (w *warningsCollector) HandleWarningHeader(code int, agent, text string) {
+	w.warnings = append(w.warnings, text)
+}
+
+// doRawWithMaxSize works like doRaw, but the response body is streamed and we stop reading it, when it exceeds the
+// given maximum size in bytes. In this case the ErrResponseTooLarge error is returned.
+func doRawWithMaxSize(ctx context.Context, req *rest.Request, maxSize int64) ([]byte, []string, error) {
+	collector := &warningsCollector{}
+
+	stream, err := req.WarningHandler(collector).Stream(ctx)
+	if err != nil {
+		return nil, nil, err
 	}
+	defer stream.Close()
+
+	res, err := io.ReadAll(io.LimitReader(stream, maxSize+1))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if int64(len(res)) > maxSize {
+		return nil, nil, fmt.Errorf("%w: the response exceeds the maximum size of %d bytes", ErrResponseTooLarge, maxSize)
+	}
+
+	return res, collector.warnings, nil
+}
+
+// WatchResources sends the list of resources for the given path and resource via the passed in writer (e.g. a WebSocket
+// connection).
+// After the initial list was sent, we are watching the resources and sending every change as a separate event, so that
+// the table in the frontend can be updated without polling the Kubernetes API. When the resource version of the list is
+// expired (410 Gone), we get a new list, send it to the client and start watching again from the new resource version.
+func (c *Cluster) WatchResources(ctx context.Context, writer ResourceEventWriter, namespace, path, resource, paramName, param string) error {
+	for {
+		res, err := c.clientset.RESTClient().Get().AbsPath(path).Namespace(namespace).Resource(resource).Param(paramName, param).DoRaw(ctx)
+		if err != nil {
+			log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "path": path, "resource": resource}).Errorf("WatchResources")
+			return err
+		}
+
+		var list map[string]interface{}
+		if err := json.Unmarshal(res, &list); err != nil {
+			return err
+		}
+
+		var resourceVersion string
+		if metadata, ok := list["metadata"].(map[string]interface{}); ok {
+			if rv, ok := metadata["resourceVersion"].(string); ok {
+				resourceVersion = rv
+			}
+		}
 
-	return res, nil
+		if err := writer.WriteJSON(ResourceEvent{Type: "LIST", Object: list}); err != nil {
+			return err
+		}
+
+		err = c.watchResources(ctx, writer, namespace, path, resource, paramName, param, resourceVersion)
+		if err == errResourceVersionExpired {
+			log.WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "path": path, "resource": resource}).Debugf("Resource version expired, restart watch.")
+			continue
+		}
+
+		return err
+	}
+}
+
+// watchResources watches the given resource starting at the provided resource version. Each event is sent via the
+// passed in writer. When the Kubernetes API server closes the watch (e.g. because the timeout was reached), we start
+// a new watch from the resource version of the last received event. If the resource version is expired, we return the
+// errResourceVersionExpired error, so that the caller can start over with a new list.
+// We are also requesting bookmark events, which are only used to update the resource version and are not sent to the
+// client. This allows us to resume the watch from a recent resource version, also when there were no changes for the
+// watched resources for a long time, so that we have to relist the resources less often.
+func (c *Cluster) watchResources(ctx context.Context, writer ResourceEventWriter, namespace, path, resource, paramName, param, resourceVersion string) error {
+	for {
+		stream, err := c.getStreamClientset().RESTClient().Get().AbsPath(path).Namespace(namespace).Resource(resource).Param(paramName, param).Param("watch", "true").Param("allowWatchBookmarks", "true").Param("resourceVersion", resourceVersion).Stream(ctx)
+		if err != nil {
+			if apierrors.IsGone(err) || apierrors.IsResourceExpired(err) {
+				return errResourceVersionExpired
+			}
+
+			return err
+		}
+
+		decoder := json.NewDecoder(stream)
+
+		for {
+			var event watchEvent
+			if err := decoder.Decode(&event); err != nil {
+				stream.Close()
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+
+				break
+			}
+
+			if event.Type == "ERROR" {
+				stream.Close()
+
+				var status metav1.Status
+				if err := json.Unmarshal(event.Object, &status); err != nil {
+					return err
+				}
+
+				if status.Code == http.StatusGone {
+					return errResourceVersionExpired
+				}
+
+				return fmt.Errorf("%s", status.Message)
+			}
+
+			var object map[string]interface{}
+			if err := json.Unmarshal(event.Object, &object); err != nil {
+				stream.Close()
+				return err
+			}
+
+			if metadata, ok := object["metadata"].(map[string]interface{}); ok {
+				if rv, ok := metadata["resourceVersion"].(string); ok {
+					resourceVersion = rv
+				}
+			}
+
+			if event.Type == "BOOKMARK" {
+				continue
+			}
+
+			if err := writer.WriteJSON(ResourceEvent{Type: event.Type, Object: object}); err != nil {
+				stream.Close()
+				return err
+			}
+		}
+	}
 }
 
 // DeleteResource can be used to delete the given resource. The resource is identified by the Kubernetes API path and
@@ -177,9 +765,14 @@ func (c *Cluster) DeleteResource(ctx context.Context, namespace, name, path, res
 }
 
 // PatchResource can be used to edit the given resource. The resource is identified by the Kubernetes API path and the
-// name of the resource.
-func (c *Cluster) PatchResource(ctx context.Context, namespace, name, path, resource string, body []byte) error {
-	_, err := c.clientset.RESTClient().Patch(types.JSONPatchType).AbsPath(path).Namespace(namespace).Resource(resource).Name(name).Body(body).DoRaw(ctx)
+// name of the resource. The patch type must be a JSON patch, a JSON merge patch or a strategic merge patch. If no patch
+// type is provided we use a JSON patch.
+func (c *Cluster) PatchResource(ctx context.Context, namespace, name, path, resource string, patchType types.PatchType, body []byte) error {
+	if patchType == "" {
+		patchType = types.JSONPatchType
+	}
+
+	_, err := c.clientset.RESTClient().Patch(patchType).AbsPath(path).Namespace(namespace).Resource(resource).Name(name).Body(body).DoRaw(ctx)
 	if err != nil {
 		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "path": path, "resource": resource}).Errorf("PatchResource")
 		return err
@@ -188,6 +781,111 @@ func (c *Cluster) PatchResource(ctx context.Context, namespace, name, path, reso
 	return nil
 }
 
+// DiffResource returns the changes between the live object and the submitted manifest (body). The live object is
+// identified by the Kubernetes API path and the name of the resource. Fields which are managed by the Kubernetes API
+// server are ignored, so that only the changes made by the user are returned.
+func (c *Cluster) DiffResource(ctx context.Context, namespace, name, path, resource string, body []byte) ([]diff.Change, error) {
+	res, err := c.clientset.RESTClient().Get().AbsPath(path).Namespace(namespace).Resource(resource).Name(name).DoRaw(ctx)
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "name": name, "path": path, "resource": resource}).Errorf("DiffResource")
+		return nil, err
+	}
+
+	var live map[string]interface{}
+	if err := json.Unmarshal(res, &live); err != nil {
+		return nil, err
+	}
+
+	var submitted map[string]interface{}
+	if err := json.Unmarshal(body, &submitted); err != nil {
+		return nil, err
+	}
+
+	return diff.Compare(live, submitted), nil
+}
+
+// DiffAppliedResource returns the changes between the last applied manifest and the live object. The last applied
+// manifest is taken from the "kubectl.kubernetes.io/last-applied-configuration" annotation. Since the live object
+// already contains all defaults set by the Kubernetes API server and admission webhooks, the returned changes show
+// which fields were defaulted or mutated by the server.
+func (c *Cluster) DiffAppliedResource(ctx context.Context, namespace, name, path, resource string) ([]diff.Change, error) {
+	res, err := c.clientset.RESTClient().Get().AbsPath(path).Namespace(namespace).Resource(resource).Name(name).DoRaw(ctx)
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "name": name, "path": path, "resource": resource}).Errorf("DiffAppliedResource")
+		return nil, err
+	}
+
+	var live map[string]interface{}
+	if err := json.Unmarshal(res, &live); err != nil {
+		return nil, err
+	}
+
+	var lastApplied string
+	if metadata, ok := live["metadata"].(map[string]interface{}); ok {
+		if annotations, ok := metadata["annotations"].(map[string]interface{}); ok {
+			lastApplied, _ = annotations["kubectl.kubernetes.io/last-applied-configuration"].(string)
+		}
+	}
+
+	if lastApplied == "" {
+		return nil, fmt.Errorf("resource has no last applied configuration")
+	}
+
+	var applied map[string]interface{}
+	if err := json.Unmarshal([]byte(lastApplied), &applied); err != nil {
+		return nil, err
+	}
+
+	return diff.Compare(applied, live), nil
+}
+
+// UpdateLabels adds, changes or removes the given labels of a resource. The resource is identified by the Kubernetes API
+// path and the name of the resource. A label with a nil value is removed from the resource. Before the labels are
+// updated we validate the keys and values of all labels.
+func (c *Cluster) UpdateLabels(ctx context.Context, namespace, name, path, resource string, labels map[string]*string) error {
+	for key, value := range labels {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("invalid label key %s: %s", key, strings.Join(errs, ", "))
+		}
+
+		if value != nil {
+			if errs := validation.IsValidLabelValue(*value); len(errs) > 0 {
+				return fmt.Errorf("invalid label value %s: %s", *value, strings.Join(errs, ", "))
+			}
+		}
+	}
+
+	return c.updateMetadata(ctx, namespace, name, path, resource, "labels", labels)
+}
+
+// UpdateAnnotations adds, changes or removes the given annotations of a resource. The resource is identified by the
+// Kubernetes API path and the name of the resource. An annotation with a nil value is removed from the resource.
+func (c *Cluster) UpdateAnnotations(ctx context.Context, namespace, name, path, resource string, annotations map[string]*string) error {
+	for key := range annotations {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("invalid annotation key %s: %s", key, strings.Join(errs, ", "))
+		}
+	}
+
+	return c.updateMetadata(ctx, namespace, name, path, resource, "annotations", annotations)
+}
+
+// updateMetadata builds a JSON merge patch for the given metadata field (labels or annotations) and applies it to the
+// resource. We are using a JSON merge patch instead of a strategic merge patch, because it also works for Custom
+// Resources. Keys with a nil value are set to null in the patch, which removes them from the resource.
+func (c *Cluster) updateMetadata(ctx context.Context, namespace, name, path, resource, field string, values map[string]*string) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			field: values,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.PatchResource(ctx, namespace, name, path, resource, types.MergePatchType, body)
+}
+
 // CreateResource can be used to create the given resource. The resource is identified by the Kubernetes API path and the
 // name of the resource.
 func (c *Cluster) CreateResource(ctx context.Context, namespace, name, path, resource, subResource string, body []byte) error {
@@ -210,68 +908,280 @@ func (c *Cluster) CreateResource(ctx context.Context, namespace, name, path, res
 	return nil
 }
 
-// GetLogs returns the logs for a Container. The Container is identified by the namespace and pod name and the container
-// name. Is is also possible to set the time since when the logs should be received and with the previous flag the logs
-// for the last container can be received.
-func (c *Cluster) GetLogs(ctx context.Context, namespace, name, container, regex string, since, tail int64, previous bool) (string, error) {
+// GetLogs returns the logs for a Container. The Container is identified by the namespace and pod name and the container
+// name. Is is also possible to set the time since when the logs should be received and with the previous flag the logs
+// for the last container can be received. If since is 0, the logs are not limited by time.
+// The logs can be filtered by a list of regular expressions. A line is kept, when it matches any of the given regular
+// expressions.
+// The lines are joined by the given line terminator (see GetLineTerminator). If the line terminator is empty, the
+// lines are joined by "\n\r", which is required by the terminal in the frontend.
+func (c *Cluster) GetLogs(ctx context.Context, namespace, name, container string, regexes []string, since, tail int64, previous bool, lineTerminator string) (string, error) {
+	regs, err := compileRegexes(regexes)
+	if err != nil {
+		return "", err
+	}
+
+	if lineTerminator == "" {
+		lineTerminator = lineTerminators["lfcr"]
+	}
+
+	options := &corev1.PodLogOptions{
+		Container: container,
+		Previous:  previous,
+	}
+
+	if since > 0 {
+		options.SinceSeconds = &since
+	}
+
+	if tail > 0 {
+		options.TailLines = &tail
+	}
+
+	res, err := c.clientset.CoreV1().Pods(namespace).GetLogs(name, options).DoRaw(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var logs []string
+	for _, line := range strings.Split(string(res), "\n") {
+		if matchesAny(regs, line) {
+			logs = append(logs, line)
+		}
+	}
+
+	return strings.Join(logs, lineTerminator) + lineTerminator, nil
+}
+
+// lineTerminators contains all line terminators, which can be used to join the lines returned by GetLogs.
+var lineTerminators = map[string]string{
+	"lf":   "\n",
+	"crlf": "\r\n",
+	"lfcr": "\n\r",
+}
+
+// GetLineTerminator returns the line terminator for the given name. Valid names are "lf" ("\n"), "crlf" ("\r\n") and
+// "lfcr" ("\n\r"). For an empty name the "lfcr" line terminator is returned, which is used by the terminal in the
+// frontend.
+func GetLineTerminator(name string) (string, error) {
+	if name == "" {
+		return lineTerminators["lfcr"], nil
+	}
+
+	lineTerminator, ok := lineTerminators[name]
+	if !ok {
+		return "", fmt.Errorf("invalid line terminator %q, must be \"lf\", \"crlf\" or \"lfcr\"", name)
+	}
+
+	return lineTerminator, nil
+}
+
+// GetLogsReader returns a reader for the logs of a Container. In contrast to the GetLogs function the logs are not
+// loaded into memory, instead they are streamed from the Kubernetes API and filtered line by line, so that it can be
+// used to download large logs. The logs are filtered by the given regular expressions in the same way as it is done in
+// the GetLogs function. The caller is responsible for closing the returned reader.
+func (c *Cluster) GetLogsReader(ctx context.Context, namespace, name, container string, regexes []string, since, tail int64, previous bool) (io.ReadCloser, error) {
+	regs, err := compileRegexes(regexes)
+	if err != nil {
+		return nil, err
+	}
+
 	options := &corev1.PodLogOptions{
-		Container:    container,
-		SinceSeconds: &since,
-		Previous:     previous,
+		Container: container,
+		Previous:  previous,
+	}
+
+	if since > 0 {
+		options.SinceSeconds = &since
 	}
 
 	if tail > 0 {
 		options.TailLines = &tail
 	}
 
-	res, err := c.clientset.CoreV1().Pods(namespace).GetLogs(name, options).DoRaw(ctx)
+	stream, err := c.getStreamClientset().CoreV1().Pods(namespace).GetLogs(name, options).Stream(ctx)
 	if err != nil {
-		return "", err
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "name": name, "container": container}).Errorf("GetLogsReader")
+		return nil, err
 	}
 
-	if regex == "" {
-		var logs []string
-		for _, line := range strings.Split(string(res), "\n") {
-			logs = append(logs, line)
+	pr, pw := io.Pipe()
+
+	go func() {
+		defer stream.Close()
+
+		reader := bufio.NewReader(stream)
+		for {
+			line, err := reader.ReadString('\n')
+			if len(line) > 0 && matchesAny(regs, strings.TrimSuffix(line, "\n")) {
+				if _, writeErr := io.WriteString(pw, line); writeErr != nil {
+					pw.CloseWithError(writeErr)
+					return
+				}
+			}
+
+			if err != nil {
+				if err == io.EOF {
+					err = nil
+				}
+
+				pw.CloseWithError(err)
+				return
+			}
+		}
+	}()
+
+	return pr, nil
+}
+
+// compileRegexes compiles all the given patterns. Empty patterns are ignored. If one or more patterns can not be
+// compiled, the returned error wraps the ErrInvalidRegex error and contains all failing patterns.
+func compileRegexes(patterns []string) ([]*regexp.Regexp, error) {
+	var regs []*regexp.Regexp
+	var failed []string
+
+	for _, pattern := range patterns {
+		if pattern == "" {
+			continue
+		}
+
+		reg, err := regexp.Compile(pattern)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%q (%s)", pattern, err.Error()))
+			continue
 		}
 
-		return strings.Join(logs, "\n\r") + "\n\r", nil
+		regs = append(regs, reg)
 	}
 
-	reg, err := regexp.Compile(regex)
-	if err != nil {
-		return "", err
+	if len(failed) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRegex, strings.Join(failed, ", "))
 	}
 
-	var logs []string
-	for _, line := range strings.Split(string(res), "\n") {
+	return regs, nil
+}
+
+// matchesAny returns true if the line matches any of the given regular expressions. If no regular expressions are
+// given, every line matches.
+func matchesAny(regs []*regexp.Regexp, line string) bool {
+	if len(regs) == 0 {
+		return true
+	}
+
+	for _, reg := range regs {
 		if reg.MatchString(line) {
-			logs = append(logs, line)
+			return true
 		}
 	}
 
-	return strings.Join(logs, "\n\r") + "\n\r", nil
+	return false
+}
+
+// messageWriter is the interface, which is used to write the lines of a log stream. It is implemented by a WebSocket
+// connection.
+type messageWriter interface {
+	WriteMessage(messageType int, data []byte) error
+}
+
+// StreamMessage is a structured message, which is send via the WebSocket connection of a log stream, when the client
+// has to take an action. Currently this is only used to tell the client that it should reconnect, because the stream
+// could not be recovered after the credentials for the cluster expired.
+type StreamMessage struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
 }
 
 // StreamLogs can be used to stream the logs of the selected Container. For that we are using the passed in WebSocket
 // connection an write each line returned by the Kubernetes API to this connection.
-func (c *Cluster) StreamLogs(ctx context.Context, conn *websocket.Conn, namespace, name, container string, since, tail int64, follow bool) error {
+// When the stream is interrupted, e.g. because the credentials for the cluster expired, we try to reopen the stream
+// once. The logs are requested with timestamps, so that the reopened stream can start at the last line we have seen and
+// no lines are lost. The timestamps are removed before the lines are written to the WebSocket connection.
+// Lines which are larger then the given maxMessageSize are split across multiple messages. If the maxMessageSize is
+// zero, lines are never split.
+func (c *Cluster) StreamLogs(ctx context.Context, conn *websocket.Conn, namespace, name, container string, since, tail int64, follow bool, maxMessageSize int) error {
+	activeWebSocketsMetric.WithLabelValues("logs").Inc()
+	defer activeWebSocketsMetric.WithLabelValues("logs").Dec()
+
 	options := &corev1.PodLogOptions{
 		Container:    container,
 		SinceSeconds: &since,
 		Follow:       follow,
+		Timestamps:   true,
 	}
 
 	if tail > 0 {
 		options.TailLines = &tail
 	}
 
-	stream, err := c.clientset.CoreV1().Pods(namespace).GetLogs(name, options).Stream(ctx)
+	stream, err := c.getLogStream(ctx, conn, namespace, name, options)
 	if err != nil {
 		return err
 	}
 
+	reader := &timestampReader{}
+	reader.reset(stream)
+
+	err = writeLogStream(conn, reader, maxMessageSize, "")
+	stream.Close()
+	if err == nil || err == io.EOF || errors.Is(err, errWebSocketWrite) || ctx.Err() != nil {
+		return err
+	}
+
+	log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "name": name}).Warnf("Log stream was interrupted, try to reopen it")
+
+	// If we already have seen a line, we resume the stream at the timestamp of this line. Since the Kubernetes API
+	// only supports seconds for the sinceTime option, lines which we have already seen are skipped by the reader.
+	if !reader.last.IsZero() {
+		sinceTime := metav1.NewTime(reader.last)
+		options.SinceSeconds = nil
+		options.SinceTime = &sinceTime
+		options.TailLines = nil
+	}
+
+	stream, err = c.getLogStream(ctx, conn, namespace, name, options)
+	if err != nil {
+		return err
+	}
 	defer stream.Close()
+
+	reader.reset(stream)
+	return writeLogStream(conn, reader, maxMessageSize, "")
+}
+
+// getLogStream opens the log stream for the given Pod. If the Kubernetes API returns an unauthorized error, we reload
+// the stream clientset, so that rotated credentials are picked up, and try it again. If the second attempt also fails
+// with an unauthorized error, we send a reconnect message to the client and return the ErrStreamReconnect error.
+func (c *Cluster) getLogStream(ctx context.Context, conn *websocket.Conn, namespace, name string, options *corev1.PodLogOptions) (io.ReadCloser, error) {
+	stream, err := c.getStreamClientset().CoreV1().Pods(namespace).GetLogs(name, options).Stream(ctx)
+	if err == nil || !apierrors.IsUnauthorized(err) {
+		return stream, err
+	}
+
+	log.WithError(err).WithFields(logrus.Fields{"cluster": c.name}).Warnf("Authentication failed, reload stream clientset")
+
+	if err := c.reloadStreamClientset(); err != nil {
+		return nil, err
+	}
+
+	stream, err = c.getStreamClientset().CoreV1().Pods(namespace).GetLogs(name, options).Stream(ctx)
+	if err == nil || !apierrors.IsUnauthorized(err) {
+		return stream, err
+	}
+
+	log.WithError(err).WithFields(logrus.Fields{"cluster": c.name}).Errorf("Authentication failed after reloading the stream clientset")
+
+	conn.WriteJSON(StreamMessage{
+		Type:    "reconnect",
+		Message: "The credentials for the cluster expired, please reconnect",
+	})
+
+	return nil, ErrStreamReconnect
+}
+
+// writeLogStream reads the given log stream line by line and writes each line to the WebSocket connection. Errors
+// while writing to the WebSocket connection are wrapped with errWebSocketWrite, so that they can be distinguished from
+// errors while reading the stream. The optional prefix is added in front of each line.
+func writeLogStream(conn messageWriter, stream io.Reader, maxMessageSize int, prefix string) error {
 	reader := bufio.NewReaderSize(stream, 16)
 	lastLine := ""
 
@@ -295,31 +1205,179 @@ func (c *Cluster) StreamLogs(ctx context.Context, conn *websocket.Conn, namespac
 		}
 
 		for _, line := range lines {
-			if err := conn.WriteMessage(websocket.TextMessage, []byte(line)); err != nil {
-				return err
+			for _, message := range splitLine(prefix+line, maxMessageSize) {
+				if err := conn.WriteMessage(websocket.TextMessage, []byte(message)); err != nil {
+					return fmt.Errorf("%w: %s", errWebSocketWrite, err.Error())
+				}
 			}
 		}
 	}
 }
 
-// GetTerminal starts a new terminal session via the given WebSocket connection.
-func (c *Cluster) GetTerminal(conn *websocket.Conn, namespace, name, container, shell string) error {
-	reqURL, err := url.Parse(fmt.Sprintf("%s/api/v1/namespaces/%s/pods/%s/exec?container=%s&command=%s&stdin=true&stdout=true&stderr=true&tty=true", c.config.Host, namespace, name, container, shell))
+// splitLine splits the given line into multiple messages, when the line is larger then the maxMessageSize. All
+// messages except the last one end with the logContinuationMarker, so that the client knows that the line is continued
+// in the next message. The line is only split at rune boundaries, so that multi-byte characters are not broken.
+func splitLine(line string, maxMessageSize int) []string {
+	if maxMessageSize <= len(logContinuationMarker) || len(line) <= maxMessageSize {
+		return []string{line}
+	}
+
+	chunkSize := maxMessageSize - len(logContinuationMarker)
+
+	var messages []string
+	for len(line) > maxMessageSize {
+		end := chunkSize
+		for end > 0 && !utf8.RuneStart(line[end]) {
+			end--
+		}
+		if end == 0 {
+			end = chunkSize
+		}
+
+		messages = append(messages, line[:end]+logContinuationMarker)
+		line = line[end:]
+	}
+
+	return append(messages, line)
+}
+
+// GetPodImages returns the images of all containers, init containers and ephemeral containers of a Pod. Next to the
+// image from the Pod spec we also return the resolved image id and digest from the container statuses, so that users
+// can see which image is really running.
+func (c *Cluster) GetPodImages(ctx context.Context, namespace, name string) ([]ImageInfo, error) {
+	pod, err := c.clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	var images []ImageInfo
+
+	for _, container := range pod.Spec.InitContainers {
+		images = append(images, getImageInfo(container.Name, "initContainer", container.Image, pod.Status.InitContainerStatuses))
+	}
+
+	for _, container := range pod.Spec.Containers {
+		images = append(images, getImageInfo(container.Name, "container", container.Image, pod.Status.ContainerStatuses))
+	}
+
+	for _, container := range pod.Spec.EphemeralContainers {
+		images = append(images, getImageInfo(container.Name, "ephemeralContainer", container.Image, pod.Status.EphemeralContainerStatuses))
+	}
+
+	return images, nil
+}
+
+// GetPodContainerStatus returns the status of all init, regular and ephemeral containers of a Pod. The status is
+// derived from the container statuses of the Pod, so that it can be used to troubleshoot crashlooping containers.
+func (c *Cluster) GetPodContainerStatus(ctx context.Context, namespace, name string) ([]ContainerStatus, error) {
+	pod, err := c.clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "name": name}).Errorf("GetPodContainerStatus")
+		return nil, err
+	}
+
+	var statuses []ContainerStatus
+
+	for _, status := range pod.Status.InitContainerStatuses {
+		statuses = append(statuses, getContainerStatus("initContainer", status))
+	}
+
+	for _, status := range pod.Status.ContainerStatuses {
+		statuses = append(statuses, getContainerStatus("container", status))
+	}
+
+	for _, status := range pod.Status.EphemeralContainerStatuses {
+		statuses = append(statuses, getContainerStatus("ephemeralContainer", status))
+	}
+
+	return statuses, nil
+}
+
+// getContainerStatus converts the Kubernetes container status into our ContainerStatus format.
+func getContainerStatus(containerType string, status corev1.ContainerStatus) ContainerStatus {
+	containerStatus := ContainerStatus{
+		Container:    status.Name,
+		Type:         containerType,
+		Ready:        status.Ready,
+		RestartCount: status.RestartCount,
+	}
+
+	if status.State.Running != nil {
+		containerStatus.State = "running"
+	} else if status.State.Waiting != nil {
+		containerStatus.State = "waiting"
+		containerStatus.Reason = status.State.Waiting.Reason
+		containerStatus.Message = status.State.Waiting.Message
+	} else if status.State.Terminated != nil {
+		containerStatus.State = "terminated"
+		containerStatus.Reason = status.State.Terminated.Reason
+		containerStatus.Message = status.State.Terminated.Message
+		containerStatus.ExitCode = &status.State.Terminated.ExitCode
+	}
+
+	if status.LastTerminationState.Terminated != nil {
+		containerStatus.LastTerminationReason = status.LastTerminationState.Terminated.Reason
+		containerStatus.LastTerminationExitCode = &status.LastTerminationState.Terminated.ExitCode
+		containerStatus.LastTerminationFinishedAt = status.LastTerminationState.Terminated.FinishedAt.Format(time.RFC3339)
+	}
+
+	return containerStatus
+}
+
+// GetConfigMap returns the data and binary data of a ConfigMap. The data and binary data are returned separately, so
+// that they can be shown in a dedicated view in the frontend.
+func (c *Cluster) GetConfigMap(ctx context.Context, namespace, name string) (map[string]string, map[string][]byte, error) {
+	configMap, err := c.clientset.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "name": name}).Errorf("GetConfigMap")
+		return nil, nil, err
+	}
+
+	return configMap.Data, configMap.BinaryData, nil
+}
+
+// getImageInfo returns the ImageInfo for a container. The image id and digest are taken from the status of the
+// container with the same name. If the container doesn't have a status yet, the image id and digest are empty.
+func getImageInfo(name, containerType, image string, statuses []corev1.ContainerStatus) ImageInfo {
+	info := ImageInfo{
+		Container: name,
+		Type:      containerType,
+		Image:     image,
+	}
+
+	for _, status := range statuses {
+		if status.Name == name {
+			info.ImageID = status.ImageID
+			if index := strings.LastIndex(status.ImageID, "@"); index != -1 {
+				info.Digest = status.ImageID[index+1:]
+			}
+		}
 	}
 
+	return info
+}
+
+// GetTerminal starts a new terminal session via the given WebSocket connection.
+func (c *Cluster) GetTerminal(conn *websocket.Conn, namespace, name, container, shell string) error {
+	activeWebSocketsMetric.WithLabelValues("terminal").Inc()
+	defer activeWebSocketsMetric.WithLabelValues("terminal").Dec()
+
 	if !terminal.IsValidShell(shell) {
 		return fmt.Errorf("invalid shell %s", shell)
 	}
 
+	reqURL, err := url.Parse(fmt.Sprintf("%s/api/v1/namespaces/%s/pods/%s/exec?container=%s&command=%s&stdin=true&stdout=true&stderr=true&tty=true", c.config.Host, namespace, name, container, shell))
+	if err != nil {
+		return err
+	}
+
 	session := &terminal.Session{
 		WebSocket: conn,
 		SizeChan:  make(chan remotecommand.TerminalSize),
 	}
 
 	cmd := []string{shell}
-	return terminal.StartProcess(c.config, reqURL, cmd, session)
+	return terminal.StartProcess(c.getStreamConfig(), reqURL, cmd, session)
 }
 
 // CopyFileFromPod creates the request URL for downloading a file from the specified container.
@@ -330,7 +1388,20 @@ func (c *Cluster) CopyFileFromPod(w http.ResponseWriter, namespace, name, contai
 		return err
 	}
 
-	return copy.FileFromPod(w, c.config, reqURL)
+	return copy.FileFromPod(w, c.getStreamConfig(), reqURL)
+}
+
+// GetRawFileFromPod creates the request URL for downloading a single file from the specified container. The file is
+// returned with its raw content and the detected content type, so that it can be displayed inline. Symbolic links are
+// followed via the "h" flag, because files from ConfigMaps and Secrets are mounted as symbolic links.
+func (c *Cluster) GetRawFileFromPod(w http.ResponseWriter, namespace, name, container, srcPath string) error {
+	command := fmt.Sprintf("&command=tar&command=chf&command=-&command=%s", url.QueryEscape(srcPath))
+	reqURL, err := url.Parse(fmt.Sprintf("%s/api/v1/namespaces/%s/pods/%s/exec?container=%s&stdin=false&stdout=true&stderr=false&tty=false%s", c.config.Host, namespace, name, container, command))
+	if err != nil {
+		return err
+	}
+
+	return copy.RawFileFromPod(w, c.getStreamConfig(), reqURL)
 }
 
 // CopyFileToPod creates the request URL for uploading a file to the specified container.
@@ -341,7 +1412,7 @@ func (c *Cluster) CopyFileToPod(namespace, name, container string, srcFile multi
 		return err
 	}
 
-	return copy.FileToPod(c.config, reqURL, srcFile, destPath)
+	return copy.FileToPod(c.getStreamConfig(), reqURL, srcFile, destPath)
 }
 
 // GetApplications returns a list of applications gor the given namespace. It also adds the cluster, namespace and
@@ -502,31 +1573,244 @@ func (c *Cluster) GetUser(ctx context.Context, namespace, name string) (*user.Us
 	return &user, nil
 }
 
+// GetCRManifest returns the raw manifest of a kobs Custom Resource (application, dashboard, team or user) as YAML.
+// All fields which are managed by the Kubernetes API server are removed from the manifest, so that the returned YAML
+// can be used with "kubectl apply" to export or copy the Custom Resource.
+func (c *Cluster) GetCRManifest(ctx context.Context, namespace, name, resource string) ([]byte, error) {
+	switch resource {
+	case "applications", "dashboards", "teams", "users":
+	default:
+		return nil, fmt.Errorf("invalid resource %s", resource)
+	}
+
+	res, err := c.clientset.RESTClient().Get().AbsPath("/apis/kobs.io/v1beta1").Namespace(namespace).Resource(resource).Name(name).DoRaw(ctx)
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "name": name, "resource": resource}).Errorf("GetCRManifest")
+		return nil, err
+	}
+
+	var manifest map[string]interface{}
+	if err := json.Unmarshal(res, &manifest); err != nil {
+		return nil, err
+	}
+
+	diff.StripServerFields(manifest)
+
+	return yaml.Marshal(manifest)
+}
+
+// GetCRCounts returns the number of kobs Custom Resources (applications, dashboards or teams) per namespace. To avoid
+// transferring the complete specs of all Custom Resources, we request only the metadata of the resources via the
+// PartialObjectMetadataList representation.
+func (c *Cluster) GetCRCounts(ctx context.Context, resource string) (map[string]int64, error) {
+	switch resource {
+	case "applications", "dashboards", "teams":
+	default:
+		return nil, fmt.Errorf("invalid resource %s", resource)
+	}
+
+	res, err := c.clientset.RESTClient().Get().AbsPath("/apis/kobs.io/v1beta1").Resource(resource).SetHeader("Accept", "application/json;as=PartialObjectMetadataList;g=meta.k8s.io;v=v1,application/json").DoRaw(ctx)
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "resource": resource}).Errorf("GetCRCounts")
+		return nil, err
+	}
+
+	var list metav1.PartialObjectMetadataList
+	if err := json.Unmarshal(res, &list); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64)
+	for _, item := range list.Items {
+		counts[item.Namespace] = counts[item.Namespace] + 1
+	}
+
+	return counts, nil
+}
+
+// GetSavedQueries returns a list of saved queries via the savedquery clientset. If the namespace is an empty string,
+// the saved queries for all namespaces are returned.
+func (c *Cluster) GetSavedQueries(ctx context.Context, namespace string) ([]savedquery.SavedQuerySpec, error) {
+	savedQueriesList, err := c.savedQueryClientset.KobsV1beta1().SavedQueries(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var savedQueries []savedquery.SavedQuerySpec
+
+	for _, savedQueryItem := range savedQueriesList.Items {
+		savedQuery := savedQueryItem.Spec
+		savedQuery.Cluster = c.name
+		savedQuery.Namespace = savedQueryItem.Namespace
+		savedQuery.Name = savedQueryItem.Name
+
+		savedQueries = append(savedQueries, savedQuery)
+	}
+
+	return savedQueries, nil
+}
+
+// GetSavedQuery returns a saved query for the given namespace and name via the savedquery clientset.
+func (c *Cluster) GetSavedQuery(ctx context.Context, namespace, name string) (*savedquery.SavedQuerySpec, error) {
+	savedQueryCR, err := c.savedQueryClientset.KobsV1beta1().SavedQueries(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	savedQuery := savedQueryCR.Spec
+	savedQuery.Cluster = c.name
+	savedQuery.Namespace = namespace
+	savedQuery.Name = name
+
+	return &savedQuery, nil
+}
+
+// CreateSavedQuery creates a new saved query with the given namespace, name and spec. The cluster, namespace and name
+// fields of the spec are ignored, because they are always set from the CR when a saved query is returned.
+func (c *Cluster) CreateSavedQuery(ctx context.Context, namespace, name string, spec savedquery.SavedQuerySpec) (*savedquery.SavedQuerySpec, error) {
+	spec.Cluster = ""
+	spec.Namespace = ""
+	spec.Name = ""
+
+	savedQueryCR, err := c.savedQueryClientset.KobsV1beta1().SavedQueries(namespace).Create(ctx, &savedquery.SavedQuery{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+		},
+		Spec: spec,
+	}, metav1.CreateOptions{})
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "name": name}).Errorf("CreateSavedQuery")
+		return nil, err
+	}
+
+	savedQuery := savedQueryCR.Spec
+	savedQuery.Cluster = c.name
+	savedQuery.Namespace = savedQueryCR.Namespace
+	savedQuery.Name = savedQueryCR.Name
+
+	return &savedQuery, nil
+}
+
+// DeleteSavedQuery deletes the saved query with the given namespace and name.
+func (c *Cluster) DeleteSavedQuery(ctx context.Context, namespace, name string) error {
+	err := c.savedQueryClientset.KobsV1beta1().SavedQueries(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "name": name}).Errorf("DeleteSavedQuery")
+		return err
+	}
+
+	return nil
+}
+
+// lockedRand is a random number generator, which can be used from multiple goroutines. It is used to add jitter to the
+// backoff, when loading the CRDs of multiple clusters.
+type lockedRand struct {
+	mutex sync.Mutex
+	rand  *rand.Rand
+}
+
+// Int63n returns a random number in the interval [0,n).
+func (r *lockedRand) Int63n(n int64) int64 {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.rand.Int63n(n)
+}
+
+// getCRDsBackoff returns the duration to wait before the given attempt to load the CRDs. The backoff is doubled for
+// each attempt, starting at the given base and capped at the given max. To avoid that multiple clusters are retrying
+// at the same time, we are using a random duration between the half and the full backoff.
+func getCRDsBackoff(attempt int, base, max time.Duration) time.Duration {
+	backoff := base
+	for i := 1; i < attempt && backoff < max; i++ {
+		backoff = backoff * 2
+	}
+
+	if backoff > max {
+		backoff = max
+	}
+
+	if backoff <= 0 {
+		return 0
+	}
+
+	return backoff/2 + time.Duration(jitterRand.Int63n(int64(backoff/2)+1))
+}
+
+// crdsLimiter limits the number of clusters, which are loading their CRDs at the same time. It is shared between all
+// clusters, so that loading dozens of clusters at startup doesn't result in a burst of requests. The slots are created
+// on the first usage, because the concurrency is set via a flag, which isn't parsed when the package is initialized.
+type crdsLimiter struct {
+	once  sync.Once
+	slots chan struct{}
+}
+
+// loadCRDsLimiter is the limiter, which is used by the loadCRDs function of all clusters.
+var loadCRDsLimiter = &crdsLimiter{}
+
+// acquire waits for a free slot and returns a function to release the slot again. If the concurrency is 0 or lower,
+// there is no limit. If the context is canceled before a slot is free, the error of the context is returned.
+func (l *crdsLimiter) acquire(ctx context.Context, concurrency int) (func(), error) {
+	if concurrency <= 0 {
+		return func() {}, nil
+	}
+
+	l.once.Do(func() {
+		l.slots = make(chan struct{}, concurrency)
+	})
+
+	select {
+	case l.slots <- struct{}{}:
+		return func() { <-l.slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
 // loadCRDs retrieves all CRDs from the Kubernetes API of this cluster. Then the CRDs are transformed into our internal
 // CRD format and saved within the cluster. Since this function is only called once after a cluster was loaded, we call
-// it in a endless loop until it succeeds.
-func (c *Cluster) loadCRDs() {
-	offset := 30
+// it in a endless loop until it succeeds or until the given context is canceled. Between the attempts we wait for an
+// exponential backoff with jitter, which can be configured via the "clusters.crds.retry-base" and
+// "clusters.crds.retry-max" flags. The number of clusters, which are loading their CRDs at the same time, is limited
+// via the "clusters.crds.concurrency" flag.
+func (c *Cluster) loadCRDs(ctx context.Context) {
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			backoff := getCRDsBackoff(attempt, crdsRetryBase, crdsRetryMax)
+			log.WithFields(logrus.Fields{"name": c.name, "attempt": attempt, "backoff": backoff}).Debugf("Retry loading CRDs")
+
+			select {
+			case <-ctx.Done():
+				log.WithFields(logrus.Fields{"name": c.name}).Debugf("Stop loading CRDs")
+				return
+			case <-time.After(backoff):
+			}
+		}
+
+		release, err := loadCRDsLimiter.acquire(ctx, crdsConcurrency)
+		if err != nil {
+			log.WithFields(logrus.Fields{"name": c.name}).Debugf("Stop loading CRDs")
+			return
+		}
 
-	for {
 		log.WithFields(logrus.Fields{"name": c.name}).Tracef("loadCRDs")
-		ctx := context.Background()
 
-		res, err := c.clientset.RESTClient().Get().AbsPath("apis/apiextensions.k8s.io/v1/customresourcedefinitions").DoRaw(ctx)
+		requestCtx, cancel := context.WithTimeout(ctx, crdsTimeout)
+		res, err := c.clientset.RESTClient().Get().AbsPath("apis/apiextensions.k8s.io/v1/customresourcedefinitions").DoRaw(requestCtx)
+		cancel()
+		release()
 		if err != nil {
 			log.WithFields(logrus.Fields{"name": c.name}).WithError(err).Errorf("Could not get Custom Resource Definitions")
-			time.Sleep(time.Duration(offset) * time.Second)
-			offset = offset * 2
 			continue
 		}
 
 		var crdList apiextensionsv1.CustomResourceDefinitionList
+		var crds []CRD
 
 		err = json.Unmarshal(res, &crdList)
 		if err != nil {
 			log.WithFields(logrus.Fields{"name": c.name}).WithError(err).Errorf("Could not get unmarshal Custom Resource Definitions List")
-			time.Sleep(time.Duration(offset) * time.Second)
-			offset = offset * 2
 			continue
 		}
 
@@ -549,7 +1833,11 @@ func (c *Cluster) loadCRDs() {
 					}
 				}
 
-				c.crds = append(c.crds, CRD{
+				if len(columns) == 0 && crdDefaultColumns {
+					columns = getDefaultCRDColumns()
+				}
+
+				crds = append(crds, CRD{
 					Path:        fmt.Sprintf("%s/%s", crd.Spec.Group, version.Name),
 					Resource:    crd.Spec.Names.Plural,
 					Title:       crd.Spec.Names.Kind,
@@ -560,21 +1848,64 @@ func (c *Cluster) loadCRDs() {
 			}
 		}
 
-		log.WithFields(logrus.Fields{"name": c.name, "count": len(c.crds)}).Debugf("CRDs were loaded.")
+		// The CRDs are only set after all CRDs were transformed, so that a caller never sees a partial list of CRDs.
+		c.crdsMutex.Lock()
+		c.crds = crds
+		c.cache.crdsLastFetch = time.Now()
+		c.crdsMutex.Unlock()
+
+		log.WithFields(logrus.Fields{"name": c.name, "count": len(crds)}).Debugf("CRDs were loaded.")
 		break
 	}
 }
 
+// Close stops all background tasks of the cluster, like the loading of the CRDs.
+func (c *Cluster) Close() {
+	if c.cancel != nil {
+		c.cancel()
+	}
+}
+
+// getDefaultCRDColumns returns the columns for CRDs without additionalPrinterColumns. Similar to kubectl we only show
+// the age of a resource, because the name of a resource is always shown by the frontend.
+func getDefaultCRDColumns() []CRDColumn {
+	return []CRDColumn{{
+		Description: "CreationTimestamp is a timestamp representing the server time when this object was created.",
+		JSONPath:    ".metadata.creationTimestamp",
+		Name:        "Age",
+		Type:        "date",
+	}}
+}
+
 // NewCluster returns a new cluster. Each cluster must have a unique name and a client to make requests against the
 // Kubernetes API server of this cluster. When a cluster was successfully created we call the loadCRDs function to get
 // all CRDs for this cluster.
-func NewCluster(name string, restConfig *rest.Config) (*Cluster, error) {
+// Next to the clientset for the normal requests, we also create a clientset without a timeout. This clientset is used
+// for long running operations like watching resources, streaming logs or the terminal, which shouldn't be canceled by
+// the timeout from the rest config.
+// If a client certificate is configured via the tlsConfig, it is validated and added to the rest config before the
+// clientsets are created.
+func NewCluster(name string, restConfig *rest.Config, tlsConfig *TLSConfig) (*Cluster, error) {
+	if err := tlsConfig.apply(restConfig); err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"name": name}).Errorf("Could not configure client certificate.")
+		return nil, fmt.Errorf("cluster %s: %w", name, err)
+	}
+
 	clientset, err := kubernetes.NewForConfig(restConfig)
 	if err != nil {
 		log.WithError(err).Debugf("Could not create Kubernetes clientset.")
 		return nil, err
 	}
 
+	streamConfig := rest.CopyConfig(restConfig)
+	streamConfig.Timeout = 0
+
+	streamClientset, err := kubernetes.NewForConfig(streamConfig)
+	if err != nil {
+		log.WithError(err).Debugf("Could not create Kubernetes stream clientset.")
+		return nil, err
+	}
+
 	applicationClientset, err := applicationClientsetVersioned.NewForConfig(restConfig)
 	if err != nil {
 		log.WithError(err).Debugf("Could not create application clientset.")
@@ -599,19 +1930,41 @@ func NewCluster(name string, restConfig *rest.Config) (*Cluster, error) {
 		return nil, err
 	}
 
+	savedQueryClientset, err := savedQueryClientsetVersioned.NewForConfig(restConfig)
+	if err != nil {
+		log.WithError(err).Debugf("Could not create saved query clientset.")
+		return nil, err
+	}
+
+	favoriteClientset, err := favoriteClientsetVersioned.NewForConfig(restConfig)
+	if err != nil {
+		log.WithError(err).Debugf("Could not create favorite clientset.")
+		return nil, err
+	}
+
+	displayName := name
 	name = strings.Trim(slugifyRe.ReplaceAllString(strings.ToLower(name), "-"), "-")
 
 	c := &Cluster{
 		config:               restConfig,
+		tlsConfig:            tlsConfig,
 		clientset:            clientset,
+		streamConfig:         streamConfig,
+		streamClientset:      streamClientset,
 		applicationClientset: applicationClientset,
 		teamClientset:        teamClientset,
 		dashboardClientset:   dashboardClientset,
 		userClientset:        userClientset,
+		savedQueryClientset:  savedQueryClientset,
+		favoriteClientset:    favoriteClientset,
 		name:                 name,
+		displayName:          displayName,
 	}
 
-	go c.loadCRDs()
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+
+	go c.loadCRDs(ctx)
 
 	return c, nil
 }
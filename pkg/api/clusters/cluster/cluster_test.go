@@ -0,0 +1,252 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+)
+
+func TestCompileRegexes(t *testing.T) {
+	t.Run("valid patterns", func(t *testing.T) {
+		regs, err := compileRegexes([]string{"error", "", "warn.*"})
+		require.NoError(t, err)
+		require.Len(t, regs, 2)
+
+		require.True(t, matchesAny(regs, "level=error"))
+		require.True(t, matchesAny(regs, "level=warning"))
+		require.False(t, matchesAny(regs, "level=info"))
+	})
+
+	t.Run("invalid patterns", func(t *testing.T) {
+		_, err := compileRegexes([]string{"error", "(", "["})
+		require.ErrorIs(t, err, ErrInvalidRegex)
+		require.Contains(t, err.Error(), `"("`)
+		require.Contains(t, err.Error(), `"["`)
+	})
+
+	t.Run("no patterns", func(t *testing.T) {
+		regs, err := compileRegexes(nil)
+		require.NoError(t, err)
+		require.True(t, matchesAny(regs, "any line"))
+	})
+}
+
+func TestGetLineTerminator(t *testing.T) {
+	for _, tc := range []struct {
+		name                   string
+		expectedLineTerminator string
+		expectError            bool
+	}{
+		{name: "", expectedLineTerminator: "\n\r"},
+		{name: "lf", expectedLineTerminator: "\n"},
+		{name: "crlf", expectedLineTerminator: "\r\n"},
+		{name: "lfcr", expectedLineTerminator: "\n\r"},
+		{name: "cr", expectError: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			actualLineTerminator, err := GetLineTerminator(tc.name)
+			if tc.expectError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.expectedLineTerminator, actualLineTerminator)
+			}
+		})
+	}
+}
+
+func TestSplitLine(t *testing.T) {
+	t.Run("short line", func(t *testing.T) {
+		require.Equal(t, []string{"short line"}, splitLine("short line", 100))
+	})
+
+	t.Run("no limit", func(t *testing.T) {
+		require.Equal(t, []string{"short line"}, splitLine("short line", 0))
+	})
+
+	t.Run("very long line", func(t *testing.T) {
+		line := strings.Repeat("a", 1024*1024)
+		messages := splitLine(line, 64*1024)
+		require.Greater(t, len(messages), 1)
+
+		var joined string
+		for i, message := range messages {
+			require.LessOrEqual(t, len(message), 64*1024)
+
+			if i < len(messages)-1 {
+				require.True(t, strings.HasSuffix(message, logContinuationMarker))
+				joined = joined + strings.TrimSuffix(message, logContinuationMarker)
+			} else {
+				joined = joined + message
+			}
+		}
+
+		require.Equal(t, line, joined)
+	})
+
+	t.Run("multi-byte characters", func(t *testing.T) {
+		line := strings.Repeat("ä", 100)
+		for _, message := range splitLine(line, 21) {
+			require.True(t, utf8.ValidString(message))
+		}
+	})
+}
+
+func TestGetCRDsBackoff(t *testing.T) {
+	for _, tc := range []struct {
+		attempt int
+		min     time.Duration
+		max     time.Duration
+	}{
+		{attempt: 1, min: 15 * time.Second, max: 30 * time.Second},
+		{attempt: 2, min: 30 * time.Second, max: 60 * time.Second},
+		{attempt: 3, min: 60 * time.Second, max: 120 * time.Second},
+		{attempt: 10, min: 5 * time.Minute, max: 10 * time.Minute},
+	} {
+		t.Run(fmt.Sprintf("attempt %d", tc.attempt), func(t *testing.T) {
+			backoff := getCRDsBackoff(tc.attempt, 30*time.Second, 10*time.Minute)
+			require.GreaterOrEqual(t, backoff, tc.min)
+			require.LessOrEqual(t, backoff, tc.max)
+		})
+	}
+}
+
+func TestCRDsLimiter(t *testing.T) {
+	t.Run("no limit", func(t *testing.T) {
+		limiter := &crdsLimiter{}
+		for i := 0; i < 10; i++ {
+			_, err := limiter.acquire(context.Background(), 0)
+			require.NoError(t, err)
+		}
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		limiter := &crdsLimiter{}
+
+		release, err := limiter.acquire(context.Background(), 1)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err = limiter.acquire(ctx, 1)
+		require.Error(t, err)
+
+		release()
+		_, err = limiter.acquire(context.Background(), 1)
+		require.NoError(t, err)
+	})
+}
+
+func TestCRDsLoaded(t *testing.T) {
+	c := &Cluster{}
+	require.False(t, c.CRDsLoaded())
+	require.False(t, c.GetStatus().Loaded)
+
+	c.cache.crdsLastFetch = time.Now()
+	require.True(t, c.CRDsLoaded())
+	require.True(t, c.GetStatus().Loaded)
+}
+
+func TestFilterCRDs(t *testing.T) {
+	crds := []CRD{
+		{Path: "apis/kobs.io/v1beta1", Resource: "applications", Title: "Application"},
+		{Path: "apis/kobs.io/v1beta1", Resource: "dashboards", Title: "Dashboard"},
+		{Path: "apis/networking.istio.io/v1beta1", Resource: "virtualservices", Title: "VirtualService"},
+	}
+
+	for _, tt := range []struct {
+		name      string
+		filter    string
+		offset    int
+		limit     int
+		expected  []string
+		total     int
+		truncated bool
+	}{
+		{name: "no filter", expected: []string{"applications", "dashboards", "virtualservices"}, total: 3},
+		{name: "filter group", filter: "KOBS.io", expected: []string{"applications", "dashboards"}, total: 2},
+		{name: "filter kind", filter: "virtualservice", expected: []string{"virtualservices"}, total: 1},
+		{name: "limit", limit: 2, expected: []string{"applications", "dashboards"}, total: 3, truncated: true},
+		{name: "offset and limit", offset: 2, limit: 2, expected: []string{"virtualservices"}, total: 3},
+		{name: "offset out of range", offset: 5, expected: []string{}, total: 3},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			crdList := FilterCRDs(crds, tt.filter, tt.offset, tt.limit)
+
+			resources := []string{}
+			for _, crd := range crdList.CRDs {
+				resources = append(resources, crd.Resource)
+			}
+
+			require.Equal(t, tt.expected, resources)
+			require.Equal(t, tt.total, crdList.Total)
+			require.Equal(t, tt.truncated, crdList.Truncated)
+		})
+	}
+}
+
+func TestGetResourcesWithMaxSize(t *testing.T) {
+	list := `{"kind":"PodList","apiVersion":"v1","metadata":{},"items":[]}`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Add("Warning", `299 - "test warning"`)
+		w.Write([]byte(list))
+	}))
+	defer server.Close()
+
+	c, err := NewCluster("dev-de1", &rest.Config{Host: server.URL}, nil)
+	require.NoError(t, err)
+	defer c.Close()
+
+	t.Run("within the maximum size", func(t *testing.T) {
+		res, warnings, err := c.GetResourcesWithMaxSize(context.Background(), "default", "", "/api/v1", "pods", url.Values{}, int64(len(list)))
+		require.NoError(t, err)
+		require.Equal(t, list, string(res))
+		require.Equal(t, []string{"test warning"}, warnings)
+	})
+
+	t.Run("exceeds the maximum size", func(t *testing.T) {
+		_, _, err := c.GetResourcesWithMaxSize(context.Background(), "default", "", "/api/v1", "pods", url.Values{}, int64(len(list)-1))
+		require.ErrorIs(t, err, ErrResponseTooLarge)
+	})
+
+	t.Run("no maximum size", func(t *testing.T) {
+		res, warnings, err := c.GetResourcesWithMaxSize(context.Background(), "default", "", "/api/v1", "pods", url.Values{}, 0)
+		require.NoError(t, err)
+		require.Equal(t, list, string(res))
+		require.Equal(t, []string{"test warning"}, warnings)
+	})
+}
+
+func TestGetResourcesMetadata(t *testing.T) {
+	accept := make(chan string, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/pods" {
+			accept <- r.Header.Get("Accept")
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"kind":"PartialObjectMetadataList","apiVersion":"meta.k8s.io/v1","metadata":{},"items":[]}`))
+	}))
+	defer server.Close()
+
+	c, err := NewCluster("dev-de1", &rest.Config{Host: server.URL}, nil)
+	require.NoError(t, err)
+	defer c.Close()
+
+	_, _, err = c.GetResourcesMetadata(context.Background(), "", "/api/v1", "pods", 0)
+	require.NoError(t, err)
+	require.Contains(t, <-accept, "as=PartialObjectMetadataList")
+}
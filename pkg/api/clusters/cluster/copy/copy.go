@@ -1,11 +1,19 @@
 package copy
 
 import (
+	"archive/tar"
+	"bufio"
+	"errors"
+	"fmt"
 	"io"
+	"mime"
 	"mime/multipart"
 	"net/http"
 	"net/url"
 	"os"
+	"path"
+	"strconv"
+	"strings"
 
 	"github.com/sirupsen/logrus"
 	"k8s.io/client-go/rest"
@@ -14,6 +22,9 @@ import (
 
 var (
 	log = logrus.WithFields(logrus.Fields{"package": "clusters"})
+
+	// ErrNotRegularFile is returned by RawFileFromPod, when the given path is not a regular file, e.g. a directory.
+	ErrNotRegularFile = errors.New("not a regular file")
 )
 
 // FileFromPod let a user download a file from a container.
@@ -45,6 +56,93 @@ func FileFromPod(w http.ResponseWriter, config *rest.Config, reqURL *url.URL) er
 	return nil
 }
 
+// RawFileFromPod let a user download a single file from a container. In contrast to FileFromPod the tar archive created
+// in the container is extracted on the server side, so that the raw bytes of the file are returned together with the
+// detected content type. The file is streamed to the client, so that also large files can be downloaded.
+func RawFileFromPod(w http.ResponseWriter, config *rest.Config, reqURL *url.URL) error {
+	reader, outStream := io.Pipe()
+	defer reader.Close()
+
+	exec, err := remotecommand.NewSPDYExecutor(config, "POST", reqURL)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		err := exec.Stream(remotecommand.StreamOptions{
+			Stdout: outStream,
+			Tty:    false,
+		})
+		if err != nil {
+			log.WithError(err).Errorf("could not copy file from pod")
+		}
+		outStream.CloseWithError(err)
+	}()
+
+	return writeRawFile(w, reader)
+}
+
+// writeRawFile reads the first entry of the given tar archive and writes the content of the file to the response
+// writer. The content type is detected via the file extension and if this isn't possible via the first bytes of the
+// file. If the first entry isn't a regular file the ErrNotRegularFile error is returned.
+//
+// Since the content of the file is controlled by the container, it must not be interpreted by the browser as part of
+// kobs. Therefore content sniffing is disabled, scripts are blocked via a sandbox and only images and plain text files
+// are displayed inline, all other files are downloaded.
+func writeRawFile(w http.ResponseWriter, r io.Reader) error {
+	tarReader := tar.NewReader(r)
+
+	header, err := tarReader.Next()
+	if err != nil {
+		if err == io.EOF {
+			return fmt.Errorf("file not found")
+		}
+		return err
+	}
+
+	if header.Typeflag != tar.TypeReg {
+		return ErrNotRegularFile
+	}
+
+	fileReader := bufio.NewReaderSize(tarReader, 512)
+
+	contentType := mime.TypeByExtension(path.Ext(header.Name))
+	if contentType == "" {
+		sniff, err := fileReader.Peek(512)
+		if err != nil && err != io.EOF {
+			return err
+		}
+		contentType = http.DetectContentType(sniff)
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(header.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType(getDisposition(contentType), map[string]string{"filename": path.Base(header.Name)}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Security-Policy", "sandbox")
+
+	if _, err := io.Copy(w, fileReader); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// getDisposition returns "inline" for images and plain text files, so that they can be displayed in the browser. All
+// other files, including SVG images which can contain scripts, are returned as "attachment".
+func getDisposition(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "attachment"
+	}
+
+	if mediaType == "text/plain" || (strings.HasPrefix(mediaType, "image/") && mediaType != "image/svg+xml") {
+		return "inline"
+	}
+
+	return "attachment"
+}
+
 // FileToPod let a user upload a file to a container.
 func FileToPod(config *rest.Config, reqURL *url.URL, srcFile multipart.File, destPath string) error {
 	reader, writer := io.Pipe()
@@ -0,0 +1,70 @@
+package copy
+
+import (
+	"archive/tar"
+	"bytes"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createArchive(t *testing.T, header *tar.Header, content []byte) *bytes.Buffer {
+	var buf bytes.Buffer
+	tarWriter := tar.NewWriter(&buf)
+	require.NoError(t, tarWriter.WriteHeader(header))
+	if len(content) > 0 {
+		_, err := tarWriter.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tarWriter.Close())
+	return &buf
+}
+
+func TestWriteRawFile(t *testing.T) {
+	t.Run("file with extension", func(t *testing.T) {
+		content := []byte(`{"key": "value"}`)
+		archive := createArchive(t, &tar.Header{Name: "etc/config/config.json", Typeflag: tar.TypeReg, Size: int64(len(content)), Mode: 0644}, content)
+
+		w := httptest.NewRecorder()
+		require.NoError(t, writeRawFile(w, archive))
+		require.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		require.Equal(t, "16", w.Header().Get("Content-Length"))
+		require.Equal(t, "attachment; filename=config.json", w.Header().Get("Content-Disposition"))
+		require.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+		require.Equal(t, "sandbox", w.Header().Get("Content-Security-Policy"))
+		require.Equal(t, content, w.Body.Bytes())
+	})
+
+	t.Run("file without extension", func(t *testing.T) {
+		content := []byte("hello world")
+		archive := createArchive(t, &tar.Header{Name: "tmp/hello", Typeflag: tar.TypeReg, Size: int64(len(content)), Mode: 0644}, content)
+
+		w := httptest.NewRecorder()
+		require.NoError(t, writeRawFile(w, archive))
+		require.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+		require.Equal(t, "inline; filename=hello", w.Header().Get("Content-Disposition"))
+		require.Equal(t, content, w.Body.Bytes())
+	})
+
+	t.Run("html file", func(t *testing.T) {
+		content := []byte("<script>alert(1)</script>")
+		archive := createArchive(t, &tar.Header{Name: "tmp/index.html", Typeflag: tar.TypeReg, Size: int64(len(content)), Mode: 0644}, content)
+
+		w := httptest.NewRecorder()
+		require.NoError(t, writeRawFile(w, archive))
+		require.Equal(t, "attachment; filename=index.html", w.Header().Get("Content-Disposition"))
+	})
+
+	t.Run("directory", func(t *testing.T) {
+		archive := createArchive(t, &tar.Header{Name: "tmp/", Typeflag: tar.TypeDir, Mode: 0755}, nil)
+
+		w := httptest.NewRecorder()
+		require.Equal(t, ErrNotRegularFile, writeRawFile(w, archive))
+	})
+
+	t.Run("empty archive", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		require.Error(t, writeRawFile(w, &bytes.Buffer{}))
+	})
+}
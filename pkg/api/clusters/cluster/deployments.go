@@ -0,0 +1,265 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/kobsio/kobs/pkg/api/clusters/cluster/diff"
+
+	"github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// revisionAnnotation is the annotation, which is set by the Deployment controller on each ReplicaSet to track the
+	// revision of the Deployment.
+	revisionAnnotation = "deployment.kubernetes.io/revision"
+	// changeCauseAnnotation is the annotation, which contains the reason for a change of a Deployment.
+	changeCauseAnnotation = "kubernetes.io/change-cause"
+)
+
+// DeploymentRevision is a single revision of a Deployment, like it is shown by "kubectl rollout history". Each revision
+// is represented by a ReplicaSet, which is owned by the Deployment. The containers field contains a summary of the Pod
+// template of the revision.
+type DeploymentRevision struct {
+	Revision          int64               `json:"revision"`
+	ReplicaSet        string              `json:"replicaSet"`
+	CreationTimestamp int64               `json:"creationTimestamp"`
+	ChangeCause       string              `json:"changeCause,omitempty"`
+	Replicas          int32               `json:"replicas"`
+	Containers        []RevisionContainer `json:"containers"`
+	Labels            map[string]string   `json:"labels,omitempty"`
+}
+
+// RevisionContainer is the summary of a container from the Pod template of a revision.
+type RevisionContainer struct {
+	Name  string `json:"name"`
+	Image string `json:"image"`
+}
+
+// GetDeploymentRevisions returns the revisions of the given Deployment, ordered by the revision number, starting with
+// the latest revision. If the limit is greater than 0 only the latest N revisions are returned.
+func (c *Cluster) GetDeploymentRevisions(ctx context.Context, namespace, name string, limit int) ([]DeploymentRevision, error) {
+	replicaSets, err := c.getDeploymentReplicaSets(ctx, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var revisions []DeploymentRevision
+	for _, replicaSet := range replicaSets {
+		revision, err := getRevision(replicaSet)
+		if err != nil {
+			continue
+		}
+
+		var containers []RevisionContainer
+		for _, container := range replicaSet.Spec.Template.Spec.Containers {
+			containers = append(containers, RevisionContainer{Name: container.Name, Image: container.Image})
+		}
+
+		var replicas int32
+		if replicaSet.Spec.Replicas != nil {
+			replicas = *replicaSet.Spec.Replicas
+		}
+
+		labels := make(map[string]string)
+		for key, value := range replicaSet.Spec.Template.Labels {
+			if key != appsv1.DefaultDeploymentUniqueLabelKey {
+				labels[key] = value
+			}
+		}
+
+		revisions = append(revisions, DeploymentRevision{
+			Revision:          revision,
+			ReplicaSet:        replicaSet.Name,
+			CreationTimestamp: replicaSet.CreationTimestamp.Unix(),
+			ChangeCause:       replicaSet.Annotations[changeCauseAnnotation],
+			Replicas:          replicas,
+			Containers:        containers,
+			Labels:            labels,
+		})
+	}
+
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Revision > revisions[j].Revision
+	})
+
+	if limit > 0 && len(revisions) > limit {
+		revisions = revisions[:limit]
+	}
+
+	return revisions, nil
+}
+
+// getDeploymentReplicaSets returns all ReplicaSets, which are controlled by the given Deployment. The ReplicaSets are
+// selected via the label selector of the Deployment and then filtered by the owner reference, so that we do not return
+// ReplicaSets of other Deployments with overlapping selectors.
+func (c *Cluster) getDeploymentReplicaSets(ctx context.Context, namespace, name string) ([]appsv1.ReplicaSet, error) {
+	deployment, err := c.clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "name": name}).Errorf("getDeploymentReplicaSets")
+		return nil, err
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+
+	replicaSetList, err := c.clientset.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "name": name}).Errorf("getDeploymentReplicaSets")
+		return nil, err
+	}
+
+	var replicaSets []appsv1.ReplicaSet
+	for _, replicaSet := range replicaSetList.Items {
+		if owner := metav1.GetControllerOf(&replicaSet); owner != nil && owner.UID == deployment.UID {
+			replicaSets = append(replicaSets, replicaSet)
+		}
+	}
+
+	return replicaSets, nil
+}
+
+// getRevision returns the revision of a ReplicaSet from the revision annotation.
+func getRevision(replicaSet appsv1.ReplicaSet) (int64, error) {
+	return strconv.ParseInt(replicaSet.Annotations[revisionAnnotation], 10, 64)
+}
+
+// DiffDeploymentRevisions returns the changes between the Pod templates of two revisions of the given Deployment. This
+// can be used to answer the question what was changed in a rollout, e.g. which images or environment variables were
+// changed. The returned changes use the "from" revision as the old and the "to" revision as the new value.
+func (c *Cluster) DiffDeploymentRevisions(ctx context.Context, namespace, name string, from, to int64) ([]diff.Change, error) {
+	replicaSets, err := c.getDeploymentReplicaSets(ctx, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var fromTemplate, toTemplate *corev1.PodTemplateSpec
+	for index := range replicaSets {
+		revision, err := getRevision(replicaSets[index])
+		if err != nil {
+			continue
+		}
+
+		if revision == from {
+			fromTemplate = &replicaSets[index].Spec.Template
+		}
+		if revision == to {
+			toTemplate = &replicaSets[index].Spec.Template
+		}
+	}
+
+	if fromTemplate == nil {
+		return nil, fmt.Errorf("revision %d not found for deployment %s", from, name)
+	}
+	if toTemplate == nil {
+		return nil, fmt.Errorf("revision %d not found for deployment %s", to, name)
+	}
+
+	return diffPodTemplates(fromTemplate, toTemplate)
+}
+
+// diffPodTemplates returns the changes between the two given Pod templates. The Pod template hash label is removed
+// before the templates are compared, because it is different for each revision and only set by the Deployment
+// controller.
+func diffPodTemplates(from, to *corev1.PodTemplateSpec) ([]diff.Change, error) {
+	var manifests []map[string]interface{}
+	for _, template := range []*corev1.PodTemplateSpec{from, to} {
+		template = template.DeepCopy()
+		delete(template.Labels, appsv1.DefaultDeploymentUniqueLabelKey)
+
+		manifest, err := runtime.DefaultUnstructuredConverter.ToUnstructured(template)
+		if err != nil {
+			return nil, err
+		}
+
+		manifests = append(manifests, manifest)
+	}
+
+	return diff.Compare(manifests[0], manifests[1]), nil
+}
+
+// RollbackDeployment rolls back the given Deployment to a prior revision, like it is done by "kubectl rollout undo". If
+// the revision is 0, the Deployment is rolled back to the previous revision. The Pod template of the ReplicaSet for the
+// target revision is used to replace the Pod template of the Deployment. The change cause annotation of the
+// Deployment is set, so that the rollback is visible in the rollout history.
+func (c *Cluster) RollbackDeployment(ctx context.Context, namespace, name string, revision int64) error {
+	deployment, err := c.clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "name": name}).Errorf("RollbackDeployment")
+		return err
+	}
+
+	if deployment.Spec.Paused {
+		return fmt.Errorf("deployment %s is paused", name)
+	}
+
+	replicaSets, err := c.getDeploymentReplicaSets(ctx, namespace, name)
+	if err != nil {
+		return err
+	}
+
+	currentRevision, _ := strconv.ParseInt(deployment.Annotations[revisionAnnotation], 10, 64)
+
+	var target *appsv1.ReplicaSet
+	var targetRevision int64
+
+	for index := range replicaSets {
+		replicaSetRevision, err := getRevision(replicaSets[index])
+		if err != nil {
+			continue
+		}
+
+		if revision == 0 {
+			// When no revision is provided, we are looking for the latest revision before the current one.
+			if replicaSetRevision < currentRevision && replicaSetRevision > targetRevision {
+				target = &replicaSets[index]
+				targetRevision = replicaSetRevision
+			}
+		} else if replicaSetRevision == revision {
+			target = &replicaSets[index]
+			targetRevision = replicaSetRevision
+		}
+	}
+
+	if target == nil {
+		if revision == 0 {
+			return fmt.Errorf("no previous revision found for deployment %s", name)
+		}
+		return fmt.Errorf("revision %d not found for deployment %s", revision, name)
+	}
+
+	if targetRevision == currentRevision {
+		return fmt.Errorf("revision %d is already the current revision of deployment %s", targetRevision, name)
+	}
+
+	template := target.Spec.Template.DeepCopy()
+	delete(template.Labels, appsv1.DefaultDeploymentUniqueLabelKey)
+
+	// We are using a JSON patch to replace the complete Pod template, so that fields which are not present in the
+	// template of the target revision are removed. The change cause is escaped as it is required for JSON patches.
+	patch, err := json.Marshal([]map[string]interface{}{
+		{"op": "replace", "path": "/spec/template", "value": template},
+		{"op": "add", "path": "/metadata/annotations/kubernetes.io~1change-cause", "value": fmt.Sprintf("rollback to revision %d", targetRevision)},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = c.clientset.AppsV1().Deployments(namespace).Patch(ctx, name, types.JSONPatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "name": name, "revision": targetRevision}).Errorf("RollbackDeployment")
+		return err
+	}
+
+	return nil
+}
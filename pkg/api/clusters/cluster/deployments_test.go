@@ -0,0 +1,39 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/kobsio/kobs/pkg/api/clusters/cluster/diff"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDiffPodTemplates(t *testing.T) {
+	from := &corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "nginx", "pod-template-hash": "abc"}},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name:  "nginx",
+			Image: "nginx:1.20",
+			Env:   []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "info"}},
+		}}},
+	}
+
+	to := &corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "nginx", "pod-template-hash": "def"}},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name:  "nginx",
+			Image: "nginx:1.21",
+			Env:   []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}},
+		}}},
+	}
+
+	changes, err := diffPodTemplates(from, to)
+	require.NoError(t, err)
+	require.Equal(t, []diff.Change{
+		{Path: "spec.containers[0].env[0].value", Type: diff.CHANGED, OldValue: "info", NewValue: "debug"},
+		{Path: "spec.containers[0].image", Type: diff.CHANGED, OldValue: "nginx:1.20", NewValue: "nginx:1.21"},
+	}, changes)
+	require.Equal(t, "abc", from.Labels["pod-template-hash"])
+}
@@ -0,0 +1,132 @@
+// Package diff implements a simple structured diff for Kubernetes manifests. It is used to show a user which fields are
+// changed, before a manifest is applied.
+package diff
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Type is the type of a change. A field can be added, removed or changed.
+type Type string
+
+const (
+	// ADDED is the type of a change, when a field is only present in the submitted manifest.
+	ADDED Type = "added"
+	// REMOVED is the type of a change, when a field is only present in the live object.
+	REMOVED Type = "removed"
+	// CHANGED is the type of a change, when a field is present in both manifests, but has a different value.
+	CHANGED Type = "changed"
+)
+
+// Change is a single change between the live object and the submitted manifest. The path is the path of the field in
+// the manifest (e.g. "spec.template.spec.containers[0].image"). The old value is the value of the live object and the
+// new value is the value of the submitted manifest.
+type Change struct {
+	Path     string      `json:"path"`
+	Type     Type        `json:"type"`
+	OldValue interface{} `json:"oldValue,omitempty"`
+	NewValue interface{} `json:"newValue,omitempty"`
+}
+
+// StripServerFields removes all fields from the given manifest, which are managed by the Kubernetes API server. These
+// fields should not be shown in a diff, because they are never set by a user.
+func StripServerFields(manifest map[string]interface{}) {
+	if metadata, ok := manifest["metadata"].(map[string]interface{}); ok {
+		for _, field := range []string{"managedFields", "resourceVersion", "uid", "generation", "creationTimestamp", "selfLink"} {
+			delete(metadata, field)
+		}
+
+		if annotations, ok := metadata["annotations"].(map[string]interface{}); ok {
+			delete(annotations, "kubectl.kubernetes.io/last-applied-configuration")
+			if len(annotations) == 0 {
+				delete(metadata, "annotations")
+			}
+		}
+	}
+
+	delete(manifest, "status")
+}
+
+// Compare returns all changes between the live and the submitted manifest. Before the manifests are compared all server
+// managed fields are removed from both manifests. The returned changes are sorted by their path.
+func Compare(live, submitted map[string]interface{}) []Change {
+	StripServerFields(live)
+	StripServerFields(submitted)
+
+	changes := compare("", live, submitted)
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+
+	return changes
+}
+
+// compare compares the old and new value for the given path. For maps and slices we are comparing each key / item, all
+// other values are compared directly.
+func compare(path string, oldValue, newValue interface{}) []Change {
+	var changes []Change
+
+	switch o := oldValue.(type) {
+	case map[string]interface{}:
+		n, ok := newValue.(map[string]interface{})
+		if !ok {
+			return []Change{{Path: path, Type: CHANGED, OldValue: oldValue, NewValue: newValue}}
+		}
+
+		for key, value := range o {
+			if _, ok := n[key]; !ok {
+				changes = append(changes, Change{Path: joinPath(path, key), Type: REMOVED, OldValue: value})
+				continue
+			}
+
+			changes = append(changes, compare(joinPath(path, key), value, n[key])...)
+		}
+
+		for key, value := range n {
+			if _, ok := o[key]; !ok {
+				changes = append(changes, Change{Path: joinPath(path, key), Type: ADDED, NewValue: value})
+			}
+		}
+
+		return changes
+
+	case []interface{}:
+		n, ok := newValue.([]interface{})
+		if !ok {
+			return []Change{{Path: path, Type: CHANGED, OldValue: oldValue, NewValue: newValue}}
+		}
+
+		for index := 0; index < len(o) || index < len(n); index++ {
+			itemPath := fmt.Sprintf("%s[%d]", path, index)
+
+			if index >= len(n) {
+				changes = append(changes, Change{Path: itemPath, Type: REMOVED, OldValue: o[index]})
+			} else if index >= len(o) {
+				changes = append(changes, Change{Path: itemPath, Type: ADDED, NewValue: n[index]})
+			} else {
+				changes = append(changes, compare(itemPath, o[index], n[index])...)
+			}
+		}
+
+		return changes
+
+	default:
+		if !reflect.DeepEqual(oldValue, newValue) {
+			return []Change{{Path: path, Type: CHANGED, OldValue: oldValue, NewValue: newValue}}
+		}
+
+		return nil
+	}
+}
+
+// joinPath adds the given key to the path.
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
@@ -0,0 +1,61 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompare(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		live      map[string]interface{}
+		submitted map[string]interface{}
+		expect    []Change
+	}{
+		{
+			name:      "no changes",
+			live:      map[string]interface{}{"spec": map[string]interface{}{"replicas": float64(1)}},
+			submitted: map[string]interface{}{"spec": map[string]interface{}{"replicas": float64(1)}},
+			expect:    nil,
+		},
+		{
+			name:      "changed value",
+			live:      map[string]interface{}{"spec": map[string]interface{}{"replicas": float64(1)}},
+			submitted: map[string]interface{}{"spec": map[string]interface{}{"replicas": float64(2)}},
+			expect:    []Change{{Path: "spec.replicas", Type: CHANGED, OldValue: float64(1), NewValue: float64(2)}},
+		},
+		{
+			name:      "added and removed keys",
+			live:      map[string]interface{}{"metadata": map[string]interface{}{"labels": map[string]interface{}{"app": "foo"}}},
+			submitted: map[string]interface{}{"metadata": map[string]interface{}{"labels": map[string]interface{}{"team": "bar"}}},
+			expect: []Change{
+				{Path: "metadata.labels.app", Type: REMOVED, OldValue: "foo"},
+				{Path: "metadata.labels.team", Type: ADDED, NewValue: "bar"},
+			},
+		},
+		{
+			name:      "slice items",
+			live:      map[string]interface{}{"args": []interface{}{"a", "b"}},
+			submitted: map[string]interface{}{"args": []interface{}{"a", "c", "d"}},
+			expect: []Change{
+				{Path: "args[1]", Type: CHANGED, OldValue: "b", NewValue: "c"},
+				{Path: "args[2]", Type: ADDED, NewValue: "d"},
+			},
+		},
+		{
+			name: "server fields are ignored",
+			live: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "foo", "resourceVersion": "123", "managedFields": []interface{}{"x"}},
+				"status":   map[string]interface{}{"ready": true},
+			},
+			submitted: map[string]interface{}{"metadata": map[string]interface{}{"name": "foo"}},
+			expect:    nil,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := Compare(tc.live, tc.submitted)
+			require.Equal(t, tc.expect, actual)
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package cluster
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	favorite "github.com/kobsio/kobs/pkg/api/apis/favorite/v1beta1"
+
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+// getFavoriteName returns the name of the Favorite CR for the given user. Since the id of a user can contain characters,
+// which are not allowed in the name of a CR (e.g. an email address), we are using a hash of the id.
+func getFavoriteName(userID string) string {
+	hash := sha256.Sum256([]byte(userID))
+	return "user-" + hex.EncodeToString(hash[:])[:20]
+}
+
+// GetFavorites returns the pinned resources of the given user. The pinned resources are saved in a Favorite CR in the
+// given namespace. If the user doesn't have a Favorite CR yet, an empty list is returned.
+func (c *Cluster) GetFavorites(ctx context.Context, namespace, userID string) ([]favorite.Reference, error) {
+	favoriteCR, err := c.favoriteClientset.KobsV1beta1().Favorites(namespace).Get(ctx, getFavoriteName(userID), metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return []favorite.Reference{}, nil
+		}
+
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "user": userID}).Errorf("GetFavorites")
+		return nil, err
+	}
+
+	if favoriteCR.Spec.Resources == nil {
+		return []favorite.Reference{}, nil
+	}
+
+	return favoriteCR.Spec.Resources, nil
+}
+
+// AddFavorite adds the given reference to the pinned resources of the given user. If the user doesn't have a Favorite CR
+// yet, it is created. If the reference is already pinned, the list of pinned resources isn't modified.
+func (c *Cluster) AddFavorite(ctx context.Context, namespace, userID string, reference favorite.Reference) ([]favorite.Reference, error) {
+	return c.updateFavorites(ctx, namespace, userID, func(references []favorite.Reference) []favorite.Reference {
+		for _, r := range references {
+			if isSameReference(r, reference) {
+				return references
+			}
+		}
+
+		return append(references, reference)
+	})
+}
+
+// RemoveFavorite removes the given reference from the pinned resources of the given user.
+func (c *Cluster) RemoveFavorite(ctx context.Context, namespace, userID string, reference favorite.Reference) ([]favorite.Reference, error) {
+	return c.updateFavorites(ctx, namespace, userID, func(references []favorite.Reference) []favorite.Reference {
+		var filteredReferences []favorite.Reference
+		for _, r := range references {
+			if !isSameReference(r, reference) {
+				filteredReferences = append(filteredReferences, r)
+			}
+		}
+
+		return filteredReferences
+	})
+}
+
+// updateFavorites applies the given update function to the pinned resources of the given user and saves the result. If
+// the Favorite CR was modified in the meantime, the update is retried with the latest version of the CR.
+func (c *Cluster) updateFavorites(ctx context.Context, namespace, userID string, update func([]favorite.Reference) []favorite.Reference) ([]favorite.Reference, error) {
+	name := getFavoriteName(userID)
+	var references []favorite.Reference
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		favoriteCR, err := c.favoriteClientset.KobsV1beta1().Favorites(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				return err
+			}
+
+			references = update(nil)
+			_, err := c.favoriteClientset.KobsV1beta1().Favorites(namespace).Create(ctx, &favorite.Favorite{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      name,
+				},
+				Spec: favorite.FavoriteSpec{
+					User:      userID,
+					Resources: references,
+				},
+			}, metav1.CreateOptions{})
+			if apierrors.IsAlreadyExists(err) {
+				return apierrors.NewConflict(favorite.Resource("favorites"), name, err)
+			}
+
+			return err
+		}
+
+		references = update(favoriteCR.Spec.Resources)
+		favoriteCR.Spec.Resources = references
+
+		_, err = c.favoriteClientset.KobsV1beta1().Favorites(namespace).Update(ctx, favoriteCR, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "user": userID}).Errorf("updateFavorites")
+		return nil, err
+	}
+
+	if references == nil {
+		return []favorite.Reference{}, nil
+	}
+
+	return references, nil
+}
+
+// isSameReference returns true, when both references are pointing to the same resource. The title of a reference is
+// ignored.
+func isSameReference(a, b favorite.Reference) bool {
+	return a.Cluster == b.Cluster && a.Namespace == b.Namespace && a.Name == b.Name && a.Path == b.Path && a.Resource == b.Resource
+}
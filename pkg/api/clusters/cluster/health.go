@@ -0,0 +1,139 @@
+package cluster
+
+import (
+	"context"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Health is the health of the control plane and the nodes of a cluster. The source field contains the source for the
+// health of the control plane components. It is "componentstatuses" when the deprecated ComponentStatus API could be
+// used, "pods" when the health was determined via the static Pods of the control plane in the kube-system namespace
+// and "unknown" when the health of the components could not be determined (e.g. for managed control planes).
+type Health struct {
+	Source     string            `json:"source"`
+	Components []ComponentHealth `json:"components"`
+	Nodes      NodesHealth       `json:"nodes"`
+}
+
+// ComponentHealth is the health of a single control plane component, like the scheduler or the controller manager.
+type ComponentHealth struct {
+	Name    string `json:"name"`
+	Healthy bool   `json:"healthy"`
+	Message string `json:"message,omitempty"`
+}
+
+// NodesHealth is a summary of the readiness of all nodes in a cluster.
+type NodesHealth struct {
+	Total    int      `json:"total"`
+	Ready    int      `json:"ready"`
+	NotReady []string `json:"notReady"`
+}
+
+// GetHealth returns the health of the control plane components and the readiness of all nodes. Since the
+// ComponentStatus API is deprecated and returns an empty or failing list in newer clusters, we fall back to the static
+// Pods of the control plane, which are labeled with "tier=control-plane".
+func (c *Cluster) GetHealth(ctx context.Context) (*Health, error) {
+	nodes, err := c.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name}).Errorf("GetHealth")
+		return nil, err
+	}
+
+	health := &Health{Source: "unknown", Components: []ComponentHealth{}, Nodes: getNodesHealth(nodes.Items)}
+
+	if components, err := c.getComponentStatuses(ctx); err == nil && len(components) > 0 {
+		health.Source = "componentstatuses"
+		health.Components = components
+	} else if components, err := c.getControlPlanePods(ctx); err == nil && len(components) > 0 {
+		health.Source = "pods"
+		health.Components = components
+	}
+
+	sort.Slice(health.Components, func(i, j int) bool {
+		return health.Components[i].Name < health.Components[j].Name
+	})
+
+	return health, nil
+}
+
+// getComponentStatuses returns the health of the control plane components via the ComponentStatus API.
+func (c *Cluster) getComponentStatuses(ctx context.Context) ([]ComponentHealth, error) {
+	componentStatuses, err := c.clientset.CoreV1().ComponentStatuses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name}).Debugf("Could not get component statuses")
+		return nil, err
+	}
+
+	var components []ComponentHealth
+	for _, componentStatus := range componentStatuses.Items {
+		component := ComponentHealth{Name: componentStatus.Name}
+
+		for _, condition := range componentStatus.Conditions {
+			if condition.Type == corev1.ComponentHealthy {
+				component.Healthy = condition.Status == corev1.ConditionTrue
+				component.Message = condition.Message
+				if condition.Error != "" {
+					component.Message = condition.Error
+				}
+			}
+		}
+
+		components = append(components, component)
+	}
+
+	return components, nil
+}
+
+// getControlPlanePods returns the health of the control plane components via the static Pods in the kube-system
+// namespace. A component is healthy, when the Pod is ready.
+func (c *Cluster) getControlPlanePods(ctx context.Context) ([]ComponentHealth, error) {
+	pods, err := c.clientset.CoreV1().Pods("kube-system").List(ctx, metav1.ListOptions{LabelSelector: "tier=control-plane"})
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name}).Debugf("Could not get control plane pods")
+		return nil, err
+	}
+
+	var components []ComponentHealth
+	for _, pod := range pods.Items {
+		component := ComponentHealth{Name: pod.Name, Message: string(pod.Status.Phase)}
+
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == corev1.PodReady {
+				component.Healthy = condition.Status == corev1.ConditionTrue
+				if condition.Message != "" {
+					component.Message = condition.Message
+				}
+			}
+		}
+
+		components = append(components, component)
+	}
+
+	return components, nil
+}
+
+// getNodesHealth returns the number of ready nodes and the names of all nodes, which are not ready.
+func getNodesHealth(nodes []corev1.Node) NodesHealth {
+	health := NodesHealth{Total: len(nodes), NotReady: []string{}}
+
+	for _, node := range nodes {
+		ready := false
+		for _, condition := range node.Status.Conditions {
+			if condition.Type == corev1.NodeReady {
+				ready = condition.Status == corev1.ConditionTrue
+			}
+		}
+
+		if ready {
+			health.Ready = health.Ready + 1
+		} else {
+			health.NotReady = append(health.NotReady, node.Name)
+		}
+	}
+
+	return health
+}
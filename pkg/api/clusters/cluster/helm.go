@@ -0,0 +1,213 @@
+package cluster
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// helmManifestSeparator is used to split the manifest of a Helm release into the single documents.
+var helmManifestSeparator = regexp.MustCompile(`(?m)^---\s*$`)
+
+// HelmRelease is a Helm release, with the resources which are managed by the release. The resources are taken from the
+// rendered manifest of the release. We do not return the manifest itself, because it can contain the data of Secrets.
+type HelmRelease struct {
+	Name         string                `json:"name"`
+	Namespace    string                `json:"namespace"`
+	Version      int64                 `json:"version"`
+	Status       string                `json:"status"`
+	Chart        string                `json:"chart"`
+	ChartVersion string                `json:"chartVersion"`
+	AppVersion   string                `json:"appVersion,omitempty"`
+	Resources    []HelmReleaseResource `json:"resources"`
+}
+
+// HelmReleaseResource is a single resource, which is managed by a Helm release.
+type HelmReleaseResource struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace,omitempty"`
+}
+
+// helmRelease is the format in which Helm stores a release. We only decode the fields which are needed.
+type helmRelease struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Version   int64  `json:"version"`
+	Manifest  string `json:"manifest"`
+	Info      struct {
+		Status string `json:"status"`
+	} `json:"info"`
+	Chart struct {
+		Metadata struct {
+			Name       string `json:"name"`
+			Version    string `json:"version"`
+			AppVersion string `json:"appVersion"`
+		} `json:"metadata"`
+	} `json:"chart"`
+}
+
+// GetHelmRelease returns the given Helm release with the resources which are managed by the release. The driver must be
+// "secret" or "configmap", like the storage driver used by Helm. If the version is 0 the latest version of the release
+// is returned.
+func (c *Cluster) GetHelmRelease(ctx context.Context, namespace, name, driver string, version int64) (*HelmRelease, error) {
+	listOptions := metav1.ListOptions{LabelSelector: fmt.Sprintf("owner=helm,name=%s", name)}
+	releases := make(map[int64]string)
+
+	switch driver {
+	case "", "secret":
+		secrets, err := c.clientset.CoreV1().Secrets(namespace).List(ctx, listOptions)
+		if err != nil {
+			log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "name": name}).Errorf("GetHelmRelease")
+			return nil, err
+		}
+
+		for _, secret := range secrets.Items {
+			if releaseVersion, err := strconv.ParseInt(secret.Labels["version"], 10, 64); err == nil {
+				releases[releaseVersion] = string(secret.Data["release"])
+			}
+		}
+
+	case "configmap":
+		configMaps, err := c.clientset.CoreV1().ConfigMaps(namespace).List(ctx, listOptions)
+		if err != nil {
+			log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "name": name}).Errorf("GetHelmRelease")
+			return nil, err
+		}
+
+		for _, configMap := range configMaps.Items {
+			if releaseVersion, err := strconv.ParseInt(configMap.Labels["version"], 10, 64); err == nil {
+				releases[releaseVersion] = configMap.Data["release"]
+			}
+		}
+
+	default:
+		return nil, fmt.Errorf("invalid driver %s", driver)
+	}
+
+	data, err := getHelmReleaseVersion(releases, version)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeHelmRelease(data)
+}
+
+// getHelmReleaseVersion returns the stored data for the given version of a release. If the version is 0, the data of
+// the latest version is returned.
+func getHelmReleaseVersion(releases map[int64]string, version int64) (string, error) {
+	if len(releases) == 0 {
+		return "", fmt.Errorf("release not found")
+	}
+
+	if version == 0 {
+		var versions []int64
+		for releaseVersion := range releases {
+			versions = append(versions, releaseVersion)
+		}
+		sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+		version = versions[0]
+	}
+
+	data, ok := releases[version]
+	if !ok {
+		return "", fmt.Errorf("version %d of release not found", version)
+	}
+
+	return data, nil
+}
+
+// decodeHelmRelease decodes the data of a Helm release. Helm stores a release as base64 encoded and gzip compressed
+// JSON. Older versions of Helm are not compressing the release, so that we only decompress the data when it starts
+// with the gzip magic bytes.
+func decodeHelmRelease(data string) (*HelmRelease, error) {
+	decoded, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode release: %w", err)
+	}
+
+	if bytes.HasPrefix(decoded, []byte{0x1f, 0x8b, 0x08}) {
+		reader, err := gzip.NewReader(bytes.NewReader(decoded))
+		if err != nil {
+			return nil, fmt.Errorf("could not decompress release: %w", err)
+		}
+		defer reader.Close()
+
+		decoded, err = ioutil.ReadAll(reader)
+		if err != nil {
+			return nil, fmt.Errorf("could not decompress release: %w", err)
+		}
+	}
+
+	var release helmRelease
+	if err := json.Unmarshal(decoded, &release); err != nil {
+		return nil, fmt.Errorf("could not unmarshal release: %w", err)
+	}
+
+	resources, err := getHelmReleaseResources(release.Manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HelmRelease{
+		Name:         release.Name,
+		Namespace:    release.Namespace,
+		Version:      release.Version,
+		Status:       release.Info.Status,
+		Chart:        release.Chart.Metadata.Name,
+		ChartVersion: release.Chart.Metadata.Version,
+		AppVersion:   release.Chart.Metadata.AppVersion,
+		Resources:    resources,
+	}, nil
+}
+
+// getHelmReleaseResources returns the resources from the rendered manifest of a Helm release. Empty documents (e.g.
+// templates which are disabled via the values) are skipped.
+func getHelmReleaseResources(manifest string) ([]HelmReleaseResource, error) {
+	resources := []HelmReleaseResource{}
+
+	for _, document := range helmManifestSeparator.Split(manifest, -1) {
+		if strings.TrimSpace(document) == "" {
+			continue
+		}
+
+		var resource struct {
+			APIVersion string `json:"apiVersion"`
+			Kind       string `json:"kind"`
+			Metadata   struct {
+				Name      string `json:"name"`
+				Namespace string `json:"namespace"`
+			} `json:"metadata"`
+		}
+
+		if err := yaml.Unmarshal([]byte(document), &resource); err != nil {
+			return nil, fmt.Errorf("could not unmarshal manifest: %w", err)
+		}
+
+		if resource.Kind == "" {
+			continue
+		}
+
+		resources = append(resources, HelmReleaseResource{
+			APIVersion: resource.APIVersion,
+			Kind:       resource.Kind,
+			Name:       resource.Metadata.Name,
+			Namespace:  resource.Metadata.Namespace,
+		})
+	}
+
+	return resources, nil
+}
@@ -0,0 +1,69 @@
+package cluster
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testHelmRelease = `{"name":"nginx","namespace":"default","version":2,"info":{"status":"deployed"},"chart":{"metadata":{"name":"nginx","version":"1.0.0","appVersion":"1.21"}},"manifest":"---\n# Source: nginx/templates/service.yaml\napiVersion: v1\nkind: Service\nmetadata:\n  name: nginx\n---\n# Source: nginx/templates/empty.yaml\n---\napiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: nginx\n  namespace: default\n"}`
+
+func TestDecodeHelmRelease(t *testing.T) {
+	expected := &HelmRelease{
+		Name:         "nginx",
+		Namespace:    "default",
+		Version:      2,
+		Status:       "deployed",
+		Chart:        "nginx",
+		ChartVersion: "1.0.0",
+		AppVersion:   "1.21",
+		Resources: []HelmReleaseResource{
+			{APIVersion: "v1", Kind: "Service", Name: "nginx"},
+			{APIVersion: "apps/v1", Kind: "Deployment", Name: "nginx", Namespace: "default"},
+		},
+	}
+
+	t.Run("gzip compressed", func(t *testing.T) {
+		var buf bytes.Buffer
+		writer := gzip.NewWriter(&buf)
+		_, err := writer.Write([]byte(testHelmRelease))
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+
+		release, err := decodeHelmRelease(base64.StdEncoding.EncodeToString(buf.Bytes()))
+		require.NoError(t, err)
+		require.Equal(t, expected, release)
+	})
+
+	t.Run("not compressed", func(t *testing.T) {
+		release, err := decodeHelmRelease(base64.StdEncoding.EncodeToString([]byte(testHelmRelease)))
+		require.NoError(t, err)
+		require.Equal(t, expected, release)
+	})
+
+	t.Run("invalid data", func(t *testing.T) {
+		_, err := decodeHelmRelease("not base64")
+		require.Error(t, err)
+	})
+}
+
+func TestGetHelmReleaseVersion(t *testing.T) {
+	releases := map[int64]string{1: "v1", 3: "v3", 2: "v2"}
+
+	data, err := getHelmReleaseVersion(releases, 0)
+	require.NoError(t, err)
+	require.Equal(t, "v3", data)
+
+	data, err = getHelmReleaseVersion(releases, 2)
+	require.NoError(t, err)
+	require.Equal(t, "v2", data)
+
+	_, err = getHelmReleaseVersion(releases, 4)
+	require.Error(t, err)
+
+	_, err = getHelmReleaseVersion(nil, 0)
+	require.Error(t, err)
+}
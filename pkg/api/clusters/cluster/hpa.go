@@ -0,0 +1,318 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+// maxHPAEvents is the maximum number of scaling events, which are returned for a HorizontalPodAutoscaler.
+const maxHPAEvents = 10
+
+// HPA is the status of the HorizontalPodAutoscaler, which targets a workload. The metrics contain the target and the
+// current value of each metric, which is used by the HorizontalPodAutoscaler. The events are the latest events of
+// the HorizontalPodAutoscaler, which can be used to see why the workload was scaled.
+type HPA struct {
+	Name            string      `json:"name"`
+	APIVersion      string      `json:"apiVersion"`
+	MinReplicas     int32       `json:"minReplicas"`
+	MaxReplicas     int32       `json:"maxReplicas"`
+	CurrentReplicas int32       `json:"currentReplicas"`
+	DesiredReplicas int32       `json:"desiredReplicas"`
+	LastScaleTime   string      `json:"lastScaleTime,omitempty"`
+	Metrics         []HPAMetric `json:"metrics"`
+	Conditions      []HPAStatus `json:"conditions"`
+	Events          []Event     `json:"events"`
+}
+
+// HPAMetric is a single metric of a HorizontalPodAutoscaler. The type is the type of the metric source (e.g.
+// "Resource" or "External") and the name is the name of the resource (e.g. "cpu") or of the metric. The target and
+// current values are formatted as strings, where utilization values are suffixed with "%".
+type HPAMetric struct {
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Target  string `json:"target"`
+	Current string `json:"current"`
+}
+
+// HPAStatus is a single condition of a HorizontalPodAutoscaler (e.g. "AbleToScale" or "ScalingLimited").
+type HPAStatus struct {
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// GetHPA returns the status of the HorizontalPodAutoscaler, which targets the workload with the given kind and name.
+// If the kind is empty, the first HorizontalPodAutoscaler which targets a workload with the given name is used. We try
+// to use the autoscaling/v2beta2 API first, so that we can return all metrics. If the API isn't available, we fall back
+// to the autoscaling/v1 API. If no HorizontalPodAutoscaler targets the workload, nil is returned.
+func (c *Cluster) GetHPA(ctx context.Context, namespace, kind, name string) (*HPA, error) {
+	hpa, err := c.getHPAV2beta2(ctx, namespace, kind, name)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "kind": kind, "name": name}).Errorf("GetHPA")
+			return nil, err
+		}
+
+		hpa, err = c.getHPAV1(ctx, namespace, kind, name)
+		if err != nil {
+			log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "kind": kind, "name": name}).Errorf("GetHPA")
+			return nil, err
+		}
+	}
+
+	if hpa == nil {
+		return nil, nil
+	}
+
+	events, err := c.clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.SelectorFromSet(fields.Set{"involvedObject.kind": "HorizontalPodAutoscaler", "involvedObject.name": hpa.Name}).String(),
+	})
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "kind": kind, "name": name}).Errorf("GetHPA")
+		return nil, err
+	}
+
+	hpa.Events = getHPAEvents(events.Items)
+	return hpa, nil
+}
+
+func (c *Cluster) getHPAV2beta2(ctx context.Context, namespace, kind, name string) (*HPA, error) {
+	hpas, err := c.clientset.AutoscalingV2beta2().HorizontalPodAutoscalers(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, hpa := range hpas.Items {
+		if isHPATarget(hpa.Spec.ScaleTargetRef.Kind, hpa.Spec.ScaleTargetRef.Name, kind, name) {
+			return getHPAFromV2beta2(hpa), nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (c *Cluster) getHPAV1(ctx context.Context, namespace, kind, name string) (*HPA, error) {
+	hpas, err := c.clientset.AutoscalingV1().HorizontalPodAutoscalers(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, hpa := range hpas.Items {
+		if isHPATarget(hpa.Spec.ScaleTargetRef.Kind, hpa.Spec.ScaleTargetRef.Name, kind, name) {
+			return getHPAFromV1(hpa), nil
+		}
+	}
+
+	return nil, nil
+}
+
+// isHPATarget returns true, when the scale target reference of a HorizontalPodAutoscaler matches the given kind and
+// name. If the kind is empty, only the name is compared.
+func isHPATarget(targetKind, targetName, kind, name string) bool {
+	return targetName == name && (kind == "" || targetKind == kind)
+}
+
+// getHPAFromV2beta2 converts the given autoscaling/v2beta2 HorizontalPodAutoscaler into our HPA format.
+func getHPAFromV2beta2(hpa autoscalingv2beta2.HorizontalPodAutoscaler) *HPA {
+	result := &HPA{
+		Name:            hpa.Name,
+		APIVersion:      "autoscaling/v2beta2",
+		MaxReplicas:     hpa.Spec.MaxReplicas,
+		CurrentReplicas: hpa.Status.CurrentReplicas,
+		DesiredReplicas: hpa.Status.DesiredReplicas,
+		Metrics:         []HPAMetric{},
+		Conditions:      []HPAStatus{},
+		Events:          []Event{},
+	}
+
+	if hpa.Spec.MinReplicas != nil {
+		result.MinReplicas = *hpa.Spec.MinReplicas
+	} else {
+		result.MinReplicas = 1
+	}
+
+	if hpa.Status.LastScaleTime != nil {
+		result.LastScaleTime = hpa.Status.LastScaleTime.Format(time.RFC3339)
+	}
+
+	current := make(map[string]string)
+	for _, status := range hpa.Status.CurrentMetrics {
+		metricName, value := getV2beta2MetricStatus(status)
+		current[string(status.Type)+"/"+metricName] = value
+	}
+
+	for _, spec := range hpa.Spec.Metrics {
+		metricName, target := getV2beta2MetricSpec(spec)
+		result.Metrics = append(result.Metrics, HPAMetric{
+			Type:    string(spec.Type),
+			Name:    metricName,
+			Target:  target,
+			Current: current[string(spec.Type)+"/"+metricName],
+		})
+	}
+
+	for _, condition := range hpa.Status.Conditions {
+		result.Conditions = append(result.Conditions, HPAStatus{
+			Type:    string(condition.Type),
+			Status:  string(condition.Status),
+			Reason:  condition.Reason,
+			Message: condition.Message,
+		})
+	}
+
+	return result
+}
+
+// getHPAFromV1 converts the given autoscaling/v1 HorizontalPodAutoscaler into our HPA format. The v1 API only supports
+// the CPU utilization as metric.
+func getHPAFromV1(hpa autoscalingv1.HorizontalPodAutoscaler) *HPA {
+	result := &HPA{
+		Name:            hpa.Name,
+		APIVersion:      "autoscaling/v1",
+		MaxReplicas:     hpa.Spec.MaxReplicas,
+		CurrentReplicas: hpa.Status.CurrentReplicas,
+		DesiredReplicas: hpa.Status.DesiredReplicas,
+		Metrics:         []HPAMetric{},
+		Conditions:      []HPAStatus{},
+		Events:          []Event{},
+	}
+
+	if hpa.Spec.MinReplicas != nil {
+		result.MinReplicas = *hpa.Spec.MinReplicas
+	} else {
+		result.MinReplicas = 1
+	}
+
+	if hpa.Status.LastScaleTime != nil {
+		result.LastScaleTime = hpa.Status.LastScaleTime.Format(time.RFC3339)
+	}
+
+	if hpa.Spec.TargetCPUUtilizationPercentage != nil {
+		metric := HPAMetric{
+			Type:   "Resource",
+			Name:   "cpu",
+			Target: fmt.Sprintf("%d%%", *hpa.Spec.TargetCPUUtilizationPercentage),
+		}
+
+		if hpa.Status.CurrentCPUUtilizationPercentage != nil {
+			metric.Current = fmt.Sprintf("%d%%", *hpa.Status.CurrentCPUUtilizationPercentage)
+		}
+
+		result.Metrics = append(result.Metrics, metric)
+	}
+
+	return result
+}
+
+// getV2beta2MetricSpec returns the name and the formatted target value of the given metric.
+func getV2beta2MetricSpec(spec autoscalingv2beta2.MetricSpec) (string, string) {
+	switch spec.Type {
+	case autoscalingv2beta2.ResourceMetricSourceType:
+		if spec.Resource != nil {
+			return string(spec.Resource.Name), formatMetricTarget(spec.Resource.Target)
+		}
+	case autoscalingv2beta2.ContainerResourceMetricSourceType:
+		if spec.ContainerResource != nil {
+			return spec.ContainerResource.Container + "/" + string(spec.ContainerResource.Name), formatMetricTarget(spec.ContainerResource.Target)
+		}
+	case autoscalingv2beta2.PodsMetricSourceType:
+		if spec.Pods != nil {
+			return spec.Pods.Metric.Name, formatMetricTarget(spec.Pods.Target)
+		}
+	case autoscalingv2beta2.ObjectMetricSourceType:
+		if spec.Object != nil {
+			return spec.Object.Metric.Name, formatMetricTarget(spec.Object.Target)
+		}
+	case autoscalingv2beta2.ExternalMetricSourceType:
+		if spec.External != nil {
+			return spec.External.Metric.Name, formatMetricTarget(spec.External.Target)
+		}
+	}
+
+	return "", ""
+}
+
+// getV2beta2MetricStatus returns the name and the formatted current value of the given metric.
+func getV2beta2MetricStatus(status autoscalingv2beta2.MetricStatus) (string, string) {
+	switch status.Type {
+	case autoscalingv2beta2.ResourceMetricSourceType:
+		if status.Resource != nil {
+			return string(status.Resource.Name), formatMetricValue(status.Resource.Current)
+		}
+	case autoscalingv2beta2.ContainerResourceMetricSourceType:
+		if status.ContainerResource != nil {
+			return status.ContainerResource.Container + "/" + string(status.ContainerResource.Name), formatMetricValue(status.ContainerResource.Current)
+		}
+	case autoscalingv2beta2.PodsMetricSourceType:
+		if status.Pods != nil {
+			return status.Pods.Metric.Name, formatMetricValue(status.Pods.Current)
+		}
+	case autoscalingv2beta2.ObjectMetricSourceType:
+		if status.Object != nil {
+			return status.Object.Metric.Name, formatMetricValue(status.Object.Current)
+		}
+	case autoscalingv2beta2.ExternalMetricSourceType:
+		if status.External != nil {
+			return status.External.Metric.Name, formatMetricValue(status.External.Current)
+		}
+	}
+
+	return "", ""
+}
+
+// formatMetricTarget formats the target of a metric. Utilization targets are suffixed with "%".
+func formatMetricTarget(target autoscalingv2beta2.MetricTarget) string {
+	switch {
+	case target.AverageUtilization != nil:
+		return fmt.Sprintf("%d%%", *target.AverageUtilization)
+	case target.AverageValue != nil:
+		return target.AverageValue.String()
+	case target.Value != nil:
+		return target.Value.String()
+	default:
+		return ""
+	}
+}
+
+// formatMetricValue formats the current value of a metric. Utilization values are suffixed with "%".
+func formatMetricValue(value autoscalingv2beta2.MetricValueStatus) string {
+	switch {
+	case value.AverageUtilization != nil:
+		return fmt.Sprintf("%d%%", *value.AverageUtilization)
+	case value.AverageValue != nil:
+		return value.AverageValue.String()
+	case value.Value != nil:
+		return value.Value.String()
+	default:
+		return ""
+	}
+}
+
+// getHPAEvents returns the latest events of a HorizontalPodAutoscaler, sorted by the time of the last occurrence.
+func getHPAEvents(events []corev1.Event) []Event {
+	sort.Slice(events, func(i, j int) bool {
+		return getEventTime(events[i]).After(getEventTime(events[j]))
+	})
+
+	hpaEvents := []Event{}
+	for _, event := range events {
+		if len(hpaEvents) >= maxHPAEvents {
+			break
+		}
+
+		hpaEvents = append(hpaEvents, getEvent(event))
+	}
+
+	return hpaEvents
+}
@@ -0,0 +1,95 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIsHPATarget(t *testing.T) {
+	require.True(t, isHPATarget("Deployment", "nginx", "Deployment", "nginx"))
+	require.True(t, isHPATarget("Deployment", "nginx", "", "nginx"))
+	require.False(t, isHPATarget("StatefulSet", "nginx", "Deployment", "nginx"))
+	require.False(t, isHPATarget("Deployment", "nginx", "", "redis"))
+}
+
+func TestGetHPAFromV2beta2(t *testing.T) {
+	minReplicas := int32(2)
+	targetUtilization := int32(80)
+	currentUtilization := int32(95)
+	targetValue := resource.MustParse("100")
+
+	hpa := autoscalingv2beta2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx"},
+		Spec: autoscalingv2beta2.HorizontalPodAutoscalerSpec{
+			MinReplicas: &minReplicas,
+			MaxReplicas: 10,
+			Metrics: []autoscalingv2beta2.MetricSpec{
+				{
+					Type:     autoscalingv2beta2.ResourceMetricSourceType,
+					Resource: &autoscalingv2beta2.ResourceMetricSource{Name: corev1.ResourceCPU, Target: autoscalingv2beta2.MetricTarget{AverageUtilization: &targetUtilization}},
+				},
+				{
+					Type:     autoscalingv2beta2.ExternalMetricSourceType,
+					External: &autoscalingv2beta2.ExternalMetricSource{Metric: autoscalingv2beta2.MetricIdentifier{Name: "queue_length"}, Target: autoscalingv2beta2.MetricTarget{Value: &targetValue}},
+				},
+			},
+		},
+		Status: autoscalingv2beta2.HorizontalPodAutoscalerStatus{
+			CurrentReplicas: 3,
+			DesiredReplicas: 4,
+			CurrentMetrics: []autoscalingv2beta2.MetricStatus{
+				{
+					Type:     autoscalingv2beta2.ResourceMetricSourceType,
+					Resource: &autoscalingv2beta2.ResourceMetricStatus{Name: corev1.ResourceCPU, Current: autoscalingv2beta2.MetricValueStatus{AverageUtilization: &currentUtilization}},
+				},
+			},
+			Conditions: []autoscalingv2beta2.HorizontalPodAutoscalerCondition{
+				{Type: autoscalingv2beta2.AbleToScale, Status: corev1.ConditionTrue, Reason: "SucceededRescale"},
+			},
+		},
+	}
+
+	require.Equal(t, &HPA{
+		Name:            "nginx",
+		APIVersion:      "autoscaling/v2beta2",
+		MinReplicas:     2,
+		MaxReplicas:     10,
+		CurrentReplicas: 3,
+		DesiredReplicas: 4,
+		Metrics: []HPAMetric{
+			{Type: "Resource", Name: "cpu", Target: "80%", Current: "95%"},
+			{Type: "External", Name: "queue_length", Target: "100", Current: ""},
+		},
+		Conditions: []HPAStatus{{Type: "AbleToScale", Status: "True", Reason: "SucceededRescale"}},
+		Events:     []Event{},
+	}, getHPAFromV2beta2(hpa))
+}
+
+func TestGetHPAFromV1(t *testing.T) {
+	targetUtilization := int32(80)
+	currentUtilization := int32(50)
+
+	hpa := autoscalingv1.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx"},
+		Spec:       autoscalingv1.HorizontalPodAutoscalerSpec{MaxReplicas: 5, TargetCPUUtilizationPercentage: &targetUtilization},
+		Status:     autoscalingv1.HorizontalPodAutoscalerStatus{CurrentReplicas: 1, DesiredReplicas: 1, CurrentCPUUtilizationPercentage: &currentUtilization},
+	}
+
+	require.Equal(t, &HPA{
+		Name:            "nginx",
+		APIVersion:      "autoscaling/v1",
+		MinReplicas:     1,
+		MaxReplicas:     5,
+		CurrentReplicas: 1,
+		DesiredReplicas: 1,
+		Metrics:         []HPAMetric{{Type: "Resource", Name: "cpu", Target: "80%", Current: "50%"}},
+		Conditions:      []HPAStatus{},
+		Events:          []Event{},
+	}, getHPAFromV1(hpa))
+}
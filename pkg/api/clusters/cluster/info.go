@@ -0,0 +1,92 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+)
+
+// infoCacheDuration is the duration for how long the version and number of nodes of a cluster are cached.
+var infoCacheDuration = 1 * time.Minute
+
+// infoTimeout is the timeout for getting the version and number of nodes of a cluster.
+var infoTimeout = 10 * time.Second
+
+// Info contains metadata of a cluster, which can be used to show the health of all clusters at a glance. If the
+// Kubernetes API server of the cluster isn't reachable, the reachable field is false and the error field contains the
+// reason.
+type Info struct {
+	Version    string `json:"version,omitempty"`
+	Nodes      int    `json:"nodes"`
+	Reachable  bool   `json:"reachable"`
+	Error      string `json:"error,omitempty"`
+	CRDsLoaded bool   `json:"crdsLoaded"`
+}
+
+// infoCache caches the info of a cluster, so that we do not have to get the version and nodes of a cluster for each
+// request.
+type infoCache struct {
+	mutex     sync.Mutex
+	info      Info
+	lastFetch time.Time
+}
+
+// GetInfo returns the Kubernetes version, the number of nodes and if the cluster is reachable. The info is gathered on
+// the first call and then cached for one minute. The CRD load state is never cached, because it is already known by
+// the cluster.
+//
+// The info is not gathered with the context of the request, because the result is shared by all callers. Instead we
+// are using a separate context with a timeout, so that the mutex is never held longer than the timeout. When the info
+// could not be gathered because the timeout was exceeded, the result isn't cached, so that the next call tries it
+// again.
+func (c *Cluster) GetInfo() Info {
+	c.info.mutex.Lock()
+	defer c.info.mutex.Unlock()
+
+	if c.info.lastFetch.IsZero() || c.info.lastFetch.Before(time.Now().Add(-1*infoCacheDuration)) {
+		ctx, cancel := context.WithTimeout(context.Background(), infoTimeout)
+		defer cancel()
+
+		info, err := c.getInfo(ctx)
+		if err != nil && (errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled)) {
+			info.CRDsLoaded = c.CRDsLoaded()
+			return info
+		}
+
+		c.info.info = info
+		c.info.lastFetch = time.Now()
+	}
+
+	info := c.info.info
+	info.CRDsLoaded = c.CRDsLoaded()
+	return info
+}
+
+// getInfo gets the version and the number of nodes of the cluster from the Kubernetes API server. Besides the info the
+// error is returned, so that the caller can decide if the info should be cached.
+func (c *Cluster) getInfo(ctx context.Context) (Info, error) {
+	var serverVersion version.Info
+
+	body, err := c.clientset.Discovery().RESTClient().Get().AbsPath("/version").Do(ctx).Raw()
+	if err == nil {
+		err = json.Unmarshal(body, &serverVersion)
+	}
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name}).Errorf("getInfo")
+		return Info{Reachable: false, Error: err.Error()}, err
+	}
+
+	nodes, err := c.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name}).Errorf("getInfo")
+		return Info{Version: serverVersion.GitVersion, Reachable: true, Error: err.Error()}, err
+	}
+
+	return Info{Version: serverVersion.GitVersion, Nodes: len(nodes.Items), Reachable: true}, nil
+}
@@ -0,0 +1,48 @@
+package cluster
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+)
+
+func TestGetInfo(t *testing.T) {
+	c, err := NewCluster("dev-de1", &rest.Config{Host: "http://localhost:0"}, nil)
+	require.NoError(t, err)
+	defer c.Close()
+
+	info := c.GetInfo()
+	require.False(t, info.Reachable)
+	require.NotEmpty(t, info.Error)
+	require.False(t, info.CRDsLoaded)
+
+	lastFetch := c.info.lastFetch
+	require.False(t, lastFetch.IsZero())
+
+	c.GetInfo()
+	require.Equal(t, lastFetch, c.info.lastFetch)
+}
+
+func TestGetInfoTimeout(t *testing.T) {
+	// The server never responds before the request is canceled, so that the timeout for the info is exceeded.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	defaultInfoTimeout := infoTimeout
+	infoTimeout = 50 * time.Millisecond
+	defer func() { infoTimeout = defaultInfoTimeout }()
+
+	c, err := NewCluster("dev-de1", &rest.Config{Host: server.URL}, nil)
+	require.NoError(t, err)
+	defer c.Close()
+
+	info := c.GetInfo()
+	require.False(t, info.Reachable)
+	require.True(t, c.info.lastFetch.IsZero())
+}
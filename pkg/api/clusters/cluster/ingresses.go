@@ -0,0 +1,214 @@
+package cluster
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Ingress is the normalized format of an Ingress, which contains all hosts and the routes of the Ingress to the backend
+// Services.
+type Ingress struct {
+	Name         string         `json:"name"`
+	Namespace    string         `json:"namespace"`
+	IngressClass string         `json:"ingressClass,omitempty"`
+	Hosts        []string       `json:"hosts"`
+	TLSHosts     []string       `json:"tlsHosts,omitempty"`
+	Routes       []IngressRoute `json:"routes"`
+}
+
+// IngressRoute is a single route of an Ingress. The default backend of an Ingress is returned as route with the default
+// field set to true. When the backends were resolved, the service exists field is set when the Service exists and the
+// endpoints field contains the number of ready endpoints of the Service.
+type IngressRoute struct {
+	Host          string `json:"host,omitempty"`
+	Path          string `json:"path,omitempty"`
+	PathType      string `json:"pathType,omitempty"`
+	Default       bool   `json:"default,omitempty"`
+	Service       string `json:"service,omitempty"`
+	Port          string `json:"port,omitempty"`
+	Resource      string `json:"resource,omitempty"`
+	ServiceExists *bool  `json:"serviceExists,omitempty"`
+	Endpoints     *int   `json:"endpoints,omitempty"`
+}
+
+// GetIngresses returns all Ingresses of the given namespace with their hosts and routes. If the resolveBackends
+// parameter is true, we also check for each route if the backend Service exists and how many ready endpoints it has.
+func (c *Cluster) GetIngresses(ctx context.Context, namespace string, resolveBackends bool) ([]Ingress, error) {
+	ingressList, err := c.clientset.NetworkingV1().Ingresses(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace}).Errorf("GetIngresses")
+		return nil, err
+	}
+
+	ingresses := make([]Ingress, 0, len(ingressList.Items))
+	for _, ingress := range ingressList.Items {
+		ingresses = append(ingresses, getIngress(ingress))
+	}
+
+	if resolveBackends {
+		services, err := c.clientset.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace}).Errorf("GetIngresses")
+			return nil, err
+		}
+
+		endpoints, err := c.clientset.CoreV1().Endpoints(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace}).Errorf("GetIngresses")
+			return nil, err
+		}
+
+		resolveIngressBackends(ingresses, services.Items, endpoints.Items)
+	}
+
+	return ingresses, nil
+}
+
+// getIngress returns the normalized format of the given Ingress. The hosts are deduplicated and sorted.
+func getIngress(ingress networkingv1.Ingress) Ingress {
+	normalized := Ingress{
+		Name:      ingress.Name,
+		Namespace: ingress.Namespace,
+		Hosts:     []string{},
+		Routes:    []IngressRoute{},
+	}
+
+	if ingress.Spec.IngressClassName != nil {
+		normalized.IngressClass = *ingress.Spec.IngressClassName
+	} else {
+		normalized.IngressClass = ingress.Annotations["kubernetes.io/ingress.class"]
+	}
+
+	if ingress.Spec.DefaultBackend != nil {
+		route := getIngressBackend(*ingress.Spec.DefaultBackend)
+		route.Default = true
+		normalized.Routes = append(normalized.Routes, route)
+	}
+
+	hosts := make(map[string]bool)
+	for _, rule := range ingress.Spec.Rules {
+		if rule.Host != "" && !hosts[rule.Host] {
+			hosts[rule.Host] = true
+			normalized.Hosts = append(normalized.Hosts, rule.Host)
+		}
+
+		if rule.HTTP == nil {
+			continue
+		}
+
+		for _, path := range rule.HTTP.Paths {
+			route := getIngressBackend(path.Backend)
+			route.Host = rule.Host
+			route.Path = path.Path
+			if path.PathType != nil {
+				route.PathType = string(*path.PathType)
+			}
+
+			normalized.Routes = append(normalized.Routes, route)
+		}
+	}
+
+	for _, tls := range ingress.Spec.TLS {
+		normalized.TLSHosts = append(normalized.TLSHosts, tls.Hosts...)
+	}
+
+	sort.Strings(normalized.Hosts)
+	return normalized
+}
+
+// getIngressBackend returns a route for the given backend. The backend can be a Service, where the port is referenced
+// by its name or number, or a resource.
+func getIngressBackend(backend networkingv1.IngressBackend) IngressRoute {
+	var route IngressRoute
+
+	if backend.Service != nil {
+		route.Service = backend.Service.Name
+		if backend.Service.Port.Name != "" {
+			route.Port = backend.Service.Port.Name
+		} else if backend.Service.Port.Number != 0 {
+			route.Port = strconv.Itoa(int(backend.Service.Port.Number))
+		}
+	}
+
+	if backend.Resource != nil {
+		route.Resource = backend.Resource.Kind + "/" + backend.Resource.Name
+	}
+
+	return route
+}
+
+// resolveIngressBackends sets the service exists and endpoints fields for all routes with a Service backend. The
+// Services and Endpoints are keyed by their namespace and name, because an Ingress can only reference Services from
+// its own namespace, but the lists can contain Services from all namespaces.
+func resolveIngressBackends(ingresses []Ingress, services []corev1.Service, endpoints []corev1.Endpoints) {
+	existingServices := make(map[string]bool, len(services))
+	for _, service := range services {
+		existingServices[service.Namespace+"/"+service.Name] = true
+	}
+
+	readyEndpoints := make(map[string]int, len(endpoints))
+	for _, endpoint := range endpoints {
+		key := endpoint.Namespace + "/" + endpoint.Name
+		for _, subset := range endpoint.Subsets {
+			readyEndpoints[key] = readyEndpoints[key] + len(subset.Addresses)
+		}
+	}
+
+	for i := range ingresses {
+		for j := range ingresses[i].Routes {
+			route := &ingresses[i].Routes[j]
+			if route.Service == "" {
+				continue
+			}
+
+			key := ingresses[i].Namespace + "/" + route.Service
+			exists := existingServices[key]
+			count := readyEndpoints[key]
+			route.ServiceExists = &exists
+			route.Endpoints = &count
+		}
+	}
+}
+
+// FilterIngresses returns all Ingresses, where a host or the Service of a route contains the given filter. The
+// comparison is case-insensitive. If the filter is empty all Ingresses are returned.
+func FilterIngresses(ingresses []Ingress, filter string) []Ingress {
+	if filter == "" {
+		return ingresses
+	}
+
+	filter = strings.ToLower(filter)
+	filtered := []Ingress{}
+
+	for _, ingress := range ingresses {
+		if ingressMatches(ingress, filter) {
+			filtered = append(filtered, ingress)
+		}
+	}
+
+	return filtered
+}
+
+// ingressMatches returns true, when a host or the Service of a route of the Ingress contains the filter.
+func ingressMatches(ingress Ingress, filter string) bool {
+	for _, host := range ingress.Hosts {
+		if strings.Contains(strings.ToLower(host), filter) {
+			return true
+		}
+	}
+
+	for _, route := range ingress.Routes {
+		if strings.Contains(strings.ToLower(route.Service), filter) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,81 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetIngress(t *testing.T) {
+	ingressClass := "nginx"
+	pathType := networkingv1.PathTypePrefix
+
+	ingress := networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: &ingressClass,
+			DefaultBackend:   &networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: "default-backend", Port: networkingv1.ServiceBackendPort{Number: 80}}},
+			TLS:              []networkingv1.IngressTLS{{Hosts: []string{"app.kobs.io"}}},
+			Rules: []networkingv1.IngressRule{
+				{
+					Host: "app.kobs.io",
+					IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{Paths: []networkingv1.HTTPIngressPath{
+						{Path: "/api", PathType: &pathType, Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: "api", Port: networkingv1.ServiceBackendPort{Name: "http"}}}},
+						{Path: "/", PathType: &pathType, Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: "frontend", Port: networkingv1.ServiceBackendPort{Number: 8080}}}},
+					}}},
+				},
+				{Host: "app.kobs.io"},
+			},
+		},
+	}
+
+	require.Equal(t, Ingress{
+		Name:         "app",
+		Namespace:    "default",
+		IngressClass: "nginx",
+		Hosts:        []string{"app.kobs.io"},
+		TLSHosts:     []string{"app.kobs.io"},
+		Routes: []IngressRoute{
+			{Default: true, Service: "default-backend", Port: "80"},
+			{Host: "app.kobs.io", Path: "/api", PathType: "Prefix", Service: "api", Port: "http"},
+			{Host: "app.kobs.io", Path: "/", PathType: "Prefix", Service: "frontend", Port: "8080"},
+		},
+	}, getIngress(ingress))
+}
+
+func TestResolveIngressBackends(t *testing.T) {
+	ingresses := []Ingress{
+		{Namespace: "default", Routes: []IngressRoute{{Service: "api"}, {Service: "missing"}, {Resource: "StorageBucket/static"}}},
+		{Namespace: "kube-system", Routes: []IngressRoute{{Service: "api"}}},
+	}
+	services := []corev1.Service{{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"}}}
+	endpoints := []corev1.Endpoints{{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"},
+		Subsets:    []corev1.EndpointSubset{{Addresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}, {IP: "10.0.0.2"}}}},
+	}}
+
+	resolveIngressBackends(ingresses, services, endpoints)
+
+	require.True(t, *ingresses[0].Routes[0].ServiceExists)
+	require.Equal(t, 2, *ingresses[0].Routes[0].Endpoints)
+	require.False(t, *ingresses[0].Routes[1].ServiceExists)
+	require.Equal(t, 0, *ingresses[0].Routes[1].Endpoints)
+	require.Nil(t, ingresses[0].Routes[2].ServiceExists)
+	require.False(t, *ingresses[1].Routes[0].ServiceExists)
+	require.Equal(t, 0, *ingresses[1].Routes[0].Endpoints)
+}
+
+func TestFilterIngresses(t *testing.T) {
+	ingresses := []Ingress{
+		{Name: "app", Hosts: []string{"app.kobs.io"}, Routes: []IngressRoute{{Service: "frontend"}}},
+		{Name: "api", Hosts: []string{"api.kobs.io"}, Routes: []IngressRoute{{Service: "backend"}}},
+	}
+
+	require.Equal(t, ingresses, FilterIngresses(ingresses, ""))
+	require.Equal(t, ingresses[:1], FilterIngresses(ingresses, "APP"))
+	require.Equal(t, ingresses[1:], FilterIngresses(ingresses, "backend"))
+	require.Empty(t, FilterIngresses(ingresses, "unknown"))
+}
@@ -0,0 +1,146 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// jobPodsPollInterval is the interval in which we are checking for new Pods of a Job, while the logs of the Job are
+// streamed.
+var jobPodsPollInterval = 5 * time.Second
+
+// lockedWriter wraps a WebSocket connection, so that multiple goroutines can write to the connection. This is required,
+// because a WebSocket connection only supports one concurrent writer.
+type lockedWriter struct {
+	mutex sync.Mutex
+	conn  *websocket.Conn
+}
+
+// WriteMessage writes the given message to the WebSocket connection.
+func (w *lockedWriter) WriteMessage(messageType int, data []byte) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.conn.WriteMessage(messageType, data)
+}
+
+// StreamJobLogs streams the logs of all Pods of a Job via the passed in WebSocket connection. The Pods are selected via
+// the label selector of the Job. While the Job is running, we are checking for new Pods in the jobPodsPollInterval, so
+// that we automatically switch to newly created Pods, when old Pods are completed or failed. Since a Job can run
+// multiple Pods in parallel, each line is prefixed with the name of the Pod.
+// If the container is empty, the logs of the first container of each Pod are streamed. The function returns when the
+// Job is finished and all log streams are closed or when the passed in context is canceled.
+func (c *Cluster) StreamJobLogs(ctx context.Context, conn *websocket.Conn, namespace, name, container string, since int64, maxMessageSize int) error {
+	activeWebSocketsMetric.WithLabelValues("logs").Inc()
+	defer activeWebSocketsMetric.WithLabelValues("logs").Dec()
+
+	// When we return early, because of an error or because the context was canceled, all log streams are canceled
+	// before we wait for them.
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	writer := &lockedWriter{conn: conn}
+	streams := make(map[string]bool)
+
+	ticker := time.NewTicker(jobPodsPollInterval)
+	defer ticker.Stop()
+
+	for {
+		// We have to get the Job before we list the Pods, so that we do not miss the logs of a Pod, which was started
+		// and completed between our last check and the end of the Job.
+		job, err := c.clientset.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "name": name}).Errorf("StreamJobLogs")
+			return err
+		}
+
+		selector, err := metav1.LabelSelectorAsSelector(job.Spec.Selector)
+		if err != nil {
+			return err
+		}
+
+		pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "name": name}).Errorf("StreamJobLogs")
+			return err
+		}
+
+		for _, pod := range pods.Items {
+			if streams[pod.Name] || pod.Status.Phase == corev1.PodPending {
+				continue
+			}
+
+			streams[pod.Name] = true
+			wg.Add(1)
+
+			go func(pod corev1.Pod) {
+				defer wg.Done()
+				c.streamPrefixedPodLogs(ctx, writer, pod, container, since, maxMessageSize)
+			}(pod)
+		}
+
+		if isJobFinished(job) {
+			wg.Wait()
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// streamPrefixedPodLogs streams the logs of a single Pod of a Job or workload. Each line is prefixed with the name of the
+// Pod. Errors are written to the WebSocket connection, so that the log streams of the other Pods are not interrupted.
+func (c *Cluster) streamPrefixedPodLogs(ctx context.Context, writer *lockedWriter, pod corev1.Pod, container string, since int64, maxMessageSize int) {
+	if container == "" && len(pod.Spec.Containers) > 0 {
+		container = pod.Spec.Containers[0].Name
+	}
+
+	prefix := fmt.Sprintf("[%s] ", pod.Name)
+
+	stream, err := c.getStreamClientset().CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container:    container,
+		SinceSeconds: &since,
+		Follow:       true,
+	}).Stream(ctx)
+	if err != nil {
+		writer.WriteMessage(websocket.TextMessage, []byte(prefix+"Could not stream logs: "+err.Error()))
+		return
+	}
+	defer stream.Close()
+
+	writeLogStream(writer, stream, maxMessageSize, prefix)
+}
+
+// isJobFinished returns true, when the Job has a "Complete" or "Failed" condition.
+func isJobFinished(job *batchv1.Job) bool {
+	for _, condition := range job.Status.Conditions {
+		if (condition.Type == batchv1.JobComplete || condition.Type == batchv1.JobFailed) && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,121 @@
+package cluster
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// kindsCacheDuration is the duration for how long the discovered API resources of a cluster are cached.
+var kindsCacheDuration = 10 * time.Minute
+
+// kindsCache caches the API resources of a cluster, which were returned by the discovery API.
+type kindsCache struct {
+	mutex     sync.Mutex
+	kinds     []Kind
+	lastFetch time.Time
+}
+
+// Kind is the mapping of a kind to the path and resource, which must be used to get the resources of this kind via the
+// GetResources function.
+type Kind struct {
+	Kind       string `json:"kind"`
+	Group      string `json:"group"`
+	Version    string `json:"version"`
+	Path       string `json:"path"`
+	Resource   string `json:"resource"`
+	Namespaced bool   `json:"namespaced"`
+}
+
+// getKinds returns all kinds of the cluster. The kinds are loaded via the discovery API, where only the preferred
+// version of each group is used. When the discovery fails for some groups, we still return the kinds of all other
+// groups, because this is likely caused by an unavailable aggregated API server.
+func (c *Cluster) getKinds() ([]Kind, error) {
+	c.kinds.mutex.Lock()
+	defer c.kinds.mutex.Unlock()
+
+	if c.kinds.kinds != nil && c.kinds.lastFetch.After(time.Now().Add(-1*kindsCacheDuration)) {
+		return c.kinds.kinds, nil
+	}
+
+	resourceLists, err := c.clientset.Discovery().ServerPreferredResources()
+	if err != nil {
+		if len(resourceLists) == 0 {
+			log.WithError(err).WithFields(logrus.Fields{"cluster": c.name}).Errorf("getKinds")
+			return nil, err
+		}
+
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name}).Warnf("Discovery failed for some groups")
+	}
+
+	c.kinds.kinds = getKindsFromResourceLists(resourceLists)
+	c.kinds.lastFetch = time.Now()
+
+	return c.kinds.kinds, nil
+}
+
+// getKindsFromResourceLists converts the API resource lists returned by the discovery API into our Kind format.
+// Subresources (e.g. "pods/log") are skipped, because they can not be listed via the GetResources function.
+func getKindsFromResourceLists(resourceLists []*metav1.APIResourceList) []Kind {
+	kinds := []Kind{}
+
+	for _, resourceList := range resourceLists {
+		if resourceList == nil {
+			continue
+		}
+
+		gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil {
+			continue
+		}
+
+		path := "/apis/" + resourceList.GroupVersion
+		if gv.Group == "" {
+			path = "/api/" + resourceList.GroupVersion
+		}
+
+		for _, resource := range resourceList.APIResources {
+			if strings.Contains(resource.Name, "/") {
+				continue
+			}
+
+			kinds = append(kinds, Kind{
+				Kind:       resource.Kind,
+				Group:      gv.Group,
+				Version:    gv.Version,
+				Path:       path,
+				Resource:   resource.Name,
+				Namespaced: resource.Namespaced,
+			})
+		}
+	}
+
+	return kinds
+}
+
+// GetKinds returns the mappings for the given kind. The comparison is case-insensitive. Since the same kind can exist
+// in multiple groups (e.g. "Event" in the core and the "events.k8s.io" group), all matches are returned. If the kind is
+// empty, all mappings of the cluster are returned.
+func (c *Cluster) GetKinds(kind string) ([]Kind, error) {
+	kinds, err := c.getKinds()
+	if err != nil {
+		return nil, err
+	}
+
+	if kind == "" {
+		return kinds, nil
+	}
+
+	matches := []Kind{}
+	for _, k := range kinds {
+		if strings.EqualFold(k.Kind, kind) {
+			matches = append(matches, k)
+		}
+	}
+
+	return matches, nil
+}
@@ -0,0 +1,34 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetKindsFromResourceLists(t *testing.T) {
+	kinds := getKindsFromResourceLists([]*metav1.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{
+				{Name: "pods", Kind: "Pod", Namespaced: true},
+				{Name: "pods/log", Kind: "Pod", Namespaced: true},
+				{Name: "nodes", Kind: "Node", Namespaced: false},
+			},
+		},
+		{
+			GroupVersion: "apps/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "deployments", Kind: "Deployment", Namespaced: true},
+			},
+		},
+		nil,
+	})
+
+	require.Equal(t, []Kind{
+		{Kind: "Pod", Group: "", Version: "v1", Path: "/api/v1", Resource: "pods", Namespaced: true},
+		{Kind: "Node", Group: "", Version: "v1", Path: "/api/v1", Resource: "nodes", Namespaced: false},
+		{Kind: "Deployment", Group: "apps", Version: "v1", Path: "/apis/apps/v1", Resource: "deployments", Namespaced: true},
+	}, kinds)
+}
@@ -0,0 +1,67 @@
+package cluster
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+)
+
+// timestampReader removes the timestamps, which are added by the Kubernetes API to each line of a log stream when the
+// timestamps option is set. The timestamp of the last line is saved and lines which are not newer than this timestamp
+// are skipped, so that the reader can be reset with a new stream without returning lines twice.
+type timestampReader struct {
+	reader *bufio.Reader
+	buf    []byte
+	err    error
+	last   time.Time
+}
+
+// reset sets the stream, from which the lines are read. The timestamp of the last line is kept.
+func (t *timestampReader) reset(stream io.Reader) {
+	t.reader = bufio.NewReader(stream)
+	t.buf = nil
+	t.err = nil
+}
+
+// Read implements the io.Reader interface.
+func (t *timestampReader) Read(p []byte) (int, error) {
+	for len(t.buf) == 0 {
+		if t.err != nil {
+			return 0, t.err
+		}
+
+		line, err := t.reader.ReadString('\n')
+		t.err = err
+
+		if len(line) > 0 {
+			timestamp, content := splitTimestamp(line)
+			if timestamp.IsZero() {
+				t.buf = []byte(content)
+			} else if timestamp.After(t.last) {
+				t.last = timestamp
+				t.buf = []byte(content)
+			}
+		}
+	}
+
+	n := copy(p, t.buf)
+	t.buf = t.buf[n:]
+	return n, nil
+}
+
+// splitTimestamp splits a log line into the RFC3339 timestamp, which was added by the Kubernetes API, and the content of
+// the line. If the line doesn't start with a timestamp, the zero time and the unmodified line are returned.
+func splitTimestamp(line string) (time.Time, string) {
+	index := strings.Index(line, " ")
+	if index == -1 {
+		return time.Time{}, line
+	}
+
+	timestamp, err := time.Parse(time.RFC3339Nano, line[:index])
+	if err != nil {
+		return time.Time{}, line
+	}
+
+	return timestamp, line[index+1:]
+}
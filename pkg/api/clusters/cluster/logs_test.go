@@ -0,0 +1,25 @@
+package cluster
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimestampReader(t *testing.T) {
+	reader := &timestampReader{}
+
+	reader.reset(strings.NewReader("2021-10-16T10:00:00.100000000Z line 1\n2021-10-16T10:00:00.200000000Z line 2\n"))
+	data, err := ioutil.ReadAll(reader)
+	require.NoError(t, err)
+	require.Equal(t, "line 1\nline 2\n", string(data))
+	require.Equal(t, time.Date(2021, 10, 16, 10, 0, 0, 200000000, time.UTC), reader.last)
+
+	reader.reset(strings.NewReader("2021-10-16T10:00:00.100000000Z line 1\n2021-10-16T10:00:00.200000000Z line 2\n2021-10-16T10:00:00.300000000Z line 3\nno timestamp"))
+	data, err = ioutil.ReadAll(reader)
+	require.NoError(t, err)
+	require.Equal(t, "line 3\nno timestamp", string(data))
+}
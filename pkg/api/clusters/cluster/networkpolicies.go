@@ -0,0 +1,70 @@
+package cluster
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// NetworkPolicy is a NetworkPolicy, which selects a Pod. The policy types are "Ingress" and / or "Egress" and define
+// which of the rules are enforced for the Pod. If a policy type is set, but the corresponding rules are empty, all
+// traffic in this direction is blocked.
+type NetworkPolicy struct {
+	Name        string                                  `json:"name"`
+	PolicyTypes []string                                `json:"policyTypes"`
+	Ingress     []networkingv1.NetworkPolicyIngressRule `json:"ingress"`
+	Egress      []networkingv1.NetworkPolicyEgressRule  `json:"egress"`
+}
+
+// GetNetworkPoliciesForPod returns all NetworkPolicies in the namespace of the given Pod, which are selecting the Pod
+// via their pod selector. This can be used to see which ingress and egress rules are applied to the Pod.
+func (c *Cluster) GetNetworkPoliciesForPod(ctx context.Context, namespace, name string) ([]NetworkPolicy, error) {
+	pod, err := c.clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "name": name}).Errorf("GetNetworkPoliciesForPod")
+		return nil, err
+	}
+
+	networkPolicies, err := c.clientset.NetworkingV1().NetworkPolicies(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "name": name}).Errorf("GetNetworkPoliciesForPod")
+		return nil, err
+	}
+
+	return filterNetworkPolicies(networkPolicies.Items, pod.Labels)
+}
+
+// filterNetworkPolicies returns all NetworkPolicies, which pod selector matches the given labels. An empty pod selector
+// selects all Pods in the namespace.
+func filterNetworkPolicies(networkPolicies []networkingv1.NetworkPolicy, podLabels map[string]string) ([]NetworkPolicy, error) {
+	policies := []NetworkPolicy{}
+
+	for _, networkPolicy := range networkPolicies {
+		selector, err := metav1.LabelSelectorAsSelector(&networkPolicy.Spec.PodSelector)
+		if err != nil {
+			return nil, err
+		}
+
+		if !selector.Matches(labels.Set(podLabels)) {
+			continue
+		}
+
+		policy := NetworkPolicy{
+			Name:        networkPolicy.Name,
+			PolicyTypes: []string{},
+			Ingress:     networkPolicy.Spec.Ingress,
+			Egress:      networkPolicy.Spec.Egress,
+		}
+
+		for _, policyType := range networkPolicy.Spec.PolicyTypes {
+			policy.PolicyTypes = append(policy.PolicyTypes, string(policyType))
+		}
+
+		policies = append(policies, policy)
+	}
+
+	return policies, nil
+}
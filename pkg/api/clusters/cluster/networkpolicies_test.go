@@ -0,0 +1,41 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFilterNetworkPolicies(t *testing.T) {
+	networkPolicies := []networkingv1.NetworkPolicy{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "deny-all"},
+			Spec: networkingv1.NetworkPolicySpec{
+				PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "allow-nginx"},
+			Spec: networkingv1.NetworkPolicySpec{
+				PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "nginx"}},
+				PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+				Ingress:     []networkingv1.NetworkPolicyIngressRule{{}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "allow-redis"},
+			Spec: networkingv1.NetworkPolicySpec{
+				PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "redis"}},
+			},
+		},
+	}
+
+	policies, err := filterNetworkPolicies(networkPolicies, map[string]string{"app": "nginx"})
+	require.NoError(t, err)
+	require.Equal(t, []NetworkPolicy{
+		{Name: "deny-all", PolicyTypes: []string{"Ingress"}},
+		{Name: "allow-nginx", PolicyTypes: []string{"Ingress"}, Ingress: []networkingv1.NetworkPolicyIngressRule{{}}},
+	}, policies)
+}
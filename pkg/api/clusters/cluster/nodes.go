@@ -0,0 +1,136 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// EvictionResult is the result of the eviction of a single Pod during the drain of a node. The status is "evicted" when
+// the Pod was evicted, "skipped" when the Pod is managed by a DaemonSet or is a mirror Pod and "failed" when the Pod
+// could not be evicted. The message contains the reason, why a Pod was skipped or why the eviction failed.
+type EvictionResult struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	Message   string `json:"message,omitempty"`
+}
+
+// CordonNode marks the given node as unschedulable, so that no new Pods are scheduled on this node.
+func (c *Cluster) CordonNode(ctx context.Context, name string) error {
+	return c.setNodeUnschedulable(ctx, name, true)
+}
+
+// UncordonNode marks the given node as schedulable again.
+func (c *Cluster) UncordonNode(ctx context.Context, name string) error {
+	return c.setNodeUnschedulable(ctx, name, false)
+}
+
+// setNodeUnschedulable patches the "spec.unschedulable" field of a node.
+func (c *Cluster) setNodeUnschedulable(ctx context.Context, name string, unschedulable bool) error {
+	patch := []byte(fmt.Sprintf(`{"spec":{"unschedulable":%t}}`, unschedulable))
+
+	_, err := c.clientset.CoreV1().Nodes().Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "node": name, "unschedulable": unschedulable}).Errorf("setNodeUnschedulable")
+		return err
+	}
+
+	return nil
+}
+
+// DrainNode cordons the given node and evicts all Pods, which are running on this node. Pods which are managed by a
+// DaemonSet and mirror Pods are skipped, like it is done by "kubectl drain". We are using the eviction API, so that
+// PodDisruptionBudgets are respected. When an eviction is blocked by a PodDisruptionBudget, we retry the eviction until
+// the given timeout is reached. The grace period is passed to the eviction, when it is greater or equal to 0.
+func (c *Cluster) DrainNode(ctx context.Context, name string, gracePeriodSeconds int64, timeout time.Duration) ([]EvictionResult, error) {
+	if err := c.CordonNode(ctx, name); err != nil {
+		return nil, err
+	}
+
+	pods, err := c.clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: fields.SelectorFromSet(fields.Set{"spec.nodeName": name}).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var results []EvictionResult
+
+	for _, pod := range pods.Items {
+		if reason := skipEviction(pod); reason != "" {
+			results = append(results, EvictionResult{Namespace: pod.Namespace, Name: pod.Name, Status: "skipped", Message: reason})
+			continue
+		}
+
+		if err := c.evictPod(ctx, pod, gracePeriodSeconds); err != nil {
+			results = append(results, EvictionResult{Namespace: pod.Namespace, Name: pod.Name, Status: "failed", Message: err.Error()})
+			continue
+		}
+
+		results = append(results, EvictionResult{Namespace: pod.Namespace, Name: pod.Name, Status: "evicted"})
+	}
+
+	log.WithFields(logrus.Fields{"cluster": c.name, "node": name, "pods": len(results)}).Debugf("Node was drained.")
+
+	return results, nil
+}
+
+// evictPod evicts the given Pod. If the eviction is not allowed because of a PodDisruptionBudget (429 Too Many
+// Requests), we try it again every 5 seconds until the context is canceled.
+func (c *Cluster) evictPod(ctx context.Context, pod corev1.Pod, gracePeriodSeconds int64) error {
+	eviction := &policyv1beta1.Eviction{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pod.Name,
+			Namespace: pod.Namespace,
+		},
+	}
+
+	if gracePeriodSeconds >= 0 {
+		eviction.DeleteOptions = &metav1.DeleteOptions{GracePeriodSeconds: &gracePeriodSeconds}
+	}
+
+	for {
+		err := c.clientset.CoreV1().Pods(pod.Namespace).Evict(ctx, eviction)
+		if err == nil || apierrors.IsNotFound(err) {
+			return nil
+		}
+
+		if !apierrors.IsTooManyRequests(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("eviction was blocked until timeout: %s", err.Error())
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
+// skipEviction returns the reason why a Pod should not be evicted during the drain of a node. If the Pod should be
+// evicted an empty string is returned.
+func skipEviction(pod corev1.Pod) string {
+	if _, ok := pod.Annotations[corev1.MirrorPodAnnotationKey]; ok {
+		return "mirror pod"
+	}
+
+	for _, ownerReference := range pod.OwnerReferences {
+		if ownerReference.Kind == "DaemonSet" {
+			return "managed by DaemonSet"
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,425 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// PodStatus is the compact status of a single Pod, which is sent to the client by the WatchPodStatus function. It only
+// contains the phase, the number of ready containers and the restarts of a Pod, so that the payload for a namespace
+// with a lot of Pods is small. The reason is set, when a container is waiting or terminated with a reason (e.g.
+// "CrashLoopBackOff").
+type PodStatus struct {
+	Namespace  string `json:"namespace"`
+	Name       string `json:"name"`
+	Phase      string `json:"phase"`
+	Ready      int    `json:"ready"`
+	Containers int    `json:"containers"`
+	Restarts   int32  `json:"restarts"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+// PodStatusEvent is a single message of the WatchPodStatus function. The first event has the type "LIST" and contains
+// the status of all Pods. All following events contain the status of a single Pod, which was "ADDED", "MODIFIED" or
+// "DELETED".
+type PodStatusEvent struct {
+	Type string      `json:"type"`
+	Pods []PodStatus `json:"pods"`
+}
+
+// podStatusWriter implements the ResourceEventWriter interface. It converts the resource events for Pods into
+// PodStatusEvents and writes them to the underlying writer. "MODIFIED" events are only sent, when the compact status
+// of a Pod was changed.
+type podStatusWriter struct {
+	writer   ResourceEventWriter
+	statuses map[string]PodStatus
+}
+
+// WriteJSON converts the given ResourceEvent into a PodStatusEvent and writes it to the underlying writer.
+func (w *podStatusWriter) WriteJSON(v interface{}) error {
+	event, ok := v.(ResourceEvent)
+	if !ok {
+		return w.writer.WriteJSON(v)
+	}
+
+	if event.Type == "LIST" {
+		var list corev1.PodList
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(event.Object, &list); err != nil {
+			return err
+		}
+
+		w.statuses = make(map[string]PodStatus)
+		pods := []PodStatus{}
+
+		for _, pod := range list.Items {
+			status := getPodStatus(pod)
+			w.statuses[pod.Namespace+"/"+pod.Name] = status
+			pods = append(pods, status)
+		}
+
+		return w.writer.WriteJSON(PodStatusEvent{Type: event.Type, Pods: pods})
+	}
+
+	var pod corev1.Pod
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(event.Object, &pod); err != nil {
+		return err
+	}
+
+	key := pod.Namespace + "/" + pod.Name
+	status := getPodStatus(pod)
+
+	switch event.Type {
+	case "DELETED":
+		delete(w.statuses, key)
+	case "MODIFIED":
+		if lastStatus, ok := w.statuses[key]; ok && lastStatus == status {
+			return nil
+		}
+
+		w.statuses[key] = status
+	default:
+		w.statuses[key] = status
+	}
+
+	return w.writer.WriteJSON(PodStatusEvent{Type: event.Type, Pods: []PodStatus{status}})
+}
+
+// getPodStatus returns the compact status for the given Pod.
+func getPodStatus(pod corev1.Pod) PodStatus {
+	status := PodStatus{
+		Namespace:  pod.Namespace,
+		Name:       pod.Name,
+		Phase:      string(pod.Status.Phase),
+		Containers: len(pod.Spec.Containers),
+		Reason:     pod.Status.Reason,
+	}
+
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		if containerStatus.Ready {
+			status.Ready = status.Ready + 1
+		}
+
+		status.Restarts = status.Restarts + containerStatus.RestartCount
+
+		if containerStatus.State.Waiting != nil && containerStatus.State.Waiting.Reason != "" {
+			status.Reason = containerStatus.State.Waiting.Reason
+		} else if containerStatus.State.Terminated != nil && containerStatus.State.Terminated.Reason != "" {
+			status.Reason = containerStatus.State.Terminated.Reason
+		}
+	}
+
+	if pod.DeletionTimestamp != nil {
+		status.Reason = "Terminating"
+	}
+
+	return status
+}
+
+// WatchPodStatus watches all Pods in the given namespace and sends the compact status of the Pods via the passed in
+// writer. It uses the WatchResources function, so that the watch is restarted in the same way, when the Kubernetes API
+// server closes the watch or when the resource version is expired.
+func (c *Cluster) WatchPodStatus(ctx context.Context, writer ResourceEventWriter, namespace string) error {
+	return c.WatchResources(ctx, &podStatusWriter{writer: writer}, namespace, "/api/v1", "pods", "", "")
+}
+
+// GetContainerName returns the name of the container, which should be used to get the logs of a Pod. The container is
+// selected by its name first. When no container with the given name exists or when the name is ambiguous (e.g. an init
+// container and a regular container with the same name), the container is selected by the given index. The index
+// refers to the list of all containers of the Pod, where the init containers are followed by the regular containers.
+func (c *Cluster) GetContainerName(ctx context.Context, namespace, name, container string, index int) (string, error) {
+	pod, err := c.clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "name": name}).Errorf("GetContainerName")
+		return "", err
+	}
+
+	return getContainerName(pod, container, index)
+}
+
+// getContainerName selects a container of the given Pod by its name or index, see GetContainerName.
+func getContainerName(pod *corev1.Pod, container string, index int) (string, error) {
+	var containers []string
+	for _, initContainer := range pod.Spec.InitContainers {
+		containers = append(containers, initContainer.Name)
+	}
+	for _, regularContainer := range pod.Spec.Containers {
+		containers = append(containers, regularContainer.Name)
+	}
+
+	if container != "" {
+		matches := 0
+		for _, c := range containers {
+			if c == container {
+				matches = matches + 1
+			}
+		}
+
+		if matches == 1 {
+			return container, nil
+		}
+	}
+
+	if index < 0 || index >= len(containers) {
+		return "", fmt.Errorf("container index %d is out of range, pod %s has %d containers", index, pod.Name, len(containers))
+	}
+
+	return containers[index], nil
+}
+
+// ContainerEnv is the resolved environment of a single container of a Pod.
+type ContainerEnv struct {
+	Container string   `json:"container"`
+	Env       []EnvVar `json:"env"`
+}
+
+// EnvVar is a single resolved environment variable. The source field contains the reference, from which the value was
+// resolved (e.g. "configmap:my-config/key"). When the value of a Secret was not resolved, the value is empty and the
+// masked field is true.
+type EnvVar struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Source string `json:"source,omitempty"`
+	Masked bool   `json:"masked,omitempty"`
+}
+
+// envResolver resolves the references of environment variables. The ConfigMaps and Secrets are cached, so that each
+// object is only retrieved once per Pod.
+type envResolver struct {
+	cluster        *Cluster
+	namespace      string
+	resolveSecrets bool
+	configMaps     map[string]*corev1.ConfigMap
+	secrets        map[string]*corev1.Secret
+}
+
+// GetContainerEnv returns the environment of all containers of the given Pod. The values which are referenced via
+// ConfigMaps, Secrets or fields of the Pod are resolved. The values of Secrets are only resolved when resolveSecrets is
+// true, otherwise they are masked.
+func (c *Cluster) GetContainerEnv(ctx context.Context, namespace, name string, resolveSecrets bool) ([]ContainerEnv, error) {
+	pod, err := c.clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "name": name}).Errorf("GetContainerEnv")
+		return nil, err
+	}
+
+	resolver := &envResolver{
+		cluster:        c,
+		namespace:      namespace,
+		resolveSecrets: resolveSecrets,
+		configMaps:     make(map[string]*corev1.ConfigMap),
+		secrets:        make(map[string]*corev1.Secret),
+	}
+
+	var containers []ContainerEnv
+	for _, container := range append(pod.Spec.InitContainers, pod.Spec.Containers...) {
+		env, err := resolver.resolve(ctx, pod, container)
+		if err != nil {
+			log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "name": name, "container": container.Name}).Errorf("GetContainerEnv")
+			return nil, err
+		}
+
+		containers = append(containers, ContainerEnv{Container: container.Name, Env: env})
+	}
+
+	return containers, nil
+}
+
+// resolve returns the resolved environment variables of the given container. Like in Kubernetes the variables from the
+// "envFrom" field are added first and can be overwritten by the variables from the "env" field.
+func (r *envResolver) resolve(ctx context.Context, pod *corev1.Pod, container corev1.Container) ([]EnvVar, error) {
+	var env []EnvVar
+
+	for _, envFrom := range container.EnvFrom {
+		if envFrom.ConfigMapRef != nil {
+			configMap, err := r.getConfigMap(ctx, envFrom.ConfigMapRef.Name, isOptional(envFrom.ConfigMapRef.Optional))
+			if err != nil {
+				return nil, err
+			}
+
+			if configMap != nil {
+				for key, value := range configMap.Data {
+					env = setEnvVar(env, EnvVar{Name: envFrom.Prefix + key, Value: value, Source: fmt.Sprintf("configmap:%s/%s", configMap.Name, key)})
+				}
+			}
+		}
+
+		// When secrets should not be resolved, we do not know the keys of the Secret, so that we add a single masked
+		// variable, which references the Secret.
+		if envFrom.SecretRef != nil && !r.resolveSecrets {
+			env = setEnvVar(env, EnvVar{Name: envFrom.Prefix + "*", Source: fmt.Sprintf("secret:%s", envFrom.SecretRef.Name), Masked: true})
+		} else if envFrom.SecretRef != nil {
+			secret, err := r.getSecret(ctx, envFrom.SecretRef.Name, isOptional(envFrom.SecretRef.Optional))
+			if err != nil {
+				return nil, err
+			}
+
+			if secret != nil {
+				for key, value := range secret.Data {
+					env = setEnvVar(env, r.secretEnvVar(envFrom.Prefix+key, secret.Name, key, value))
+				}
+			}
+		}
+	}
+
+	sort.Slice(env, func(i, j int) bool {
+		return env[i].Name < env[j].Name
+	})
+
+	for _, envVar := range container.Env {
+		if envVar.ValueFrom == nil {
+			env = setEnvVar(env, EnvVar{Name: envVar.Name, Value: envVar.Value})
+			continue
+		}
+
+		switch {
+		case envVar.ValueFrom.ConfigMapKeyRef != nil:
+			ref := envVar.ValueFrom.ConfigMapKeyRef
+			configMap, err := r.getConfigMap(ctx, ref.Name, isOptional(ref.Optional))
+			if err != nil {
+				return nil, err
+			}
+
+			var value string
+			if configMap != nil {
+				value = configMap.Data[ref.Key]
+			}
+
+			env = setEnvVar(env, EnvVar{Name: envVar.Name, Value: value, Source: fmt.Sprintf("configmap:%s/%s", ref.Name, ref.Key)})
+		case envVar.ValueFrom.SecretKeyRef != nil:
+			ref := envVar.ValueFrom.SecretKeyRef
+			secret, err := r.getSecret(ctx, ref.Name, isOptional(ref.Optional))
+			if err != nil {
+				return nil, err
+			}
+
+			var value []byte
+			if secret != nil {
+				value = secret.Data[ref.Key]
+			}
+
+			env = setEnvVar(env, r.secretEnvVar(envVar.Name, ref.Name, ref.Key, value))
+		case envVar.ValueFrom.FieldRef != nil:
+			env = setEnvVar(env, EnvVar{Name: envVar.Name, Value: getPodFieldValue(pod, envVar.ValueFrom.FieldRef.FieldPath), Source: "field:" + envVar.ValueFrom.FieldRef.FieldPath})
+		case envVar.ValueFrom.ResourceFieldRef != nil:
+			env = setEnvVar(env, EnvVar{Name: envVar.Name, Source: "resource:" + envVar.ValueFrom.ResourceFieldRef.Resource})
+		}
+	}
+
+	return env, nil
+}
+
+// secretEnvVar returns the environment variable for the given key of a Secret. If secrets should not be resolved, the
+// value is masked.
+func (r *envResolver) secretEnvVar(name, secretName, key string, value []byte) EnvVar {
+	envVar := EnvVar{Name: name, Source: fmt.Sprintf("secret:%s/%s", secretName, key)}
+	if r.resolveSecrets {
+		envVar.Value = string(value)
+	} else {
+		envVar.Masked = true
+	}
+
+	return envVar
+}
+
+// getConfigMap returns the ConfigMap with the given name. If the ConfigMap doesn't exist and the reference is optional,
+// nil is returned.
+func (r *envResolver) getConfigMap(ctx context.Context, name string, optional bool) (*corev1.ConfigMap, error) {
+	if configMap, ok := r.configMaps[name]; ok {
+		return configMap, nil
+	}
+
+	configMap, err := r.cluster.clientset.CoreV1().ConfigMaps(r.namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if optional && apierrors.IsNotFound(err) {
+			r.configMaps[name] = nil
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	r.configMaps[name] = configMap
+	return configMap, nil
+}
+
+// getSecret returns the Secret with the given name. The Secret is only retrieved when secrets should be resolved,
+// otherwise an empty Secret is returned. If the Secret doesn't exist and the reference is optional, nil is returned.
+func (r *envResolver) getSecret(ctx context.Context, name string, optional bool) (*corev1.Secret, error) {
+	if !r.resolveSecrets {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name}}, nil
+	}
+
+	if secret, ok := r.secrets[name]; ok {
+		return secret, nil
+	}
+
+	secret, err := r.cluster.clientset.CoreV1().Secrets(r.namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if optional && apierrors.IsNotFound(err) {
+			r.secrets[name] = nil
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	r.secrets[name] = secret
+	return secret, nil
+}
+
+// setEnvVar adds the given environment variable to the list of variables. If a variable with the same name already
+// exists, it is replaced.
+func setEnvVar(env []EnvVar, envVar EnvVar) []EnvVar {
+	for i := range env {
+		if env[i].Name == envVar.Name {
+			env[i] = envVar
+			return env
+		}
+	}
+
+	return append(env, envVar)
+}
+
+// isOptional returns the value of the optional field of a ConfigMap or Secret reference.
+func isOptional(optional *bool) bool {
+	return optional != nil && *optional
+}
+
+// getPodFieldValue returns the value for the given field path of a Pod, like it is done by the Kubelet for the downward
+// API. Only the most common fields are supported, for all other fields an empty string is returned.
+func getPodFieldValue(pod *corev1.Pod, fieldPath string) string {
+	switch fieldPath {
+	case "metadata.name":
+		return pod.Name
+	case "metadata.namespace":
+		return pod.Namespace
+	case "metadata.uid":
+		return string(pod.UID)
+	case "spec.nodeName":
+		return pod.Spec.NodeName
+	case "spec.serviceAccountName":
+		return pod.Spec.ServiceAccountName
+	case "status.hostIP":
+		return pod.Status.HostIP
+	case "status.podIP":
+		return pod.Status.PodIP
+	}
+
+	if strings.HasPrefix(fieldPath, "metadata.labels['") && strings.HasSuffix(fieldPath, "']") {
+		return pod.Labels[strings.TrimSuffix(strings.TrimPrefix(fieldPath, "metadata.labels['"), "']")]
+	}
+
+	if strings.HasPrefix(fieldPath, "metadata.annotations['") && strings.HasSuffix(fieldPath, "']") {
+		return pod.Annotations[strings.TrimSuffix(strings.TrimPrefix(fieldPath, "metadata.annotations['"), "']")]
+	}
+
+	return ""
+}
@@ -0,0 +1,95 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type testEventWriter struct {
+	events []interface{}
+}
+
+func (w *testEventWriter) WriteJSON(v interface{}) error {
+	w.events = append(w.events, v)
+	return nil
+}
+
+func TestPodStatusWriter(t *testing.T) {
+	pod := func(phase string, ready bool, restarts int64) map[string]interface{} {
+		return map[string]interface{}{
+			"metadata": map[string]interface{}{"namespace": "default", "name": "nginx"},
+			"spec":     map[string]interface{}{"containers": []interface{}{map[string]interface{}{"name": "nginx"}}},
+			"status": map[string]interface{}{
+				"phase":             phase,
+				"containerStatuses": []interface{}{map[string]interface{}{"name": "nginx", "ready": ready, "restartCount": restarts}},
+			},
+		}
+	}
+
+	writer := &testEventWriter{}
+	podWriter := &podStatusWriter{writer: writer}
+
+	require.NoError(t, podWriter.WriteJSON(ResourceEvent{Type: "LIST", Object: map[string]interface{}{"items": []interface{}{pod("Pending", false, 0)}}}))
+	require.NoError(t, podWriter.WriteJSON(ResourceEvent{Type: "MODIFIED", Object: pod("Running", true, 0)}))
+	require.NoError(t, podWriter.WriteJSON(ResourceEvent{Type: "MODIFIED", Object: pod("Running", true, 0)}))
+	require.NoError(t, podWriter.WriteJSON(ResourceEvent{Type: "MODIFIED", Object: pod("Running", true, 1)}))
+	require.NoError(t, podWriter.WriteJSON(ResourceEvent{Type: "DELETED", Object: pod("Running", true, 1)}))
+
+	require.Equal(t, []interface{}{
+		PodStatusEvent{Type: "LIST", Pods: []PodStatus{{Namespace: "default", Name: "nginx", Phase: "Pending", Ready: 0, Containers: 1, Restarts: 0}}},
+		PodStatusEvent{Type: "MODIFIED", Pods: []PodStatus{{Namespace: "default", Name: "nginx", Phase: "Running", Ready: 1, Containers: 1, Restarts: 0}}},
+		PodStatusEvent{Type: "MODIFIED", Pods: []PodStatus{{Namespace: "default", Name: "nginx", Phase: "Running", Ready: 1, Containers: 1, Restarts: 1}}},
+		PodStatusEvent{Type: "DELETED", Pods: []PodStatus{{Namespace: "default", Name: "nginx", Phase: "Running", Ready: 1, Containers: 1, Restarts: 1}}},
+	}, writer.events)
+}
+
+func TestGetContainerName(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx"},
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "nginx"}},
+			Containers:     []corev1.Container{{Name: "nginx"}, {Name: "sidecar"}},
+		},
+	}
+
+	for _, tc := range []struct {
+		name      string
+		container string
+		index     int
+		expect    string
+		isError   bool
+	}{
+		{name: "unique name", container: "sidecar", index: 0, expect: "sidecar"},
+		{name: "ambiguous name", container: "nginx", index: 1, expect: "nginx"},
+		{name: "index only", container: "", index: 2, expect: "sidecar"},
+		{name: "index out of range", container: "nginx", index: 3, isError: true},
+		{name: "negative index", container: "", index: -1, isError: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := getContainerName(pod, tc.container, tc.index)
+			if tc.isError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.expect, actual)
+			}
+		})
+	}
+}
+
+func TestGetPodFieldValue(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "default", Labels: map[string]string{"app": "nginx"}},
+		Spec:       corev1.PodSpec{NodeName: "node1"},
+	}
+
+	require.Equal(t, "nginx", getPodFieldValue(pod, "metadata.name"))
+	require.Equal(t, "default", getPodFieldValue(pod, "metadata.namespace"))
+	require.Equal(t, "node1", getPodFieldValue(pod, "spec.nodeName"))
+	require.Equal(t, "nginx", getPodFieldValue(pod, "metadata.labels['app']"))
+	require.Equal(t, "", getPodFieldValue(pod, "metadata.labels['version']"))
+	require.Equal(t, "", getPodFieldValue(pod, "status.phase"))
+}
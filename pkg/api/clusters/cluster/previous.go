@@ -0,0 +1,92 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ErrNoPreviousContainer is returned by GetPreviousLogs, when the container wasn't terminated before, so that there are
+// no logs of a previous container instance.
+var ErrNoPreviousContainer = errors.New("container has no previous instance")
+
+// PreviousLogs contains the logs of the last terminated instance of a container together with the reason why it was
+// terminated.
+type PreviousLogs struct {
+	Container  string `json:"container"`
+	ExitCode   int32  `json:"exitCode"`
+	Signal     int32  `json:"signal,omitempty"`
+	Reason     string `json:"reason"`
+	Message    string `json:"message,omitempty"`
+	StartedAt  string `json:"startedAt,omitempty"`
+	FinishedAt string `json:"finishedAt,omitempty"`
+	Logs       string `json:"logs"`
+}
+
+// GetPreviousLogs returns the last lines of the logs of the previous instance of a container, together with the last
+// termination state of the container (exit code, reason and finish time). If the container wasn't terminated before,
+// the ErrNoPreviousContainer error is returned.
+func (c *Cluster) GetPreviousLogs(ctx context.Context, namespace, name, container string, tail int64) (*PreviousLogs, error) {
+	pod, err := c.clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "name": name, "container": container}).Errorf("GetPreviousLogs")
+		return nil, err
+	}
+
+	previousLogs, err := getLastTerminationState(pod, container)
+	if err != nil {
+		return nil, err
+	}
+
+	logs, err := c.GetLogs(ctx, namespace, name, container, nil, 0, tail, true, lineTerminators["lf"])
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "name": name, "container": container}).Errorf("GetPreviousLogs")
+		return nil, err
+	}
+
+	previousLogs.Logs = logs
+	return previousLogs, nil
+}
+
+// getLastTerminationState returns the last termination state of the given container of a Pod. The container can be a
+// regular or an init container.
+func getLastTerminationState(pod *corev1.Pod, container string) (*PreviousLogs, error) {
+	var statuses []corev1.ContainerStatus
+	statuses = append(statuses, pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, pod.Status.ContainerStatuses...)
+
+	for _, status := range statuses {
+		if status.Name != container {
+			continue
+		}
+
+		terminated := status.LastTerminationState.Terminated
+		if terminated == nil {
+			return nil, ErrNoPreviousContainer
+		}
+
+		previousLogs := &PreviousLogs{
+			Container: container,
+			ExitCode:  terminated.ExitCode,
+			Signal:    terminated.Signal,
+			Reason:    terminated.Reason,
+			Message:   terminated.Message,
+		}
+
+		if !terminated.StartedAt.IsZero() {
+			previousLogs.StartedAt = terminated.StartedAt.Format(time.RFC3339)
+		}
+
+		if !terminated.FinishedAt.IsZero() {
+			previousLogs.FinishedAt = terminated.FinishedAt.Format(time.RFC3339)
+		}
+
+		return previousLogs, nil
+	}
+
+	return nil, ErrNoPreviousContainer
+}
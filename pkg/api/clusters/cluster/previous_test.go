@@ -0,0 +1,42 @@
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetLastTerminationState(t *testing.T) {
+	finishedAt := metav1.NewTime(time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC))
+
+	pod := &corev1.Pod{
+		Status: corev1.PodStatus{
+			InitContainerStatuses: []corev1.ContainerStatus{{Name: "init"}},
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name: "nginx",
+				LastTerminationState: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{ExitCode: 137, Reason: "OOMKilled", FinishedAt: finishedAt},
+				},
+			}},
+		},
+	}
+
+	t.Run("terminated container", func(t *testing.T) {
+		previousLogs, err := getLastTerminationState(pod, "nginx")
+		require.NoError(t, err)
+		require.Equal(t, &PreviousLogs{Container: "nginx", ExitCode: 137, Reason: "OOMKilled", FinishedAt: "2021-10-01T12:00:00Z"}, previousLogs)
+	})
+
+	t.Run("container without previous instance", func(t *testing.T) {
+		_, err := getLastTerminationState(pod, "init")
+		require.Equal(t, ErrNoPreviousContainer, err)
+	})
+
+	t.Run("unknown container", func(t *testing.T) {
+		_, err := getLastTerminationState(pod, "redis")
+		require.Equal(t, ErrNoPreviousContainer, err)
+	})
+}
@@ -0,0 +1,204 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+// maxProbeEvents is the maximum number of probe events, which are returned for a single container.
+const maxProbeEvents = 10
+
+// PodProbes contains the probes of all containers of a Pod and the conditions of the Pod, which are affected by the
+// probes (e.g. "Ready" and "ContainersReady").
+type PodProbes struct {
+	Conditions []PodCondition    `json:"conditions"`
+	Containers []ContainerProbes `json:"containers"`
+}
+
+// PodCondition is a single condition of a Pod.
+type PodCondition struct {
+	Type               string `json:"type"`
+	Status             string `json:"status"`
+	Reason             string `json:"reason,omitempty"`
+	Message            string `json:"message,omitempty"`
+	LastTransitionTime string `json:"lastTransitionTime,omitempty"`
+}
+
+// ContainerProbes contains the liveness, readiness and startup probe of a container, together with the current ready
+// and started state of the container and the latest events for failed probes.
+type ContainerProbes struct {
+	Container string  `json:"container"`
+	Ready     bool    `json:"ready"`
+	Started   *bool   `json:"started,omitempty"`
+	Liveness  *Probe  `json:"liveness,omitempty"`
+	Readiness *Probe  `json:"readiness,omitempty"`
+	Startup   *Probe  `json:"startup,omitempty"`
+	Events    []Event `json:"events"`
+}
+
+// Probe is the definition of a single probe. The type is "httpGet", "tcpSocket" or "exec" and the action is a human
+// readable description of what is checked by the probe (e.g. "GET http://:8080/healthz").
+type Probe struct {
+	Type                string `json:"type"`
+	Action              string `json:"action"`
+	InitialDelaySeconds int32  `json:"initialDelaySeconds"`
+	TimeoutSeconds      int32  `json:"timeoutSeconds"`
+	PeriodSeconds       int32  `json:"periodSeconds"`
+	SuccessThreshold    int32  `json:"successThreshold"`
+	FailureThreshold    int32  `json:"failureThreshold"`
+}
+
+// Event is a single Kubernetes event of an object, e.g. an event for a failed probe of a container or a scaling event
+// of a HorizontalPodAutoscaler.
+type Event struct {
+	Reason        string `json:"reason"`
+	Message       string `json:"message"`
+	Count         int32  `json:"count"`
+	LastTimestamp string `json:"lastTimestamp"`
+}
+
+// GetPodProbes returns the probe definitions of all containers of the given Pod together with the latest events for
+// failed probes. The events are created by the kubelet with the reason "Unhealthy" (or "ProbeWarning") and are
+// assigned to the container via the field path of the involved object.
+func (c *Cluster) GetPodProbes(ctx context.Context, namespace, name string) (*PodProbes, error) {
+	pod, err := c.clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "name": name}).Errorf("GetPodProbes")
+		return nil, err
+	}
+
+	events, err := c.clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.SelectorFromSet(fields.Set{"involvedObject.kind": "Pod", "involvedObject.name": name}).String(),
+	})
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "name": name}).Errorf("GetPodProbes")
+		return nil, err
+	}
+
+	return getPodProbes(pod, events.Items), nil
+}
+
+// getPodProbes returns the probes for the given Pod and the list of events for the Pod, see GetPodProbes.
+func getPodProbes(pod *corev1.Pod, events []corev1.Event) *PodProbes {
+	podProbes := &PodProbes{Conditions: []PodCondition{}, Containers: []ContainerProbes{}}
+
+	for _, condition := range pod.Status.Conditions {
+		podCondition := PodCondition{
+			Type:    string(condition.Type),
+			Status:  string(condition.Status),
+			Reason:  condition.Reason,
+			Message: condition.Message,
+		}
+
+		if !condition.LastTransitionTime.IsZero() {
+			podCondition.LastTransitionTime = condition.LastTransitionTime.Format(time.RFC3339)
+		}
+
+		podProbes.Conditions = append(podProbes.Conditions, podCondition)
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return getEventTime(events[i]).After(getEventTime(events[j]))
+	})
+
+	for _, container := range pod.Spec.Containers {
+		containerProbes := ContainerProbes{
+			Container: container.Name,
+			Liveness:  getProbe(container.LivenessProbe),
+			Readiness: getProbe(container.ReadinessProbe),
+			Startup:   getProbe(container.StartupProbe),
+			Events:    []Event{},
+		}
+
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Name == container.Name {
+				containerProbes.Ready = status.Ready
+				containerProbes.Started = status.Started
+			}
+		}
+
+		fieldPath := fmt.Sprintf("spec.containers{%s}", container.Name)
+		for _, event := range events {
+			if len(containerProbes.Events) >= maxProbeEvents {
+				break
+			}
+
+			if event.InvolvedObject.FieldPath != fieldPath || (event.Reason != "Unhealthy" && event.Reason != "ProbeWarning") {
+				continue
+			}
+
+			containerProbes.Events = append(containerProbes.Events, getEvent(event))
+		}
+
+		podProbes.Containers = append(podProbes.Containers, containerProbes)
+	}
+
+	return podProbes
+}
+
+// getProbe converts the given Kubernetes probe into our Probe format. If the container doesn't define the probe, nil
+// is returned.
+func getProbe(probe *corev1.Probe) *Probe {
+	if probe == nil {
+		return nil
+	}
+
+	p := &Probe{
+		InitialDelaySeconds: probe.InitialDelaySeconds,
+		TimeoutSeconds:      probe.TimeoutSeconds,
+		PeriodSeconds:       probe.PeriodSeconds,
+		SuccessThreshold:    probe.SuccessThreshold,
+		FailureThreshold:    probe.FailureThreshold,
+	}
+
+	switch {
+	case probe.HTTPGet != nil:
+		scheme := strings.ToLower(string(probe.HTTPGet.Scheme))
+		if scheme == "" {
+			scheme = "http"
+		}
+
+		p.Type = "httpGet"
+		p.Action = fmt.Sprintf("GET %s://%s:%s%s", scheme, probe.HTTPGet.Host, probe.HTTPGet.Port.String(), probe.HTTPGet.Path)
+	case probe.TCPSocket != nil:
+		p.Type = "tcpSocket"
+		p.Action = fmt.Sprintf("tcp %s:%s", probe.TCPSocket.Host, probe.TCPSocket.Port.String())
+	case probe.Exec != nil:
+		p.Type = "exec"
+		p.Action = strings.Join(probe.Exec.Command, " ")
+	}
+
+	return p
+}
+
+// getEvent converts the given Kubernetes event into our Event format.
+func getEvent(event corev1.Event) Event {
+	return Event{
+		Reason:        event.Reason,
+		Message:       event.Message,
+		Count:         event.Count,
+		LastTimestamp: getEventTime(event).Format(time.RFC3339),
+	}
+}
+
+// getEventTime returns the time of the last occurrence of the given event. For events created via the new events API
+// the last timestamp might be empty, so that we fall back to the event time and the creation time.
+func getEventTime(event corev1.Event) time.Time {
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	}
+
+	if !event.EventTime.IsZero() {
+		return event.EventTime.Time
+	}
+
+	return event.CreationTimestamp.Time
+}
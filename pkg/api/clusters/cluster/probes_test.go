@@ -0,0 +1,59 @@
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestGetPodProbes(t *testing.T) {
+	started := true
+	now := time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx"},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name: "nginx",
+				ReadinessProbe: &corev1.Probe{
+					Handler:          corev1.Handler{HTTPGet: &corev1.HTTPGetAction{Path: "/healthz", Port: intstr.FromInt(8080)}},
+					PeriodSeconds:    10,
+					FailureThreshold: 3,
+				},
+				LivenessProbe: &corev1.Probe{
+					Handler: corev1.Handler{TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromString("http")}},
+				},
+			}},
+		},
+		Status: corev1.PodStatus{
+			Conditions:        []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse, Reason: "ContainersNotReady"}},
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "nginx", Ready: false, Started: &started}},
+		},
+	}
+
+	events := []corev1.Event{
+		{Reason: "Unhealthy", Message: "Readiness probe failed: old", Count: 1, LastTimestamp: metav1.NewTime(now.Add(-time.Minute)), InvolvedObject: corev1.ObjectReference{FieldPath: "spec.containers{nginx}"}},
+		{Reason: "Unhealthy", Message: "Readiness probe failed: new", Count: 5, LastTimestamp: metav1.NewTime(now), InvolvedObject: corev1.ObjectReference{FieldPath: "spec.containers{nginx}"}},
+		{Reason: "Pulled", Message: "Container image already present", InvolvedObject: corev1.ObjectReference{FieldPath: "spec.containers{nginx}"}},
+		{Reason: "Unhealthy", Message: "Liveness probe failed", InvolvedObject: corev1.ObjectReference{FieldPath: "spec.containers{sidecar}"}},
+	}
+
+	require.Equal(t, &PodProbes{
+		Conditions: []PodCondition{{Type: "Ready", Status: "False", Reason: "ContainersNotReady"}},
+		Containers: []ContainerProbes{{
+			Container: "nginx",
+			Ready:     false,
+			Started:   &started,
+			Liveness:  &Probe{Type: "tcpSocket", Action: "tcp :http"},
+			Readiness: &Probe{Type: "httpGet", Action: "GET http://:8080/healthz", PeriodSeconds: 10, FailureThreshold: 3},
+			Events: []Event{
+				{Reason: "Unhealthy", Message: "Readiness probe failed: new", Count: 5, LastTimestamp: "2021-10-01T12:00:00Z"},
+				{Reason: "Unhealthy", Message: "Readiness probe failed: old", Count: 1, LastTimestamp: "2021-10-01T11:59:00Z"},
+			},
+		}},
+	}, getPodProbes(pod, events))
+}
@@ -0,0 +1,134 @@
+package cluster
+
+import (
+	"context"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PVC is the status of a single PersistentVolumeClaim. The requested field is the requested storage from the spec of
+// the claim and the capacity is the actual capacity of the bound PersistentVolume. The volume field is only set, when
+// kobs is allowed to list the PersistentVolumes of the cluster.
+type PVC struct {
+	Namespace    string   `json:"namespace"`
+	Name         string   `json:"name"`
+	Status       string   `json:"status"`
+	StorageClass string   `json:"storageClass"`
+	AccessModes  []string `json:"accessModes"`
+	Requested    string   `json:"requested"`
+	Capacity     string   `json:"capacity"`
+	VolumeName   string   `json:"volumeName"`
+	Volume       *PV      `json:"volume,omitempty"`
+}
+
+// PV contains the details of the PersistentVolume, which is bound to a PersistentVolumeClaim. The source is the type of
+// the underlying storage (e.g. "awsElasticBlockStore") and the id is the identifier of the volume in this storage
+// (e.g. the EBS volume id).
+type PV struct {
+	ReclaimPolicy string `json:"reclaimPolicy"`
+	Source        string `json:"source"`
+	ID            string `json:"id"`
+}
+
+// GetPVCs returns all PersistentVolumeClaims in the given namespace. If the namespace is empty the claims from all
+// namespaces are returned. We also try to get the PersistentVolumes of the cluster to add the source of the bound
+// volume to each claim. Since PersistentVolumes are cluster scoped, kobs might not be allowed to list them, so that an
+// error is only logged and the claims are returned without the volume details.
+func (c *Cluster) GetPVCs(ctx context.Context, namespace string) ([]PVC, error) {
+	pvcList, err := c.clientset.CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace}).Errorf("GetPVCs")
+		return nil, err
+	}
+
+	pvs := make(map[string]corev1.PersistentVolume)
+	pvList, err := c.clientset.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name}).Debugf("GetPVCs: could not get persistent volumes")
+	} else {
+		for _, pv := range pvList.Items {
+			pvs[pv.Name] = pv
+		}
+	}
+
+	pvcs := make([]PVC, 0, len(pvcList.Items))
+	for _, pvc := range pvcList.Items {
+		pvcs = append(pvcs, getPVC(pvc, pvs))
+	}
+
+	sort.Slice(pvcs, func(i, j int) bool {
+		if pvcs[i].Namespace != pvcs[j].Namespace {
+			return pvcs[i].Namespace < pvcs[j].Namespace
+		}
+		return pvcs[i].Name < pvcs[j].Name
+	})
+
+	return pvcs, nil
+}
+
+// getPVC converts the given PersistentVolumeClaim into our PVC structure. When the bound volume is contained in the
+// given map of PersistentVolumes, the details of the volume are added.
+func getPVC(pvc corev1.PersistentVolumeClaim, pvs map[string]corev1.PersistentVolume) PVC {
+	item := PVC{
+		Namespace:   pvc.Namespace,
+		Name:        pvc.Name,
+		Status:      string(pvc.Status.Phase),
+		AccessModes: []string{},
+		VolumeName:  pvc.Spec.VolumeName,
+	}
+
+	if pvc.Spec.StorageClassName != nil {
+		item.StorageClass = *pvc.Spec.StorageClassName
+	}
+
+	for _, accessMode := range pvc.Spec.AccessModes {
+		item.AccessModes = append(item.AccessModes, string(accessMode))
+	}
+
+	if requested, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+		item.Requested = requested.String()
+	}
+
+	if capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok {
+		item.Capacity = capacity.String()
+	}
+
+	if pv, ok := pvs[pvc.Spec.VolumeName]; ok && pvc.Spec.VolumeName != "" {
+		source, id := getVolumeSource(pv.Spec.PersistentVolumeSource)
+		item.Volume = &PV{
+			ReclaimPolicy: string(pv.Spec.PersistentVolumeReclaimPolicy),
+			Source:        source,
+			ID:            id,
+		}
+	}
+
+	return item
+}
+
+// getVolumeSource returns the type and the identifier of the storage, which backs a PersistentVolume. For unknown
+// sources the type is "unknown" and the identifier is empty.
+func getVolumeSource(source corev1.PersistentVolumeSource) (string, string) {
+	switch {
+	case source.CSI != nil:
+		return "csi:" + source.CSI.Driver, source.CSI.VolumeHandle
+	case source.AWSElasticBlockStore != nil:
+		return "awsElasticBlockStore", source.AWSElasticBlockStore.VolumeID
+	case source.GCEPersistentDisk != nil:
+		return "gcePersistentDisk", source.GCEPersistentDisk.PDName
+	case source.AzureDisk != nil:
+		return "azureDisk", source.AzureDisk.DataDiskURI
+	case source.AzureFile != nil:
+		return "azureFile", source.AzureFile.ShareName
+	case source.NFS != nil:
+		return "nfs", source.NFS.Server + ":" + source.NFS.Path
+	case source.HostPath != nil:
+		return "hostPath", source.HostPath.Path
+	case source.Local != nil:
+		return "local", source.Local.Path
+	default:
+		return "unknown", ""
+	}
+}
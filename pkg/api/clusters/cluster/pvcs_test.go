@@ -0,0 +1,71 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetPVC(t *testing.T) {
+	storageClass := "gp2"
+	pvc := corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "data"},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			StorageClassName: &storageClass,
+			VolumeName:       "pvc-1234",
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+			},
+		},
+		Status: corev1.PersistentVolumeClaimStatus{
+			Phase:    corev1.ClaimBound,
+			Capacity: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("20Gi")},
+		},
+	}
+
+	pvs := map[string]corev1.PersistentVolume{
+		"pvc-1234": {
+			ObjectMeta: metav1.ObjectMeta{Name: "pvc-1234"},
+			Spec: corev1.PersistentVolumeSpec{
+				PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimDelete,
+				PersistentVolumeSource: corev1.PersistentVolumeSource{
+					AWSElasticBlockStore: &corev1.AWSElasticBlockStoreVolumeSource{VolumeID: "vol-0123456789"},
+				},
+			},
+		},
+	}
+
+	t.Run("with volume", func(t *testing.T) {
+		require.Equal(t, PVC{
+			Namespace:    "default",
+			Name:         "data",
+			Status:       "Bound",
+			StorageClass: "gp2",
+			AccessModes:  []string{"ReadWriteOnce"},
+			Requested:    "10Gi",
+			Capacity:     "20Gi",
+			VolumeName:   "pvc-1234",
+			Volume:       &PV{ReclaimPolicy: "Delete", Source: "awsElasticBlockStore", ID: "vol-0123456789"},
+		}, getPVC(pvc, pvs))
+	})
+
+	t.Run("without volume", func(t *testing.T) {
+		actualPVC := getPVC(pvc, nil)
+		require.Equal(t, "pvc-1234", actualPVC.VolumeName)
+		require.Nil(t, actualPVC.Volume)
+	})
+}
+
+func TestGetVolumeSource(t *testing.T) {
+	source, id := getVolumeSource(corev1.PersistentVolumeSource{CSI: &corev1.CSIPersistentVolumeSource{Driver: "ebs.csi.aws.com", VolumeHandle: "vol-0123456789"}})
+	require.Equal(t, "csi:ebs.csi.aws.com", source)
+	require.Equal(t, "vol-0123456789", id)
+
+	source, id = getVolumeSource(corev1.PersistentVolumeSource{})
+	require.Equal(t, "unknown", source)
+	require.Equal(t, "", id)
+}
@@ -0,0 +1,120 @@
+package cluster
+
+import (
+	"context"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NamespaceQuotas contains the ResourceQuotas and LimitRanges of a namespace. When a namespace doesn't have any quotas
+// or limit ranges the corresponding fields are empty lists.
+type NamespaceQuotas struct {
+	Quotas      []Quota      `json:"quotas"`
+	LimitRanges []LimitRange `json:"limitRanges"`
+}
+
+// Quota is the status of a single ResourceQuota. The resources field contains the used and hard limit for each
+// resource, which is limited by the quota.
+type Quota struct {
+	Name      string          `json:"name"`
+	Resources []QuotaResource `json:"resources"`
+}
+
+// QuotaResource is the usage of a single resource (e.g. "requests.cpu") of a ResourceQuota.
+type QuotaResource struct {
+	Name string `json:"name"`
+	Used string `json:"used"`
+	Hard string `json:"hard"`
+}
+
+// LimitRange is a single LimitRange with all its limits.
+type LimitRange struct {
+	Name   string           `json:"name"`
+	Limits []LimitRangeItem `json:"limits"`
+}
+
+// LimitRangeItem contains the default values and the minimum and maximum values for a single type (e.g. "Container") of
+// a LimitRange.
+type LimitRangeItem struct {
+	Type           string            `json:"type"`
+	Default        map[string]string `json:"default,omitempty"`
+	DefaultRequest map[string]string `json:"defaultRequest,omitempty"`
+	Min            map[string]string `json:"min,omitempty"`
+	Max            map[string]string `json:"max,omitempty"`
+}
+
+// GetNamespaceQuotas returns the usage of all ResourceQuotas and the defaults of all LimitRanges in the given namespace.
+func (c *Cluster) GetNamespaceQuotas(ctx context.Context, namespace string) (*NamespaceQuotas, error) {
+	resourceQuotas, err := c.clientset.CoreV1().ResourceQuotas(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace}).Errorf("GetNamespaceQuotas")
+		return nil, err
+	}
+
+	limitRanges, err := c.clientset.CoreV1().LimitRanges(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace}).Errorf("GetNamespaceQuotas")
+		return nil, err
+	}
+
+	quotas := &NamespaceQuotas{Quotas: []Quota{}, LimitRanges: []LimitRange{}}
+
+	for _, resourceQuota := range resourceQuotas.Items {
+		quota := Quota{Name: resourceQuota.Name, Resources: []QuotaResource{}}
+
+		for name, hard := range resourceQuota.Status.Hard {
+			used := resourceQuota.Status.Used[name]
+			quota.Resources = append(quota.Resources, QuotaResource{
+				Name: string(name),
+				Used: used.String(),
+				Hard: hard.String(),
+			})
+		}
+
+		sortQuotaResources(quota.Resources)
+		quotas.Quotas = append(quotas.Quotas, quota)
+	}
+
+	for _, limitRange := range limitRanges.Items {
+		item := LimitRange{Name: limitRange.Name, Limits: []LimitRangeItem{}}
+
+		for _, limit := range limitRange.Spec.Limits {
+			item.Limits = append(item.Limits, LimitRangeItem{
+				Type:           string(limit.Type),
+				Default:        resourceListToMap(limit.Default),
+				DefaultRequest: resourceListToMap(limit.DefaultRequest),
+				Min:            resourceListToMap(limit.Min),
+				Max:            resourceListToMap(limit.Max),
+			})
+		}
+
+		quotas.LimitRanges = append(quotas.LimitRanges, item)
+	}
+
+	return quotas, nil
+}
+
+// sortQuotaResources sorts the resources of a quota by their name, so that the order is stable between requests.
+func sortQuotaResources(resources []QuotaResource) {
+	sort.Slice(resources, func(i, j int) bool {
+		return resources[i].Name < resources[j].Name
+	})
+}
+
+// resourceListToMap converts the given resource list into a map with the resource names as keys and the quantities as
+// values.
+func resourceListToMap(resources corev1.ResourceList) map[string]string {
+	if len(resources) == 0 {
+		return nil
+	}
+
+	m := make(map[string]string, len(resources))
+	for name, quantity := range resources {
+		m[string(name)] = quantity.String()
+	}
+
+	return m
+}
@@ -0,0 +1,94 @@
+package cluster
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Restarts contains the sum of all container restarts in a namespace and the containers with the most restarts.
+type Restarts struct {
+	Total      int32              `json:"total"`
+	Containers []ContainerRestart `json:"containers"`
+}
+
+// ContainerRestart contains the restart count of a single container together with the reason and exit code of the last
+// termination, which can be used to triage why a container is restarting.
+type ContainerRestart struct {
+	Pod          string `json:"pod"`
+	Container    string `json:"container"`
+	RestartCount int32  `json:"restartCount"`
+	Reason       string `json:"reason,omitempty"`
+	ExitCode     int32  `json:"exitCode,omitempty"`
+	FinishedAt   string `json:"finishedAt,omitempty"`
+}
+
+// GetRestarts returns the sum of the restarts of all containers in the given namespace and the top N containers with
+// the most restarts. Containers which were never restarted are not returned. If the limit is 0, all restarted
+// containers are returned.
+func (c *Cluster) GetRestarts(ctx context.Context, namespace string, limit int) (*Restarts, error) {
+	pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace}).Errorf("GetRestarts")
+		return nil, err
+	}
+
+	return getRestarts(pods.Items, limit), nil
+}
+
+// getRestarts aggregates the restart counts of all init and regular containers of the given Pods. The containers are
+// sorted by their restart count, containers with the same restart count are sorted by the Pod and container name.
+func getRestarts(pods []corev1.Pod, limit int) *Restarts {
+	restarts := &Restarts{Containers: []ContainerRestart{}}
+
+	for _, pod := range pods {
+		var statuses []corev1.ContainerStatus
+		statuses = append(statuses, pod.Status.InitContainerStatuses...)
+		statuses = append(statuses, pod.Status.ContainerStatuses...)
+
+		for _, status := range statuses {
+			if status.RestartCount == 0 {
+				continue
+			}
+
+			restarts.Total = restarts.Total + status.RestartCount
+
+			containerRestart := ContainerRestart{
+				Pod:          pod.Name,
+				Container:    status.Name,
+				RestartCount: status.RestartCount,
+			}
+
+			if terminated := status.LastTerminationState.Terminated; terminated != nil {
+				containerRestart.Reason = terminated.Reason
+				containerRestart.ExitCode = terminated.ExitCode
+				if !terminated.FinishedAt.IsZero() {
+					containerRestart.FinishedAt = terminated.FinishedAt.Format(time.RFC3339)
+				}
+			}
+
+			restarts.Containers = append(restarts.Containers, containerRestart)
+		}
+	}
+
+	sort.Slice(restarts.Containers, func(i, j int) bool {
+		a, b := restarts.Containers[i], restarts.Containers[j]
+		if a.RestartCount != b.RestartCount {
+			return a.RestartCount > b.RestartCount
+		}
+		if a.Pod != b.Pod {
+			return a.Pod < b.Pod
+		}
+		return a.Container < b.Container
+	})
+
+	if limit > 0 && len(restarts.Containers) > limit {
+		restarts.Containers = restarts.Containers[:limit]
+	}
+
+	return restarts
+}
@@ -0,0 +1,55 @@
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetRestarts(t *testing.T) {
+	finishedAt := metav1.NewTime(time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC))
+
+	pods := []corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "nginx"},
+			Status: corev1.PodStatus{
+				InitContainerStatuses: []corev1.ContainerStatus{{Name: "init", RestartCount: 1}},
+				ContainerStatuses: []corev1.ContainerStatus{
+					{Name: "nginx", RestartCount: 0},
+					{Name: "sidecar", RestartCount: 5, LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137, FinishedAt: finishedAt}}},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "redis"},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{{Name: "redis", RestartCount: 5, LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Error", ExitCode: 1}}}},
+			},
+		},
+	}
+
+	t.Run("all containers", func(t *testing.T) {
+		require.Equal(t, &Restarts{
+			Total: 11,
+			Containers: []ContainerRestart{
+				{Pod: "nginx", Container: "sidecar", RestartCount: 5, Reason: "OOMKilled", ExitCode: 137, FinishedAt: "2021-10-01T12:00:00Z"},
+				{Pod: "redis", Container: "redis", RestartCount: 5, Reason: "Error", ExitCode: 1},
+				{Pod: "nginx", Container: "init", RestartCount: 1},
+			},
+		}, getRestarts(pods, 0))
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		restarts := getRestarts(pods, 1)
+		require.Equal(t, int32(11), restarts.Total)
+		require.Len(t, restarts.Containers, 1)
+		require.Equal(t, "sidecar", restarts.Containers[0].Container)
+	})
+
+	t.Run("no pods", func(t *testing.T) {
+		require.Equal(t, &Restarts{Containers: []ContainerRestart{}}, getRestarts(nil, 10))
+	})
+}
@@ -0,0 +1,310 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// RolloutStatus is the status of the rollout of a Deployment, StatefulSet or DaemonSet. It contains the number of
+// desired, updated, ready and available replicas and a human readable message. When the rollout is completed, Done is
+// true. When the rollout failed (e.g. the progress deadline of a Deployment was exceeded), Error contains the reason.
+type RolloutStatus struct {
+	Kind              string `json:"kind"`
+	Name              string `json:"name"`
+	Replicas          int32  `json:"replicas"`
+	UpdatedReplicas   int32  `json:"updatedReplicas"`
+	ReadyReplicas     int32  `json:"readyReplicas"`
+	AvailableReplicas int32  `json:"availableReplicas"`
+	Message           string `json:"message"`
+	Done              bool   `json:"done"`
+	Error             string `json:"error,omitempty"`
+}
+
+// getDeploymentRolloutStatus returns the rollout status of a Deployment. The logic is the same as it is used by the
+// "kubectl rollout status" command.
+func getDeploymentRolloutStatus(deployment *appsv1.Deployment) RolloutStatus {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+
+	status := RolloutStatus{
+		Kind:              "deployments",
+		Name:              deployment.Name,
+		Replicas:          replicas,
+		UpdatedReplicas:   deployment.Status.UpdatedReplicas,
+		ReadyReplicas:     deployment.Status.ReadyReplicas,
+		AvailableReplicas: deployment.Status.AvailableReplicas,
+	}
+
+	if deployment.Generation > deployment.Status.ObservedGeneration {
+		status.Message = "Waiting for deployment spec update to be observed"
+		return status
+	}
+
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == appsv1.DeploymentProgressing && condition.Reason == "ProgressDeadlineExceeded" {
+			status.Done = true
+			status.Error = fmt.Sprintf("deployment %s exceeded its progress deadline", deployment.Name)
+			status.Message = status.Error
+			return status
+		}
+	}
+
+	if deployment.Status.UpdatedReplicas < replicas {
+		status.Message = fmt.Sprintf("Waiting for deployment rollout to finish: %d out of %d new replicas have been updated", deployment.Status.UpdatedReplicas, replicas)
+		return status
+	}
+
+	if deployment.Status.Replicas > deployment.Status.UpdatedReplicas {
+		status.Message = fmt.Sprintf("Waiting for deployment rollout to finish: %d old replicas are pending termination", deployment.Status.Replicas-deployment.Status.UpdatedReplicas)
+		return status
+	}
+
+	if deployment.Status.AvailableReplicas < deployment.Status.UpdatedReplicas {
+		status.Message = fmt.Sprintf("Waiting for deployment rollout to finish: %d of %d updated replicas are available", deployment.Status.AvailableReplicas, deployment.Status.UpdatedReplicas)
+		return status
+	}
+
+	status.Done = true
+	status.Message = fmt.Sprintf("Deployment %s successfully rolled out", deployment.Name)
+	return status
+}
+
+// getStatefulSetRolloutStatus returns the rollout status of a StatefulSet. The logic is the same as it is used by the
+// "kubectl rollout status" command.
+func getStatefulSetRolloutStatus(statefulSet *appsv1.StatefulSet) RolloutStatus {
+	replicas := int32(1)
+	if statefulSet.Spec.Replicas != nil {
+		replicas = *statefulSet.Spec.Replicas
+	}
+
+	status := RolloutStatus{
+		Kind:              "statefulsets",
+		Name:              statefulSet.Name,
+		Replicas:          replicas,
+		UpdatedReplicas:   statefulSet.Status.UpdatedReplicas,
+		ReadyReplicas:     statefulSet.Status.ReadyReplicas,
+		AvailableReplicas: statefulSet.Status.ReadyReplicas,
+	}
+
+	if statefulSet.Spec.UpdateStrategy.Type != appsv1.RollingUpdateStatefulSetStrategyType {
+		status.Done = true
+		status.Message = fmt.Sprintf("Rollout status is only available for %s strategy type", appsv1.RollingUpdateStatefulSetStrategyType)
+		return status
+	}
+
+	if statefulSet.Status.ObservedGeneration == 0 || statefulSet.Generation > statefulSet.Status.ObservedGeneration {
+		status.Message = "Waiting for statefulset spec update to be observed"
+		return status
+	}
+
+	if statefulSet.Status.ReadyReplicas < replicas {
+		status.Message = fmt.Sprintf("Waiting for %d pods to be ready", replicas-statefulSet.Status.ReadyReplicas)
+		return status
+	}
+
+	if statefulSet.Spec.UpdateStrategy.RollingUpdate != nil && statefulSet.Spec.UpdateStrategy.RollingUpdate.Partition != nil {
+		partition := *statefulSet.Spec.UpdateStrategy.RollingUpdate.Partition
+		if statefulSet.Status.UpdatedReplicas < replicas-partition {
+			status.Message = fmt.Sprintf("Waiting for partitioned roll out to finish: %d out of %d new pods have been updated", statefulSet.Status.UpdatedReplicas, replicas-partition)
+			return status
+		}
+
+		status.Done = true
+		status.Message = fmt.Sprintf("Partitioned roll out complete: %d new pods have been updated", statefulSet.Status.UpdatedReplicas)
+		return status
+	}
+
+	if statefulSet.Status.UpdateRevision != statefulSet.Status.CurrentRevision {
+		status.Message = fmt.Sprintf("Waiting for statefulset rolling update to complete %d pods at revision %s", statefulSet.Status.UpdatedReplicas, statefulSet.Status.UpdateRevision)
+		return status
+	}
+
+	status.Done = true
+	status.Message = fmt.Sprintf("Statefulset rolling update complete %d pods at revision %s", statefulSet.Status.CurrentReplicas, statefulSet.Status.CurrentRevision)
+	return status
+}
+
+// getDaemonSetRolloutStatus returns the rollout status of a DaemonSet. The logic is the same as it is used by the
+// "kubectl rollout status" command.
+func getDaemonSetRolloutStatus(daemonSet *appsv1.DaemonSet) RolloutStatus {
+	status := RolloutStatus{
+		Kind:              "daemonsets",
+		Name:              daemonSet.Name,
+		Replicas:          daemonSet.Status.DesiredNumberScheduled,
+		UpdatedReplicas:   daemonSet.Status.UpdatedNumberScheduled,
+		ReadyReplicas:     daemonSet.Status.NumberReady,
+		AvailableReplicas: daemonSet.Status.NumberAvailable,
+	}
+
+	if daemonSet.Spec.UpdateStrategy.Type != appsv1.RollingUpdateDaemonSetStrategyType {
+		status.Done = true
+		status.Message = fmt.Sprintf("Rollout status is only available for %s strategy type", appsv1.RollingUpdateDaemonSetStrategyType)
+		return status
+	}
+
+	if daemonSet.Generation > daemonSet.Status.ObservedGeneration {
+		status.Message = "Waiting for daemon set spec update to be observed"
+		return status
+	}
+
+	if daemonSet.Status.UpdatedNumberScheduled < daemonSet.Status.DesiredNumberScheduled {
+		status.Message = fmt.Sprintf("Waiting for daemon set %s rollout to finish: %d out of %d new pods have been updated", daemonSet.Name, daemonSet.Status.UpdatedNumberScheduled, daemonSet.Status.DesiredNumberScheduled)
+		return status
+	}
+
+	if daemonSet.Status.NumberAvailable < daemonSet.Status.DesiredNumberScheduled {
+		status.Message = fmt.Sprintf("Waiting for daemon set %s rollout to finish: %d of %d updated pods are available", daemonSet.Name, daemonSet.Status.NumberAvailable, daemonSet.Status.DesiredNumberScheduled)
+		return status
+	}
+
+	status.Done = true
+	status.Message = fmt.Sprintf("Daemon set %s successfully rolled out", daemonSet.Name)
+	return status
+}
+
+// getRolloutStatus returns the rollout status for the given object.
+func getRolloutStatus(obj runtime.Object) (RolloutStatus, error) {
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		return getDeploymentRolloutStatus(o), nil
+	case *appsv1.StatefulSet:
+		return getStatefulSetRolloutStatus(o), nil
+	case *appsv1.DaemonSet:
+		return getDaemonSetRolloutStatus(o), nil
+	default:
+		return RolloutStatus{}, fmt.Errorf("unsupported object type %T", obj)
+	}
+}
+
+// getRolloutObject returns the workload and a watch interface for the workload with the given kind and name. The
+// watch is started with the resource version of the returned object, so that we do not miss any changes.
+func (c *Cluster) getRolloutObject(ctx context.Context, namespace, name, kind string) (runtime.Object, watch.Interface, error) {
+	var obj runtime.Object
+	var resourceVersion string
+	var err error
+
+	switch kind {
+	case "deployments":
+		var deployment *appsv1.Deployment
+		deployment, err = c.clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if deployment != nil {
+			obj, resourceVersion = deployment, deployment.ResourceVersion
+		}
+	case "statefulsets":
+		var statefulSet *appsv1.StatefulSet
+		statefulSet, err = c.clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if statefulSet != nil {
+			obj, resourceVersion = statefulSet, statefulSet.ResourceVersion
+		}
+	case "daemonsets":
+		var daemonSet *appsv1.DaemonSet
+		daemonSet, err = c.clientset.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if daemonSet != nil {
+			obj, resourceVersion = daemonSet, daemonSet.ResourceVersion
+		}
+	default:
+		return nil, nil, fmt.Errorf("rollout status is not supported for %s", kind)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	options := metav1.ListOptions{
+		FieldSelector:   fields.OneTermEqualSelector("metadata.name", name).String(),
+		ResourceVersion: resourceVersion,
+	}
+
+	var watcher watch.Interface
+	switch kind {
+	case "deployments":
+		watcher, err = c.getStreamClientset().AppsV1().Deployments(namespace).Watch(ctx, options)
+	case "statefulsets":
+		watcher, err = c.getStreamClientset().AppsV1().StatefulSets(namespace).Watch(ctx, options)
+	case "daemonsets":
+		watcher, err = c.getStreamClientset().AppsV1().DaemonSets(namespace).Watch(ctx, options)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return obj, watcher, nil
+}
+
+// WaitForRollout watches the Deployment, StatefulSet or DaemonSet with the given name and emits the rollout status on
+// the returned channel, each time the workload changes. The channel is closed when the rollout is done, when the
+// workload was deleted, when the watch was closed by the Kubernetes API server or when the context is canceled.
+func (c *Cluster) WaitForRollout(ctx context.Context, namespace, name, kind string) (<-chan RolloutStatus, error) {
+	obj, watcher, err := c.getRolloutObject(ctx, namespace, name, kind)
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "name": name, "kind": kind}).Errorf("WaitForRollout")
+		return nil, err
+	}
+
+	status, err := getRolloutStatus(obj)
+	if err != nil {
+		watcher.Stop()
+		return nil, err
+	}
+
+	statusCh := make(chan RolloutStatus)
+
+	go func() {
+		defer close(statusCh)
+		defer watcher.Stop()
+
+		send := func(status RolloutStatus) bool {
+			select {
+			case statusCh <- status:
+				return !status.Done
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		if !send(status) {
+			return
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.ResultChan():
+				if !ok {
+					return
+				}
+
+				switch event.Type {
+				case watch.Deleted:
+					send(RolloutStatus{Kind: kind, Name: name, Done: true, Error: fmt.Sprintf("%s %s was deleted", kind, name), Message: fmt.Sprintf("%s %s was deleted", kind, name)})
+					return
+				case watch.Error:
+					err := apierrors.FromObject(event.Object)
+					send(RolloutStatus{Kind: kind, Name: name, Done: true, Error: err.Error(), Message: "Watch failed"})
+					return
+				case watch.Added, watch.Modified:
+					status, err := getRolloutStatus(event.Object)
+					if err != nil {
+						continue
+					}
+
+					if !send(status) {
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return statusCh, nil
+}
@@ -0,0 +1,39 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetDeploymentRolloutStatus(t *testing.T) {
+	replicas := int32(3)
+
+	for _, tt := range []struct {
+		name     string
+		status   appsv1.DeploymentStatus
+		done     bool
+		hasError bool
+	}{
+		{name: "spec update not observed", status: appsv1.DeploymentStatus{ObservedGeneration: 1}},
+		{name: "replicas not updated", status: appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 1}},
+		{name: "old replicas pending termination", status: appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 4, UpdatedReplicas: 3}},
+		{name: "updated replicas not available", status: appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 3, AvailableReplicas: 2}},
+		{name: "rolled out", status: appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 3, ReadyReplicas: 3, AvailableReplicas: 3}, done: true},
+		{name: "progress deadline exceeded", status: appsv1.DeploymentStatus{ObservedGeneration: 2, Conditions: []appsv1.DeploymentCondition{{Type: appsv1.DeploymentProgressing, Reason: "ProgressDeadlineExceeded"}}}, done: true, hasError: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			status := getDeploymentRolloutStatus(&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "reviews", Generation: 2},
+				Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+				Status:     tt.status,
+			})
+
+			require.Equal(t, tt.done, status.Done)
+			require.Equal(t, tt.hasError, status.Error != "")
+			require.NotEmpty(t, status.Message)
+		})
+	}
+}
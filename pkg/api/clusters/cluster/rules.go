@@ -0,0 +1,54 @@
+package cluster
+
+import (
+	"context"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetKobsRules returns the verbs kobs is allowed to use for each resource in the given namespace. The rules are
+// retrieved via a SelfSubjectRulesReview with the credentials of kobs, so that they do not reflect the permissions of
+// the user which sends the request to kobs, but the permissions kobs has to perform the request. The returned map
+// contains the resource name (e.g. "pods" or "pods/log") as key and the sorted list of allowed verbs as value.
+func (c *Cluster) GetKobsRules(ctx context.Context, namespace string) (map[string][]string, error) {
+	review, err := c.clientset.AuthorizationV1().SelfSubjectRulesReviews().Create(ctx, &authorizationv1.SelfSubjectRulesReview{
+		Spec: authorizationv1.SelfSubjectRulesReviewSpec{
+			Namespace: namespace,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace}).Errorf("GetKobsRules")
+		return nil, err
+	}
+
+	if review.Status.Incomplete {
+		log.WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "error": review.Status.EvaluationError}).Warnf("Rules review is incomplete.")
+	}
+
+	verbs := make(map[string]map[string]bool)
+	for _, rule := range review.Status.ResourceRules {
+		for _, resource := range rule.Resources {
+			if _, ok := verbs[resource]; !ok {
+				verbs[resource] = make(map[string]bool)
+			}
+
+			for _, verb := range rule.Verbs {
+				verbs[resource][verb] = true
+			}
+		}
+	}
+
+	rules := make(map[string][]string)
+	for resource, resourceVerbs := range verbs {
+		for verb := range resourceVerbs {
+			rules[resource] = append(rules[resource], verb)
+		}
+
+		sort.Strings(rules[resource])
+	}
+
+	return rules, nil
+}
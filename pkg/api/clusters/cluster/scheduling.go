@@ -0,0 +1,203 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	zoneLabels   = []string{"topology.kubernetes.io/zone", "failure-domain.beta.kubernetes.io/zone"}
+	regionLabels = []string{"topology.kubernetes.io/region", "failure-domain.beta.kubernetes.io/region"}
+)
+
+// PodScheduling contains the node, zone and region of a Pod together with a summary of the scheduling constraints
+// (node selector, affinities and tolerations), which lead to the placement of the Pod.
+type PodScheduling struct {
+	Pod             string            `json:"pod"`
+	Phase           string            `json:"phase"`
+	Node            string            `json:"node"`
+	Zone            string            `json:"zone,omitempty"`
+	Region          string            `json:"region,omitempty"`
+	NodeSelector    map[string]string `json:"nodeSelector,omitempty"`
+	NodeAffinity    []string          `json:"nodeAffinity,omitempty"`
+	PodAffinity     []string          `json:"podAffinity,omitempty"`
+	PodAntiAffinity []string          `json:"podAntiAffinity,omitempty"`
+	Tolerations     []string          `json:"tolerations,omitempty"`
+}
+
+// GetPodScheduling returns the scheduling information for all Pods in the given namespace, which are matching the
+// label selector. The labels of a node are only retrieved once, even when multiple Pods are running on the same node.
+func (c *Cluster) GetPodScheduling(ctx context.Context, namespace, labelSelector string) ([]PodScheduling, error) {
+	pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace}).Errorf("GetPodScheduling")
+		return nil, err
+	}
+
+	nodeLabels := make(map[string]map[string]string)
+	schedulings := []PodScheduling{}
+
+	for _, pod := range pods.Items {
+		nodeName := pod.Spec.NodeName
+		if _, ok := nodeLabels[nodeName]; !ok && nodeName != "" {
+			node, err := c.clientset.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+			if err != nil {
+				if !apierrors.IsNotFound(err) {
+					log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "node": nodeName}).Errorf("GetPodScheduling")
+					return nil, err
+				}
+
+				// The node was already removed from the cluster, so that we can not get the zone and region of the
+				// Pod. We cache the missing labels anyway, to avoid another API call for the next Pod on this node.
+				nodeLabels[nodeName] = nil
+			} else {
+				nodeLabels[nodeName] = node.Labels
+			}
+		}
+
+		schedulings = append(schedulings, getPodScheduling(pod, nodeLabels[nodeName]))
+	}
+
+	sortPodSchedulings(schedulings)
+	return schedulings, nil
+}
+
+// getPodScheduling returns the scheduling information for the given Pod. The zone and region are taken from the well
+// known topology labels of the node, where the Pod is running on.
+func getPodScheduling(pod corev1.Pod, nodeLabels map[string]string) PodScheduling {
+	scheduling := PodScheduling{
+		Pod:          pod.Name,
+		Phase:        string(pod.Status.Phase),
+		Node:         pod.Spec.NodeName,
+		Zone:         getFirstLabel(nodeLabels, zoneLabels),
+		Region:       getFirstLabel(nodeLabels, regionLabels),
+		NodeSelector: pod.Spec.NodeSelector,
+	}
+
+	if affinity := pod.Spec.Affinity; affinity != nil {
+		if affinity.NodeAffinity != nil {
+			scheduling.NodeAffinity = formatNodeAffinity(affinity.NodeAffinity)
+		}
+		if affinity.PodAffinity != nil {
+			scheduling.PodAffinity = formatPodAffinityTerms(affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution, affinity.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution)
+		}
+		if affinity.PodAntiAffinity != nil {
+			scheduling.PodAntiAffinity = formatPodAffinityTerms(affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution, affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution)
+		}
+	}
+
+	for _, toleration := range pod.Spec.Tolerations {
+		scheduling.Tolerations = append(scheduling.Tolerations, formatToleration(toleration))
+	}
+
+	return scheduling
+}
+
+// getFirstLabel returns the value of the first label from the keys slice, which is set in the given labels.
+func getFirstLabel(labels map[string]string, keys []string) string {
+	for _, key := range keys {
+		if value, ok := labels[key]; ok && value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// formatNodeAffinity returns a human readable summary of the required and preferred node selector terms.
+func formatNodeAffinity(nodeAffinity *corev1.NodeAffinity) []string {
+	var terms []string
+
+	if required := nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution; required != nil {
+		for _, term := range required.NodeSelectorTerms {
+			terms = append(terms, fmt.Sprintf("required: %s", formatNodeSelectorTerm(term)))
+		}
+	}
+
+	for _, preferred := range nodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+		terms = append(terms, fmt.Sprintf("preferred (weight %d): %s", preferred.Weight, formatNodeSelectorTerm(preferred.Preference)))
+	}
+
+	return terms
+}
+
+// formatNodeSelectorTerm formats all match expressions and fields of a node selector term, e.g.
+// "kubernetes.io/arch In (amd64, arm64)".
+func formatNodeSelectorTerm(term corev1.NodeSelectorTerm) string {
+	var requirements []string
+
+	for _, selectorRequirements := range [][]corev1.NodeSelectorRequirement{term.MatchExpressions, term.MatchFields} {
+		for _, requirement := range selectorRequirements {
+			if len(requirement.Values) == 0 {
+				requirements = append(requirements, fmt.Sprintf("%s %s", requirement.Key, requirement.Operator))
+			} else {
+				requirements = append(requirements, fmt.Sprintf("%s %s (%s)", requirement.Key, requirement.Operator, strings.Join(requirement.Values, ", ")))
+			}
+		}
+	}
+
+	return strings.Join(requirements, ", ")
+}
+
+// formatPodAffinityTerms returns a human readable summary of the required and preferred (anti) affinity terms, which
+// contains the topology key and the label selector of each term.
+func formatPodAffinityTerms(required []corev1.PodAffinityTerm, preferred []corev1.WeightedPodAffinityTerm) []string {
+	var terms []string
+
+	for _, term := range required {
+		terms = append(terms, fmt.Sprintf("required: %s", formatPodAffinityTerm(term)))
+	}
+
+	for _, term := range preferred {
+		terms = append(terms, fmt.Sprintf("preferred (weight %d): %s", term.Weight, formatPodAffinityTerm(term.PodAffinityTerm)))
+	}
+
+	return terms
+}
+
+func formatPodAffinityTerm(term corev1.PodAffinityTerm) string {
+	return fmt.Sprintf("%s on %s", metav1.FormatLabelSelector(term.LabelSelector), term.TopologyKey)
+}
+
+// formatToleration formats a toleration in the same way as it is shown by kubectl, e.g. "key=value:NoSchedule" or
+// "key:NoExecute op=Exists for 300s".
+func formatToleration(toleration corev1.Toleration) string {
+	var builder strings.Builder
+
+	builder.WriteString(toleration.Key)
+	if toleration.Value != "" {
+		builder.WriteString("=" + toleration.Value)
+	}
+	if toleration.Effect != "" {
+		builder.WriteString(":" + string(toleration.Effect))
+	}
+	if toleration.Operator == corev1.TolerationOpExists && toleration.Value == "" {
+		if builder.Len() > 0 {
+			builder.WriteString(" ")
+		}
+		builder.WriteString("op=Exists")
+	}
+	if toleration.TolerationSeconds != nil {
+		builder.WriteString(fmt.Sprintf(" for %ds", *toleration.TolerationSeconds))
+	}
+
+	return builder.String()
+}
+
+// sortPodSchedulings sorts the scheduling information by the node and Pod name, so that Pods on the same node are
+// grouped together.
+func sortPodSchedulings(schedulings []PodScheduling) {
+	sort.Slice(schedulings, func(i, j int) bool {
+		if schedulings[i].Node != schedulings[j].Node {
+			return schedulings[i].Node < schedulings[j].Node
+		}
+		return schedulings[i].Pod < schedulings[j].Pod
+	})
+}
@@ -0,0 +1,64 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetPodScheduling(t *testing.T) {
+	tolerationSeconds := int64(300)
+
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx"},
+		Spec: corev1.PodSpec{
+			NodeName:     "node1",
+			NodeSelector: map[string]string{"kubernetes.io/os": "linux"},
+			Affinity: &corev1.Affinity{
+				NodeAffinity: &corev1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+						NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "kubernetes.io/arch", Operator: corev1.NodeSelectorOpIn, Values: []string{"amd64", "arm64"}}}}},
+					},
+					PreferredDuringSchedulingIgnoredDuringExecution: []corev1.PreferredSchedulingTerm{{Weight: 10, Preference: corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "spot", Operator: corev1.NodeSelectorOpDoesNotExist}}}}},
+				},
+				PodAntiAffinity: &corev1.PodAntiAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "nginx"}}, TopologyKey: "kubernetes.io/hostname"}},
+				},
+			},
+			Tolerations: []corev1.Toleration{
+				{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "web", Effect: corev1.TaintEffectNoSchedule},
+				{Key: "node.kubernetes.io/not-ready", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute, TolerationSeconds: &tolerationSeconds},
+				{Operator: corev1.TolerationOpExists},
+			},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+
+	t.Run("with node labels", func(t *testing.T) {
+		actual := getPodScheduling(pod, map[string]string{"topology.kubernetes.io/zone": "eu-central-1a", "failure-domain.beta.kubernetes.io/region": "eu-central-1"})
+		require.Equal(t, PodScheduling{
+			Pod:             "nginx",
+			Phase:           "Running",
+			Node:            "node1",
+			Zone:            "eu-central-1a",
+			Region:          "eu-central-1",
+			NodeSelector:    map[string]string{"kubernetes.io/os": "linux"},
+			NodeAffinity:    []string{"required: kubernetes.io/arch In (amd64, arm64)", "preferred (weight 10): spot DoesNotExist"},
+			PodAntiAffinity: []string{"required: app=nginx on kubernetes.io/hostname"},
+			Tolerations:     []string{"dedicated=web:NoSchedule", "node.kubernetes.io/not-ready:NoExecute op=Exists for 300s", "op=Exists"},
+		}, actual)
+	})
+
+	t.Run("without node labels", func(t *testing.T) {
+		actual := getPodScheduling(corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "nginx"}, Status: corev1.PodStatus{Phase: corev1.PodPending}}, nil)
+		require.Equal(t, PodScheduling{Pod: "nginx", Phase: "Pending"}, actual)
+	})
+}
+
+func TestSortPodSchedulings(t *testing.T) {
+	schedulings := []PodScheduling{{Pod: "b", Node: "node2"}, {Pod: "c", Node: "node1"}, {Pod: "a", Node: "node2"}, {Pod: "d"}}
+	sortPodSchedulings(schedulings)
+	require.Equal(t, []PodScheduling{{Pod: "d"}, {Pod: "c", Node: "node1"}, {Pod: "a", Node: "node2"}, {Pod: "b", Node: "node2"}}, schedulings)
+}
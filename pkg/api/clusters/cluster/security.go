@@ -0,0 +1,243 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PodSecurity contains the security related settings of a Pod and the effective security context of each container.
+// The risks field contains a human readable description for all settings of the Pod, which are considered as risky,
+// e.g. when the Pod uses the network namespace of the host.
+type PodSecurity struct {
+	HostNetwork        bool                `json:"hostNetwork"`
+	HostPID            bool                `json:"hostPID"`
+	HostIPC            bool                `json:"hostIPC"`
+	ServiceAccount     string              `json:"serviceAccount,omitempty"`
+	RunAsUser          *int64              `json:"runAsUser,omitempty"`
+	RunAsGroup         *int64              `json:"runAsGroup,omitempty"`
+	RunAsNonRoot       *bool               `json:"runAsNonRoot,omitempty"`
+	FSGroup            *int64              `json:"fsGroup,omitempty"`
+	SupplementalGroups []int64             `json:"supplementalGroups,omitempty"`
+	SeccompProfile     string              `json:"seccompProfile,omitempty"`
+	Risks              []string            `json:"risks"`
+	Containers         []ContainerSecurity `json:"containers"`
+}
+
+// ContainerSecurity is the effective security context of a single container. The user, group, non root and seccomp
+// settings are taken from the Pod, when they are not set for the container. All fields which are set for the container
+// and the Pod are listed in the overrides field, because the value of the container takes precedence.
+type ContainerSecurity struct {
+	Container                string   `json:"container"`
+	Init                     bool     `json:"init"`
+	RunAsUser                *int64   `json:"runAsUser,omitempty"`
+	RunAsGroup               *int64   `json:"runAsGroup,omitempty"`
+	RunAsNonRoot             *bool    `json:"runAsNonRoot,omitempty"`
+	Privileged               bool     `json:"privileged"`
+	AllowPrivilegeEscalation *bool    `json:"allowPrivilegeEscalation,omitempty"`
+	ReadOnlyRootFilesystem   bool     `json:"readOnlyRootFilesystem"`
+	SeccompProfile           string   `json:"seccompProfile,omitempty"`
+	CapabilitiesAdd          []string `json:"capabilitiesAdd"`
+	CapabilitiesDrop         []string `json:"capabilitiesDrop"`
+	Overrides                []string `json:"overrides"`
+	Risks                    []string `json:"risks"`
+}
+
+// GetPodSecurity returns the security settings of the given Pod and the effective security context of all init
+// containers and containers of the Pod.
+func (c *Cluster) GetPodSecurity(ctx context.Context, namespace, name string) (*PodSecurity, error) {
+	pod, err := c.clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "name": name}).Errorf("GetPodSecurity")
+		return nil, err
+	}
+
+	return getPodSecurity(pod), nil
+}
+
+// getPodSecurity returns the security settings for the given Pod, see GetPodSecurity.
+func getPodSecurity(pod *corev1.Pod) *PodSecurity {
+	podSecurityContext := pod.Spec.SecurityContext
+	if podSecurityContext == nil {
+		podSecurityContext = &corev1.PodSecurityContext{}
+	}
+
+	podSecurity := &PodSecurity{
+		HostNetwork:        pod.Spec.HostNetwork,
+		HostPID:            pod.Spec.HostPID,
+		HostIPC:            pod.Spec.HostIPC,
+		ServiceAccount:     pod.Spec.ServiceAccountName,
+		RunAsUser:          podSecurityContext.RunAsUser,
+		RunAsGroup:         podSecurityContext.RunAsGroup,
+		RunAsNonRoot:       podSecurityContext.RunAsNonRoot,
+		FSGroup:            podSecurityContext.FSGroup,
+		SupplementalGroups: podSecurityContext.SupplementalGroups,
+		SeccompProfile:     formatSeccompProfile(podSecurityContext.SeccompProfile),
+		Risks:              []string{},
+		Containers:         []ContainerSecurity{},
+	}
+
+	if pod.Spec.HostNetwork {
+		podSecurity.Risks = append(podSecurity.Risks, "Pod uses the network namespace of the host")
+	}
+	if pod.Spec.HostPID {
+		podSecurity.Risks = append(podSecurity.Risks, "Pod uses the process namespace of the host")
+	}
+	if pod.Spec.HostIPC {
+		podSecurity.Risks = append(podSecurity.Risks, "Pod uses the IPC namespace of the host")
+	}
+	for _, volume := range pod.Spec.Volumes {
+		if volume.HostPath != nil {
+			podSecurity.Risks = append(podSecurity.Risks, fmt.Sprintf("Volume %s mounts the host path %s", volume.Name, volume.HostPath.Path))
+		}
+	}
+
+	for _, container := range pod.Spec.InitContainers {
+		podSecurity.Containers = append(podSecurity.Containers, getContainerSecurity(container, true, podSecurityContext))
+	}
+	for _, container := range pod.Spec.Containers {
+		podSecurity.Containers = append(podSecurity.Containers, getContainerSecurity(container, false, podSecurityContext))
+	}
+
+	return podSecurity
+}
+
+// getContainerSecurity returns the effective security context of the given container. Settings which are not set for
+// the container are inherited from the security context of the Pod.
+func getContainerSecurity(container corev1.Container, init bool, podSecurityContext *corev1.PodSecurityContext) ContainerSecurity {
+	securityContext := container.SecurityContext
+	if securityContext == nil {
+		securityContext = &corev1.SecurityContext{}
+	}
+
+	containerSecurity := ContainerSecurity{
+		Container:                container.Name,
+		Init:                     init,
+		RunAsUser:                podSecurityContext.RunAsUser,
+		RunAsGroup:               podSecurityContext.RunAsGroup,
+		RunAsNonRoot:             podSecurityContext.RunAsNonRoot,
+		AllowPrivilegeEscalation: securityContext.AllowPrivilegeEscalation,
+		SeccompProfile:           formatSeccompProfile(podSecurityContext.SeccompProfile),
+		CapabilitiesAdd:          []string{},
+		CapabilitiesDrop:         []string{},
+		Overrides:                []string{},
+		Risks:                    []string{},
+	}
+
+	if securityContext.RunAsUser != nil {
+		if podSecurityContext.RunAsUser != nil {
+			containerSecurity.Overrides = append(containerSecurity.Overrides, "runAsUser")
+		}
+		containerSecurity.RunAsUser = securityContext.RunAsUser
+	}
+	if securityContext.RunAsGroup != nil {
+		if podSecurityContext.RunAsGroup != nil {
+			containerSecurity.Overrides = append(containerSecurity.Overrides, "runAsGroup")
+		}
+		containerSecurity.RunAsGroup = securityContext.RunAsGroup
+	}
+	if securityContext.RunAsNonRoot != nil {
+		if podSecurityContext.RunAsNonRoot != nil {
+			containerSecurity.Overrides = append(containerSecurity.Overrides, "runAsNonRoot")
+		}
+		containerSecurity.RunAsNonRoot = securityContext.RunAsNonRoot
+	}
+	if securityContext.SeccompProfile != nil {
+		if podSecurityContext.SeccompProfile != nil {
+			containerSecurity.Overrides = append(containerSecurity.Overrides, "seccompProfile")
+		}
+		containerSecurity.SeccompProfile = formatSeccompProfile(securityContext.SeccompProfile)
+	}
+
+	if securityContext.Privileged != nil {
+		containerSecurity.Privileged = *securityContext.Privileged
+	}
+	if securityContext.ReadOnlyRootFilesystem != nil {
+		containerSecurity.ReadOnlyRootFilesystem = *securityContext.ReadOnlyRootFilesystem
+	}
+	if securityContext.Capabilities != nil {
+		containerSecurity.CapabilitiesAdd = normalizeCapabilities(securityContext.Capabilities.Add)
+		containerSecurity.CapabilitiesDrop = normalizeCapabilities(securityContext.Capabilities.Drop)
+	}
+
+	containerSecurity.Risks = getContainerSecurityRisks(containerSecurity)
+	return containerSecurity
+}
+
+// getContainerSecurityRisks returns a human readable description for all risky settings of the effective security
+// context of a container. The checks are based on the "restricted" profile of the Pod Security Standards.
+func getContainerSecurityRisks(containerSecurity ContainerSecurity) []string {
+	risks := []string{}
+
+	if containerSecurity.Privileged {
+		risks = append(risks, "Container is privileged")
+	}
+
+	// When allowPrivilegeEscalation isn't set, it defaults to true, so that we also report a missing value.
+	if containerSecurity.AllowPrivilegeEscalation == nil || *containerSecurity.AllowPrivilegeEscalation {
+		risks = append(risks, "Container allows privilege escalation")
+	}
+
+	if containerSecurity.RunAsUser != nil && *containerSecurity.RunAsUser == 0 {
+		risks = append(risks, "Container runs as root user")
+	} else if containerSecurity.RunAsNonRoot == nil || !*containerSecurity.RunAsNonRoot {
+		risks = append(risks, "Container may run as root user")
+	}
+
+	for _, capability := range containerSecurity.CapabilitiesAdd {
+		risks = append(risks, fmt.Sprintf("Container adds capability %s", capability))
+	}
+
+	droppedAll := false
+	for _, capability := range containerSecurity.CapabilitiesDrop {
+		if capability == "ALL" {
+			droppedAll = true
+		}
+	}
+	if !droppedAll {
+		risks = append(risks, "Container doesn't drop all capabilities")
+	}
+
+	if containerSecurity.SeccompProfile == string(corev1.SeccompProfileTypeUnconfined) {
+		risks = append(risks, "Container runs without seccomp profile")
+	}
+
+	return risks
+}
+
+// normalizeCapabilities returns the sorted list of the given capabilities in upper case and without the "CAP_" prefix,
+// so that e.g. "cap_net_admin" and "NET_ADMIN" are returned in the same format.
+func normalizeCapabilities(capabilities []corev1.Capability) []string {
+	normalized := []string{}
+	seen := make(map[string]bool)
+
+	for _, capability := range capabilities {
+		name := strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(string(capability))), "CAP_")
+		if name != "" && !seen[name] {
+			seen[name] = true
+			normalized = append(normalized, name)
+		}
+	}
+
+	sort.Strings(normalized)
+	return normalized
+}
+
+// formatSeccompProfile returns the type of the given seccomp profile. For a profile of the type "Localhost" the path
+// of the profile is added, e.g. "Localhost/profiles/audit.json".
+func formatSeccompProfile(profile *corev1.SeccompProfile) string {
+	if profile == nil {
+		return ""
+	}
+
+	if profile.Type == corev1.SeccompProfileTypeLocalhost && profile.LocalhostProfile != nil {
+		return fmt.Sprintf("%s/%s", profile.Type, *profile.LocalhostProfile)
+	}
+
+	return string(profile.Type)
+}
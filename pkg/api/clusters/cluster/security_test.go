@@ -0,0 +1,92 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetPodSecurity(t *testing.T) {
+	boolPtr := func(value bool) *bool { return &value }
+	int64Ptr := func(value int64) *int64 { return &value }
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx"},
+		Spec: corev1.PodSpec{
+			HostNetwork:        true,
+			ServiceAccountName: "nginx",
+			SecurityContext: &corev1.PodSecurityContext{
+				RunAsUser:      int64Ptr(1000),
+				RunAsNonRoot:   boolPtr(true),
+				SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+			},
+			Volumes: []corev1.Volume{{Name: "docker", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/var/run/docker.sock"}}}},
+			InitContainers: []corev1.Container{{
+				Name: "init",
+				SecurityContext: &corev1.SecurityContext{
+					RunAsUser:  int64Ptr(0),
+					Privileged: boolPtr(true),
+				},
+			}},
+			Containers: []corev1.Container{{
+				Name: "nginx",
+				SecurityContext: &corev1.SecurityContext{
+					AllowPrivilegeEscalation: boolPtr(false),
+					ReadOnlyRootFilesystem:   boolPtr(true),
+					Capabilities:             &corev1.Capabilities{Add: []corev1.Capability{"net_bind_service", "CAP_NET_BIND_SERVICE"}, Drop: []corev1.Capability{"all"}},
+				},
+			}},
+		},
+	}
+
+	require.Equal(t, &PodSecurity{
+		HostNetwork:    true,
+		ServiceAccount: "nginx",
+		RunAsUser:      int64Ptr(1000),
+		RunAsNonRoot:   boolPtr(true),
+		SeccompProfile: "RuntimeDefault",
+		Risks:          []string{"Pod uses the network namespace of the host", "Volume docker mounts the host path /var/run/docker.sock"},
+		Containers: []ContainerSecurity{
+			{
+				Container:        "init",
+				Init:             true,
+				RunAsUser:        int64Ptr(0),
+				RunAsNonRoot:     boolPtr(true),
+				Privileged:       true,
+				SeccompProfile:   "RuntimeDefault",
+				CapabilitiesAdd:  []string{},
+				CapabilitiesDrop: []string{},
+				Overrides:        []string{"runAsUser"},
+				Risks:            []string{"Container is privileged", "Container allows privilege escalation", "Container runs as root user", "Container doesn't drop all capabilities"},
+			},
+			{
+				Container:                "nginx",
+				RunAsUser:                int64Ptr(1000),
+				RunAsNonRoot:             boolPtr(true),
+				AllowPrivilegeEscalation: boolPtr(false),
+				ReadOnlyRootFilesystem:   true,
+				SeccompProfile:           "RuntimeDefault",
+				CapabilitiesAdd:          []string{"NET_BIND_SERVICE"},
+				CapabilitiesDrop:         []string{"ALL"},
+				Overrides:                []string{},
+				Risks:                    []string{"Container adds capability NET_BIND_SERVICE"},
+			},
+		},
+	}, getPodSecurity(pod))
+}
+
+func TestGetPodSecurityWithoutSecurityContext(t *testing.T) {
+	actual := getPodSecurity(&corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "nginx"}}}})
+	require.Equal(t, []string{}, actual.Risks)
+	require.Equal(t, []string{"Container allows privilege escalation", "Container may run as root user", "Container doesn't drop all capabilities"}, actual.Containers[0].Risks)
+}
+
+func TestFormatSeccompProfile(t *testing.T) {
+	localhostProfile := "profiles/audit.json"
+
+	require.Equal(t, "", formatSeccompProfile(nil))
+	require.Equal(t, "Unconfined", formatSeccompProfile(&corev1.SeccompProfile{Type: corev1.SeccompProfileTypeUnconfined}))
+	require.Equal(t, "Localhost/profiles/audit.json", formatSeccompProfile(&corev1.SeccompProfile{Type: corev1.SeccompProfileTypeLocalhost, LocalhostProfile: &localhostProfile}))
+}
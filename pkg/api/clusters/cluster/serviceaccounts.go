@@ -0,0 +1,244 @@
+package cluster
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PodPermissions contains the ServiceAccount of a Pod, all RoleBindings and ClusterRoleBindings which are referencing
+// the ServiceAccount and the normalized rules of all bound Roles and ClusterRoles.
+type PodPermissions struct {
+	ServiceAccount string           `json:"serviceAccount"`
+	Bindings       []RoleBinding    `json:"bindings"`
+	Rules          []PermissionRule `json:"rules"`
+}
+
+// RoleBinding is a RoleBinding or ClusterRoleBinding, which references a ServiceAccount. The missing field is set,
+// when the referenced Role or ClusterRole doesn't exist.
+type RoleBinding struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	RoleKind  string `json:"roleKind"`
+	RoleName  string `json:"roleName"`
+	Missing   bool   `json:"missing,omitempty"`
+}
+
+// PermissionRule is a single normalized rule. Each rule is for exactly one resource (or non resource url) in one
+// scope, where the scope is the namespace for rules granted via a RoleBinding and "*" for rules granted via a
+// ClusterRoleBinding. The verbs contain all verbs, which are granted for the resource by any of the bound roles.
+type PermissionRule struct {
+	Scope          string   `json:"scope"`
+	APIGroup       string   `json:"apiGroup,omitempty"`
+	Resource       string   `json:"resource,omitempty"`
+	ResourceNames  []string `json:"resourceNames,omitempty"`
+	NonResourceURL string   `json:"nonResourceURL,omitempty"`
+	Verbs          []string `json:"verbs"`
+}
+
+// GetPodPermissions returns the permissions of the ServiceAccount, which is used by the given Pod. For that we are
+// getting all RoleBindings in the namespace of the Pod and all ClusterRoleBindings, which are referencing the
+// ServiceAccount, and return the rules of the referenced Roles and ClusterRoles. Aggregated ClusterRoles are already
+// containing the rules of all aggregated roles, so that they are handled like all other ClusterRoles.
+func (c *Cluster) GetPodPermissions(ctx context.Context, namespace, name string) (*PodPermissions, error) {
+	pod, err := c.clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "name": name}).Errorf("GetPodPermissions")
+		return nil, err
+	}
+
+	serviceAccount := pod.Spec.ServiceAccountName
+	if serviceAccount == "" {
+		serviceAccount = "default"
+	}
+
+	roleBindings, err := c.clientset.RbacV1().RoleBindings(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "name": name}).Errorf("GetPodPermissions")
+		return nil, err
+	}
+
+	clusterRoleBindings, err := c.clientset.RbacV1().ClusterRoleBindings().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "name": name}).Errorf("GetPodPermissions")
+		return nil, err
+	}
+
+	roles, err := c.clientset.RbacV1().Roles(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "name": name}).Errorf("GetPodPermissions")
+		return nil, err
+	}
+
+	clusterRoles, err := c.clientset.RbacV1().ClusterRoles().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "name": name}).Errorf("GetPodPermissions")
+		return nil, err
+	}
+
+	return getPodPermissions(namespace, serviceAccount, roleBindings.Items, clusterRoleBindings.Items, roles.Items, clusterRoles.Items), nil
+}
+
+// getPodPermissions returns the permissions of the given ServiceAccount, see GetPodPermissions.
+func getPodPermissions(namespace, serviceAccount string, roleBindings []rbacv1.RoleBinding, clusterRoleBindings []rbacv1.ClusterRoleBinding, roles []rbacv1.Role, clusterRoles []rbacv1.ClusterRole) *PodPermissions {
+	roleRules := make(map[string][]rbacv1.PolicyRule, len(roles))
+	for _, role := range roles {
+		roleRules[role.Name] = role.Rules
+	}
+
+	clusterRoleRules := make(map[string][]rbacv1.PolicyRule, len(clusterRoles))
+	for _, clusterRole := range clusterRoles {
+		clusterRoleRules[clusterRole.Name] = clusterRole.Rules
+	}
+
+	getRules := func(roleRef rbacv1.RoleRef) ([]rbacv1.PolicyRule, bool) {
+		if roleRef.Kind == "Role" {
+			rules, ok := roleRules[roleRef.Name]
+			return rules, ok
+		}
+
+		rules, ok := clusterRoleRules[roleRef.Name]
+		return rules, ok
+	}
+
+	permissions := &PodPermissions{ServiceAccount: serviceAccount, Bindings: []RoleBinding{}}
+	permissionRules := make(map[string]PermissionRule)
+	verbs := make(map[string]map[string]bool)
+
+	addRules := func(scope string, rules []rbacv1.PolicyRule) {
+		for _, rule := range rules {
+			for _, permissionRule := range normalizePolicyRule(scope, rule) {
+				key := strings.Join([]string{permissionRule.Scope, permissionRule.APIGroup, permissionRule.Resource, strings.Join(permissionRule.ResourceNames, ","), permissionRule.NonResourceURL}, "|")
+				if _, ok := verbs[key]; !ok {
+					permissionRules[key] = permissionRule
+					verbs[key] = make(map[string]bool)
+				}
+
+				for _, verb := range rule.Verbs {
+					verbs[key][verb] = true
+				}
+			}
+		}
+	}
+
+	for _, roleBinding := range roleBindings {
+		if !hasServiceAccountSubject(roleBinding.Subjects, roleBinding.Namespace, namespace, serviceAccount) {
+			continue
+		}
+
+		rules, ok := getRules(roleBinding.RoleRef)
+		permissions.Bindings = append(permissions.Bindings, RoleBinding{
+			Kind:      "RoleBinding",
+			Namespace: roleBinding.Namespace,
+			Name:      roleBinding.Name,
+			RoleKind:  roleBinding.RoleRef.Kind,
+			RoleName:  roleBinding.RoleRef.Name,
+			Missing:   !ok,
+		})
+		addRules(roleBinding.Namespace, rules)
+	}
+
+	for _, clusterRoleBinding := range clusterRoleBindings {
+		if !hasServiceAccountSubject(clusterRoleBinding.Subjects, "", namespace, serviceAccount) {
+			continue
+		}
+
+		rules, ok := getRules(clusterRoleBinding.RoleRef)
+		permissions.Bindings = append(permissions.Bindings, RoleBinding{
+			Kind:     "ClusterRoleBinding",
+			Name:     clusterRoleBinding.Name,
+			RoleKind: clusterRoleBinding.RoleRef.Kind,
+			RoleName: clusterRoleBinding.RoleRef.Name,
+			Missing:  !ok,
+		})
+		addRules("*", rules)
+	}
+
+	permissions.Rules = make([]PermissionRule, 0, len(permissionRules))
+	for key, permissionRule := range permissionRules {
+		permissionRule.Verbs = []string{}
+		for verb := range verbs[key] {
+			permissionRule.Verbs = append(permissionRule.Verbs, verb)
+		}
+		sort.Strings(permissionRule.Verbs)
+
+		permissions.Rules = append(permissions.Rules, permissionRule)
+	}
+
+	sort.Slice(permissions.Rules, func(i, j int) bool {
+		a, b := permissions.Rules[i], permissions.Rules[j]
+		if a.Scope != b.Scope {
+			return a.Scope < b.Scope
+		}
+		if a.APIGroup != b.APIGroup {
+			return a.APIGroup < b.APIGroup
+		}
+		if a.Resource != b.Resource {
+			return a.Resource < b.Resource
+		}
+		if a.NonResourceURL != b.NonResourceURL {
+			return a.NonResourceURL < b.NonResourceURL
+		}
+		return strings.Join(a.ResourceNames, ",") < strings.Join(b.ResourceNames, ",")
+	})
+
+	return permissions
+}
+
+// hasServiceAccountSubject returns true, when the given subjects contain the ServiceAccount with the given name and
+// namespace. For RoleBindings the namespace of a ServiceAccount subject defaults to the namespace of the binding.
+func hasServiceAccountSubject(subjects []rbacv1.Subject, bindingNamespace, namespace, serviceAccount string) bool {
+	for _, subject := range subjects {
+		if subject.Kind != rbacv1.ServiceAccountKind || subject.Name != serviceAccount {
+			continue
+		}
+
+		subjectNamespace := subject.Namespace
+		if subjectNamespace == "" {
+			subjectNamespace = bindingNamespace
+		}
+
+		if subjectNamespace == namespace {
+			return true
+		}
+	}
+
+	return false
+}
+
+// normalizePolicyRule splits the given PolicyRule into one PermissionRule per api group and resource and one
+// PermissionRule per non resource url. The verbs of the returned rules are set by the caller.
+func normalizePolicyRule(scope string, rule rbacv1.PolicyRule) []PermissionRule {
+	var permissionRules []PermissionRule
+
+	var resourceNames []string
+	if len(rule.ResourceNames) > 0 {
+		resourceNames = append(resourceNames, rule.ResourceNames...)
+		sort.Strings(resourceNames)
+	}
+
+	for _, apiGroup := range rule.APIGroups {
+		for _, resource := range rule.Resources {
+			permissionRules = append(permissionRules, PermissionRule{
+				Scope:         scope,
+				APIGroup:      apiGroup,
+				Resource:      resource,
+				ResourceNames: resourceNames,
+			})
+		}
+	}
+
+	for _, nonResourceURL := range rule.NonResourceURLs {
+		permissionRules = append(permissionRules, PermissionRule{
+			Scope:          scope,
+			NonResourceURL: nonResourceURL,
+		})
+	}
+
+	return permissionRules
+}
@@ -0,0 +1,74 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetPodPermissions(t *testing.T) {
+	roleBindings := []rbacv1.RoleBinding{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "pod-reader", Namespace: "default"},
+			Subjects:   []rbacv1.Subject{{Kind: "ServiceAccount", Name: "app"}},
+			RoleRef:    rbacv1.RoleRef{Kind: "Role", Name: "pod-reader"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"},
+			Subjects:   []rbacv1.Subject{{Kind: "ServiceAccount", Name: "other"}},
+			RoleRef:    rbacv1.RoleRef{Kind: "Role", Name: "pod-reader"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "missing", Namespace: "default"},
+			Subjects:   []rbacv1.Subject{{Kind: "ServiceAccount", Name: "app", Namespace: "default"}},
+			RoleRef:    rbacv1.RoleRef{Kind: "Role", Name: "missing"},
+		},
+	}
+
+	clusterRoleBindings := []rbacv1.ClusterRoleBinding{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "view"},
+			Subjects:   []rbacv1.Subject{{Kind: "ServiceAccount", Name: "app", Namespace: "default"}},
+			RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "view"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "other-namespace"},
+			Subjects:   []rbacv1.Subject{{Kind: "ServiceAccount", Name: "app", Namespace: "kube-system"}},
+			RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "view"},
+		},
+	}
+
+	roles := []rbacv1.Role{{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod-reader", Namespace: "default"},
+		Rules: []rbacv1.PolicyRule{
+			{APIGroups: []string{""}, Resources: []string{"pods", "pods/log"}, Verbs: []string{"get", "list"}},
+			{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"watch", "get"}},
+		},
+	}}
+
+	clusterRoles := []rbacv1.ClusterRole{{
+		ObjectMeta: metav1.ObjectMeta{Name: "view"},
+		Rules: []rbacv1.PolicyRule{
+			{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, ResourceNames: []string{"b", "a"}, Verbs: []string{"get"}},
+			{NonResourceURLs: []string{"/healthz"}, Verbs: []string{"get"}},
+		},
+	}}
+
+	permissions := getPodPermissions("default", "app", roleBindings, clusterRoleBindings, roles, clusterRoles)
+	require.Equal(t, &PodPermissions{
+		ServiceAccount: "app",
+		Bindings: []RoleBinding{
+			{Kind: "RoleBinding", Namespace: "default", Name: "pod-reader", RoleKind: "Role", RoleName: "pod-reader"},
+			{Kind: "RoleBinding", Namespace: "default", Name: "missing", RoleKind: "Role", RoleName: "missing", Missing: true},
+			{Kind: "ClusterRoleBinding", Name: "view", RoleKind: "ClusterRole", RoleName: "view"},
+		},
+		Rules: []PermissionRule{
+			{Scope: "*", NonResourceURL: "/healthz", Verbs: []string{"get"}},
+			{Scope: "*", APIGroup: "apps", Resource: "deployments", ResourceNames: []string{"a", "b"}, Verbs: []string{"get"}},
+			{Scope: "default", Resource: "pods", Verbs: []string{"get", "list", "watch"}},
+			{Scope: "default", Resource: "pods/log", Verbs: []string{"get", "list"}},
+		},
+	}, permissions)
+}
@@ -0,0 +1,138 @@
+package cluster
+
+import (
+	"fmt"
+	"sort"
+
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// SortedItem is a single item of a list, which was sorted via the SortItems function. The list field is the index of the
+// list, which contains the item.
+type SortedItem struct {
+	List int
+	Item interface{}
+}
+
+// SortItems merges the items of all given lists and sorts them by the value of the given jsonPath (e.g. ".metadata.name"
+// or ".status.startTime"), like it is done by the "--sort-by" flag of kubectl. The jsonPath uses the same format as the
+// additionalPrinterColumns of a CRD. Items without a value for the jsonPath are always placed at the end of the result.
+// The sort is stable, so that items with the same value keep the order of the lists and the order returned by the
+// Kubernetes API. Lists without items (e.g. when a single resource was requested) are ignored.
+func SortItems(lists []map[string]interface{}, sortBy string, descending bool) ([]SortedItem, error) {
+	parser := jsonpath.New("sort").AllowMissingKeys(true)
+	if err := parser.Parse(fmt.Sprintf("{%s}", sortBy)); err != nil {
+		return nil, err
+	}
+
+	var sortedItems []SortedItem
+	var values []interface{}
+
+	for listIndex, list := range lists {
+		items, ok := list["items"].([]interface{})
+		if !ok {
+			continue
+		}
+
+		for _, item := range items {
+			value, err := getSortValue(parser, item)
+			if err != nil {
+				return nil, err
+			}
+
+			sortedItems = append(sortedItems, SortedItem{List: listIndex, Item: item})
+			values = append(values, value)
+		}
+	}
+
+	indexes := make([]int, len(sortedItems))
+	for i := range indexes {
+		indexes[i] = i
+	}
+
+	sort.SliceStable(indexes, func(i, j int) bool {
+		a, b := values[indexes[i]], values[indexes[j]]
+		if a == nil || b == nil {
+			return a != nil && b == nil
+		}
+
+		if descending {
+			return lessSortValue(b, a)
+		}
+		return lessSortValue(a, b)
+	})
+
+	result := make([]SortedItem, 0, len(sortedItems))
+	for _, index := range indexes {
+		result = append(result, sortedItems[index])
+	}
+
+	return result, nil
+}
+
+// SortResources sorts the items of the given list by the value of the given jsonPath. See the SortItems function for
+// the format of the jsonPath and the sort order. If the given object isn't a list (e.g. when a single resource was
+// requested), the object isn't modified.
+func SortResources(list map[string]interface{}, sortBy string, descending bool) error {
+	sortedItems, err := SortItems([]map[string]interface{}{list}, sortBy, descending)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := list["items"].([]interface{}); !ok {
+		return nil
+	}
+
+	items := make([]interface{}, 0, len(sortedItems))
+	for _, sortedItem := range sortedItems {
+		items = append(items, sortedItem.Item)
+	}
+	list["items"] = items
+
+	return nil
+}
+
+// getSortValue returns the first value found for the jsonPath in the given item. If the jsonPath doesn't match any
+// value nil is returned.
+func getSortValue(parser *jsonpath.JSONPath, item interface{}) (interface{}, error) {
+	results, err := parser.FindResults(item)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, result := range results {
+		for _, value := range result {
+			if value.IsValid() && value.CanInterface() {
+				return value.Interface(), nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// lessSortValue compares two values of a JSON document. Numbers are compared by their numeric value, booleans are
+// sorted false before true and all other values are compared by their string representation. Because timestamps in
+// Kubernetes resources are using the RFC3339 format, they are sorted correctly by their string representation.
+func lessSortValue(a, b interface{}) bool {
+	switch aValue := a.(type) {
+	case float64:
+		if bValue, ok := b.(float64); ok {
+			return aValue < bValue
+		}
+	case int64:
+		if bValue, ok := b.(int64); ok {
+			return aValue < bValue
+		}
+	case bool:
+		if bValue, ok := b.(bool); ok {
+			return !aValue && bValue
+		}
+	case string:
+		if bValue, ok := b.(string); ok {
+			return aValue < bValue
+		}
+	}
+
+	return fmt.Sprintf("%v", a) < fmt.Sprintf("%v", b)
+}
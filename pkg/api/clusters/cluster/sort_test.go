@@ -0,0 +1,85 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSortResources(t *testing.T) {
+	pod := func(name string, restarts interface{}) map[string]interface{} {
+		status := map[string]interface{}{}
+		if restarts != nil {
+			status["containerStatuses"] = []interface{}{map[string]interface{}{"restartCount": restarts}}
+		}
+		return map[string]interface{}{"metadata": map[string]interface{}{"name": name}, "status": status}
+	}
+
+	getNames := func(list map[string]interface{}) []string {
+		var names []string
+		for _, item := range list["items"].([]interface{}) {
+			names = append(names, item.(map[string]interface{})["metadata"].(map[string]interface{})["name"].(string))
+		}
+		return names
+	}
+
+	newList := func() map[string]interface{} {
+		return map[string]interface{}{"items": []interface{}{pod("b", float64(10)), pod("c", nil), pod("a", float64(2)), pod("d", float64(10))}}
+	}
+
+	for _, tc := range []struct {
+		name          string
+		sortBy        string
+		descending    bool
+		expectedNames []string
+		isError       bool
+	}{
+		{name: "name ascending", sortBy: ".metadata.name", expectedNames: []string{"a", "b", "c", "d"}},
+		{name: "name descending", sortBy: ".metadata.name", descending: true, expectedNames: []string{"d", "c", "b", "a"}},
+		{name: "number ascending", sortBy: ".status.containerStatuses[0].restartCount", expectedNames: []string{"a", "b", "d", "c"}},
+		{name: "number descending", sortBy: ".status.containerStatuses[0].restartCount", descending: true, expectedNames: []string{"b", "d", "a", "c"}},
+		{name: "missing field", sortBy: ".spec.nodeName", expectedNames: []string{"b", "c", "a", "d"}},
+		{name: "invalid jsonPath", sortBy: ".metadata.name[", isError: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			list := newList()
+			err := SortResources(list, tc.sortBy, tc.descending)
+			if tc.isError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedNames, getNames(list))
+		})
+	}
+
+	t.Run("single object", func(t *testing.T) {
+		object := pod("a", nil)
+		require.NoError(t, SortResources(object, ".metadata.name", false))
+		require.Equal(t, pod("a", nil), object)
+	})
+}
+
+func TestSortItems(t *testing.T) {
+	list := func(names ...string) map[string]interface{} {
+		var items []interface{}
+		for _, name := range names {
+			items = append(items, map[string]interface{}{"metadata": map[string]interface{}{"name": name}})
+		}
+		return map[string]interface{}{"items": items}
+	}
+
+	sortedItems, err := SortItems([]map[string]interface{}{list("b", "d"), {"kind": "Pod"}, list("c", "a")}, ".metadata.name", false)
+	require.NoError(t, err)
+
+	var lists []int
+	var names []string
+	for _, sortedItem := range sortedItems {
+		lists = append(lists, sortedItem.List)
+		names = append(names, sortedItem.Item.(map[string]interface{})["metadata"].(map[string]interface{})["name"].(string))
+	}
+
+	require.Equal(t, []int{2, 0, 2, 0}, lists)
+	require.Equal(t, []string{"a", "b", "c", "d"}, names)
+}
@@ -9,17 +9,32 @@ import (
 	"fmt"
 	"io"
 	"net/url"
+	"os"
+	"strings"
 
 	"github.com/gorilla/websocket"
 	"github.com/sirupsen/logrus"
+	flag "github.com/spf13/pflag"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/remotecommand"
 )
 
 var (
-	log = logrus.WithFields(logrus.Fields{"package": "clusters"})
+	log    = logrus.WithFields(logrus.Fields{"package": "clusters"})
+	shells []string
 )
 
+// init is used to define all command-line flags for the terminal package. The list of allowed shells can be adjusted
+// by operators, e.g. to allow "zsh" or to remove "bash" in hardened environments.
+func init() {
+	defaultShells := []string{"bash", "sh", "powershell", "cmd"}
+	if os.Getenv("KOBS_CLUSTERS_TERMINAL_SHELLS") != "" {
+		defaultShells = strings.Split(os.Getenv("KOBS_CLUSTERS_TERMINAL_SHELLS"), ",")
+	}
+
+	flag.StringSliceVar(&shells, "clusters.terminal.shells", defaultShells, "A list of shells, which are allowed to be used in a terminal session.")
+}
+
 const END_OF_TRANSMISSION = "\u0004"
 
 // PtyHandler is what remotecommand expects from a pty.
@@ -123,9 +138,10 @@ func StartProcess(config *rest.Config, reqURL *url.URL, cmd []string, ptyHandler
 	return nil
 }
 
-// IsValidShell checks if the user provided shell is an allowed one.
+// IsValidShell checks if the user provided shell is an allowed one. The list of allowed shells can be set via the
+// "--clusters.terminal.shells" flag.
 func IsValidShell(shell string) bool {
-	for _, validShell := range []string{"bash", "sh", "powershell", "cmd"} {
+	for _, validShell := range shells {
 		if validShell == shell {
 			return true
 		}
@@ -0,0 +1,29 @@
+package terminal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsValidShell(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		shells []string
+		shell  string
+		expect bool
+	}{
+		{name: "default shell allowed", shells: []string{"bash", "sh", "powershell", "cmd"}, shell: "bash", expect: true},
+		{name: "default shell not allowed", shells: []string{"bash", "sh", "powershell", "cmd"}, shell: "zsh", expect: false},
+		{name: "custom shell allowed", shells: []string{"sh", "zsh"}, shell: "zsh", expect: true},
+		{name: "removed shell not allowed", shells: []string{"sh", "zsh"}, shell: "bash", expect: false},
+		{name: "empty shell not allowed", shells: []string{"sh"}, shell: "", expect: false},
+		{name: "command not allowed", shells: []string{"sh"}, shell: "sh -c id", expect: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			shells = tc.shells
+			actual := IsValidShell(tc.shell)
+			require.Equal(t, tc.expect, actual)
+		})
+	}
+}
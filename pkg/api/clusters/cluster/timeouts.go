@@ -0,0 +1,23 @@
+package cluster
+
+import (
+	"time"
+)
+
+// Timeouts are the timeouts for the requests against the Kubernetes API servers of the clusters from a provider. The
+// default timeout is used for all clusters, which do not have their own timeout in the clusters map. The map is keyed by
+// the name of the cluster (e.g. the name of the context in a Kubeconfig file). A value of 0 means no timeout. The
+// timeouts are not used for long running operations like watching resources, streaming logs or the terminal.
+type Timeouts struct {
+	Default  time.Duration
+	Clusters map[string]time.Duration
+}
+
+// Get returns the timeout for the cluster with the given name.
+func (t Timeouts) Get(name string) time.Duration {
+	if timeout, ok := t.Clusters[name]; ok {
+		return timeout
+	}
+
+	return t.Default
+}
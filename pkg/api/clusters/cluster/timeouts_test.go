@@ -0,0 +1,17 @@
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeoutsGet(t *testing.T) {
+	timeouts := Timeouts{Default: 30 * time.Second, Clusters: map[string]time.Duration{"prod": time.Minute, "dev": 0}}
+
+	require.Equal(t, time.Minute, timeouts.Get("prod"))
+	require.Equal(t, time.Duration(0), timeouts.Get("dev"))
+	require.Equal(t, 30*time.Second, timeouts.Get("stage"))
+	require.Equal(t, time.Duration(0), Timeouts{}.Get("prod"))
+}
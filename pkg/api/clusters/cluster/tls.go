@@ -0,0 +1,82 @@
+package cluster
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+
+	"k8s.io/client-go/rest"
+)
+
+// TLSConfig is the configuration for the client certificate, which is used to authenticate against the Kubernetes API
+// server of a cluster (mTLS). The certificate and key can be provided as path to a file or as inline PEM encoded data.
+// When a client certificate is configured, it is used alongside the other authentication methods of the cluster (e.g.
+// a bearer token). A client certificate can only be configured for clusters, which do not already have a client
+// certificate (e.g. from the context in the Kubeconfig file).
+type TLSConfig struct {
+	ClientCertificate     string `json:"clientCertificate"`
+	ClientKey             string `json:"clientKey"`
+	ClientCertificateData string `json:"clientCertificateData"`
+	ClientKeyData         string `json:"clientKeyData"`
+}
+
+// loadPEM returns the inline PEM data or when no inline data is provided the content of the file at the given path.
+func loadPEM(data, path string) ([]byte, error) {
+	if data != "" {
+		return []byte(data), nil
+	}
+
+	if path != "" {
+		return ioutil.ReadFile(path)
+	}
+
+	return nil, nil
+}
+
+// apply loads the client certificate and key and adds them to the given rest config. Before the certificate and key
+// are added, we validate that they are a valid key pair, so that a wrong configuration is detected when kobs is
+// started and not with the first request against the Kubernetes API server. If the rest config already contains a
+// client certificate or key, an error is returned, because it isn't clear which certificate should be used.
+func (t *TLSConfig) apply(restConfig *rest.Config) error {
+	if t == nil {
+		return nil
+	}
+
+	cert, err := loadPEM(t.ClientCertificateData, t.ClientCertificate)
+	if err != nil {
+		return fmt.Errorf("could not load client certificate: %w", err)
+	}
+
+	key, err := loadPEM(t.ClientKeyData, t.ClientKey)
+	if err != nil {
+		return fmt.Errorf("could not load client key: %w", err)
+	}
+
+	if cert == nil && key == nil {
+		return nil
+	}
+
+	if cert == nil || key == nil {
+		return fmt.Errorf("client certificate and key must be provided together")
+	}
+
+	if _, err := tls.X509KeyPair(cert, key); err != nil {
+		return fmt.Errorf("invalid client certificate and key: %w", err)
+	}
+
+	if hasClientCertificate(restConfig) {
+		return fmt.Errorf("cluster %s already has a client certificate", restConfig.Host)
+	}
+
+	restConfig.TLSClientConfig.CertData = cert
+	restConfig.TLSClientConfig.KeyData = key
+
+	return nil
+}
+
+// hasClientCertificate returns true, when the given rest config already contains a client certificate or key, either
+// as file or as inline data.
+func hasClientCertificate(restConfig *rest.Config) bool {
+	tlsClientConfig := restConfig.TLSClientConfig
+	return tlsClientConfig.CertFile != "" || tlsClientConfig.KeyFile != "" || len(tlsClientConfig.CertData) > 0 || len(tlsClientConfig.KeyData) > 0
+}
@@ -0,0 +1,96 @@
+package cluster
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+)
+
+func generateKeyPair(t *testing.T) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "kobs"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	cert, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyBytes, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyBytes})
+}
+
+func TestTLSConfigApply(t *testing.T) {
+	cert, key := generateKeyPair(t)
+	_, otherKey := generateKeyPair(t)
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tls.crt"), cert, 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tls.key"), key, 0600))
+
+	t.Run("no config", func(t *testing.T) {
+		var tlsConfig *TLSConfig
+		restConfig := &rest.Config{BearerToken: "token"}
+		require.NoError(t, tlsConfig.apply(restConfig))
+		require.Nil(t, restConfig.TLSClientConfig.CertData)
+	})
+
+	t.Run("inline data", func(t *testing.T) {
+		restConfig := &rest.Config{BearerToken: "token"}
+		require.NoError(t, (&TLSConfig{ClientCertificateData: string(cert), ClientKeyData: string(key)}).apply(restConfig))
+		require.Equal(t, cert, restConfig.TLSClientConfig.CertData)
+		require.Equal(t, key, restConfig.TLSClientConfig.KeyData)
+		require.Equal(t, "token", restConfig.BearerToken)
+	})
+
+	t.Run("files", func(t *testing.T) {
+		restConfig := &rest.Config{}
+		require.NoError(t, (&TLSConfig{ClientCertificate: filepath.Join(dir, "tls.crt"), ClientKey: filepath.Join(dir, "tls.key")}).apply(restConfig))
+		require.Equal(t, cert, restConfig.TLSClientConfig.CertData)
+		require.Equal(t, key, restConfig.TLSClientConfig.KeyData)
+	})
+
+	t.Run("existing client certificate", func(t *testing.T) {
+		otherCert, otherKey := generateKeyPair(t)
+		restConfig := &rest.Config{TLSClientConfig: rest.TLSClientConfig{CertData: otherCert, KeyData: otherKey}}
+		require.Error(t, (&TLSConfig{ClientCertificateData: string(cert), ClientKeyData: string(key)}).apply(restConfig))
+		require.Equal(t, otherCert, restConfig.TLSClientConfig.CertData)
+		require.Equal(t, otherKey, restConfig.TLSClientConfig.KeyData)
+	})
+
+	t.Run("existing client certificate file", func(t *testing.T) {
+		restConfig := &rest.Config{TLSClientConfig: rest.TLSClientConfig{CertFile: "/etc/kubernetes/tls.crt", KeyFile: "/etc/kubernetes/tls.key"}}
+		require.Error(t, (&TLSConfig{ClientCertificateData: string(cert), ClientKeyData: string(key)}).apply(restConfig))
+		require.Equal(t, "/etc/kubernetes/tls.crt", restConfig.TLSClientConfig.CertFile)
+		require.Nil(t, restConfig.TLSClientConfig.CertData)
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		require.Error(t, (&TLSConfig{ClientCertificateData: string(cert)}).apply(&rest.Config{}))
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		require.Error(t, (&TLSConfig{ClientCertificate: filepath.Join(dir, "missing.crt"), ClientKeyData: string(key)}).apply(&rest.Config{}))
+	})
+
+	t.Run("key pair mismatch", func(t *testing.T) {
+		require.Error(t, (&TLSConfig{ClientCertificateData: string(cert), ClientKeyData: string(otherKey)}).apply(&rest.Config{}))
+	})
+}
@@ -0,0 +1,157 @@
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/kube-openapi/pkg/util/proto"
+	"k8s.io/kube-openapi/pkg/util/proto/validation"
+	"sigs.k8s.io/yaml"
+)
+
+// openAPICache caches the OpenAPI schema of a cluster. Next to the parsed models we also keep a map of all group,
+// version, kind combinations to the name of the corresponding model, so that we can lookup the model for a manifest.
+type openAPICache struct {
+	mutex     sync.Mutex
+	models    proto.Models
+	gvks      map[schema.GroupVersionKind]string
+	lastFetch time.Time
+}
+
+// ValidationError is a single field-level error, which was found during the validation of a manifest against the
+// OpenAPI schema of a cluster.
+type ValidationError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// getOpenAPIModels returns the OpenAPI models of the cluster and the map of group, version, kind combinations to model
+// names. The models are cached for the duration defined via the "--clusters.cache-duration.openapi" flag, because CRDs
+// can change the schema of a cluster.
+func (c *Cluster) getOpenAPIModels() (proto.Models, map[schema.GroupVersionKind]string, error) {
+	c.openAPI.mutex.Lock()
+	defer c.openAPI.mutex.Unlock()
+
+	if c.openAPI.models != nil && c.openAPI.lastFetch.After(time.Now().Add(-1*cacheDurationOpenAPI)) {
+		return c.openAPI.models, c.openAPI.gvks, nil
+	}
+
+	document, err := c.clientset.Discovery().OpenAPISchema()
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name}).Errorf("getOpenAPIModels")
+		return nil, nil, err
+	}
+
+	models, err := proto.NewOpenAPIData(document)
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name}).Errorf("getOpenAPIModels")
+		return nil, nil, err
+	}
+
+	c.openAPI.models = models
+	c.openAPI.gvks = getModelGVKs(models)
+	c.openAPI.lastFetch = time.Now()
+
+	return c.openAPI.models, c.openAPI.gvks, nil
+}
+
+// getModelGVKs returns a map of all group, version, kind combinations to the name of the model, which is used for the
+// group, version and kind. The combinations are defined via the "x-kubernetes-group-version-kind" extension of a model.
+func getModelGVKs(models proto.Models) map[schema.GroupVersionKind]string {
+	gvks := make(map[schema.GroupVersionKind]string)
+
+	for _, name := range models.ListModels() {
+		model := models.LookupModel(name)
+		if model == nil {
+			continue
+		}
+
+		extension, ok := model.GetExtensions()["x-kubernetes-group-version-kind"].([]interface{})
+		if !ok {
+			continue
+		}
+
+		for _, item := range extension {
+			gvk, ok := item.(map[interface{}]interface{})
+			if !ok {
+				continue
+			}
+
+			group, _ := gvk["group"].(string)
+			version, _ := gvk["version"].(string)
+			kind, _ := gvk["kind"].(string)
+
+			gvks[schema.GroupVersionKind{Group: group, Version: version, Kind: kind}] = name
+		}
+	}
+
+	return gvks
+}
+
+// ValidateManifest validates the given manifest against the OpenAPI schema of the cluster. The manifest can be provided
+// as JSON or YAML. The function returns a list of field-level errors, which is empty when the manifest is valid. An
+// error is only returned when the manifest could not be parsed or when the schema could not be loaded.
+func (c *Cluster) ValidateManifest(manifest []byte) ([]ValidationError, error) {
+	jsonManifest, err := yaml.YAMLToJSON(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal(jsonManifest, &obj); err != nil {
+		return nil, err
+	}
+
+	apiVersion, _ := obj["apiVersion"].(string)
+	kind, _ := obj["kind"].(string)
+	if apiVersion == "" || kind == "" {
+		return nil, fmt.Errorf("manifest must contain apiVersion and kind")
+	}
+
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	models, gvks, err := c.getOpenAPIModels()
+	if err != nil {
+		return nil, err
+	}
+
+	name, ok := gvks[gv.WithKind(kind)]
+	if !ok {
+		return nil, fmt.Errorf("no schema found for %s, %s", apiVersion, kind)
+	}
+
+	var validationErrors []ValidationError
+	for _, err := range validation.ValidateModel(obj, models.LookupModel(name), kind) {
+		validationErrors = append(validationErrors, getValidationError(err))
+	}
+
+	return validationErrors, nil
+}
+
+// getValidationError converts an error returned by the OpenAPI validation into our ValidationError format, so that the
+// frontend can show the error next to the invalid field.
+func getValidationError(err error) ValidationError {
+	if validationError, ok := err.(validation.ValidationError); ok {
+		switch e := validationError.Err.(type) {
+		case validation.UnknownFieldError:
+			return ValidationError{Field: e.Path + "." + e.Field, Message: e.Error()}
+		case validation.MissingRequiredFieldError:
+			return ValidationError{Field: e.Path + "." + e.Field, Message: e.Error()}
+		case validation.InvalidTypeError:
+			return ValidationError{Field: e.Path, Message: e.Error()}
+		case validation.InvalidObjectTypeError:
+			return ValidationError{Field: e.Path, Message: e.Error()}
+		}
+
+		return ValidationError{Field: validationError.Path, Message: validationError.Err.Error()}
+	}
+
+	return ValidationError{Message: err.Error()}
+}
@@ -0,0 +1,37 @@
+package cluster
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/kube-openapi/pkg/util/proto/validation"
+)
+
+func TestGetValidationError(t *testing.T) {
+	for _, tt := range []struct {
+		err      error
+		expected ValidationError
+	}{
+		{
+			err:      validation.ValidationError{Path: "Deployment.spec", Err: validation.UnknownFieldError{Path: "Deployment.spec", Field: "replica"}},
+			expected: ValidationError{Field: "Deployment.spec.replica", Message: "unknown field \"replica\" in Deployment.spec"},
+		},
+		{
+			err:      validation.ValidationError{Path: "Deployment.spec", Err: validation.MissingRequiredFieldError{Path: "Deployment.spec", Field: "selector"}},
+			expected: ValidationError{Field: "Deployment.spec.selector", Message: "missing required field \"selector\" in Deployment.spec"},
+		},
+		{
+			err:      validation.ValidationError{Path: "Deployment.spec.replicas", Err: validation.InvalidTypeError{Path: "Deployment.spec.replicas", Expected: "integer", Actual: "string"}},
+			expected: ValidationError{Field: "Deployment.spec.replicas", Message: "invalid type for Deployment.spec.replicas: got \"string\", expected \"integer\""},
+		},
+		{
+			err:      fmt.Errorf("some error"),
+			expected: ValidationError{Message: "some error"},
+		},
+	} {
+		t.Run(tt.expected.Message, func(t *testing.T) {
+			require.Equal(t, tt.expected, getValidationError(tt.err))
+		})
+	}
+}
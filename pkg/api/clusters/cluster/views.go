@@ -0,0 +1,81 @@
+package cluster
+
+import (
+	"fmt"
+
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// CRDView is a custom view for a CRD, which can be configured by the operators of kobs. A view is identified by the
+// group, version and kind of the CRD and defines which fields, links and nested tables should be shown in the frontend,
+// instead of the generic table.
+type CRDView struct {
+	Group   string         `json:"group"`
+	Version string         `json:"version"`
+	Kind    string         `json:"kind"`
+	Fields  []CRDViewField `json:"fields,omitempty"`
+	Links   []CRDViewField `json:"links,omitempty"`
+	Tables  []CRDViewTable `json:"tables,omitempty"`
+}
+
+// CRDViewField is a single field or link in a view. The value of the field is returned by the given jsonPath.
+type CRDViewField struct {
+	Title    string `json:"title"`
+	JSONPath string `json:"jsonPath"`
+}
+
+// CRDViewTable is a nested table in a view. The jsonPath must return a list of objects and the columns are used to
+// render each object in this list. The jsonPaths of the columns are relative to an object in the list.
+type CRDViewTable struct {
+	Title    string      `json:"title"`
+	JSONPath string      `json:"jsonPath"`
+	Columns  []CRDColumn `json:"columns"`
+}
+
+// matches returns true if the view should be used for the given CRD.
+func (v CRDView) matches(crd CRD) bool {
+	return crd.Path == fmt.Sprintf("%s/%s", v.Group, v.Version) && crd.Title == v.Kind
+}
+
+// ValidateViews checks that all views contain a group, version and kind and that all configured jsonPaths can be
+// parsed. This should be called when the configuration is loaded, so that an invalid view isn't silently ignored.
+func ValidateViews(views []CRDView) error {
+	for _, view := range views {
+		if view.Group == "" || view.Version == "" || view.Kind == "" {
+			return fmt.Errorf("view must contain a group, version and kind")
+		}
+
+		name := fmt.Sprintf("%s/%s/%s", view.Group, view.Version, view.Kind)
+
+		for _, field := range append(view.Fields, view.Links...) {
+			if err := validateJSONPath(field.JSONPath); err != nil {
+				return fmt.Errorf("invalid jsonPath %s for field %s in view %s: %w", field.JSONPath, field.Title, name, err)
+			}
+		}
+
+		for _, table := range view.Tables {
+			if err := validateJSONPath(table.JSONPath); err != nil {
+				return fmt.Errorf("invalid jsonPath %s for table %s in view %s: %w", table.JSONPath, table.Title, name, err)
+			}
+
+			for _, column := range table.Columns {
+				if err := validateJSONPath(column.JSONPath); err != nil {
+					return fmt.Errorf("invalid jsonPath %s for column %s in view %s: %w", column.JSONPath, column.Name, name, err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateJSONPath checks if the given jsonPath can be parsed. The jsonPaths are using the same format as the
+// additionalPrinterColumns of a CRD (e.g. ".spec.replicas"), so that we have to wrap them in curly braces before we
+// can parse them.
+func validateJSONPath(path string) error {
+	if path == "" {
+		return fmt.Errorf("jsonPath is required")
+	}
+
+	return jsonpath.New("view").Parse(fmt.Sprintf("{%s}", path))
+}
@@ -0,0 +1,31 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateViews(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		views   []CRDView
+		isError bool
+	}{
+		{name: "no views", views: nil, isError: false},
+		{name: "valid view", views: []CRDView{{Group: "kobs.io", Version: "v1beta1", Kind: "Team", Fields: []CRDViewField{{Title: "Description", JSONPath: ".spec.description"}}, Tables: []CRDViewTable{{Title: "Links", JSONPath: ".spec.links", Columns: []CRDColumn{{Name: "Title", JSONPath: ".title"}}}}}}, isError: false},
+		{name: "missing kind", views: []CRDView{{Group: "kobs.io", Version: "v1beta1"}}, isError: true},
+		{name: "missing jsonPath", views: []CRDView{{Group: "kobs.io", Version: "v1beta1", Kind: "Team", Links: []CRDViewField{{Title: "Link"}}}}, isError: true},
+		{name: "invalid jsonPath", views: []CRDView{{Group: "kobs.io", Version: "v1beta1", Kind: "Team", Fields: []CRDViewField{{Title: "Description", JSONPath: ".spec.links[0"}}}}, isError: true},
+		{name: "invalid column jsonPath", views: []CRDView{{Group: "kobs.io", Version: "v1beta1", Kind: "Team", Tables: []CRDViewTable{{Title: "Links", JSONPath: ".spec.links", Columns: []CRDColumn{{Name: "Title", JSONPath: ".title["}}}}}}, isError: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateViews(tt.views)
+			if tt.isError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
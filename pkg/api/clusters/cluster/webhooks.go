@@ -0,0 +1,151 @@
+package cluster
+
+import (
+	"context"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WebhookInfo describes a single validating or mutating admission webhook. The configuration is the name of the
+// ValidatingWebhookConfiguration or MutatingWebhookConfiguration, which contains the webhook. The webhook is either
+// served by a service in the cluster or by the given url.
+type WebhookInfo struct {
+	Name              string          `json:"name"`
+	Configuration     string          `json:"configuration"`
+	Type              string          `json:"type"`
+	FailurePolicy     string          `json:"failurePolicy,omitempty"`
+	SideEffects       string          `json:"sideEffects,omitempty"`
+	TimeoutSeconds    int32           `json:"timeoutSeconds,omitempty"`
+	Service           *WebhookService `json:"service,omitempty"`
+	URL               string          `json:"url,omitempty"`
+	NamespaceSelector string          `json:"namespaceSelector,omitempty"`
+	Rules             []WebhookRule   `json:"rules"`
+}
+
+// WebhookService is the service, which serves an admission webhook.
+type WebhookService struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Path      string `json:"path,omitempty"`
+	Port      int32  `json:"port,omitempty"`
+}
+
+// WebhookRule describes for which operations and resources an admission webhook is called.
+type WebhookRule struct {
+	Operations  []string `json:"operations"`
+	APIGroups   []string `json:"apiGroups"`
+	APIVersions []string `json:"apiVersions"`
+	Resources   []string `json:"resources"`
+	Scope       string   `json:"scope,omitempty"`
+}
+
+// GetAdmissionWebhooks returns all validating and mutating admission webhooks of the cluster, with their rules, failure
+// policy and target. This can be used to find out why a request was rejected or modified by the Kubernetes API server.
+// The webhooks are sorted by the type, configuration and name.
+func (c *Cluster) GetAdmissionWebhooks(ctx context.Context) ([]WebhookInfo, error) {
+	validatingConfigurations, err := c.clientset.AdmissionregistrationV1().ValidatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name}).Errorf("GetAdmissionWebhooks")
+		return nil, err
+	}
+
+	mutatingConfigurations, err := c.clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name}).Errorf("GetAdmissionWebhooks")
+		return nil, err
+	}
+
+	var webhooks []WebhookInfo
+
+	for _, configuration := range validatingConfigurations.Items {
+		for _, webhook := range configuration.Webhooks {
+			webhooks = append(webhooks, getWebhookInfo(webhook.Name, configuration.Name, "validating", webhook.FailurePolicy, webhook.SideEffects, webhook.TimeoutSeconds, webhook.ClientConfig, webhook.NamespaceSelector, webhook.Rules))
+		}
+	}
+
+	for _, configuration := range mutatingConfigurations.Items {
+		for _, webhook := range configuration.Webhooks {
+			webhooks = append(webhooks, getWebhookInfo(webhook.Name, configuration.Name, "mutating", webhook.FailurePolicy, webhook.SideEffects, webhook.TimeoutSeconds, webhook.ClientConfig, webhook.NamespaceSelector, webhook.Rules))
+		}
+	}
+
+	sort.Slice(webhooks, func(i, j int) bool {
+		if webhooks[i].Type != webhooks[j].Type {
+			return webhooks[i].Type < webhooks[j].Type
+		}
+		if webhooks[i].Configuration != webhooks[j].Configuration {
+			return webhooks[i].Configuration < webhooks[j].Configuration
+		}
+		return webhooks[i].Name < webhooks[j].Name
+	})
+
+	return webhooks, nil
+}
+
+// getWebhookInfo returns the WebhookInfo for a single webhook. Since validating and mutating webhooks are different
+// types, we have to pass all the required fields to this function.
+func getWebhookInfo(name, configuration, webhookType string, failurePolicy *admissionregistrationv1.FailurePolicyType, sideEffects *admissionregistrationv1.SideEffectClass, timeoutSeconds *int32, clientConfig admissionregistrationv1.WebhookClientConfig, namespaceSelector *metav1.LabelSelector, rules []admissionregistrationv1.RuleWithOperations) WebhookInfo {
+	info := WebhookInfo{
+		Name:          name,
+		Configuration: configuration,
+		Type:          webhookType,
+	}
+
+	if failurePolicy != nil {
+		info.FailurePolicy = string(*failurePolicy)
+	}
+
+	if sideEffects != nil {
+		info.SideEffects = string(*sideEffects)
+	}
+
+	if timeoutSeconds != nil {
+		info.TimeoutSeconds = *timeoutSeconds
+	}
+
+	if clientConfig.Service != nil {
+		info.Service = &WebhookService{
+			Namespace: clientConfig.Service.Namespace,
+			Name:      clientConfig.Service.Name,
+		}
+
+		if clientConfig.Service.Path != nil {
+			info.Service.Path = *clientConfig.Service.Path
+		}
+
+		if clientConfig.Service.Port != nil {
+			info.Service.Port = *clientConfig.Service.Port
+		}
+	}
+
+	if clientConfig.URL != nil {
+		info.URL = *clientConfig.URL
+	}
+
+	if namespaceSelector != nil {
+		info.NamespaceSelector = metav1.FormatLabelSelector(namespaceSelector)
+	}
+
+	for _, rule := range rules {
+		webhookRule := WebhookRule{
+			APIGroups:   rule.APIGroups,
+			APIVersions: rule.APIVersions,
+			Resources:   rule.Resources,
+		}
+
+		for _, operation := range rule.Operations {
+			webhookRule.Operations = append(webhookRule.Operations, string(operation))
+		}
+
+		if rule.Scope != nil {
+			webhookRule.Scope = string(*rule.Scope)
+		}
+
+		info.Rules = append(info.Rules, webhookRule)
+	}
+
+	return info
+}
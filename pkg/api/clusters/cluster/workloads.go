@@ -0,0 +1,160 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// getWorkloadSelector returns the label selector for the Pods of the given workload. The workload can be a Deployment,
+// StatefulSet, DaemonSet, ReplicaSet, Job or Service.
+func (c *Cluster) getWorkloadSelector(ctx context.Context, namespace, kind, name string) (labels.Selector, error) {
+	var selector *metav1.LabelSelector
+
+	switch kind {
+	case "deployments":
+		deployment, err := c.clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector = deployment.Spec.Selector
+	case "statefulsets":
+		statefulSet, err := c.clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector = statefulSet.Spec.Selector
+	case "daemonsets":
+		daemonSet, err := c.clientset.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector = daemonSet.Spec.Selector
+	case "replicasets":
+		replicaSet, err := c.clientset.AppsV1().ReplicaSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector = replicaSet.Spec.Selector
+	case "jobs":
+		job, err := c.clientset.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector = job.Spec.Selector
+	case "services":
+		service, err := c.clientset.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+
+		// A Service without a selector doesn't select any Pods, so that we return an error instead of the selector,
+		// which would match all Pods in the namespace.
+		if len(service.Spec.Selector) == 0 {
+			return nil, fmt.Errorf("service %s doesn't have a selector", name)
+		}
+
+		return labels.SelectorFromSet(service.Spec.Selector), nil
+	default:
+		return nil, fmt.Errorf("unsupported workload type %s", kind)
+	}
+
+	if selector == nil {
+		return nil, fmt.Errorf("%s %s doesn't have a selector", kind, name)
+	}
+
+	return metav1.LabelSelectorAsSelector(selector)
+}
+
+// StreamWorkloadLogs streams the logs of all Pods of a workload via the passed in WebSocket connection. The Pods are
+// selected via the selector of the workload. While the logs are streamed, we are checking for new Pods in the
+// jobPodsPollInterval, so that the logs of new Pods are added to the stream. The streams of deleted Pods are closed by
+// the Kubernetes API. Each line is prefixed with the name of the Pod.
+// To not overload the Kubernetes API, we never stream the logs of more than maxStreams Pods at the same time. When the
+// limit is reached, new Pods are skipped until the stream of another Pod is closed. The function returns when the
+// passed in context is canceled.
+func (c *Cluster) StreamWorkloadLogs(ctx context.Context, conn *websocket.Conn, namespace, kind, name, container string, since int64, maxMessageSize, maxStreams int) error {
+	activeWebSocketsMetric.WithLabelValues("logs").Inc()
+	defer activeWebSocketsMetric.WithLabelValues("logs").Dec()
+
+	selector, err := c.getWorkloadSelector(ctx, namespace, kind, name)
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "kind": kind, "name": name}).Errorf("StreamWorkloadLogs")
+		return err
+	}
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	writer := &lockedWriter{conn: conn}
+	streamed := make(map[types.UID]bool)
+	streamsLimitReported := false
+
+	var mutex sync.Mutex
+	activeStreams := 0
+
+	ticker := time.NewTicker(jobPodsPollInterval)
+	defer ticker.Stop()
+
+	for {
+		pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			log.WithError(err).WithFields(logrus.Fields{"cluster": c.name, "namespace": namespace, "kind": kind, "name": name}).Errorf("StreamWorkloadLogs")
+			return err
+		}
+
+		for _, pod := range pods.Items {
+			if streamed[pod.UID] || pod.Status.Phase == corev1.PodPending {
+				continue
+			}
+
+			mutex.Lock()
+			if activeStreams >= maxStreams {
+				mutex.Unlock()
+
+				if !streamsLimitReported {
+					streamsLimitReported = true
+					writer.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf("Only the logs of %d Pods are streamed at the same time, further Pods are skipped", maxStreams)))
+				}
+				break
+			}
+			activeStreams++
+			mutex.Unlock()
+
+			streamed[pod.UID] = true
+			wg.Add(1)
+
+			go func(pod corev1.Pod) {
+				defer func() {
+					mutex.Lock()
+					activeStreams--
+					mutex.Unlock()
+					wg.Done()
+				}()
+
+				c.streamPrefixedPodLogs(ctx, writer, pod, container, since, maxMessageSize)
+			}(pod)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
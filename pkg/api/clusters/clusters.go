@@ -1,7 +1,10 @@
 package clusters
 
 import (
+	"errors"
+	"fmt"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/kobsio/kobs/pkg/api/clusters/cluster"
@@ -14,7 +17,12 @@ import (
 var (
 	log                     = logrus.WithFields(logrus.Fields{"package": "clusters"})
 	cacheDurationNamespaces time.Duration
+	favoritesCluster        string
+	favoritesNamespace      string
 	forbiddenResources      []string
+
+	// ErrClusterNotFound is returned by the GetCluster function, when no cluster with the given name exists.
+	ErrClusterNotFound = errors.New("cluster not found")
 )
 
 // init is used to define all command-line flags for the clusters package.
@@ -28,35 +36,109 @@ func init() {
 	}
 
 	flag.DurationVar(&cacheDurationNamespaces, "clusters.cache-duration.namespaces", defaultCacheDurationNamespaces, "The duration, for how long requests to get the list of namespaces should be cached.")
+
+	defaultFavoritesCluster := ""
+	if os.Getenv("KOBS_CLUSTERS_FAVORITES_CLUSTER") != "" {
+		defaultFavoritesCluster = os.Getenv("KOBS_CLUSTERS_FAVORITES_CLUSTER")
+	}
+
+	defaultFavoritesNamespace := "kobs"
+	if os.Getenv("KOBS_CLUSTERS_FAVORITES_NAMESPACE") != "" {
+		defaultFavoritesNamespace = os.Getenv("KOBS_CLUSTERS_FAVORITES_NAMESPACE")
+	}
+
+	flag.StringVar(&favoritesCluster, "clusters.favorites.cluster", defaultFavoritesCluster, "The cluster, where the pinned resources of the users are saved. If no cluster is provided, the first cluster is used.")
+	flag.StringVar(&favoritesNamespace, "clusters.favorites.namespace", defaultFavoritesNamespace, "The namespace, where the pinned resources of the users are saved.")
 }
 
 // Config is the configuration required to load all clusters. It takes an array of providers, which are defined in the
-// providers package.
+// providers package. The optional views can be used to define custom views for CRDs, which are used in the frontend
+// instead of the generic table. The optional default cluster is used, when a request doesn't specify any cluster.
 type Config struct {
-	Providers []provider.Config `json:"providers"`
+	Providers      []provider.Config `json:"providers"`
+	Views          []cluster.CRDView `json:"views"`
+	DefaultCluster string            `json:"defaultCluster"`
 }
 
 // TODO
 // Clusters contains all fields and methods to interact with the configured Kubernetes clusters. It must implement the
-// Clusters service from the protocol buffers definition.
+// Clusters service from the protocol buffers definition. The loaded clusters are only accessible via the GetClusters
+// and GetCluster methods, so that all reads are guarded by the mutex.
 type Clusters struct {
-	Clusters []*cluster.Cluster
+	clusters       []*cluster.Cluster
+	mutex          sync.RWMutex
+	limiter        *limiter
+	defaultCluster string
+}
+
+// GetStatus returns the status of all loaded clusters.
+func (c *Clusters) GetStatus() []cluster.Status {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	var status []cluster.Status
+	for _, cl := range c.clusters {
+		status = append(status, cl.GetStatus())
+	}
+
+	return status
+}
+
+// GetClusters returns all loaded clusters. The returned slice is a copy, so that the caller can iterate over the
+// clusters without holding the lock.
+func (c *Clusters) GetClusters() []*cluster.Cluster {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	clusters := make([]*cluster.Cluster, len(c.clusters))
+	copy(clusters, c.clusters)
+	return clusters
 }
 
-func (c *Clusters) GetCluster(name string) *cluster.Cluster {
-	for _, cl := range c.Clusters {
+// GetCluster returns the cluster with the given name. If no cluster with the given name exists, the ErrClusterNotFound
+// error is returned, so that the caller can distinguish between a missing cluster and other errors.
+func (c *Clusters) GetCluster(name string) (*cluster.Cluster, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	for _, cl := range c.clusters {
 		if cl.GetName() == name {
-			return cl
+			return cl, nil
 		}
 	}
 
-	return nil
+	return nil, ErrClusterNotFound
+}
+
+// GetClusterNames returns the given cluster names. If no cluster names are given and a default cluster is configured,
+// a slice with the name of the default cluster is returned, so that single cluster setups do not have to specify the
+// cluster in every request.
+func (c *Clusters) GetClusterNames(names []string) []string {
+	if len(names) == 0 && c.defaultCluster != "" {
+		return []string{c.defaultCluster}
+	}
+
+	return names
+}
+
+// Close stops the background tasks of all clusters.
+func (c *Clusters) Close() {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	for _, cl := range c.clusters {
+		cl.Close()
+	}
 }
 
 // Load loads all clusters for the given configuration.
 // The clusters can be retrieved from different providers. Currently we are supporting incluster configuration and
 // kubeconfig files. In the future it is planning to directly support GKE, EKS, AKS, etc.
 func Load(config Config) (*Clusters, error) {
+	if err := cluster.ValidateViews(config.Views); err != nil {
+		return nil, err
+	}
+
 	var clusters []*cluster.Cluster
 
 	for _, p := range config.Providers {
@@ -70,8 +152,25 @@ func Load(config Config) (*Clusters, error) {
 		}
 	}
 
+	var names []string
+	var hasDefaultCluster bool
+	for _, c := range clusters {
+		c.SetViews(config.Views)
+		names = append(names, c.GetName())
+
+		if c.GetName() == config.DefaultCluster {
+			hasDefaultCluster = true
+		}
+	}
+
+	if config.DefaultCluster != "" && !hasDefaultCluster {
+		return nil, fmt.Errorf("default cluster %s not found", config.DefaultCluster)
+	}
+
 	cs := &Clusters{
-		Clusters: clusters,
+		clusters:       clusters,
+		limiter:        newLimiter(names, concurrencyGlobal, concurrencyPerCluster, concurrencyTimeout),
+		defaultCluster: config.DefaultCluster,
 	}
 
 	return cs, nil
@@ -0,0 +1,95 @@
+package clusters
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kobsio/kobs/pkg/api/clusters/cluster"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+)
+
+func TestGetCluster(t *testing.T) {
+	c, err := cluster.NewCluster("dev-de1", &rest.Config{Host: "http://localhost:0"}, nil)
+	require.NoError(t, err)
+
+	clusters := &Clusters{clusters: []*cluster.Cluster{c}}
+
+	t.Run("present cluster", func(t *testing.T) {
+		actual, err := clusters.GetCluster("dev-de1")
+		require.NoError(t, err)
+		require.Equal(t, c, actual)
+	})
+
+	t.Run("absent cluster", func(t *testing.T) {
+		actual, err := clusters.GetCluster("stage-de1")
+		require.Equal(t, ErrClusterNotFound, err)
+		require.Nil(t, actual)
+	})
+}
+
+func TestGetClusterNames(t *testing.T) {
+	t.Run("without default cluster", func(t *testing.T) {
+		clusters := &Clusters{}
+		require.Empty(t, clusters.GetClusterNames(nil))
+		require.Equal(t, []string{"dev-de1"}, clusters.GetClusterNames([]string{"dev-de1"}))
+	})
+
+	t.Run("with default cluster", func(t *testing.T) {
+		clusters := &Clusters{defaultCluster: "dev-de1"}
+		require.Equal(t, []string{"dev-de1"}, clusters.GetClusterNames(nil))
+		require.Equal(t, []string{"stage-de1", "prod-de1"}, clusters.GetClusterNames([]string{"stage-de1", "prod-de1"}))
+	})
+}
+
+func TestAcquire(t *testing.T) {
+	clusters := &Clusters{limiter: newLimiter([]string{"dev-de1"}, 2, 1, 10*time.Millisecond)}
+
+	release, err := clusters.Acquire(context.Background(), "dev-de1")
+	require.NoError(t, err)
+
+	_, err = clusters.Acquire(context.Background(), "dev-de1")
+	require.Equal(t, ErrTooManyRequests, err)
+
+	_, err = clusters.Acquire(context.Background(), "stage-de1")
+	require.Equal(t, ErrUnknownCluster, err)
+
+	release()
+
+	release, err = clusters.Acquire(context.Background(), "dev-de1")
+	require.NoError(t, err)
+	release()
+
+	t.Run("global limit", func(t *testing.T) {
+		clusters := &Clusters{limiter: newLimiter([]string{"dev-de1", "stage-de1"}, 1, 0, 10*time.Millisecond)}
+
+		release, err := clusters.Acquire(context.Background(), "dev-de1")
+		require.NoError(t, err)
+
+		_, err = clusters.Acquire(context.Background(), "stage-de1")
+		require.Equal(t, ErrTooManyRequests, err)
+
+		release()
+
+		release, err = clusters.Acquire(context.Background(), "unknown")
+		require.NoError(t, err)
+		release()
+	})
+}
+
+func TestRulesCache(t *testing.T) {
+	cache := &rulesCache{entries: make(map[string]rulesCacheEntry)}
+	cache.entries["expired"] = rulesCacheEntry{rules: map[string][]string{"pods": {"get"}}, lastFetch: time.Now().Add(-2 * rulesCacheDuration)}
+
+	_, ok := cache.get("expired")
+	require.False(t, ok)
+
+	cache.set("user1/dev-de1/default", map[string][]string{"pods": {"get", "list"}})
+	require.Len(t, cache.entries, 1)
+
+	rules, ok := cache.get("user1/dev-de1/default")
+	require.True(t, ok)
+	require.Equal(t, map[string][]string{"pods": {"get", "list"}}, rules)
+}
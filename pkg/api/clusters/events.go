@@ -0,0 +1,148 @@
+package clusters
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kobsio/kobs/pkg/api/clusters/cluster"
+
+	"github.com/sirupsen/logrus"
+)
+
+// eventsRetryInterval is the interval, after which a failed watch for the events of a cluster is restarted.
+var eventsRetryInterval = 10 * time.Second
+
+// ClusterEvent is a single event in the merged events feed of multiple clusters. It is the event returned by the watch
+// of a cluster, tagged with the name of the cluster. When the watch for a cluster fails, an event with the type "ERROR"
+// and the error message is sent, before the watch is restarted.
+type ClusterEvent struct {
+	Cluster string                 `json:"cluster"`
+	Type    string                 `json:"type"`
+	Object  map[string]interface{} `json:"object,omitempty"`
+	Error   string                 `json:"error,omitempty"`
+}
+
+// EventsWatch is a single watch of the merged events feed, which watches the events in the namespace of a cluster. If
+// the namespace is empty, the events of all namespaces are watched.
+type EventsWatch struct {
+	Cluster   string
+	Namespace string
+}
+
+// clusterEventWriter implements the ResourceEventWriter interface. It tags all events with the name of the cluster
+// and writes them to the shared writer. The mutex is shared between all watches, so that only one event is written at
+// the same time.
+type clusterEventWriter struct {
+	cluster string
+	writer  cluster.ResourceEventWriter
+	mutex   *sync.Mutex
+}
+
+func (w *clusterEventWriter) WriteJSON(v interface{}) error {
+	event := ClusterEvent{Cluster: w.cluster}
+
+	if resourceEvent, ok := v.(cluster.ResourceEvent); ok {
+		event.Type = resourceEvent.Type
+		event.Object = resourceEvent.Object
+	}
+
+	return w.write(event)
+}
+
+func (w *clusterEventWriter) write(event ClusterEvent) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.writer.WriteJSON(event)
+}
+
+// WatchEvents watches the Kubernetes events for all given watches and merges them into a single feed, which is written
+// to the passed in writer. Each event is tagged with the name of its cluster. The number of watches is limited by
+// maxWatches, so that a single feed can not open an unbounded number of connections to the Kubernetes API servers.
+// When the watch for a cluster fails, the error is sent as event and the watch is restarted after a short interval, so
+// that a single failing cluster doesn't close the whole feed. The function returns when the context is canceled or
+// when the events can not be written anymore.
+func (c *Clusters) WatchEvents(ctx context.Context, writer cluster.ResourceEventWriter, watches []EventsWatch, maxWatches int) error {
+	if len(watches) > maxWatches {
+		return fmt.Errorf("too many watches: %d (maximum is %d)", len(watches), maxWatches)
+	}
+
+	var clusters []*cluster.Cluster
+	for _, watch := range watches {
+		cl, err := c.GetCluster(watch.Cluster)
+		if err != nil {
+			return fmt.Errorf("%s: %w", watch.Cluster, err)
+		}
+
+		clusters = append(clusters, cl)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	var writeErr error
+	var writeErrOnce sync.Once
+
+	for i, watch := range watches {
+		wg.Add(1)
+
+		go func(cl *cluster.Cluster, namespace string) {
+			defer wg.Done()
+
+			eventWriter := &clusterEventWriter{cluster: cl.GetName(), writer: writer, mutex: &mutex}
+			failingWriter := &failingEventWriter{writer: eventWriter}
+
+			for {
+				err := cl.WatchResources(ctx, failingWriter, namespace, "/api/v1", "events", "", "")
+				if ctx.Err() != nil {
+					return
+				}
+
+				if failingWriter.err != nil {
+					writeErrOnce.Do(func() { writeErr = failingWriter.err })
+					cancel()
+					return
+				}
+
+				if err != nil {
+					log.WithError(err).WithFields(logrus.Fields{"cluster": cl.GetName(), "namespace": namespace}).Warnf("Events watch failed, restart watch.")
+
+					if err := eventWriter.write(ClusterEvent{Cluster: cl.GetName(), Type: "ERROR", Error: err.Error()}); err != nil {
+						writeErrOnce.Do(func() { writeErr = err })
+						cancel()
+						return
+					}
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(eventsRetryInterval):
+				}
+			}
+		}(clusters[i], watch.Namespace)
+	}
+
+	wg.Wait()
+	return writeErr
+}
+
+// failingEventWriter remembers the error of the underlying writer, so that we can distinguish between errors of the
+// watch and errors while writing the events to the client.
+type failingEventWriter struct {
+	writer cluster.ResourceEventWriter
+	err    error
+}
+
+func (w *failingEventWriter) WriteJSON(v interface{}) error {
+	if err := w.writer.WriteJSON(v); err != nil {
+		w.err = err
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package clusters
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/kobsio/kobs/pkg/api/clusters/cluster"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testEventWriter struct {
+	events []interface{}
+}
+
+func (w *testEventWriter) WriteJSON(v interface{}) error {
+	w.events = append(w.events, v)
+	return nil
+}
+
+func TestClusterEventWriter(t *testing.T) {
+	writer := &testEventWriter{}
+	eventWriter := &clusterEventWriter{cluster: "dev-de1", writer: writer, mutex: &sync.Mutex{}}
+
+	require.NoError(t, eventWriter.WriteJSON(cluster.ResourceEvent{Type: "ADDED", Object: map[string]interface{}{"reason": "Started"}}))
+	require.Equal(t, []interface{}{ClusterEvent{Cluster: "dev-de1", Type: "ADDED", Object: map[string]interface{}{"reason": "Started"}}}, writer.events)
+}
+
+func TestWatchEvents(t *testing.T) {
+	c := &Clusters{}
+
+	t.Run("too many watches", func(t *testing.T) {
+		err := c.WatchEvents(context.Background(), &testEventWriter{}, []EventsWatch{{Cluster: "dev-de1"}, {Cluster: "stage-de1"}}, 1)
+		require.Error(t, err)
+	})
+
+	t.Run("cluster not found", func(t *testing.T) {
+		err := c.WatchEvents(context.Background(), &testEventWriter{}, []EventsWatch{{Cluster: "dev-de1"}}, 1)
+		require.True(t, errors.Is(err, ErrClusterNotFound))
+	})
+}
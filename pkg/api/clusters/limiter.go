@@ -0,0 +1,153 @@
+package clusters
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	flag "github.com/spf13/pflag"
+)
+
+var (
+	concurrencyGlobal     int
+	concurrencyPerCluster int
+	concurrencyTimeout    time.Duration
+
+	// ErrTooManyRequests is returned by the Acquire function, when a request couldn't get a free slot within the
+	// configured timeout.
+	ErrTooManyRequests = errors.New("too many concurrent requests to the Kubernetes API server")
+
+	// ErrUnknownCluster is returned by the Acquire function, when the per cluster limit is enabled, but no limit exists
+	// for the cluster with the given name. We do not allow unlimited requests for unknown clusters, so that the limit
+	// can not be bypassed.
+	ErrUnknownCluster = errors.New("no concurrency limit for cluster")
+
+	queueMetric = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "kobs",
+		Name:      "clusters_requests_queued",
+		Help:      "Number of requests, which are waiting for a free slot to call the Kubernetes API server of a cluster.",
+	}, []string{"cluster"})
+)
+
+// init defines the command-line flags for the concurrency limits of requests against the Kubernetes API servers.
+func init() {
+	defaultConcurrencyGlobal := 100
+	if os.Getenv("KOBS_CLUSTERS_CONCURRENCY_GLOBAL") != "" {
+		parsedConcurrencyGlobal, err := strconv.Atoi(os.Getenv("KOBS_CLUSTERS_CONCURRENCY_GLOBAL"))
+		if err == nil {
+			defaultConcurrencyGlobal = parsedConcurrencyGlobal
+		}
+	}
+
+	defaultConcurrencyPerCluster := 20
+	if os.Getenv("KOBS_CLUSTERS_CONCURRENCY_CLUSTER") != "" {
+		parsedConcurrencyPerCluster, err := strconv.Atoi(os.Getenv("KOBS_CLUSTERS_CONCURRENCY_CLUSTER"))
+		if err == nil {
+			defaultConcurrencyPerCluster = parsedConcurrencyPerCluster
+		}
+	}
+
+	defaultConcurrencyTimeout := time.Duration(10 * time.Second)
+	if os.Getenv("KOBS_CLUSTERS_CONCURRENCY_TIMEOUT") != "" {
+		parsedConcurrencyTimeout, err := time.ParseDuration(os.Getenv("KOBS_CLUSTERS_CONCURRENCY_TIMEOUT"))
+		if err == nil {
+			defaultConcurrencyTimeout = parsedConcurrencyTimeout
+		}
+	}
+
+	flag.IntVar(&concurrencyGlobal, "clusters.concurrency.global", defaultConcurrencyGlobal, "The maximum number of concurrent requests against all Kubernetes API servers. A value of 0 disables the limit.")
+	flag.IntVar(&concurrencyPerCluster, "clusters.concurrency.cluster", defaultConcurrencyPerCluster, "The maximum number of concurrent requests against the Kubernetes API server of a single cluster. A value of 0 disables the limit.")
+	flag.DurationVar(&concurrencyTimeout, "clusters.concurrency.timeout", defaultConcurrencyTimeout, "The maximum duration a request waits for a free slot, before it is rejected.")
+}
+
+// limiter implements the global and per cluster concurrency limits. Each limit is a buffered channel, which is used as
+// semaphore. A nil channel means that the limit is disabled. When the per cluster limit is enabled, the clusters map
+// contains a semaphore for each known cluster.
+type limiter struct {
+	global     chan struct{}
+	perCluster bool
+	clusters   map[string]chan struct{}
+	timeout    time.Duration
+}
+
+// newLimiter returns a new limiter for the given cluster names.
+func newLimiter(names []string, global, perCluster int, timeout time.Duration) *limiter {
+	l := &limiter{
+		clusters: make(map[string]chan struct{}),
+		timeout:  timeout,
+	}
+
+	if global > 0 {
+		l.global = make(chan struct{}, global)
+	}
+
+	if perCluster > 0 {
+		l.perCluster = true
+		for _, name := range names {
+			l.clusters[name] = make(chan struct{}, perCluster)
+		}
+	}
+
+	return l
+}
+
+// Acquire waits for a free slot to run a request against the Kubernetes API server of the cluster with the given name.
+// When a slot is available the returned function must be called to release the slot again. If no slot is available
+// within the configured timeout, the ErrTooManyRequests error is returned. When the limits are not configured the
+// function returns directly. Requests for unknown clusters are rejected with the ErrUnknownCluster error, when the per
+// cluster limit is enabled.
+func (c *Clusters) Acquire(ctx context.Context, name string) (func(), error) {
+	if c.limiter == nil {
+		return func() {}, nil
+	}
+
+	clusterSemaphore, ok := c.limiter.clusters[name]
+	if c.limiter.perCluster && !ok {
+		return nil, ErrUnknownCluster
+	}
+
+	queueMetric.WithLabelValues(name).Inc()
+	defer queueMetric.WithLabelValues(name).Dec()
+
+	ctx, cancel := context.WithTimeout(ctx, c.limiter.timeout)
+	defer cancel()
+
+	if err := acquire(ctx, c.limiter.global); err != nil {
+		return nil, err
+	}
+
+	if err := acquire(ctx, clusterSemaphore); err != nil {
+		release(c.limiter.global)
+		return nil, err
+	}
+
+	return func() {
+		release(clusterSemaphore)
+		release(c.limiter.global)
+	}, nil
+}
+
+// acquire takes a slot from the given semaphore. If the semaphore is nil, the limit is disabled and we return directly.
+func acquire(ctx context.Context, semaphore chan struct{}) error {
+	if semaphore == nil {
+		return nil
+	}
+
+	select {
+	case semaphore <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ErrTooManyRequests
+	}
+}
+
+// release returns a slot to the given semaphore.
+func release(semaphore chan struct{}) {
+	if semaphore != nil {
+		<-semaphore
+	}
+}
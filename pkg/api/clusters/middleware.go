@@ -0,0 +1,73 @@
+package clusters
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/kobsio/kobs/pkg/api/clusters/cluster"
+	authContext "github.com/kobsio/kobs/pkg/api/middleware/auth/context"
+	"github.com/kobsio/kobs/pkg/api/middleware/errresponse"
+)
+
+// clusterContextKey is the key, which is used to store the resolved cluster in the context of a request.
+type clusterContextKey struct{}
+
+// getClusterFromContext returns the cluster, which was resolved by the clusterHandler middleware. When the handler
+// isn't using the clusterHandler middleware, the ErrClusterNotFound error is returned.
+func getClusterFromContext(ctx context.Context) (*cluster.Cluster, error) {
+	c, ok := ctx.Value(clusterContextKey{}).(*cluster.Cluster)
+	if !ok || c == nil {
+		return nil, ErrClusterNotFound
+	}
+
+	return c, nil
+}
+
+// clusterHandler is a middleware, which resolves the cluster from the cluster parameter of a request and adds it to the
+// context of the request, so that the handlers do not have to look up the cluster again. When the cluster doesn't
+// exist a 404 is returned. If validateNamespace is true, the middleware also checks that the namespace from the
+// namespace parameter exists in the cluster. Before the namespace is looked up, we check that the user has access to
+// the namespace, so that the response can not be used to find out which namespaces exist.
+func (router *Router) clusterHandler(validateNamespace bool) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			clusterName := r.URL.Query().Get("cluster")
+			namespace := r.URL.Query().Get("namespace")
+
+			c, err := router.clusters.GetCluster(clusterName)
+			if err != nil {
+				errresponse.Render(w, r, err, http.StatusNotFound, "Invalid cluster name")
+				return
+			}
+
+			if validateNamespace && namespace != "" {
+				user, err := authContext.GetUser(r.Context())
+				if err != nil {
+					errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
+					return
+				}
+
+				if !user.HasNamespaceAccess(clusterName, namespace) {
+					errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: %s", clusterName, namespace), http.StatusForbidden, "You are not authorized to access the resource")
+					return
+				}
+
+				exists, err := c.NamespaceExists(r.Context(), namespace, cacheDurationNamespaces)
+				if err != nil {
+					errresponse.Render(w, r, err, http.StatusBadRequest, "Could not get namespace")
+					return
+				}
+
+				if !exists {
+					errresponse.Render(w, r, fmt.Errorf("namespace %s not found", namespace), http.StatusNotFound, "Invalid namespace")
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), clusterContextKey{}, c)))
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
@@ -0,0 +1,62 @@
+package clusters
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	team "github.com/kobsio/kobs/pkg/api/apis/team/v1beta1"
+	"github.com/kobsio/kobs/pkg/api/clusters/cluster"
+	authContext "github.com/kobsio/kobs/pkg/api/middleware/auth/context"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+)
+
+func TestClusterHandler(t *testing.T) {
+	c, err := cluster.NewCluster("dev-de1", &rest.Config{Host: "http://localhost:0"}, nil)
+	require.NoError(t, err)
+
+	router := Router{clusters: &Clusters{clusters: []*cluster.Cluster{c}}}
+
+	var resolved *cluster.Cluster
+	handler := router.clusterHandler(false)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resolved, _ = getClusterFromContext(r.Context())
+	}))
+
+	t.Run("present cluster", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?cluster=dev-de1", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, c, resolved)
+	})
+
+	t.Run("absent cluster", func(t *testing.T) {
+		resolved = nil
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?cluster=stage-de1", nil))
+		require.Equal(t, http.StatusNotFound, w.Code)
+		require.Nil(t, resolved)
+	})
+
+	t.Run("forbidden namespace", func(t *testing.T) {
+		resolved = nil
+
+		r := httptest.NewRequest(http.MethodGet, "/?cluster=dev-de1&namespace=kube-system", nil)
+		r = r.WithContext(context.WithValue(r.Context(), authContext.UserKey, authContext.User{
+			Permissions: team.Permissions{Resources: []team.PermissionsResources{{Clusters: []string{"dev-de1"}, Namespaces: []string{"default"}, Resources: []string{"*"}}}},
+		}))
+
+		w := httptest.NewRecorder()
+		router.clusterHandler(true)(handler).ServeHTTP(w, r)
+		require.Equal(t, http.StatusForbidden, w.Code)
+		require.Nil(t, resolved)
+	})
+}
+
+func TestGetClusterFromContext(t *testing.T) {
+	_, err := getClusterFromContext(context.Background())
+	require.Equal(t, ErrClusterNotFound, err)
+}
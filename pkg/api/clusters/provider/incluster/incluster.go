@@ -17,8 +17,10 @@ type Config struct {
 }
 
 // GetCluster returns the cluster, where kobs is running in via the incluster configuration. For the selection of the
-// cluster via a name, the user has to provide this name.
-func GetCluster(config *Config) ([]*cluster.Cluster, error) {
+// cluster via a name, the user has to provide this name. The timeout for the cluster is set for all requests against the
+// Kubernetes API server, a value of 0 means no timeout. The TLS config for the name of the cluster is used to add a
+// client certificate to the cluster.
+func GetCluster(config *Config, timeouts cluster.Timeouts, tlsConfigs map[string]*cluster.TLSConfig) ([]*cluster.Cluster, error) {
 	log.WithFields(logrus.Fields{"name": config.Name}).Tracef("Load incluster config.")
 
 	restConfig, err := rest.InClusterConfig()
@@ -27,10 +29,25 @@ func GetCluster(config *Config) ([]*cluster.Cluster, error) {
 		return nil, err
 	}
 
-	c, err := cluster.NewCluster(config.Name, restConfig)
+	timeout := timeouts.Get(config.Name)
+	restConfig.Timeout = timeout
+
+	c, err := cluster.NewCluster(config.Name, restConfig, tlsConfigs[config.Name])
 	if err != nil {
 		return nil, err
 	}
 
+	// The incluster configuration is loaded again, when the credentials for the cluster expired, so that a rotated
+	// service account token is used.
+	c.SetConfigLoader(func() (*rest.Config, error) {
+		restConfig, err := rest.InClusterConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		restConfig.Timeout = timeout
+		return restConfig, nil
+	})
+
 	return []*cluster.Cluster{c}, nil
 }
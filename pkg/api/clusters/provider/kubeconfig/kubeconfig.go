@@ -1,9 +1,13 @@
 package kubeconfig
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/kobsio/kobs/pkg/api/clusters/cluster"
 
 	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 )
@@ -17,17 +21,62 @@ type Config struct {
 	Path string `json:"path"`
 }
 
-// GetClusters returns all clusters from a given Kubeconfig file. For that the user have to provide the path to the
-// Kubeconfig file.
-func GetClusters(config *Config) ([]*cluster.Cluster, error) {
-	log.WithFields(logrus.Fields{"path": config.Path}).Tracef("Load Kubeconfig file.")
-
+// loadRawConfig loads the Kubeconfig file from the given path.
+func loadRawConfig(path string) (clientcmdapi.Config, error) {
 	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
-		&clientcmd.ClientConfigLoadingRules{ExplicitPath: config.Path},
+		&clientcmd.ClientConfigLoadingRules{ExplicitPath: path},
 		&clientcmd.ConfigOverrides{},
 	)
 
-	raw, err := clientConfig.RawConfig()
+	return clientConfig.RawConfig()
+}
+
+// getRestConfig returns the rest config for the context with the given name from the loaded Kubeconfig file.
+func getRestConfig(raw clientcmdapi.Config, name string, context *clientcmdapi.Context, timeout time.Duration) (*rest.Config, error) {
+	clientConfig := clientcmd.NewDefaultClientConfig(clientcmdapi.Config{
+		APIVersion:     "v1",
+		Kind:           "Config",
+		CurrentContext: name,
+		Contexts:       map[string]*clientcmdapi.Context{name: context},
+		Clusters:       map[string]*clientcmdapi.Cluster{context.Cluster: raw.Clusters[context.Cluster]},
+		AuthInfos:      map[string]*clientcmdapi.AuthInfo{context.AuthInfo: raw.AuthInfos[context.AuthInfo]},
+	}, &clientcmd.ConfigOverrides{})
+
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	restConfig.Timeout = timeout
+	return restConfig, nil
+}
+
+// getConfigLoader returns a function, which loads the Kubeconfig file again and returns the rest config for the context
+// with the given name. It is used to pick up rotated credentials, without restarting kobs.
+func getConfigLoader(path, name string, timeout time.Duration) cluster.ConfigLoader {
+	return func() (*rest.Config, error) {
+		raw, err := loadRawConfig(path)
+		if err != nil {
+			return nil, err
+		}
+
+		context, ok := raw.Contexts[name]
+		if !ok {
+			return nil, fmt.Errorf("context %s not found", name)
+		}
+
+		return getRestConfig(raw, name, context, timeout)
+	}
+}
+
+// GetClusters returns all clusters from a given Kubeconfig file. For that the user have to provide the path to the
+// Kubeconfig file. The timeout for each cluster is looked up by the name of the context and set for all requests against
+// the Kubernetes API servers, a value of 0 means no timeout. The TLS config is also looked up by the name of the context
+// and used to add a client certificate to the cluster.
+func GetClusters(config *Config, timeouts cluster.Timeouts, tlsConfigs map[string]*cluster.TLSConfig) ([]*cluster.Cluster, error) {
+	log.WithFields(logrus.Fields{"path": config.Path}).Tracef("Load Kubeconfig file.")
+
+	raw, err := loadRawConfig(config.Path)
 	if err != nil {
 		return nil, err
 	}
@@ -44,26 +93,19 @@ func GetClusters(config *Config) ([]*cluster.Cluster, error) {
 					"authinfo": context.AuthInfo,
 				}).Tracef("Context was found.")
 
-				clientConfig := clientcmd.NewDefaultClientConfig(clientcmdapi.Config{
-					APIVersion:     "v1",
-					Kind:           "Config",
-					CurrentContext: name,
-					Contexts:       map[string]*clientcmdapi.Context{name: context},
-					Clusters:       map[string]*clientcmdapi.Cluster{context.Cluster: raw.Clusters[context.Cluster]},
-					AuthInfos:      map[string]*clientcmdapi.AuthInfo{context.AuthInfo: raw.AuthInfos[context.AuthInfo]},
-				}, &clientcmd.ConfigOverrides{})
-
-				restConfig, err := clientConfig.ClientConfig()
+				timeout := timeouts.Get(name)
+				restConfig, err := getRestConfig(raw, name, context, timeout)
 				if err != nil {
 					log.WithError(err).Debugf("Could not create rest config.")
 					return nil, err
 				}
 
-				c, err := cluster.NewCluster(name, restConfig)
+				c, err := cluster.NewCluster(name, restConfig, tlsConfigs[name])
 				if err != nil {
 					return nil, err
 				}
 
+				c.SetConfigLoader(getConfigLoader(config.Path, name, timeout))
 				clusters = append(clusters, c)
 			} else {
 				log.WithFields(logrus.Fields{"name": name}).Warnf("Could not find auth info.")
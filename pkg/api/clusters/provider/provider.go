@@ -1,6 +1,9 @@
 package provider
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/kobsio/kobs/pkg/api/clusters/cluster"
 	"github.com/kobsio/kobs/pkg/api/clusters/provider/incluster"
 	"github.com/kobsio/kobs/pkg/api/clusters/provider/kubeconfig"
@@ -25,25 +28,102 @@ const (
 	KUBECONFIG Type = "kubeconfig"
 )
 
-// Config is the provider configuration to get Kubernetes clusters from. The provider configuration only contains the
-// provider type and a provider specific configuration.
+// Config is the provider configuration to get Kubernetes clusters from. The provider configuration contains the
+// provider type, a provider specific configuration and an optional timeout for the requests against the Kubernetes API
+// servers of the clusters. The timeout can be overwritten for single clusters via the timeouts map, which is keyed by
+// the name of the cluster. The timeouts don't affect long running operations like watching resources or streaming
+// logs. The optional TLS configuration can be used to authenticate against the Kubernetes API servers via a client
+// certificate. Like the timeouts, the TLS configuration is keyed by the name of the cluster, because the clusters of a
+// provider (e.g. the contexts of a Kubeconfig file) normally require different client certificates.
 type Config struct {
-	Provider   Type              `json:"provider"`
-	InCluster  incluster.Config  `json:"incluster"`
-	Kubeconfig kubeconfig.Config `json:"kubeconfig"`
+	Provider   Type                          `json:"provider"`
+	Timeout    string                        `json:"timeout"`
+	Timeouts   map[string]string             `json:"timeouts"`
+	TLS        map[string]*cluster.TLSConfig `json:"tls"`
+	InCluster  incluster.Config              `json:"incluster"`
+	Kubeconfig kubeconfig.Config             `json:"kubeconfig"`
 }
 
 // GetClusters returns all clusters for the given provider. When the provider field doesn't match our custom Type, we
 // only log a warning instead of throwing an error. This allows kobs to start also, when one provided provider is
 // invalid.
 func GetClusters(config *Config) ([]*cluster.Cluster, error) {
+	timeouts, err := getTimeouts(config)
+	if err != nil {
+		return nil, err
+	}
+
+	var clusters []*cluster.Cluster
+
 	switch config.Provider {
 	case INCLUSTER:
-		return incluster.GetCluster(&config.InCluster)
+		clusters, err = incluster.GetCluster(&config.InCluster, timeouts, config.TLS)
 	case KUBECONFIG:
-		return kubeconfig.GetClusters(&config.Kubeconfig)
+		clusters, err = kubeconfig.GetClusters(&config.Kubeconfig, timeouts, config.TLS)
 	default:
 		log.WithFields(logrus.Fields{"provider": config.Provider}).Warnf("Invalid provider.")
 		return nil, nil
 	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateTLSClusterNames(config.TLS, clusters); err != nil {
+		return nil, err
+	}
+
+	return clusters, nil
+}
+
+// validateTLSClusterNames returns an error, when the TLS configuration contains a cluster name, which doesn't exist in
+// the provider. This way a typo in the cluster name is detected when kobs is started and not when the first request
+// against the Kubernetes API server of the cluster fails, because the client certificate is missing.
+func validateTLSClusterNames(tlsConfigs map[string]*cluster.TLSConfig, clusters []*cluster.Cluster) error {
+	for name := range tlsConfigs {
+		found := false
+		for _, c := range clusters {
+			if c.GetName() == name {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return fmt.Errorf("tls configuration for cluster %s, but the cluster doesn't exist", name)
+		}
+	}
+
+	return nil
+}
+
+// getTimeouts parses the default timeout and the timeouts for the single clusters from the provider configuration.
+func getTimeouts(config *Config) (cluster.Timeouts, error) {
+	var timeouts cluster.Timeouts
+
+	if config.Timeout != "" {
+		timeout, err := time.ParseDuration(config.Timeout)
+		if err != nil {
+			log.WithError(err).WithFields(logrus.Fields{"timeout": config.Timeout}).Errorf("Invalid timeout.")
+			return timeouts, err
+		}
+
+		timeouts.Default = timeout
+	}
+
+	if len(config.Timeouts) > 0 {
+		timeouts.Clusters = make(map[string]time.Duration, len(config.Timeouts))
+
+		for name, value := range config.Timeouts {
+			timeout, err := time.ParseDuration(value)
+			if err != nil {
+				log.WithError(err).WithFields(logrus.Fields{"cluster": name, "timeout": value}).Errorf("Invalid timeout.")
+				return timeouts, err
+			}
+
+			timeouts.Clusters[name] = timeout
+		}
+	}
+
+	return timeouts, nil
 }
@@ -1,10 +1,19 @@
 package clusters
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
+	favorite "github.com/kobsio/kobs/pkg/api/apis/favorite/v1beta1"
+	savedquery "github.com/kobsio/kobs/pkg/api/apis/savedquery/v1beta1"
 	"github.com/kobsio/kobs/pkg/api/clusters/cluster"
+	authContext "github.com/kobsio/kobs/pkg/api/middleware/auth/context"
 	"github.com/kobsio/kobs/pkg/api/middleware/errresponse"
 
 	"github.com/go-chi/chi/v5"
@@ -17,18 +26,157 @@ import (
 type Router struct {
 	*chi.Mux
 	clusters *Clusters
+	rules    *rulesCache
+	counts   *countsCache
+}
+
+// rulesCache caches the rules returned by the getAllowedVerbs function for each user, cluster and namespace, so that we
+// do not have to create a new SelfSubjectRulesReview for each request. Expired entries are removed when a new entry is
+// added, so that the cache doesn't grow with each user.
+type rulesCache struct {
+	mutex   sync.Mutex
+	entries map[string]rulesCacheEntry
+}
+
+// rulesCacheEntry is a single entry in the rules cache.
+type rulesCacheEntry struct {
+	rules     map[string][]string
+	lastFetch time.Time
+}
+
+// rulesCacheDuration is the duration for how long the rules of a user are cached.
+var rulesCacheDuration = 1 * time.Minute
+
+// get returns the cached rules for the given key, when they are not expired.
+func (c *rulesCache) get(key string) (map[string][]string, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || entry.lastFetch.Before(time.Now().Add(-1*rulesCacheDuration)) {
+		return nil, false
+	}
+
+	return entry.rules, true
+}
+
+// set adds the rules for the given key to the cache and removes all expired entries.
+func (c *rulesCache) set(key string, rules map[string][]string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := time.Now()
+	for k, entry := range c.entries {
+		if entry.lastFetch.Before(now.Add(-1 * rulesCacheDuration)) {
+			delete(c.entries, k)
+		}
+	}
+
+	c.entries[key] = rulesCacheEntry{rules: rules, lastFetch: now}
+}
+
+// countsCache caches the number of Custom Resources per namespace for each cluster and resource, which are returned by
+// the getCounts function.
+type countsCache struct {
+	mutex   sync.Mutex
+	entries map[string]countsCacheEntry
+}
+
+// countsCacheEntry is a single entry in the counts cache.
+type countsCacheEntry struct {
+	counts    map[string]int64
+	lastFetch time.Time
+}
+
+// countsCacheDuration is the duration for how long the counts of Custom Resources are cached.
+var countsCacheDuration = 30 * time.Second
+
+// Count is the number of Custom Resources of a type in a namespace of a cluster.
+type Count struct {
+	Cluster   string `json:"cluster"`
+	Namespace string `json:"namespace"`
+	Count     int64  `json:"count"`
+}
+
+// Cluster is the structure, which is returned by the getClusters function, when the display names were requested. The
+// name is the slugified name of the cluster, which must be used in API requests. The display name is the original name
+// of the cluster, which should be shown in the UI. When the metadata was requested, the info field contains the version,
+// number of nodes and status of the cluster.
+type Cluster struct {
+	Name        string        `json:"name"`
+	DisplayName string        `json:"displayName"`
+	Info        *cluster.Info `json:"info,omitempty"`
 }
 
 // GetClusters returns all loaded Kubernetes clusters.
 // We are not returning the complete cluster structure. Instead we are returning just the names of the clusters. We are
 // also sorting the clusters alphabetically, to improve the user experience in the frontend.
 // NOTE: Maybe we can also save the cluster names slice, since the name of a cluster couldn't change during runtime.
+// When the displayNames parameter is set, we return the name and display name of all clusters. When the metadata
+// parameter is set, we also return the version, number of nodes and status of all clusters.
 func (router *Router) getClusters(w http.ResponseWriter, r *http.Request) {
-	log.Tracef("getClusters")
+	displayNames := r.URL.Query().Get("displayNames")
+	metadata := r.URL.Query().Get("metadata")
+
+	log.WithFields(logrus.Fields{"displayNames": displayNames, "metadata": metadata}).Tracef("getClusters")
+
+	// If the metadata parameter is set to true, we get the info for all clusters in parallel. Since the info is cached
+	// by each cluster, only the first request or a request after the cache expired has to wait for the clusters.
+	if metadata == "true" {
+		loadedClusters := router.clusters.GetClusters()
+		clusters := make([]Cluster, len(loadedClusters))
+
+		var wg sync.WaitGroup
+		wg.Add(len(loadedClusters))
+
+		for index, cl := range loadedClusters {
+			go func(index int, cl *cluster.Cluster) {
+				defer wg.Done()
+
+				info := cl.GetInfo()
+				clusters[index] = Cluster{
+					Name:        cl.GetName(),
+					DisplayName: cl.GetDisplayName(),
+					Info:        &info,
+				}
+			}(index, cl)
+		}
+
+		wg.Wait()
+
+		sort.Slice(clusters, func(i, j int) bool {
+			return clusters[i].Name < clusters[j].Name
+		})
+
+		log.WithFields(logrus.Fields{"clusters": len(clusters)}).Tracef("getClusters")
+		render.JSON(w, r, clusters)
+		return
+	}
+
+	// If the displayNames parameter is set to true, we return the name and the display name for each cluster. The name
+	// must be used in all API requests, while the display name should be shown to the user.
+	if displayNames == "true" {
+		var clusters []Cluster
+
+		for _, cluster := range router.clusters.GetClusters() {
+			clusters = append(clusters, Cluster{
+				Name:        cluster.GetName(),
+				DisplayName: cluster.GetDisplayName(),
+			})
+		}
+
+		sort.Slice(clusters, func(i, j int) bool {
+			return clusters[i].Name < clusters[j].Name
+		})
+
+		log.WithFields(logrus.Fields{"clusters": clusters}).Tracef("getClusters")
+		render.JSON(w, r, clusters)
+		return
+	}
 
 	var clusterNames []string
 
-	for _, cluster := range router.clusters.Clusters {
+	for _, cluster := range router.clusters.GetClusters() {
 		clusterNames = append(clusterNames, cluster.GetName())
 	}
 
@@ -51,9 +199,9 @@ func (router *Router) getNamespaces(w http.ResponseWriter, r *http.Request) {
 	var namespaces []string
 
 	for _, clusterName := range clusterNames {
-		cluster := router.clusters.GetCluster(clusterName)
-		if cluster == nil {
-			errresponse.Render(w, r, nil, http.StatusBadRequest, "Invalid cluster name")
+		cluster, err := router.clusters.GetCluster(clusterName)
+		if err != nil {
+			errresponse.Render(w, r, err, http.StatusNotFound, "Invalid cluster name")
 			return
 		}
 
@@ -91,9 +239,52 @@ func (router *Router) getNamespaces(w http.ResponseWriter, r *http.Request) {
 // this function once from the React app. The CRDs form all loaded clusters are merged and then deduplicated.
 func (router *Router) getCRDs(w http.ResponseWriter, r *http.Request) {
 	log.Tracef("getCRDs")
+
+	uniqueCRDs := router.getUniqueCRDs()
+
+	log.WithFields(logrus.Fields{"count": len(uniqueCRDs)}).Tracef("getCRDs")
+	render.JSON(w, r, uniqueCRDs)
+}
+
+// crdsStatus is the loading status of the CRDs of all clusters. The loaded field is only true, when the CRDs of all
+// clusters were loaded.
+type crdsStatus struct {
+	Loaded   bool                `json:"loaded"`
+	Clusters []clusterCRDsStatus `json:"clusters"`
+}
+
+// clusterCRDsStatus is the loading status of the CRDs of a single cluster.
+type clusterCRDsStatus struct {
+	Cluster string `json:"cluster"`
+	Loaded  bool   `json:"loaded"`
+}
+
+// getCRDsStatus returns if the CRDs of the clusters were already loaded. Because the CRDs are loaded asynchronously the
+// list of CRDs can be empty right after the start of kobs. The status can be used by the React app to show a loading
+// indicator instead of an empty list.
+func (router *Router) getCRDsStatus(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("getCRDsStatus")
+
+	status := crdsStatus{Loaded: true, Clusters: []clusterCRDsStatus{}}
+
+	for _, cluster := range router.clusters.GetClusters() {
+		loaded := cluster.CRDsLoaded()
+		if !loaded {
+			status.Loaded = false
+		}
+
+		status.Clusters = append(status.Clusters, clusterCRDsStatus{Cluster: cluster.GetName(), Loaded: loaded})
+	}
+
+	log.WithFields(logrus.Fields{"loaded": status.Loaded}).Tracef("getCRDsStatus")
+	render.JSON(w, r, status)
+}
+
+// getUniqueCRDs returns the merged and deduplicated CRDs of all clusters.
+func (router *Router) getUniqueCRDs() []cluster.CRD {
 	var crds []cluster.CRD
 
-	for _, cluster := range router.clusters.Clusters {
+	for _, cluster := range router.clusters.GetClusters() {
 		crds = append(crds, cluster.GetCRDs()...)
 	}
 
@@ -106,20 +297,708 @@ func (router *Router) getCRDs(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	log.WithFields(logrus.Fields{"count": len(uniqueCRDs)}).Tracef("getCRDs")
-	render.JSON(w, r, uniqueCRDs)
+	return uniqueCRDs
+}
+
+// filterCRDs returns the CRDs of all clusters, which are matching the filter parameter. The filter is applied to the
+// group, kind and resource of a CRD. The result can be paginated via the offset and limit parameters. Next to the CRDs
+// the total number of matching CRDs and if the result was truncated is returned.
+func (router *Router) filterCRDs(w http.ResponseWriter, r *http.Request) {
+	filter := r.URL.Query().Get("filter")
+	offset := r.URL.Query().Get("offset")
+	limit := r.URL.Query().Get("limit")
+
+	log.WithFields(logrus.Fields{"filter": filter, "offset": offset, "limit": limit}).Tracef("filterCRDs")
+
+	var parsedOffset, parsedLimit int
+	var err error
+
+	if offset != "" {
+		parsedOffset, err = strconv.Atoi(offset)
+		if err != nil || parsedOffset < 0 {
+			errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid offset parameter")
+			return
+		}
+	}
+
+	if limit != "" {
+		parsedLimit, err = strconv.Atoi(limit)
+		if err != nil || parsedLimit < 0 {
+			errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid limit parameter")
+			return
+		}
+	}
+
+	crdList := cluster.FilterCRDs(router.getUniqueCRDs(), filter, parsedOffset, parsedLimit)
+
+	log.WithFields(logrus.Fields{"count": len(crdList.CRDs), "total": crdList.Total, "truncated": crdList.Truncated}).Tracef("filterCRDs")
+	render.JSON(w, r, crdList)
+}
+
+// getAllowedVerbs returns the verbs, which can be used via kobs for each resource in the given cluster and namespace.
+// Since all requests against the Kubernetes API are made with the credentials of kobs, the verbs are retrieved via a
+// SelfSubjectRulesReview for kobs and then filtered by the permissions of the user in kobs. This means the result
+// doesn't contain the RBAC permissions of the user in the cluster, but the actions the user can perform in kobs, so
+// that the frontend can hide all actions a user can not perform. The verbs are cached for each user, cluster and
+// namespace for a short time.
+func (router *Router) getAllowedVerbs(w http.ResponseWriter, r *http.Request) {
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
+		return
+	}
+
+	clusterName := r.URL.Query().Get("cluster")
+	namespace := r.URL.Query().Get("namespace")
+
+	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "user": user.ID}).Tracef("getAllowedVerbs")
+
+	key := user.ID + "/" + clusterName + "/" + namespace
+
+	if cachedRules, ok := router.rules.get(key); ok {
+		log.WithFields(logrus.Fields{"count": len(cachedRules)}).Tracef("getAllowedVerbs return cached verbs")
+		render.JSON(w, r, cachedRules)
+		return
+	}
+
+	cluster, err := getClusterFromContext(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusNotFound, "Invalid cluster name")
+		return
+	}
+
+	clusterRules, err := cluster.GetKobsRules(r.Context(), namespace)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not get allowed verbs")
+		return
+	}
+
+	accessNamespace := namespace
+	if accessNamespace == "" {
+		accessNamespace = "*"
+	}
+
+	rules := make(map[string][]string)
+	for resource, verbs := range clusterRules {
+		if resource == "*" || user.HasResourceAccess(clusterName, accessNamespace, strings.Split(resource, "/")[0]) {
+			rules[resource] = verbs
+		}
+	}
+
+	router.rules.set(key, rules)
+
+	log.WithFields(logrus.Fields{"count": len(rules)}).Tracef("getAllowedVerbs")
+	render.JSON(w, r, rules)
+}
+
+// getCounts returns the number of applications, dashboards or teams grouped by cluster and namespace. This can be used
+// for overview pages, without loading the complete Custom Resources. The counts are cached per cluster and resource
+// and filtered by the permissions of the user.
+func (router *Router) getCounts(w http.ResponseWriter, r *http.Request) {
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
+		return
+	}
+
+	clusterNames := r.URL.Query()["cluster"]
+	resource := r.URL.Query().Get("resource")
+
+	log.WithFields(logrus.Fields{"clusters": clusterNames, "resource": resource}).Tracef("getCounts")
+
+	if resource == "" {
+		resource = "applications"
+	}
+
+	var counts []Count
+
+	for _, clusterName := range clusterNames {
+		key := clusterName + "/" + resource
+
+		router.counts.mutex.Lock()
+		entry, ok := router.counts.entries[key]
+		router.counts.mutex.Unlock()
+
+		if !ok || entry.lastFetch.Before(time.Now().Add(-1*countsCacheDuration)) {
+			cluster, err := router.clusters.GetCluster(clusterName)
+			if err != nil {
+				errresponse.Render(w, r, err, http.StatusNotFound, "Invalid cluster name")
+				return
+			}
+
+			clusterCounts, err := cluster.GetCRCounts(r.Context(), resource)
+			if err != nil {
+				errresponse.Render(w, r, err, http.StatusBadRequest, "Could not get counts")
+				return
+			}
+
+			entry = countsCacheEntry{counts: clusterCounts, lastFetch: time.Now()}
+
+			router.counts.mutex.Lock()
+			router.counts.entries[key] = entry
+			router.counts.mutex.Unlock()
+		}
+
+		for namespace, count := range entry.counts {
+			if user.HasResourceAccess(clusterName, namespace, resource) {
+				counts = append(counts, Count{Cluster: clusterName, Namespace: namespace, Count: count})
+			}
+		}
+	}
+
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Cluster != counts[j].Cluster {
+			return counts[i].Cluster < counts[j].Cluster
+		}
+		return counts[i].Namespace < counts[j].Namespace
+	})
+
+	log.WithFields(logrus.Fields{"count": len(counts)}).Tracef("getCounts")
+	render.JSON(w, r, counts)
+}
+
+// getSavedQueries returns all saved queries for the given clusters and namespaces. If no namespace is provided, the
+// saved queries for all namespaces are returned. Saved queries in namespaces the user can not access are skipped.
+func (router *Router) getSavedQueries(w http.ResponseWriter, r *http.Request) {
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
+		return
+	}
+
+	clusterNames := r.URL.Query()["cluster"]
+	namespaces := r.URL.Query()["namespace"]
+
+	log.WithFields(logrus.Fields{"clusters": clusterNames, "namespaces": namespaces}).Tracef("getSavedQueries")
+
+	if namespaces == nil {
+		namespaces = []string{""}
+	}
+
+	var savedQueries []savedquery.SavedQuerySpec
+
+	for _, clusterName := range clusterNames {
+		cluster, err := router.clusters.GetCluster(clusterName)
+		if err != nil {
+			errresponse.Render(w, r, err, http.StatusNotFound, "Invalid cluster name")
+			return
+		}
+
+		for _, namespace := range namespaces {
+			queries, err := cluster.GetSavedQueries(r.Context(), namespace)
+			if err != nil {
+				errresponse.Render(w, r, err, http.StatusBadRequest, "Could not get saved queries")
+				return
+			}
+
+			for _, query := range queries {
+				if user.HasResourceAccess(clusterName, query.Namespace, "savedqueries") {
+					savedQueries = append(savedQueries, query)
+				}
+			}
+		}
+	}
+
+	log.WithFields(logrus.Fields{"count": len(savedQueries)}).Tracef("getSavedQueries")
+	render.JSON(w, r, savedQueries)
+}
+
+// getSavedQuery returns a single saved query, which is identified by the cluster, namespace and name query parameters.
+func (router *Router) getSavedQuery(w http.ResponseWriter, r *http.Request) {
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
+		return
+	}
+
+	clusterName := r.URL.Query().Get("cluster")
+	namespace := r.URL.Query().Get("namespace")
+	name := r.URL.Query().Get("name")
+
+	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "name": name}).Tracef("getSavedQuery")
+
+	if !user.HasResourceAccess(clusterName, namespace, "savedqueries") {
+		errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: %s, resource: savedqueries", clusterName, namespace), http.StatusForbidden, "You are not authorized to access the resource")
+		return
+	}
+
+	cluster, err := getClusterFromContext(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusNotFound, "Invalid cluster name")
+		return
+	}
+
+	savedQuery, err := cluster.GetSavedQuery(r.Context(), namespace, name)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not get saved query")
+		return
+	}
+
+	render.JSON(w, r, savedQuery)
+}
+
+// getAdmissionWebhooks returns all validating and mutating admission webhooks for the given cluster. Since the webhook
+// configurations are cluster scoped, the user must have access to the resources in all namespaces.
+func (router *Router) getAdmissionWebhooks(w http.ResponseWriter, r *http.Request) {
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
+		return
+	}
+
+	clusterName := r.URL.Query().Get("cluster")
+
+	log.WithFields(logrus.Fields{"cluster": clusterName}).Tracef("getAdmissionWebhooks")
+
+	for _, resource := range []string{"validatingwebhookconfigurations", "mutatingwebhookconfigurations"} {
+		if !user.HasResourceAccess(clusterName, "*", resource) {
+			errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: *, resource: %s", clusterName, resource), http.StatusForbidden, "You are not authorized to access the resource")
+			return
+		}
+	}
+
+	cluster, err := getClusterFromContext(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusNotFound, "Invalid cluster name")
+		return
+	}
+
+	webhooks, err := cluster.GetAdmissionWebhooks(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not get admission webhooks")
+		return
+	}
+
+	log.WithFields(logrus.Fields{"count": len(webhooks)}).Tracef("getAdmissionWebhooks")
+	render.JSON(w, r, webhooks)
+}
+
+// getNamespaceQuotas returns the usage of the ResourceQuotas and the defaults of the LimitRanges for the given
+// namespace. If the namespace doesn't have any quotas or limit ranges, empty lists are returned.
+func (router *Router) getNamespaceQuotas(w http.ResponseWriter, r *http.Request) {
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
+		return
+	}
+
+	clusterName := r.URL.Query().Get("cluster")
+	namespace := r.URL.Query().Get("namespace")
+
+	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace}).Tracef("getNamespaceQuotas")
+
+	if namespace == "" {
+		errresponse.Render(w, r, nil, http.StatusBadRequest, "Namespace is required")
+		return
+	}
+
+	for _, resource := range []string{"resourcequotas", "limitranges"} {
+		if !user.HasResourceAccess(clusterName, namespace, resource) {
+			errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: %s, resource: %s", clusterName, namespace, resource), http.StatusForbidden, "You are not authorized to access the resource")
+			return
+		}
+	}
+
+	cluster, err := getClusterFromContext(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusNotFound, "Invalid cluster name")
+		return
+	}
+
+	quotas, err := cluster.GetNamespaceQuotas(r.Context(), namespace)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not get quotas")
+		return
+	}
+
+	log.WithFields(logrus.Fields{"quotas": len(quotas.Quotas), "limitRanges": len(quotas.LimitRanges)}).Tracef("getNamespaceQuotas")
+	render.JSON(w, r, quotas)
+}
+
+// getPVCs returns the PersistentVolumeClaims of a namespace with the requested and actual capacity and the bound
+// volume. If the namespace parameter is empty, the claims of all namespaces are returned. The details of the bound
+// volume are only returned, when the user is allowed to access PersistentVolumes.
+func (router *Router) getPVCs(w http.ResponseWriter, r *http.Request) {
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
+		return
+	}
+
+	clusterName := r.URL.Query().Get("cluster")
+	namespace := r.URL.Query().Get("namespace")
+
+	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace}).Tracef("getPVCs")
+
+	accessNamespace := namespace
+	if accessNamespace == "" {
+		accessNamespace = "*"
+	}
+
+	if !user.HasResourceAccess(clusterName, accessNamespace, "persistentvolumeclaims") {
+		errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: %s, resource: persistentvolumeclaims", clusterName, accessNamespace), http.StatusForbidden, "You are not authorized to access the resource")
+		return
+	}
+
+	cluster, err := getClusterFromContext(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusNotFound, "Invalid cluster name")
+		return
+	}
+
+	pvcs, err := cluster.GetPVCs(r.Context(), namespace)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not get persistent volume claims")
+		return
+	}
+
+	if !user.HasResourceAccess(clusterName, "*", "persistentvolumes") {
+		for i := range pvcs {
+			pvcs[i].Volume = nil
+		}
+	}
+
+	log.WithFields(logrus.Fields{"pvcs": len(pvcs)}).Tracef("getPVCs")
+	render.JSON(w, r, pvcs)
+}
+
+// getKinds returns the path and resource for the kind parameter, so that the frontend can get the resources for a kind
+// without a hardcoded mapping. If the same kind exists in multiple groups, all matches are returned. If the kind
+// parameter is empty, the mappings for all kinds of the cluster are returned.
+func (router *Router) getKinds(w http.ResponseWriter, r *http.Request) {
+	clusterName := r.URL.Query().Get("cluster")
+	kind := r.URL.Query().Get("kind")
+
+	log.WithFields(logrus.Fields{"cluster": clusterName, "kind": kind}).Tracef("getKinds")
+
+	cluster, err := getClusterFromContext(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusNotFound, "Invalid cluster name")
+		return
+	}
+
+	kinds, err := cluster.GetKinds(kind)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not get kinds")
+		return
+	}
+
+	log.WithFields(logrus.Fields{"count": len(kinds)}).Tracef("getKinds")
+	render.JSON(w, r, kinds)
+}
+
+// getHealth returns the health of the control plane components and the readiness of the nodes of a cluster. Since
+// this information is cluster scoped, the user must have access to the nodes in all namespaces.
+func (router *Router) getHealth(w http.ResponseWriter, r *http.Request) {
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
+		return
+	}
+
+	clusterName := r.URL.Query().Get("cluster")
+
+	log.WithFields(logrus.Fields{"cluster": clusterName}).Tracef("getHealth")
+
+	if !user.HasResourceAccess(clusterName, "*", "nodes") {
+		errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: *, resource: nodes", clusterName), http.StatusForbidden, "You are not authorized to access the resource")
+		return
+	}
+
+	cluster, err := getClusterFromContext(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusNotFound, "Invalid cluster name")
+		return
+	}
+
+	health, err := cluster.GetHealth(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not get cluster health")
+		return
+	}
+
+	log.WithFields(logrus.Fields{"source": health.Source, "components": len(health.Components)}).Tracef("getHealth")
+	render.JSON(w, r, health)
+}
+
+// getManifest returns the YAML manifest of a kobs Custom Resource (application, dashboard, team or user). Server
+// managed fields are removed, so that the manifest can be used to export or copy the resource.
+func (router *Router) getManifest(w http.ResponseWriter, r *http.Request) {
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
+		return
+	}
+
+	clusterName := r.URL.Query().Get("cluster")
+	namespace := r.URL.Query().Get("namespace")
+	name := r.URL.Query().Get("name")
+	resource := r.URL.Query().Get("resource")
+
+	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "name": name, "resource": resource}).Tracef("getManifest")
+
+	if !user.HasResourceAccess(clusterName, namespace, resource) {
+		errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: %s, resource: %s", clusterName, namespace, resource), http.StatusForbidden, "You are not authorized to access the resource")
+		return
+	}
+
+	cluster, err := getClusterFromContext(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusNotFound, "Invalid cluster name")
+		return
+	}
+
+	manifest, err := cluster.GetCRManifest(r.Context(), namespace, name, resource)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not get manifest")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/yaml")
+	w.WriteHeader(http.StatusOK)
+	w.Write(manifest)
+}
+
+// createSavedQuery creates a new saved query in the given cluster and namespace. The spec of the saved query must be
+// provided in the request body.
+func (router *Router) createSavedQuery(w http.ResponseWriter, r *http.Request) {
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
+		return
+	}
+
+	clusterName := r.URL.Query().Get("cluster")
+	namespace := r.URL.Query().Get("namespace")
+	name := r.URL.Query().Get("name")
+
+	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "name": name}).Tracef("createSavedQuery")
+
+	if !user.HasResourceAccess(clusterName, namespace, "savedqueries") {
+		errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: %s, resource: savedqueries", clusterName, namespace), http.StatusForbidden, "You are not authorized to access the resource")
+		return
+	}
+
+	cluster, err := getClusterFromContext(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusNotFound, "Invalid cluster name")
+		return
+	}
+
+	var spec savedquery.SavedQuerySpec
+	if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not decode request body")
+		return
+	}
+
+	savedQuery, err := cluster.CreateSavedQuery(r.Context(), namespace, name, spec)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not create saved query")
+		return
+	}
+
+	render.JSON(w, r, savedQuery)
+}
+
+// deleteSavedQuery deletes the saved query, which is identified by the cluster, namespace and name query parameters.
+func (router *Router) deleteSavedQuery(w http.ResponseWriter, r *http.Request) {
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
+		return
+	}
+
+	clusterName := r.URL.Query().Get("cluster")
+	namespace := r.URL.Query().Get("namespace")
+	name := r.URL.Query().Get("name")
+
+	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "name": name}).Tracef("deleteSavedQuery")
+
+	if !user.HasResourceAccess(clusterName, namespace, "savedqueries") {
+		errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: %s, resource: savedqueries", clusterName, namespace), http.StatusForbidden, "You are not authorized to access the resource")
+		return
+	}
+
+	cluster, err := getClusterFromContext(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusNotFound, "Invalid cluster name")
+		return
+	}
+
+	if err := cluster.DeleteSavedQuery(r.Context(), namespace, name); err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not delete saved query")
+		return
+	}
+
+	render.JSON(w, r, nil)
+}
+
+// getFavoritesCluster returns the cluster, which is used to save the pinned resources of the users. If no cluster was
+// configured via the "clusters.favorites.cluster" flag, the first cluster is used.
+func (router *Router) getFavoritesCluster() (*cluster.Cluster, error) {
+	if favoritesCluster != "" {
+		return router.clusters.GetCluster(favoritesCluster)
+	}
+
+	clusters := router.clusters.GetClusters()
+	if len(clusters) == 0 {
+		return nil, ErrClusterNotFound
+	}
+
+	return clusters[0], nil
+}
+
+// getFavorites returns the pinned resources of the current user. Pinned resources, which the user can not access anymore
+// are not returned.
+func (router *Router) getFavorites(w http.ResponseWriter, r *http.Request) {
+	user, err := authContext.GetUser(r.Context())
+	if err != nil || user.ID == "" {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
+		return
+	}
+
+	log.WithFields(logrus.Fields{"user": user.ID}).Tracef("getFavorites")
+
+	cluster, err := router.getFavoritesCluster()
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusNotFound, "Invalid cluster name")
+		return
+	}
+
+	references, err := cluster.GetFavorites(r.Context(), favoritesNamespace, user.ID)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not get favorites")
+		return
+	}
+
+	favorites := []favorite.Reference{}
+	for _, reference := range references {
+		if user.HasResourceAccess(reference.Cluster, getAccessNamespace(reference.Namespace), reference.Resource) {
+			favorites = append(favorites, reference)
+		}
+	}
+
+	log.WithFields(logrus.Fields{"count": len(favorites)}).Tracef("getFavorites")
+	render.JSON(w, r, favorites)
+}
+
+// addFavorite pins the resource from the request body for the current user. The user must have access to the resource.
+func (router *Router) addFavorite(w http.ResponseWriter, r *http.Request) {
+	user, err := authContext.GetUser(r.Context())
+	if err != nil || user.ID == "" {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
+		return
+	}
+
+	var reference favorite.Reference
+	if err := json.NewDecoder(r.Body).Decode(&reference); err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not decode request body")
+		return
+	}
+
+	log.WithFields(logrus.Fields{"user": user.ID, "reference": reference}).Tracef("addFavorite")
+
+	if reference.Cluster == "" || reference.Name == "" || reference.Path == "" || reference.Resource == "" {
+		errresponse.Render(w, r, nil, http.StatusBadRequest, "Cluster, name, path and resource are required")
+		return
+	}
+
+	if !user.HasResourceAccess(reference.Cluster, getAccessNamespace(reference.Namespace), reference.Resource) {
+		errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: %s, resource: %s", reference.Cluster, reference.Namespace, reference.Resource), http.StatusForbidden, "You are not authorized to access the resource")
+		return
+	}
+
+	cluster, err := router.getFavoritesCluster()
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusNotFound, "Invalid cluster name")
+		return
+	}
+
+	favorites, err := cluster.AddFavorite(r.Context(), favoritesNamespace, user.ID, reference)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not add favorite")
+		return
+	}
+
+	render.JSON(w, r, favorites)
+}
+
+// removeFavorite removes the resource from the request body from the pinned resources of the current user.
+func (router *Router) removeFavorite(w http.ResponseWriter, r *http.Request) {
+	user, err := authContext.GetUser(r.Context())
+	if err != nil || user.ID == "" {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
+		return
+	}
+
+	var reference favorite.Reference
+	if err := json.NewDecoder(r.Body).Decode(&reference); err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not decode request body")
+		return
+	}
+
+	log.WithFields(logrus.Fields{"user": user.ID, "reference": reference}).Tracef("removeFavorite")
+
+	cluster, err := router.getFavoritesCluster()
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusNotFound, "Invalid cluster name")
+		return
+	}
+
+	favorites, err := cluster.RemoveFavorite(r.Context(), favoritesNamespace, user.ID, reference)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not remove favorite")
+		return
+	}
+
+	render.JSON(w, r, favorites)
+}
+
+// getAccessNamespace returns the namespace, which should be used to check the permissions of a user. For cluster
+// scoped resources the namespace is empty, so that we have to check the permissions for all namespaces.
+func getAccessNamespace(namespace string) string {
+	if namespace == "" {
+		return "*"
+	}
+
+	return namespace
 }
 
 // NewRouter return a new router with all the cluster routes.
 func NewRouter(clusters *Clusters) chi.Router {
 	router := Router{
-		chi.NewRouter(),
-		clusters,
+		Mux:      chi.NewRouter(),
+		clusters: clusters,
+		rules:    &rulesCache{entries: make(map[string]rulesCacheEntry)},
+		counts:   &countsCache{entries: make(map[string]countsCacheEntry)},
 	}
 
 	router.Get("/", router.getClusters)
 	router.Get("/namespaces", router.getNamespaces)
 	router.Get("/crds", router.getCRDs)
+	router.Get("/crds/filter", router.filterCRDs)
+	router.Get("/crds/status", router.getCRDsStatus)
+	router.Get("/counts", router.getCounts)
+	router.Get("/savedqueries", router.getSavedQueries)
+
+	// All routes, which are working with a single cluster, are using the clusterHandler middleware to resolve the
+	// cluster from the cluster parameter. The quotas route also requires an existing namespace.
+	router.Group(func(r chi.Router) {
+		r.Use(router.clusterHandler(false))
+		r.Get("/kinds", router.getKinds)
+		r.Get("/verbs", router.getAllowedVerbs)
+		r.Get("/manifest", router.getManifest)
+		r.Get("/webhooks", router.getAdmissionWebhooks)
+		r.Get("/health", router.getHealth)
+		r.Get("/pvcs", router.getPVCs)
+		r.Get("/savedquery", router.getSavedQuery)
+		r.Post("/savedquery", router.createSavedQuery)
+		r.Delete("/savedquery", router.deleteSavedQuery)
+	})
+	router.With(router.clusterHandler(true)).Get("/quotas", router.getNamespaceQuotas)
+	router.Get("/favorites", router.getFavorites)
+	router.Post("/favorites", router.addFavorite)
+	router.Delete("/favorites", router.removeFavorite)
 
 	return router
 }
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	authContext "github.com/kobsio/kobs/pkg/api/middleware/auth/context"
+	"github.com/kobsio/kobs/pkg/api/middleware/errresponse"
+
+	"github.com/go-chi/render"
+	"github.com/sirupsen/logrus"
+)
+
+// Config holds the (redacted) configuration of kobs, which is returned via the /api/config endpoint. The configuration
+// can be replaced, when kobs reloads its configuration file, so that the endpoint always returns the current
+// configuration.
+type Config struct {
+	mutex  sync.RWMutex
+	config interface{}
+}
+
+// Set replaces the returned configuration with the given configuration.
+func (c *Config) Set(config interface{}) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.config = config
+}
+
+// get returns the current configuration.
+func (c *Config) get() interface{} {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.config
+}
+
+// NewConfig returns a new Config for the given (redacted) configuration.
+func NewConfig(config interface{}) *Config {
+	return &Config{config: config}
+}
+
+// configHandler returns a handler, which returns the current (redacted) configuration of kobs. Since the configuration
+// contains the setup of all clusters and plugins, the handler can only be used by admins (see the api.auth.admins
+// flag).
+func configHandler(config *Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authContext.GetUser(r.Context())
+		if err != nil {
+			errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the configuration")
+			return
+		}
+
+		log.WithFields(logrus.Fields{"user": user.ID}).Tracef("getConfig")
+
+		if !user.IsAdmin {
+			errresponse.Render(w, r, fmt.Errorf("user is not an admin"), http.StatusForbidden, "You are not allowed to access the configuration")
+			return
+		}
+
+		render.JSON(w, r, config.get())
+	}
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	authContext "github.com/kobsio/kobs/pkg/api/middleware/auth/context"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigHandler(t *testing.T) {
+	config := NewConfig(map[string]interface{}{"key": "value1"})
+	handler := configHandler(config)
+
+	request := func(user authContext.User) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/api/config", nil)
+		r = r.WithContext(context.WithValue(r.Context(), authContext.UserKey, user))
+		w := httptest.NewRecorder()
+		handler(w, r)
+		return w
+	}
+
+	t.Run("user is not an admin", func(t *testing.T) {
+		w := request(authContext.User{ID: "user1"})
+		require.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("user is an admin", func(t *testing.T) {
+		w := request(authContext.User{ID: "user1", IsAdmin: true})
+		require.Equal(t, http.StatusOK, w.Code)
+		require.JSONEq(t, `{"key": "value1"}`, w.Body.String())
+	})
+
+	t.Run("reloaded config", func(t *testing.T) {
+		config.Set(map[string]interface{}{"key": "value2"})
+
+		w := request(authContext.User{ID: "user1", IsAdmin: true})
+		require.Equal(t, http.StatusOK, w.Code)
+		require.JSONEq(t, `{"key": "value2"}`, w.Body.String())
+	})
+}
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"strings"
 	"sync"
@@ -19,12 +20,17 @@ import (
 // Auth is the struct for handling authorization for resources.
 type Auth struct {
 	enabled            bool
-	userHeader         string
+	userHeaders        []string
+	admins             []string
+	groupsHeader       string
+	groupsPrefix       string
 	defaultTeam        string
 	refreshInterval    time.Duration
 	clusters           *clusters.Clusters
 	defaultPermissions team.Permissions
 	users              sync.Map
+	groupTeams         []team.TeamSpec
+	groupTeamsMutex    sync.RWMutex
 }
 
 // Handler apply the authorization policy for a request and adds the user information to the request.
@@ -39,9 +45,15 @@ type Auth struct {
 func (a *Auth) Handler(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
-		userID := r.Header.Get(flagUserHeader)
+		userID, err := a.getUserID(r)
+		groups := a.getGroups(r)
 
 		if a.enabled {
+			if err != nil {
+				errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid user headers")
+				return
+			}
+
 			if userID == "" {
 				errresponse.Render(w, r, nil, http.StatusUnauthorized, "Unauthorized")
 				return
@@ -60,11 +72,16 @@ func (a *Auth) Handler(next http.Handler) http.Handler {
 				user = u.(authContext.User)
 			}
 
-			urlPaths := strings.Split(r.URL.Path, "/")
-			if len(urlPaths) >= 4 && urlPaths[1] == "api" && urlPaths[2] == "plugins" {
-				if !user.HasPluginAccess(urlPaths[3]) {
-					errresponse.Render(w, r, nil, http.StatusForbidden, "Your are not allowed to access the plugin")
-					return
+			user.Groups = groups
+			user.IsAdmin = a.isAdmin(userID)
+
+			// The teams, which are resolved from the groups of the user, are merged with the teams from the User CR.
+			// When the user doesn't have a User CR, the permissions of these teams replace the default permissions.
+			if groupPermissions, ok := a.getGroupPermissions(groups); ok {
+				if user.HasProfile {
+					user.Permissions = mergePermissions(user.Permissions, groupPermissions)
+				} else {
+					user.Permissions = groupPermissions
 				}
 			}
 
@@ -77,6 +94,7 @@ func (a *Auth) Handler(next http.Handler) http.Handler {
 			ctx = context.WithValue(ctx, authContext.UserKey, authContext.User{
 				ID:         userID,
 				HasProfile: false,
+				Groups:     groups,
 				Permissions: team.Permissions{
 					Plugins: []string{"*"},
 					Resources: []team.PermissionsResources{{
@@ -92,6 +110,105 @@ func (a *Auth) Handler(next http.Handler) http.Handler {
 	})
 }
 
+// getUserID returns the id of the authenticated user. The id is taken from the configured user header, which is
+// present in the request and contains a non-empty value. If none of the headers is set an empty string is returned.
+// Because a client could set one of the headers, which isn't used by the authentication proxy, to impersonate another
+// user, an error is returned when more than one of the configured headers contains a value.
+func (a *Auth) getUserID(r *http.Request) (string, error) {
+	var userID string
+	var userHeader string
+
+	for _, header := range a.userHeaders {
+		if value := strings.TrimSpace(r.Header.Get(header)); value != "" {
+			if userID != "" {
+				return "", fmt.Errorf("user headers %s and %s are both set", userHeader, header)
+			}
+
+			userID = value
+			userHeader = header
+		}
+	}
+
+	return userID, nil
+}
+
+// isAdmin returns true, when the given user id is contained in the list of configured admins.
+func (a *Auth) isAdmin(userID string) bool {
+	for _, admin := range a.admins {
+		if admin == userID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getGroupPermissions returns the merged permissions of all teams, which contain one of the given groups. If no team
+// contains one of the groups, false is returned.
+func (a *Auth) getGroupPermissions(groups []string) (team.Permissions, bool) {
+	a.groupTeamsMutex.RLock()
+	defer a.groupTeamsMutex.RUnlock()
+
+	var permissions team.Permissions
+	found := false
+
+	for _, t := range a.groupTeams {
+		if hasGroup(t.Groups, groups) {
+			permissions = mergePermissions(permissions, t.Permissions)
+			found = true
+		}
+	}
+
+	return permissions, found
+}
+
+// hasGroup returns true, when one of the given groups is contained in the groups of a team.
+func hasGroup(teamGroups, groups []string) bool {
+	for _, teamGroup := range teamGroups {
+		for _, group := range groups {
+			if teamGroup == group {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// mergePermissions returns new permissions, which contain the plugins and resources of both given permissions. New
+// slices are created, so that the permissions of the cached users are not modified.
+func mergePermissions(a, b team.Permissions) team.Permissions {
+	var permissions team.Permissions
+	permissions.Plugins = append(append(permissions.Plugins, a.Plugins...), b.Plugins...)
+	permissions.Resources = append(append(permissions.Resources, a.Resources...), b.Resources...)
+
+	return permissions
+}
+
+// getGroups returns the groups of the authenticated user from the configured groups header. The header must contain a
+// comma separated list of groups, like it is set by the OAuth2 Proxy from the groups claim of an OIDC token. If a prefix
+// is configured, it is added to each group, so that the groups can be distinguished from other groups in a cluster.
+func (a *Auth) getGroups(r *http.Request) []string {
+	if a.groupsHeader == "" {
+		return nil
+	}
+
+	header := r.Header.Get(a.groupsHeader)
+	if header == "" {
+		return nil
+	}
+
+	var groups []string
+	for _, group := range strings.Split(header, ",") {
+		group = strings.TrimSpace(group)
+		if group != "" {
+			groups = append(groups, a.groupsPrefix+group)
+		}
+	}
+
+	return groups
+}
+
 // GetPermissions should be called in a new goroutine to get a list of users and there permissions. This list is
 // refreshed by the refresh interval parameter.
 // When authentication and authorization isn't enabled this function directly returns. If the auth module is enabled it
@@ -134,7 +251,7 @@ func (a *Auth) getPermissions() error {
 	var teams []team.TeamSpec
 	var users []user.UserSpec
 
-	for _, cluster := range a.clusters.Clusters {
+	for _, cluster := range a.clusters.GetClusters() {
 		t, err := cluster.GetTeams(ctx, "")
 		if err != nil {
 			log.WithError(err).WithFields(logrus.Fields{"cluster": cluster.GetName()}).Warnf("could not get teams")
@@ -163,6 +280,17 @@ func (a *Auth) getPermissions() error {
 		a.users.Store(u.ID, getUserPermissions(u, teams))
 	}
 
+	var groupTeams []team.TeamSpec
+	for _, t := range teams {
+		if len(t.Groups) > 0 {
+			groupTeams = append(groupTeams, t)
+		}
+	}
+
+	a.groupTeamsMutex.Lock()
+	a.groupTeams = groupTeams
+	a.groupTeamsMutex.Unlock()
+
 	return nil
 }
 
@@ -196,10 +324,20 @@ func getUserPermissions(user user.UserSpec, teams []team.TeamSpec) authContext.U
 }
 
 // New returns a new authentication and authorization object.
-func New(enabled bool, userHeader, defaultTeam string, interval time.Duration, clusters *clusters.Clusters) *Auth {
+func New(enabled bool, userHeaders, admins []string, groupsHeader, groupsPrefix, defaultTeam string, interval time.Duration, clusters *clusters.Clusters) *Auth {
+	var trimmedUserHeaders []string
+	for _, header := range userHeaders {
+		if header = strings.TrimSpace(header); header != "" {
+			trimmedUserHeaders = append(trimmedUserHeaders, header)
+		}
+	}
+
 	return &Auth{
 		enabled:         enabled,
-		userHeader:      userHeader,
+		userHeaders:     trimmedUserHeaders,
+		admins:          admins,
+		groupsHeader:    groupsHeader,
+		groupsPrefix:    groupsPrefix,
 		defaultTeam:     defaultTeam,
 		refreshInterval: interval,
 		clusters:        clusters,
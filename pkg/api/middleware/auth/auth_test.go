@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	team "github.com/kobsio/kobs/pkg/api/apis/team/v1beta1"
+	authContext "github.com/kobsio/kobs/pkg/api/middleware/auth/context"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetUserID(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		userHeaders    []string
+		requestHeaders map[string]string
+		expectedUserID string
+		expectError    bool
+	}{
+		{name: "no headers configured", userHeaders: nil, requestHeaders: map[string]string{"X-Auth-Request-Email": "user1@kobs.io"}, expectedUserID: ""},
+		{name: "default header", userHeaders: []string{"X-Auth-Request-Email"}, requestHeaders: map[string]string{"X-Auth-Request-Email": "user1@kobs.io"}, expectedUserID: "user1@kobs.io"},
+		{name: "missing header", userHeaders: []string{"X-Auth-Request-Email"}, requestHeaders: nil, expectedUserID: ""},
+		{name: "empty header", userHeaders: []string{"X-Auth-Request-Email"}, requestHeaders: map[string]string{"X-Auth-Request-Email": "  "}, expectedUserID: ""},
+		{name: "fallback header", userHeaders: []string{"X-Auth-Request-Email", "X-Forwarded-User"}, requestHeaders: map[string]string{"X-Auth-Request-Email": "", "X-Forwarded-User": "user1"}, expectedUserID: "user1"},
+		{name: "multiple headers set", userHeaders: []string{"X-Auth-Request-Email", "X-Forwarded-User"}, requestHeaders: map[string]string{"X-Auth-Request-Email": "user1@kobs.io", "X-Forwarded-User": "user2"}, expectError: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			for key, value := range tc.requestHeaders {
+				r.Header.Set(key, value)
+			}
+
+			a := Auth{userHeaders: tc.userHeaders}
+			userID, err := a.getUserID(r)
+			if tc.expectError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.expectedUserID, userID)
+			}
+		})
+	}
+}
+
+func TestNewTrimsUserHeaders(t *testing.T) {
+	a := New(false, []string{" X-Auth-Request-Email", "", "X-Forwarded-User "}, nil, "", "", "", 0, nil)
+	require.Equal(t, []string{"X-Auth-Request-Email", "X-Forwarded-User"}, a.userHeaders)
+}
+
+func TestIsAdmin(t *testing.T) {
+	var isAdmin bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, err := authContext.GetUser(r.Context())
+		require.NoError(t, err)
+		isAdmin = user.IsAdmin
+	})
+
+	for _, tc := range []struct {
+		name     string
+		enabled  bool
+		expected bool
+	}{
+		{name: "auth enabled", enabled: true, expected: true},
+		{name: "auth disabled", enabled: false, expected: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("X-Auth-Request-Email", "admin@kobs.io")
+
+			a := Auth{enabled: tc.enabled, userHeaders: []string{"X-Auth-Request-Email"}, admins: []string{"admin@kobs.io"}}
+			a.Handler(next).ServeHTTP(httptest.NewRecorder(), r)
+			require.Equal(t, tc.expected, isAdmin)
+		})
+	}
+}
+
+func TestGetGroupPermissions(t *testing.T) {
+	a := Auth{groupTeams: []team.TeamSpec{
+		{Name: "team1", Groups: []string{"oidc:team1"}, Permissions: team.Permissions{Plugins: []string{"prometheus"}}},
+		{Name: "team2", Groups: []string{"oidc:team2", "oidc:admins"}, Permissions: team.Permissions{Plugins: []string{"*"}}},
+	}}
+
+	t.Run("no matching group", func(t *testing.T) {
+		_, ok := a.getGroupPermissions([]string{"oidc:team3"})
+		require.False(t, ok)
+	})
+
+	t.Run("matching groups", func(t *testing.T) {
+		permissions, ok := a.getGroupPermissions([]string{"oidc:team1", "oidc:admins"})
+		require.True(t, ok)
+		require.Equal(t, []string{"prometheus", "*"}, permissions.Plugins)
+	})
+}
@@ -15,10 +15,14 @@ type ctxKeyUser int
 const UserKey ctxKeyUser = 0
 
 // User is the structure of the user object saved in the request context. It contains the users id and permissions if
-// authentication is enabled.
+// authentication is enabled. The groups are the groups of the user from the configured groups header, which are used to
+// resolve the teams of the user. The admin field is only set, when authentication is enabled and the user was configured
+// as admin via the "api.auth.admins" flag.
 type User struct {
 	ID          string           `json:"id"`
 	HasProfile  bool             `json:"hasProfile"`
+	IsAdmin     bool             `json:"isAdmin,omitempty"`
+	Groups      []string         `json:"groups,omitempty"`
 	Profile     user.UserSpec    `json:"profile,omitempty"`
 	Permissions team.Permissions `json:"permissions"`
 }
@@ -3,6 +3,7 @@ package auth
 import (
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/go-chi/render"
@@ -17,16 +18,34 @@ import (
 var (
 	log = logrus.WithFields(logrus.Fields{"package": "authentication"})
 
-	flagEnabled     bool
-	flagUserHeader  string
-	flagInterval    time.Duration
-	flagDefaultTeam string
+	flagEnabled      bool
+	flagUserHeaders  []string
+	flagAdmins       []string
+	flagGroupsHeader string
+	flagGroupsPrefix string
+	flagInterval     time.Duration
+	flagDefaultTeam  string
 )
 
 func init() {
-	defaultHeader := "X-Auth-Request-Email"
+	defaultHeaders := []string{"X-Auth-Request-Email"}
 	if os.Getenv("KOBS_API_AUTH_HEADER") != "" {
-		defaultHeader = os.Getenv("KOBS_API_AUTH_HEADER")
+		defaultHeaders = strings.Split(os.Getenv("KOBS_API_AUTH_HEADER"), ",")
+	}
+
+	var defaultAdmins []string
+	if os.Getenv("KOBS_API_AUTH_ADMINS") != "" {
+		defaultAdmins = strings.Split(os.Getenv("KOBS_API_AUTH_ADMINS"), ",")
+	}
+
+	defaultGroupsHeader := "X-Auth-Request-Groups"
+	if os.Getenv("KOBS_API_AUTH_GROUPS_HEADER") != "" {
+		defaultGroupsHeader = os.Getenv("KOBS_API_AUTH_GROUPS_HEADER")
+	}
+
+	defaultGroupsPrefix := ""
+	if os.Getenv("KOBS_API_AUTH_GROUPS_PREFIX") != "" {
+		defaultGroupsPrefix = os.Getenv("KOBS_API_AUTH_GROUPS_PREFIX")
 	}
 
 	defaultInterval := time.Duration(1 * time.Hour)
@@ -43,14 +62,17 @@ func init() {
 	}
 
 	flag.BoolVar(&flagEnabled, "api.auth.enabled", false, "Enable the authentication and authorization middleware.")
-	flag.StringVar(&flagUserHeader, "api.auth.header", defaultHeader, "The header, which contains the details about the authenticated user.")
+	flag.StringSliceVar(&flagUserHeaders, "api.auth.header", defaultHeaders, "The header, which contains the details about the authenticated user. If multiple headers are configured, requests where more than one of them is set are rejected.")
+	flag.StringSliceVar(&flagAdmins, "api.auth.admins", defaultAdmins, "The ids of the users, which are allowed to use administrative endpoints, like testing the connection to a new plugin instance. Admins are only available when authentication is enabled.")
+	flag.StringVar(&flagGroupsHeader, "api.auth.groups-header", defaultGroupsHeader, "The header, which contains the comma separated list of groups of the authenticated user (e.g. from the groups claim of an OIDC token).")
+	flag.StringVar(&flagGroupsPrefix, "api.auth.groups-prefix", defaultGroupsPrefix, "A prefix, which is added to all groups of the authenticated user.")
 	flag.StringVar(&flagDefaultTeam, "api.auth.default-team", defaultTeam, "The name of the team, which should be used for a users permissions when a user hasn't any teams. The team is specified in the following format: \"cluster,namespace,name\"")
 	flag.DurationVar(&flagInterval, "api.auth.interval", defaultInterval, "The interval to refresh the internal users list and there permissions.")
 }
 
 // Handler creates a new Auth handler with passed options.
 func Handler(clusters *clusters.Clusters) func(next http.Handler) http.Handler {
-	a := New(flagEnabled, flagUserHeader, flagDefaultTeam, flagInterval, clusters)
+	a := New(flagEnabled, flagUserHeaders, flagAdmins, flagGroupsHeader, flagGroupsPrefix, flagDefaultTeam, flagInterval, clusters)
 	go a.GetPermissions()
 	return a.Handler
 }
@@ -5,6 +5,8 @@ package httplog
 import (
 	"fmt"
 	"net/http"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	authContext "github.com/kobsio/kobs/pkg/api/middleware/auth/context"
@@ -18,16 +20,53 @@ import (
 // own. Also take a look at https://github.com/pressly/lg for a dedicated pkg based
 // on this work, designed for context-based http routers.
 
-func NewStructuredLogger(logger *logrus.Logger) func(next http.Handler) http.Handler {
-	return middleware.RequestLogger(&StructuredLogger{logger})
+// Option is a function to configure the structured logger.
+type Option func(l *StructuredLogger)
+
+// WithSampling enables sampling for all requests, where the path starts with one of the given routes. For these
+// requests only 1 in rate requests is logged. Requests which fail with a status code of 400 or larger are always logged.
+// This can be used to reduce the number of logs for endpoints, which are polled by the frontend.
+func WithSampling(rate uint64, routes []string) Option {
+	return func(l *StructuredLogger) {
+		l.sampleRate = rate
+		l.sampleRoutes = routes
+	}
+}
+
+func NewStructuredLogger(logger *logrus.Logger, opts ...Option) func(next http.Handler) http.Handler {
+	l := &StructuredLogger{Logger: logger}
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	return middleware.RequestLogger(l)
 }
 
 type StructuredLogger struct {
-	Logger *logrus.Logger
+	Logger       *logrus.Logger
+	sampleRate   uint64
+	sampleRoutes []string
+	sampleCount  uint64
+}
+
+// isSampledOut returns true, when the request should not be logged, because the request path matches one of the
+// sampled routes and it isn't the 1 in N request which should be logged.
+func (l *StructuredLogger) isSampledOut(r *http.Request) bool {
+	if l.sampleRate <= 1 {
+		return false
+	}
+
+	for _, route := range l.sampleRoutes {
+		if strings.HasPrefix(r.URL.Path, route) {
+			return (atomic.AddUint64(&l.sampleCount, 1)-1)%l.sampleRate != 0
+		}
+	}
+
+	return false
 }
 
 func (l *StructuredLogger) NewLogEntry(r *http.Request) middleware.LogEntry {
-	entry := &StructuredLoggerEntry{Logger: logrus.NewEntry(l.Logger)}
+	entry := &StructuredLoggerEntry{Logger: logrus.NewEntry(l.Logger), sampledOut: l.isSampledOut(r)}
 	logFields := logrus.Fields{}
 
 	logFields["ts"] = time.Now().UTC().Format(time.RFC1123)
@@ -55,16 +94,23 @@ func (l *StructuredLogger) NewLogEntry(r *http.Request) middleware.LogEntry {
 
 	entry.Logger = entry.Logger.WithFields(logFields)
 
-	entry.Logger.Infoln("request started")
+	if !entry.sampledOut {
+		entry.Logger.Infoln("request started")
+	}
 
 	return entry
 }
 
 type StructuredLoggerEntry struct {
-	Logger logrus.FieldLogger
+	Logger     logrus.FieldLogger
+	sampledOut bool
 }
 
 func (l *StructuredLoggerEntry) Write(status, bytes int, header http.Header, elapsed time.Duration, extra interface{}) {
+	if l.sampledOut && status < 400 {
+		return
+	}
+
 	l.Logger = l.Logger.WithFields(logrus.Fields{
 		"resp_status":       status,
 		"resp_bytes_length": bytes,
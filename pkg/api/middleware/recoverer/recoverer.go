@@ -0,0 +1,147 @@
+// Package recoverer implements a middleware, which recovers from panics in the handlers of the kobs api. Like the
+// Recoverer middleware from chi it logs the panic and returns a 500 Internal Server Error. Additionally the panic can
+// be reported to an error tracking sink, by sending the stack trace and the request context to a configurable webhook.
+package recoverer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"time"
+
+	"github.com/kobsio/kobs/pkg/api/middleware/errresponse"
+	"github.com/kobsio/kobs/pkg/api/middleware/roundtripper"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/sirupsen/logrus"
+	flag "github.com/spf13/pflag"
+)
+
+var (
+	log = logrus.WithFields(logrus.Fields{"package": "recoverer"})
+
+	flagSink        string
+	flagSinkTimeout time.Duration
+)
+
+func init() {
+	defaultSink := ""
+	if os.Getenv("KOBS_API_RECOVERER_SINK") != "" {
+		defaultSink = os.Getenv("KOBS_API_RECOVERER_SINK")
+	}
+
+	defaultSinkTimeout := 5 * time.Second
+	if os.Getenv("KOBS_API_RECOVERER_SINK_TIMEOUT") != "" {
+		parsedSinkTimeout, err := time.ParseDuration(os.Getenv("KOBS_API_RECOVERER_SINK_TIMEOUT"))
+		if err == nil && parsedSinkTimeout > 0 {
+			defaultSinkTimeout = parsedSinkTimeout
+		}
+	}
+
+	flag.StringVar(&flagSink, "api.recoverer.sink", defaultSink, "The url of a webhook, where recovered panics should be reported to. If the url is empty, panics are only logged.")
+	flag.DurationVar(&flagSinkTimeout, "api.recoverer.sink-timeout", defaultSinkTimeout, "The timeout for reporting a recovered panic to the sink.")
+}
+
+// Event is the structure of the payload, which is send to the sink for each recovered panic.
+type Event struct {
+	Timestamp  time.Time `json:"timestamp"`
+	Message    string    `json:"message"`
+	Stacktrace string    `json:"stacktrace"`
+	RequestID  string    `json:"requestID,omitempty"`
+	Method     string    `json:"method"`
+	Path       string    `json:"path"`
+	RemoteAddr string    `json:"remoteAddr"`
+	UserAgent  string    `json:"userAgent,omitempty"`
+}
+
+// Recoverer recovers from panics and reports them to the configured sink.
+type Recoverer struct {
+	sink    string
+	timeout time.Duration
+}
+
+// report sends the given event to the sink. Errors are only logged, because the panic was already recovered and the
+// response for the user doesn't depend on the result of the report.
+func (rec *Recoverer) report(event Event) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		log.WithError(err).Errorf("Could not marshal panic event")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), rec.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rec.sink, bytes.NewReader(body))
+	if err != nil {
+		log.WithError(err).Errorf("Could not create request for panic event")
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := roundtripper.DefaultClient.Do(req)
+	if err != nil {
+		log.WithError(err).Errorf("Could not report panic event")
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		log.WithFields(logrus.Fields{"status": resp.StatusCode}).Errorf("Could not report panic event")
+	}
+}
+
+// Handler is the middleware, which recovers from panics. The http.ErrAbortHandler panic is used to abort a handler, so
+// that we do not recover from it, like it is also done in the Recoverer middleware from chi.
+func (rec *Recoverer) Handler(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if rvr := recover(); rvr != nil {
+				if rvr == http.ErrAbortHandler {
+					panic(rvr)
+				}
+
+				event := Event{
+					Timestamp:  time.Now(),
+					Message:    fmt.Sprintf("%v", rvr),
+					Stacktrace: string(debug.Stack()),
+					RequestID:  middleware.GetReqID(r.Context()),
+					Method:     r.Method,
+					Path:       r.URL.Path,
+					RemoteAddr: r.RemoteAddr,
+					UserAgent:  r.UserAgent(),
+				}
+
+				log.WithFields(logrus.Fields{"message": event.Message, "requestID": event.RequestID, "method": event.Method, "path": event.Path, "stacktrace": event.Stacktrace}).Errorf("Recovered from panic")
+
+				if rec.sink != "" {
+					go rec.report(event)
+				}
+
+				errresponse.Render(w, r, nil, http.StatusInternalServerError, "Internal Server Error")
+			}
+		}()
+
+		next.ServeHTTP(w, r)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// New returns a new Recoverer, which reports recovered panics to the given sink. If the sink is empty, panics are only
+// logged.
+func New(sink string, timeout time.Duration) *Recoverer {
+	return &Recoverer{
+		sink:    sink,
+		timeout: timeout,
+	}
+}
+
+// Handler creates a new Recoverer with the options from the command-line flags and returns the middleware.
+func Handler() func(next http.Handler) http.Handler {
+	return New(flagSink, flagSinkTimeout).Handler
+}
@@ -0,0 +1,40 @@
+package recoverer
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	events := make(chan Event, 1)
+
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event Event
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+		events <- event
+	}))
+	defer sink.Close()
+
+	handler := New(sink.URL, time.Second).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("test panic")
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/test", nil))
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+
+	select {
+	case event := <-events:
+		require.Equal(t, "test panic", event.Message)
+		require.Equal(t, http.MethodGet, event.Method)
+		require.Equal(t, "/api/test", event.Path)
+		require.Contains(t, event.Stacktrace, "recoverer")
+	case <-time.After(5 * time.Second):
+		t.Fatal("panic was not reported to the sink")
+	}
+}
@@ -3,17 +3,74 @@ package roundtripper
 import (
 	"net"
 	"net/http"
+	"os"
+	"strconv"
 	"time"
+
+	flag "github.com/spf13/pflag"
 )
 
-// DefaultRoundTripper is our default RoundTripper.
-var DefaultRoundTripper http.RoundTripper = &http.Transport{
-	Proxy: http.ProxyFromEnvironment,
-	DialContext: (&net.Dialer{
+var (
+	// dialer is the dialer, which is used by our default transport. It is a package variable, so that the keep-alive
+	// period can be set via a command-line flag.
+	dialer = &net.Dialer{
 		Timeout:   30 * time.Second,
 		KeepAlive: 30 * time.Second,
-	}).DialContext,
-	TLSHandshakeTimeout: 10 * time.Second,
+	}
+
+	// defaultTransport is the transport, which is used by the DefaultRoundTripper and the DefaultClient. The settings
+	// for the idle connections can be set via command-line flags, so that connections can be reused efficiently, when
+	// many requests are made against the same hosts (e.g. when polling RSS feeds).
+	defaultTransport = &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: 10 * time.Second,
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     90 * time.Second,
+	}
+)
+
+// init is used to define all command-line flags for the default transport. The flags are bound directly to the fields
+// of the default transport, so that the values are used as soon as the flags are parsed.
+func init() {
+	if os.Getenv("KOBS_API_HTTP_MAX_IDLE_CONNS") != "" {
+		if parsed, err := strconv.Atoi(os.Getenv("KOBS_API_HTTP_MAX_IDLE_CONNS")); err == nil {
+			defaultTransport.MaxIdleConns = parsed
+		}
+	}
+
+	if os.Getenv("KOBS_API_HTTP_MAX_IDLE_CONNS_PER_HOST") != "" {
+		if parsed, err := strconv.Atoi(os.Getenv("KOBS_API_HTTP_MAX_IDLE_CONNS_PER_HOST")); err == nil {
+			defaultTransport.MaxIdleConnsPerHost = parsed
+		}
+	}
+
+	if os.Getenv("KOBS_API_HTTP_IDLE_CONN_TIMEOUT") != "" {
+		if parsed, err := time.ParseDuration(os.Getenv("KOBS_API_HTTP_IDLE_CONN_TIMEOUT")); err == nil {
+			defaultTransport.IdleConnTimeout = parsed
+		}
+	}
+
+	if os.Getenv("KOBS_API_HTTP_KEEP_ALIVE") != "" {
+		if parsed, err := time.ParseDuration(os.Getenv("KOBS_API_HTTP_KEEP_ALIVE")); err == nil {
+			dialer.KeepAlive = parsed
+		}
+	}
+
+	flag.IntVar(&defaultTransport.MaxIdleConns, "api.http.max-idle-conns", defaultTransport.MaxIdleConns, "The maximum number of idle connections across all hosts for outgoing HTTP requests. A value of 0 means no limit.")
+	flag.IntVar(&defaultTransport.MaxIdleConnsPerHost, "api.http.max-idle-conns-per-host", defaultTransport.MaxIdleConnsPerHost, "The maximum number of idle connections per host for outgoing HTTP requests.")
+	flag.DurationVar(&defaultTransport.IdleConnTimeout, "api.http.idle-conn-timeout", defaultTransport.IdleConnTimeout, "The maximum amount of time an idle connection for outgoing HTTP requests remains open. A value of 0 means no limit.")
+	flag.DurationVar(&dialer.KeepAlive, "api.http.keep-alive", dialer.KeepAlive, "The interval between keep-alive probes for outgoing HTTP connections. A negative value disables keep-alives.")
+}
+
+// DefaultRoundTripper is our default RoundTripper.
+var DefaultRoundTripper http.RoundTripper = defaultTransport
+
+// DefaultClient is a shared HTTP client, which uses the DefaultRoundTripper. It should be used for all outgoing
+// requests, which do not require a special configuration, so that connections can be reused.
+var DefaultClient = &http.Client{
+	Transport: DefaultRoundTripper,
 }
 
 // BasicAuthTransport is the struct to add basic auth to a RoundTripper.
@@ -0,0 +1,154 @@
+// Package timeout implements a middleware, which cancels the context of a request after the configured timeout. When
+// the timeout is exceeded, the response of the handler is discarded and a 504 Gateway Timeout error is returned.
+package timeout
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kobsio/kobs/pkg/api/middleware/errresponse"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// timeoutWriter wraps the http.ResponseWriter of a request. As long as the timeout isn't exceeded all writes are passed
+// to the underlying writer. After the timeout is exceeded all writes are discarded, so that the middleware can return
+// the timeout error, when the handler didn't already write a response.
+type timeoutWriter struct {
+	http.ResponseWriter
+	ctx         context.Context
+	mutex       sync.Mutex
+	wroteHeader bool
+}
+
+// WriteHeader writes the given status code, when the timeout isn't exceeded.
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mutex.Lock()
+	defer tw.mutex.Unlock()
+
+	if tw.wroteHeader || tw.isTimedOut() {
+		return
+	}
+
+	tw.wroteHeader = true
+	tw.ResponseWriter.WriteHeader(code)
+}
+
+// Write writes the given data, when the timeout isn't exceeded or when the handler already started to write the
+// response before the timeout was exceeded.
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	tw.mutex.Lock()
+	defer tw.mutex.Unlock()
+
+	if !tw.wroteHeader {
+		if tw.isTimedOut() {
+			return 0, context.DeadlineExceeded
+		}
+
+		tw.wroteHeader = true
+	}
+
+	return tw.ResponseWriter.Write(b)
+}
+
+// Flush sends the buffered data to the client, when the timeout isn't exceeded or when the handler already started to
+// write the response before the timeout was exceeded. This is required for handlers which are streaming their response.
+func (tw *timeoutWriter) Flush() {
+	tw.mutex.Lock()
+	defer tw.mutex.Unlock()
+
+	if !tw.wroteHeader {
+		if tw.isTimedOut() {
+			return
+		}
+
+		tw.wroteHeader = true
+	}
+
+	if flusher, ok := tw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack lets the handler take over the connection, when the timeout isn't exceeded and the handler didn't already
+// write a response. This is required for WebSocket connections.
+func (tw *timeoutWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	tw.mutex.Lock()
+	defer tw.mutex.Unlock()
+
+	if tw.wroteHeader {
+		return nil, nil, fmt.Errorf("response was already written")
+	}
+
+	if tw.isTimedOut() {
+		return nil, nil, context.DeadlineExceeded
+	}
+
+	hijacker, ok := tw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer does not support hijacking")
+	}
+
+	tw.wroteHeader = true
+	return hijacker.Hijack()
+}
+
+// isTimedOut returns true, when the timeout of the request is exceeded.
+func (tw *timeoutWriter) isTimedOut() bool {
+	return errors.Is(tw.ctx.Err(), context.DeadlineExceeded)
+}
+
+// isStreaming returns true for WebSocket requests and for requests to one of the given streaming routes. These requests
+// are long running by design, so that the timeout must not be applied. The routes are relative to the route under which
+// the router of the plugin is mounted, so that we have to compare them with the wildcard parameter of the mount.
+func isStreaming(r *http.Request, streamingRoutes []string) bool {
+	if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		return true
+	}
+
+	route := "/" + strings.TrimPrefix(chi.URLParam(r, "*"), "/")
+	for _, streamingRoute := range streamingRoutes {
+		if route == streamingRoute {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Handler returns a middleware, which applies the given timeout to all requests. If the timeout is 0, the middleware
+// doesn't modify the request. The timeout is also not applied to WebSocket requests and to the given streaming routes
+// (e.g. routes which are using Server-Sent Events or which are streaming a file to the client).
+func Handler(timeout time.Duration, streamingRoutes ...string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			if timeout <= 0 || isStreaming(r, streamingRoutes) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+
+			tw := &timeoutWriter{ResponseWriter: w, ctx: ctx}
+			next.ServeHTTP(tw, r.WithContext(ctx))
+
+			tw.mutex.Lock()
+			defer tw.mutex.Unlock()
+
+			if !tw.wroteHeader && tw.isTimedOut() {
+				tw.wroteHeader = true
+				errresponse.Render(w, r, nil, http.StatusGatewayTimeout, "Request timed out")
+			}
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
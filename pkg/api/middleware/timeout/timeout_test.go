@@ -0,0 +1,70 @@
+package timeout
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	slowHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, nil)
+		case <-time.After(100 * time.Millisecond):
+			render.JSON(w, r, nil)
+		}
+	})
+
+	for _, tc := range []struct {
+		name    string
+		timeout time.Duration
+		path    string
+		header  http.Header
+		expect  int
+	}{
+		{name: "timeout exceeded", timeout: 10 * time.Millisecond, expect: http.StatusGatewayTimeout},
+		{name: "timeout not exceeded", timeout: time.Second, expect: http.StatusOK},
+		{name: "no timeout", timeout: 0, expect: http.StatusOK},
+		{name: "websocket", timeout: 10 * time.Millisecond, header: http.Header{"Upgrade": []string{"websocket"}}, expect: http.StatusOK},
+		{name: "streaming route", timeout: 10 * time.Millisecond, path: "/plugin/events", expect: http.StatusOK},
+		{name: "event stream without streaming route", timeout: 10 * time.Millisecond, path: "/plugin/list", header: http.Header{"Accept": []string{"text/event-stream"}}, expect: http.StatusGatewayTimeout},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			path := tc.path
+			if path == "" {
+				path = "/plugin/"
+			}
+
+			router := chi.NewRouter()
+			router.With(Handler(tc.timeout, "/events")).Mount("/plugin", slowHandler)
+
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			for key, values := range tc.header {
+				req.Header[key] = values
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			require.Equal(t, tc.expect, w.Code)
+		})
+	}
+}
+
+func TestTimeoutWriterFlush(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("data"))
+		w.(http.Flusher).Flush()
+	})
+
+	w := httptest.NewRecorder()
+	Handler(time.Second)(handler).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.True(t, w.Flushed)
+	require.Equal(t, "data", w.Body.String())
+}
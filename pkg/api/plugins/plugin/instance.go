@@ -0,0 +1,58 @@
+package plugin
+
+// InstanceInfo returns the name of the instance at the given index and if the instance was marked as default in the
+// configuration.
+type InstanceInfo func(index int) (name string, isDefault bool)
+
+// IsDefaultName returns true, when the given instance name is empty or "default". These names can be used to select
+// the default instance of a plugin.
+func IsDefaultName(name string) bool {
+	return name == "" || name == "default"
+}
+
+// GetInstanceIndexByName returns the index of the instance with the given name. If no instance with the given name
+// exists, -1 is returned.
+func GetInstanceIndexByName(name string, count int, info InstanceInfo) int {
+	for i := 0; i < count; i++ {
+		if instanceName, _ := info(i); instanceName == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// GetDefaultInstanceIndex returns the index of the instance, which was marked as default in the configuration. If no
+// instance was marked as default, the first instance is used as default. If no instances are configured, -1 is
+// returned.
+func GetDefaultInstanceIndex(count int, info InstanceInfo) int {
+	for i := 0; i < count; i++ {
+		if _, isDefault := info(i); isDefault {
+			return i
+		}
+	}
+
+	if count > 0 {
+		return 0
+	}
+
+	return -1
+}
+
+// GetInstanceIndex returns the index of the instance, which should be used for the given name. An instance with exactly
+// the given name is always preferred. If no instance has the given name and the name is empty or "default", the index
+// of the default instance is returned. If no instance was found, -1 is returned.
+//
+// The function is used by all plugins, which are supporting multiple instances, so that the instances are always
+// selected in the same way.
+func GetInstanceIndex(name string, count int, info InstanceInfo) int {
+	if index := GetInstanceIndexByName(name, count, info); index != -1 {
+		return index
+	}
+
+	if IsDefaultName(name) {
+		return GetDefaultInstanceIndex(count, info)
+	}
+
+	return -1
+}
@@ -0,0 +1,39 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testInstance struct {
+	name      string
+	isDefault bool
+}
+
+func getTestInstanceInfo(instances []testInstance) InstanceInfo {
+	return func(index int) (string, bool) {
+		return instances[index].name, instances[index].isDefault
+	}
+}
+
+func TestGetInstanceIndex(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		instances     []testInstance
+		instanceName  string
+		expectedIndex int
+	}{
+		{name: "exact name", instances: []testInstance{{name: "a"}, {name: "b", isDefault: true}}, instanceName: "a", expectedIndex: 0},
+		{name: "exact name default", instances: []testInstance{{name: "a", isDefault: true}, {name: "default"}}, instanceName: "default", expectedIndex: 1},
+		{name: "empty name", instances: []testInstance{{name: "a"}, {name: "b", isDefault: true}}, instanceName: "", expectedIndex: 1},
+		{name: "default name", instances: []testInstance{{name: "a"}, {name: "b", isDefault: true}}, instanceName: "default", expectedIndex: 1},
+		{name: "no default instance", instances: []testInstance{{name: "a"}, {name: "b"}}, instanceName: "default", expectedIndex: 0},
+		{name: "unknown name", instances: []testInstance{{name: "a", isDefault: true}}, instanceName: "b", expectedIndex: -1},
+		{name: "no instances", instances: nil, instanceName: "default", expectedIndex: -1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expectedIndex, GetInstanceIndex(tc.instanceName, len(tc.instances), getTestInstanceInfo(tc.instances)))
+		})
+	}
+}
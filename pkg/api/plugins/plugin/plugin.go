@@ -19,3 +19,16 @@ type Plugins []Plugin
 func (p *Plugins) Append(plugin Plugin) {
 	*p = append(*p, plugin)
 }
+
+// Update replaces the display name, description and options of the plugin instance with the same name and type as the
+// given plugin. The name and type of a plugin instance can not be changed. If no plugin instance with the same name and
+// type exists, nothing is changed.
+func (p *Plugins) Update(plugin Plugin) {
+	for i := range *p {
+		if (*p)[i].Name == plugin.Name && (*p)[i].Type == plugin.Type {
+			(*p)[i].DisplayName = plugin.DisplayName
+			(*p)[i].Description = plugin.Description
+			(*p)[i].Options = plugin.Options
+		}
+	}
+}
@@ -6,7 +6,12 @@ import (
 	"os"
 	"time"
 
+	"github.com/kobsio/kobs/pkg/api/clusters"
+	"github.com/kobsio/kobs/pkg/api/clusters/cluster"
+	"github.com/kobsio/kobs/pkg/api/plugins/plugin"
+
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/sirupsen/logrus"
 	flag "github.com/spf13/pflag"
@@ -28,6 +33,13 @@ func init() {
 	flag.StringVar(&address, "metrics.address", defaultAddress, "The address, where the Prometheus metrics are served.")
 }
 
+// Status is the self-diagnostic status of kobs, which is served via the "/debug/status" endpoint. It contains the
+// status of all clusters and the number of instances for each plugin type.
+type Status struct {
+	Clusters []cluster.Status `json:"clusters"`
+	Plugins  map[string]int   `json:"plugins"`
+}
+
 // Server implements the metrics server. The metrics server is used to serve Prometheus metrics for kobs.
 type Server struct {
 	*http.Server
@@ -54,10 +66,34 @@ func (s *Server) Stop() {
 	}
 }
 
-// New return a new metrics server.
-func New() *Server {
+// getStatus returns the status of all clusters and plugins as JSON. This gives operators a quick human-readable
+// snapshot of kobs, without scraping the Prometheus metrics.
+func getStatus(loadedClusters *clusters.Clusters, plugins *plugin.Plugins) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		status := Status{
+			Plugins: make(map[string]int),
+		}
+
+		if loadedClusters != nil {
+			status.Clusters = loadedClusters.GetStatus()
+		}
+
+		if plugins != nil {
+			for _, p := range *plugins {
+				status.Plugins[p.Type] = status.Plugins[p.Type] + 1
+			}
+		}
+
+		render.JSON(w, r, status)
+	}
+}
+
+// New return a new metrics server. Next to the Prometheus metrics the server also serves the status of the given
+// clusters and plugins.
+func New(loadedClusters *clusters.Clusters, plugins *plugin.Plugins) *Server {
 	router := chi.NewRouter()
 	router.Handle("/metrics", promhttp.Handler())
+	router.Get("/debug/status", getStatus(loadedClusters, plugins))
 
 	return &Server{
 		&http.Server{
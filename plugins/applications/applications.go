@@ -2,15 +2,21 @@ package applications
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"time"
 
 	application "github.com/kobsio/kobs/pkg/api/apis/application/v1beta1"
+	dashboard "github.com/kobsio/kobs/pkg/api/apis/dashboard/v1beta1"
 	"github.com/kobsio/kobs/pkg/api/clusters"
 	"github.com/kobsio/kobs/pkg/api/middleware/errresponse"
 	"github.com/kobsio/kobs/pkg/api/plugins/plugin"
+	"github.com/kobsio/kobs/plugins/applications/pkg/health"
+	"github.com/kobsio/kobs/plugins/applications/pkg/tags"
 	"github.com/kobsio/kobs/plugins/applications/pkg/teams"
 	"github.com/kobsio/kobs/plugins/applications/pkg/topology"
+	"github.com/kobsio/kobs/plugins/dashboards/pkg/placeholders"
+	"github.com/kobsio/kobs/plugins/prometheus/pkg/instance"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
@@ -26,17 +32,46 @@ var (
 
 // Config is the structure of the configuration for the applications plugin.
 type Config struct {
-	TopologyCacheDuration string `json:"topologyCacheDuration"`
-	TeamsCacheDuration    string `json:"teamsCacheDuration"`
+	TopologyCacheDuration string       `json:"topologyCacheDuration"`
+	TeamsCacheDuration    string       `json:"teamsCacheDuration"`
+	Health                HealthConfig `json:"health"`
+}
+
+// HealthConfig is the configuration for the health rollup of all applications. The health rollup must be enabled
+// explicitly, because it runs the health checks of all applications against the configured Prometheus instances.
+type HealthConfig struct {
+	Enabled       bool   `json:"enabled"`
+	CacheDuration string `json:"cacheDuration"`
+	Concurrency   int    `json:"concurrency"`
+	Timeout       string `json:"timeout"`
+}
+
+// expandedApplication is the structure, which is returned by the getApplication api call, when the user requested to
+// expand the dashboards of an application. Next to the application it contains the resolved dashboards.
+type expandedApplication struct {
+	*application.ApplicationSpec
+	ExpandedDashboards []expandedDashboard `json:"expandedDashboards"`
+}
+
+// expandedDashboard is a single resolved dashboard reference. If the referenced dashboard could not be resolved the
+// error field contains the reason, so that the other dashboards of the application can still be rendered.
+type expandedDashboard struct {
+	Reference dashboard.Reference      `json:"reference"`
+	Dashboard *dashboard.DashboardSpec `json:"dashboard,omitempty"`
+	Error     string                   `json:"error,omitempty"`
 }
 
 // Router implements the router for the resources plugin, which can be registered in the router for our rest api.
 type Router struct {
 	*chi.Mux
-	clusters *clusters.Clusters
-	config   Config
-	topology topology.Cache
-	teams    teams.Cache
+	clusters            *clusters.Clusters
+	config              Config
+	topology            topology.Cache
+	teams               teams.Cache
+	health              *health.Cache
+	healthConcurrency   int
+	healthTimeout       time.Duration
+	prometheusInstances []*instance.Instance
 }
 
 // getApplications returns a list of applications. This api endpoint supports multiple options to get applications. So
@@ -45,14 +80,16 @@ type Router struct {
 // a team for which he wants to retrieve the applications. The third option is the topology view, for which a list of
 // cluster and namespaces is needed.
 func (router *Router) getApplications(w http.ResponseWriter, r *http.Request) {
-	clusterNames := r.URL.Query()["cluster"]
+	clusterNames := router.clusters.GetClusterNames(r.URL.Query()["cluster"])
 	namespaces := r.URL.Query()["namespace"]
 	view := r.URL.Query().Get("view")
 	teamCluster := r.URL.Query().Get("teamCluster")
 	teamNamespace := r.URL.Query().Get("teamNamespace")
 	teamName := r.URL.Query().Get("teamName")
+	tagNames := r.URL.Query()["tag"]
+	tagOperator := r.URL.Query().Get("tagOperator")
 
-	log.WithFields(logrus.Fields{"clusters": clusterNames, "namespaces": namespaces, "team-cluster": teamCluster, "team-namespace": teamNamespace, "team-name": teamName, "view": view}).Tracef("getApplications")
+	log.WithFields(logrus.Fields{"clusters": clusterNames, "namespaces": namespaces, "team-cluster": teamCluster, "team-namespace": teamNamespace, "team-name": teamName, "tags": tagNames, "tag-operator": tagOperator, "view": view}).Tracef("getApplications")
 
 	if view == "gallery" {
 		// If the view parameter has the value "gallery" and the team parameters are defined we return all applications
@@ -63,7 +100,7 @@ func (router *Router) getApplications(w http.ResponseWriter, r *http.Request) {
 		// background.
 		if teamCluster != "" && teamNamespace != "" && teamName != "" {
 			if router.teams.LastFetch.After(time.Now().Add(-1 * router.teams.CacheDuration)) {
-				applications := teams.GetApplications(router.teams.Teams, teamCluster, teamNamespace, teamName)
+				applications := tags.Filter(teams.GetApplications(router.teams.Teams, teamCluster, teamNamespace, teamName), tagNames, tagOperator)
 				log.WithFields(logrus.Fields{"team": "return cached applications", "applications": len(applications)}).Tracef("getApplications")
 				render.JSON(w, r, applications)
 				return
@@ -75,7 +112,7 @@ func (router *Router) getApplications(w http.ResponseWriter, r *http.Request) {
 					router.teams.LastFetch = time.Now()
 					router.teams.Teams = ts
 
-					applications := teams.GetApplications(ts, teamCluster, teamNamespace, teamName)
+					applications := tags.Filter(teams.GetApplications(ts, teamCluster, teamNamespace, teamName), tagNames, tagOperator)
 					log.WithFields(logrus.Fields{"team": "get and return applications", "applications": len(applications)}).Tracef("getApplications")
 					render.JSON(w, r, applications)
 					return
@@ -94,46 +131,21 @@ func (router *Router) getApplications(w http.ResponseWriter, r *http.Request) {
 				}
 			}()
 
-			applications := teams.GetApplications(router.teams.Teams, teamCluster, teamNamespace, teamName)
+			applications := tags.Filter(teams.GetApplications(router.teams.Teams, teamCluster, teamNamespace, teamName), tagNames, tagOperator)
 			log.WithFields(logrus.Fields{"team": "return applications", "applications": len(applications)}).Tracef("getApplications")
 			render.JSON(w, r, applications)
 			return
 		}
 
 		// When no team is definied for the gallery view, we are returning all applications for the requested clusters
-		// and namespaces. For this we just have to loop through the clusters and namespaces and add all the
-		// applications to one list.
-		var applications []application.ApplicationSpec
-
-		for _, clusterName := range clusterNames {
-			cluster := router.clusters.GetCluster(clusterName)
-			if cluster == nil {
-				errresponse.Render(w, r, nil, http.StatusBadRequest, "Invalid cluster name")
-				return
-			}
-
-			if namespaces == nil {
-				application, err := cluster.GetApplications(r.Context(), "")
-				if err != nil {
-					errresponse.Render(w, r, err, http.StatusBadRequest, "Could not get applications")
-					return
-				}
-
-				applications = append(applications, application...)
-			} else {
-				for _, namespace := range namespaces {
-					application, err := cluster.GetApplications(r.Context(), namespace)
-					if err != nil {
-						errresponse.Render(w, r, err, http.StatusBadRequest, "Could not get applications")
-						return
-					}
-
-					applications = append(applications, application...)
-				}
-			}
+		// and namespaces. If the user provided a list of tags, only the applications with these tags are returned.
+		applications, err := router.listApplications(r.Context(), clusterNames, namespaces)
+		if err != nil {
+			errresponse.Render(w, r, err, getErrorStatus(err), "Could not get applications")
+			return
 		}
 
-		// TODO: Check if the user provided a list of tags and filter the applications for this tag.
+		applications = tags.Filter(applications, tagNames, tagOperator)
 
 		log.WithFields(logrus.Fields{"count": len(applications)}).Tracef("getApplications")
 		render.JSON(w, r, applications)
@@ -187,18 +199,194 @@ func (router *Router) getApplications(w http.ResponseWriter, r *http.Request) {
 	errresponse.Render(w, r, nil, http.StatusBadRequest, "Invalid view property")
 }
 
+// listApplications returns all applications for the given clusters and namespaces. If the namespaces slice is nil, the
+// applications from all namespaces of a cluster are returned.
+func (router *Router) listApplications(ctx context.Context, clusterNames, namespaces []string) ([]application.ApplicationSpec, error) {
+	var applications []application.ApplicationSpec
+
+	if namespaces == nil {
+		namespaces = []string{""}
+	}
+
+	for _, clusterName := range clusterNames {
+		cluster, err := router.clusters.GetCluster(clusterName)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, namespace := range namespaces {
+			release, err := router.clusters.Acquire(ctx, clusterName)
+			if err != nil {
+				return nil, err
+			}
+
+			application, err := cluster.GetApplications(ctx, namespace)
+			release()
+			if err != nil {
+				return nil, err
+			}
+
+			applications = append(applications, application...)
+		}
+	}
+
+	return applications, nil
+}
+
+// getErrorStatus returns the status code for an error returned by the listApplications function.
+func getErrorStatus(err error) int {
+	if errors.Is(err, clusters.ErrTooManyRequests) {
+		return http.StatusTooManyRequests
+	}
+
+	return http.StatusBadRequest
+}
+
+// getTags returns all tags of the applications in the given clusters and namespaces, so that the frontend can build a
+// filter for the tags without loading all applications.
+func (router *Router) getTags(w http.ResponseWriter, r *http.Request) {
+	clusterNames := router.clusters.GetClusterNames(r.URL.Query()["cluster"])
+	namespaces := r.URL.Query()["namespace"]
+
+	log.WithFields(logrus.Fields{"clusters": clusterNames, "namespaces": namespaces}).Tracef("getTags")
+
+	applications, err := router.listApplications(r.Context(), clusterNames, namespaces)
+	if err != nil {
+		errresponse.Render(w, r, err, getErrorStatus(err), "Could not get tags")
+		return
+	}
+
+	applicationTags := tags.Get(applications)
+
+	log.WithFields(logrus.Fields{"count": len(applicationTags)}).Tracef("getTags")
+	render.JSON(w, r, applicationTags)
+}
+
+// getQuerier returns the Prometheus instance with the given name, which is used to evaluate a health check. If no
+// instance has the given name and the name is empty or "default", the default instance is returned.
+func (router *Router) getQuerier(name string) health.Querier {
+	index := plugin.GetInstanceIndex(name, len(router.prometheusInstances), func(i int) (string, bool) {
+		return router.prometheusInstances[i].Name, router.prometheusInstances[i].Default
+	})
+	if index == -1 {
+		return nil
+	}
+
+	return router.prometheusInstances[index]
+}
+
+// getHealth returns the aggregated health status of all applications. The health status is computed by running the
+// health checks of all applications and cached for a short time, so that multiple users can not overload the Prometheus
+// instances. Since the result is shared between all users, the health checks are not canceled when the request is
+// canceled. The optional cluster and namespace parameters can be used to get the rollup for a subset of applications.
+func (router *Router) getHealth(w http.ResponseWriter, r *http.Request) {
+	clusterName := r.URL.Query().Get("cluster")
+	namespace := r.URL.Query().Get("namespace")
+
+	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace}).Tracef("getHealth")
+
+	if router.health == nil {
+		errresponse.Render(w, r, nil, http.StatusNotImplemented, "Health rollup is not enabled")
+		return
+	}
+
+	applicationsHealth, err := router.health.Get(func() ([]health.Application, error) {
+		var clusterNames []string
+		for _, cluster := range router.clusters.GetClusters() {
+			clusterNames = append(clusterNames, cluster.GetName())
+		}
+
+		applications, err := router.listApplications(context.Background(), clusterNames, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		return health.Evaluate(context.Background(), applications, router.getQuerier, router.healthConcurrency, router.healthTimeout), nil
+	})
+	if err != nil {
+		errresponse.Render(w, r, err, getErrorStatus(err), "Could not get health of applications")
+		return
+	}
+
+	rollup := health.Summarize(applicationsHealth, clusterName, namespace)
+
+	log.WithFields(logrus.Fields{"healthy": rollup.Healthy, "degraded": rollup.Degraded, "unknown": rollup.Unknown}).Tracef("getHealth")
+	render.JSON(w, r, rollup)
+}
+
+// expandDashboards resolves all dashboard references of the given application. The cluster and namespace of a
+// reference are defaulted to the cluster and namespace of the application, like it is done in the dashboards plugin.
+// When a reference can not be resolved, we add the error to the reference instead of failing the whole request.
+func (router *Router) expandDashboards(ctx context.Context, app *application.ApplicationSpec) []expandedDashboard {
+	var dashboards []expandedDashboard
+
+	for _, reference := range app.Dashboards {
+		if reference.Cluster == "" {
+			reference.Cluster = app.Cluster
+		}
+
+		if reference.Namespace == "" {
+			reference.Namespace = app.Namespace
+		}
+
+		if reference.Inline != nil {
+			dashboards = append(dashboards, expandedDashboard{
+				Reference: reference,
+				Dashboard: &dashboard.DashboardSpec{
+					Cluster:     "-",
+					Namespace:   "-",
+					Name:        "-",
+					Title:       reference.Title,
+					Description: reference.Description,
+					Variables:   reference.Inline.Variables,
+					Rows:        reference.Inline.Rows,
+				},
+			})
+			continue
+		}
+
+		cluster, err := router.clusters.GetCluster(reference.Cluster)
+		if err != nil {
+			dashboards = append(dashboards, expandedDashboard{Reference: reference, Error: err.Error()})
+			continue
+		}
+
+		dash, err := cluster.GetDashboard(ctx, reference.Namespace, reference.Name)
+		if err != nil {
+			dashboards = append(dashboards, expandedDashboard{Reference: reference, Error: err.Error()})
+			continue
+		}
+
+		if reference.Placeholders != nil {
+			dash, err = placeholders.Replace(reference.Placeholders, *dash)
+			if err != nil {
+				dashboards = append(dashboards, expandedDashboard{Reference: reference, Error: err.Error()})
+				continue
+			}
+		}
+
+		dash.Title = reference.Title
+		dashboards = append(dashboards, expandedDashboard{Reference: reference, Dashboard: dash})
+	}
+
+	return dashboards
+}
+
 // getApplication returns a a single application for the given clusters and namespaces and name. The cluster, namespace
-// and name is defined via the corresponding query parameters.
+// and name is defined via the corresponding query parameters. If the expand parameter is set to "dashboards", the
+// referenced dashboards are resolved and returned together with the application, so that the frontend can render the
+// application page with one request.
 func (router *Router) getApplication(w http.ResponseWriter, r *http.Request) {
 	clusterName := r.URL.Query().Get("cluster")
 	namespace := r.URL.Query().Get("namespace")
 	name := r.URL.Query().Get("name")
+	expand := r.URL.Query().Get("expand")
 
-	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "name": name}).Tracef("getApplication")
+	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "name": name, "expand": expand}).Tracef("getApplication")
 
-	cluster := router.clusters.GetCluster(clusterName)
-	if cluster == nil {
-		errresponse.Render(w, r, nil, http.StatusBadRequest, "Invalid cluster name")
+	cluster, err := router.clusters.GetCluster(clusterName)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
 		return
 	}
 
@@ -208,11 +396,19 @@ func (router *Router) getApplication(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if expand == "dashboards" {
+		render.JSON(w, r, expandedApplication{
+			ApplicationSpec:    application,
+			ExpandedDashboards: router.expandDashboards(r.Context(), application),
+		})
+		return
+	}
+
 	render.JSON(w, r, application)
 }
 
 // Register returns a new router which can be used in the router for the kobs rest api.
-func Register(clusters *clusters.Clusters, plugins *plugin.Plugins, config Config) chi.Router {
+func Register(clusters *clusters.Clusters, plugins *plugin.Plugins, config Config, prometheusInstances []*instance.Instance) chi.Router {
 	plugins.Append(plugin.Plugin{
 		Name:        "applications",
 		DisplayName: "Applications",
@@ -237,16 +433,44 @@ func Register(clusters *clusters.Clusters, plugins *plugin.Plugins, config Confi
 		teams.CacheDuration = teamsCacheDuration
 	}
 
+	// The health rollup is only enabled when it is set in the configuration. By default the health status is cached for
+	// one minute, five applications are evaluated at the same time and a single health check must finish within ten
+	// seconds.
+	var healthCache *health.Cache
+	healthConcurrency := 5
+	healthTimeout := 10 * time.Second
+
+	if config.Health.Enabled {
+		healthCache = &health.Cache{CacheDuration: 1 * time.Minute}
+		if healthCacheDuration, err := time.ParseDuration(config.Health.CacheDuration); err == nil && healthCacheDuration > 0 {
+			healthCache.CacheDuration = healthCacheDuration
+		}
+
+		if config.Health.Concurrency > 0 {
+			healthConcurrency = config.Health.Concurrency
+		}
+
+		if parsedTimeout, err := time.ParseDuration(config.Health.Timeout); err == nil && parsedTimeout > 0 {
+			healthTimeout = parsedTimeout
+		}
+	}
+
 	router := Router{
 		chi.NewRouter(),
 		clusters,
 		config,
 		topology,
 		teams,
+		healthCache,
+		healthConcurrency,
+		healthTimeout,
+		prometheusInstances,
 	}
 
 	router.Get("/applications", router.getApplications)
 	router.Get("/application", router.getApplication)
+	router.Get("/tags", router.getTags)
+	router.Get("/health", router.getHealth)
 
 	return router
 }
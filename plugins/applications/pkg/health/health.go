@@ -0,0 +1,169 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	application "github.com/kobsio/kobs/pkg/api/apis/application/v1beta1"
+)
+
+// Status is the health status of an application.
+type Status string
+
+const (
+	// StatusHealthy is used for applications, where none of the health checks returned a result.
+	StatusHealthy Status = "healthy"
+	// StatusDegraded is used for applications, where at least one health check returned a result.
+	StatusDegraded Status = "degraded"
+	// StatusUnknown is used for applications without health checks or where a health check could not be evaluated.
+	StatusUnknown Status = "unknown"
+)
+
+// Querier is the interface, which must be implemented by a Prometheus instance to evaluate the health checks of an
+// application.
+type Querier interface {
+	HasResults(ctx context.Context, query string) (bool, error)
+}
+
+// Application is the health status of a single application.
+type Application struct {
+	Cluster   string `json:"cluster"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Status    Status `json:"status"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Rollup is the aggregated health status of a list of applications.
+type Rollup struct {
+	Healthy      int           `json:"healthy"`
+	Degraded     int           `json:"degraded"`
+	Unknown      int           `json:"unknown"`
+	Applications []Application `json:"applications"`
+}
+
+// Cache is the structure which can be used for caching the health status of all applications. In contrast to the
+// topology and teams cache the health status is only cached for a short time, so that we have to guard it with a mutex.
+type Cache struct {
+	mutex         sync.Mutex
+	LastFetch     time.Time
+	CacheDuration time.Duration
+	Applications  []Application
+}
+
+// Get returns the cached health status of all applications. If the cache is older than the configured cache duration
+// the health status is computed via the given get function and added to the cache before it is returned.
+func (c *Cache) Get(get func() ([]Application, error)) ([]Application, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.Applications != nil && c.LastFetch.After(time.Now().Add(-1*c.CacheDuration)) {
+		return c.Applications, nil
+	}
+
+	applications, err := get()
+	if err != nil {
+		return nil, err
+	}
+
+	c.LastFetch = time.Now()
+	c.Applications = applications
+
+	return applications, nil
+}
+
+// Evaluate returns the health status for all given applications. The health checks of the applications are evaluated
+// in parallel, but we never run more than the given number of applications at the same time, so that we do not
+// overload the Prometheus instances. Each health check must finish within the given timeout.
+func Evaluate(ctx context.Context, applications []application.ApplicationSpec, getQuerier func(name string) Querier, concurrency int, timeout time.Duration) []Application {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]Application, len(applications))
+	semaphore := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+	for i := range applications {
+		wg.Add(1)
+		semaphore <- struct{}{}
+
+		go func(i int) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+
+			status, err := evaluateApplication(ctx, applications[i].Health, getQuerier, timeout)
+			results[i] = Application{
+				Cluster:   applications[i].Cluster,
+				Namespace: applications[i].Namespace,
+				Name:      applications[i].Name,
+				Status:    status,
+			}
+
+			if err != nil {
+				results[i].Error = err.Error()
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+// evaluateApplication returns the health status for the given health checks. An application is degraded as soon as one
+// of the health checks returns a result. Applications without health checks or where a health check fails are unknown.
+func evaluateApplication(ctx context.Context, checks []application.HealthCheck, getQuerier func(name string) Querier, timeout time.Duration) (Status, error) {
+	if len(checks) == 0 {
+		return StatusUnknown, nil
+	}
+
+	for _, check := range checks {
+		querier := getQuerier(check.Prometheus)
+		if querier == nil {
+			return StatusUnknown, fmt.Errorf("prometheus instance \"%s\" not found", check.Prometheus)
+		}
+
+		checkCtx, cancel := context.WithTimeout(ctx, timeout)
+		degraded, err := querier.HasResults(checkCtx, check.Query)
+		cancel()
+		if err != nil {
+			return StatusUnknown, err
+		}
+
+		if degraded {
+			return StatusDegraded, nil
+		}
+	}
+
+	return StatusHealthy, nil
+}
+
+// Summarize returns the rollup for the given applications. If a cluster or namespace is provided only the applications
+// from this cluster or namespace are considered.
+func Summarize(applications []Application, cluster, namespace string) Rollup {
+	rollup := Rollup{Applications: []Application{}}
+
+	for _, app := range applications {
+		if (cluster != "" && app.Cluster != cluster) || (namespace != "" && app.Namespace != namespace) {
+			continue
+		}
+
+		switch app.Status {
+		case StatusHealthy:
+			rollup.Healthy = rollup.Healthy + 1
+		case StatusDegraded:
+			rollup.Degraded = rollup.Degraded + 1
+		default:
+			rollup.Unknown = rollup.Unknown + 1
+		}
+
+		rollup.Applications = append(rollup.Applications, app)
+	}
+
+	return rollup
+}
@@ -0,0 +1,53 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	application "github.com/kobsio/kobs/pkg/api/apis/application/v1beta1"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeQuerier map[string]error
+
+func (q fakeQuerier) HasResults(ctx context.Context, query string) (bool, error) {
+	if err := q[query]; err != nil {
+		return false, err
+	}
+
+	return query == "degraded", nil
+}
+
+func TestEvaluate(t *testing.T) {
+	getQuerier := func(name string) Querier {
+		if name == "missing" {
+			return nil
+		}
+
+		return fakeQuerier{"error": fmt.Errorf("query failed")}
+	}
+
+	applications := []application.ApplicationSpec{
+		{Name: "app1", Health: []application.HealthCheck{{Query: "healthy"}}},
+		{Name: "app2", Health: []application.HealthCheck{{Query: "healthy"}, {Query: "degraded"}}},
+		{Name: "app3"},
+		{Name: "app4", Health: []application.HealthCheck{{Query: "error"}}},
+		{Name: "app5", Health: []application.HealthCheck{{Prometheus: "missing", Query: "healthy"}}},
+	}
+
+	results := Evaluate(context.Background(), applications, getQuerier, 2, time.Second)
+	require.Equal(t, StatusHealthy, results[0].Status)
+	require.Equal(t, StatusDegraded, results[1].Status)
+	require.Equal(t, StatusUnknown, results[2].Status)
+	require.Equal(t, StatusUnknown, results[3].Status)
+	require.Equal(t, "query failed", results[3].Error)
+	require.Equal(t, StatusUnknown, results[4].Status)
+
+	rollup := Summarize(results, "", "")
+	require.Equal(t, 1, rollup.Healthy)
+	require.Equal(t, 1, rollup.Degraded)
+	require.Equal(t, 3, rollup.Unknown)
+}
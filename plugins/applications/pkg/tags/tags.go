@@ -0,0 +1,64 @@
+package tags
+
+import (
+	"sort"
+	"strings"
+
+	application "github.com/kobsio/kobs/pkg/api/apis/application/v1beta1"
+)
+
+// Filter returns all applications, which are matching the given tags. When the operator is "and" an application must
+// contain all of the given tags. For all other operators an application must contain at least one of the given tags.
+// The tags are compared case-insensitive. If no tags are provided, all applications are returned.
+func Filter(applications []application.ApplicationSpec, tags []string, operator string) []application.ApplicationSpec {
+	if len(tags) == 0 {
+		return applications
+	}
+
+	var filteredApplications []application.ApplicationSpec
+
+	for _, app := range applications {
+		matches := 0
+		for _, tag := range tags {
+			if contains(app.Tags, tag) {
+				matches = matches + 1
+			}
+		}
+
+		if (operator == "and" && matches == len(tags)) || (operator != "and" && matches > 0) {
+			filteredApplications = append(filteredApplications, app)
+		}
+	}
+
+	return filteredApplications
+}
+
+// Get returns a sorted list of all unique tags of the given applications, which can be used to build the filter in the
+// frontend. All tags are returned in lower case.
+func Get(applications []application.ApplicationSpec) []string {
+	uniqueTags := make(map[string]struct{})
+	for _, app := range applications {
+		for _, tag := range app.Tags {
+			uniqueTags[strings.ToLower(tag)] = struct{}{}
+		}
+	}
+
+	tags := []string{}
+	for tag := range uniqueTags {
+		tags = append(tags, tag)
+	}
+
+	sort.Strings(tags)
+	return tags
+}
+
+// contains checks if the given tag is part of the list of tags.
+func contains(tags []string, tag string) bool {
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+
+	return false
+}
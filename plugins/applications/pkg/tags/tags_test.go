@@ -0,0 +1,45 @@
+package tags
+
+import (
+	"testing"
+
+	application "github.com/kobsio/kobs/pkg/api/apis/application/v1beta1"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter(t *testing.T) {
+	applications := []application.ApplicationSpec{
+		{Name: "app1", Tags: []string{"frontend", "Go"}},
+		{Name: "app2", Tags: []string{"backend", "go"}},
+		{Name: "app3"},
+	}
+
+	for _, tc := range []struct {
+		name     string
+		tags     []string
+		operator string
+		expect   []string
+	}{
+		{name: "no tags", tags: nil, operator: "", expect: []string{"app1", "app2", "app3"}},
+		{name: "or", tags: []string{"frontend", "backend"}, operator: "or", expect: []string{"app1", "app2"}},
+		{name: "and", tags: []string{"frontend", "go"}, operator: "and", expect: []string{"app1"}},
+		{name: "no match", tags: []string{"database"}, operator: "", expect: nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var actual []string
+			for _, app := range Filter(applications, tc.tags, tc.operator) {
+				actual = append(actual, app.Name)
+			}
+
+			require.Equal(t, tc.expect, actual)
+		})
+	}
+}
+
+func TestGet(t *testing.T) {
+	require.Equal(t, []string{"backend", "frontend", "go"}, Get([]application.ApplicationSpec{
+		{Name: "app1", Tags: []string{"frontend", "Go"}},
+		{Name: "app2", Tags: []string{"backend", "go"}},
+	}))
+}
@@ -32,7 +32,7 @@ type Team struct {
 func Get(ctx context.Context, clusters *clusters.Clusters) []Team {
 	var cachedTeams []Team
 
-	for _, c := range clusters.Clusters {
+	for _, c := range clusters.GetClusters() {
 		teams, err := c.GetTeams(ctx, "")
 		if err != nil {
 			continue
@@ -47,7 +47,7 @@ func Get(ctx context.Context, clusters *clusters.Clusters) []Team {
 		}
 	}
 
-	for _, c := range clusters.Clusters {
+	for _, c := range clusters.GetClusters() {
 		applications, err := c.GetApplications(ctx, "")
 		if err != nil {
 			continue
@@ -66,7 +66,7 @@ func Get(ctx context.Context, clusters *clusters.Clusters) *Topology {
 	var edges []Edge
 	var nodes []Node
 
-	for _, c := range clusters.Clusters {
+	for _, c := range clusters.GetClusters() {
 		applications, err := c.GetApplications(ctx, "")
 		if err != nil {
 			continue
@@ -1,12 +1,17 @@
 package clickhouse
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/kobsio/kobs/pkg/api/clusters"
+	authContext "github.com/kobsio/kobs/pkg/api/middleware/auth/context"
 	"github.com/kobsio/kobs/pkg/api/middleware/errresponse"
 	"github.com/kobsio/kobs/pkg/api/plugins/plugin"
 	"github.com/kobsio/kobs/plugins/clickhouse/pkg/instance"
@@ -19,6 +24,13 @@ import (
 // Route is the route under which the plugin should be registered in our router for the rest api.
 const Route = "/clickhouse"
 
+// mergedLogsLimit is the maximum number of documents, which are fetched from each source and returned by the
+// getMergedLogs function.
+const mergedLogsLimit = 1000
+
+// testTimeout is the timeout for testing the connection to a ClickHouse instance.
+const testTimeout = 10 * time.Second
+
 var (
 	log = logrus.WithFields(logrus.Fields{"package": "clickhouse"})
 )
@@ -30,11 +42,93 @@ type Config []instance.Config
 type Router struct {
 	*chi.Mux
 	clusters  *clusters.Clusters
+	plugins   *plugin.Plugins
+	route     string
+	instances []*instance.Instance
+	mutex     sync.RWMutex
+}
+
+// getInstances returns the current ClickHouse instances. The instances can be replaced via a PendingReload, so that
+// the instances must always be accessed via this method.
+func (router *Router) getInstances() []*instance.Instance {
+	router.mutex.RLock()
+	defer router.mutex.RUnlock()
+
+	return router.instances
+}
+
+// PendingReload contains the new ClickHouse instances for a reload of the configuration, which are not used yet. It is
+// returned by the PrepareReload method and must be applied or discarded by the caller.
+type PendingReload struct {
+	router    *Router
+	config    Config
 	instances []*instance.Instance
 }
 
-func (router *Router) getInstance(name string) *instance.Instance {
-	for _, i := range router.instances {
+// PrepareReload creates new ClickHouse instances for the given configuration, so that changes of the configuration can
+// be applied without a restart of kobs. The new instances are only used, after the Apply method of the returned
+// PendingReload was called, so that the caller can validate the configuration of multiple plugins, before the
+// configuration of one plugin is applied. If the configuration is invalid, all created instances are closed and the
+// old instances are still used.
+//
+// Instances can not be added, removed or renamed, because the list of plugins and the Istio instances which are using a
+// ClickHouse instance are only created during the start of kobs. The metadata of the plugins (e.g. the display name or
+// the traceIDField option) is updated for the reloaded instances.
+func (router *Router) PrepareReload(config Config) (*PendingReload, error) {
+	if err := validateInstanceNames(router.getInstances(), config); err != nil {
+		return nil, err
+	}
+
+	pending := &PendingReload{router: router, config: config}
+	for _, cfg := range config {
+		i, err := instance.New(cfg)
+		if err != nil {
+			pending.Discard()
+			return nil, fmt.Errorf("could not create instance %s: %w", cfg.Name, err)
+		}
+
+		pending.instances = append(pending.instances, i)
+	}
+
+	return pending, nil
+}
+
+// Apply replaces the instances of the router with the new instances and updates the metadata of the plugins. The old
+// instances are closed afterwards; queries which are already running are finished before the connections of an old
+// instance are closed.
+func (p *PendingReload) Apply() {
+	p.router.mutex.Lock()
+	oldInstances := p.router.instances
+	p.router.instances = p.instances
+	for _, cfg := range p.config {
+		p.router.plugins.Update(getPlugin(cfg, p.router.route))
+	}
+	p.router.mutex.Unlock()
+
+	for _, i := range oldInstances {
+		if err := i.Close(); err != nil {
+			log.WithError(err).WithFields(logrus.Fields{"name": i.Name}).Warnf("Could not close ClickHouse instance")
+		}
+	}
+
+	log.WithFields(logrus.Fields{"instances": len(p.instances)}).Infof("Reloaded ClickHouse instances")
+}
+
+// Discard closes the new instances, when the reload should not be applied (e.g. because the configuration of another
+// plugin is invalid).
+func (p *PendingReload) Discard() {
+	for _, i := range p.instances {
+		if err := i.Close(); err != nil {
+			log.WithError(err).WithFields(logrus.Fields{"name": i.Name}).Warnf("Could not close ClickHouse instance")
+		}
+	}
+}
+
+// GetInstanceByName returns the current ClickHouse instance with the given name or nil, when no instance with this
+// name exists. It must be used by other plugins (e.g. the Istio plugin) instead of storing an instance, so that they
+// are also using the reloaded instances.
+func (router *Router) GetInstanceByName(name string) *instance.Instance {
+	for _, i := range router.getInstances() {
 		if i.Name == name {
 			return i
 		}
@@ -43,14 +137,83 @@ func (router *Router) getInstance(name string) *instance.Instance {
 	return nil
 }
 
+// validateInstanceNames checks that the given configuration contains exactly the same instances as the current
+// instances.
+func validateInstanceNames(instances []*instance.Instance, config Config) error {
+	if len(instances) != len(config) {
+		return fmt.Errorf("the number of instances can not be changed, expected %d instances, got %d", len(instances), len(config))
+	}
+
+	names := make(map[string]bool, len(instances))
+	for _, i := range instances {
+		names[i.Name] = true
+	}
+
+	for _, cfg := range config {
+		if !names[cfg.Name] {
+			return fmt.Errorf("instance %s does not exist, instances can not be added or renamed", cfg.Name)
+		}
+		delete(names, cfg.Name)
+	}
+
+	return nil
+}
+
+// getInstance returns the instance with the given name. An instance with exactly the given name is always preferred. If
+// no instance has the given name and the name is empty or "default", the instance which is mapped to the given
+// namespace is returned, so that different namespaces can use different ClickHouse instances. If no instance is mapped
+// to the namespace, the default instance is returned.
+func (router *Router) getInstance(name, namespace string) *instance.Instance {
+	instances := router.getInstances()
+	info := func(i int) (string, bool) {
+		return instances[i].Name, instances[i].Default
+	}
+
+	if index := plugin.GetInstanceIndexByName(name, len(instances), info); index != -1 {
+		return instances[index]
+	}
+
+	if !plugin.IsDefaultName(name) {
+		return nil
+	}
+
+	if i := getNamespaceInstance(instances, namespace); i != nil {
+		return i
+	}
+
+	if index := plugin.GetDefaultInstanceIndex(len(instances), info); index != -1 {
+		return instances[index]
+	}
+
+	return nil
+}
+
+// getNamespaceInstance returns the first instance, which contains the given namespace in its list of namespaces.
+func getNamespaceInstance(instances []*instance.Instance, namespace string) *instance.Instance {
+	if namespace == "" {
+		return nil
+	}
+
+	for _, i := range instances {
+		for _, n := range i.Namespaces {
+			if n == namespace {
+				return i
+			}
+		}
+	}
+
+	return nil
+}
+
 func (router *Router) getFields(w http.ResponseWriter, r *http.Request) {
 	name := chi.URLParam(r, "name")
+	namespace := r.URL.Query().Get("namespace")
 	filter := r.URL.Query().Get("filter")
 	fieldType := r.URL.Query().Get("fieldType")
 
-	log.WithFields(logrus.Fields{"name": name, "filter": filter, "fieldType": fieldType}).Tracef("getFields")
+	log.WithFields(logrus.Fields{"name": name, "namespace": namespace, "filter": filter, "fieldType": fieldType}).Tracef("getFields")
 
-	i := router.getInstance(name)
+	i := router.getInstance(name, namespace)
 	if i == nil {
 		errresponse.Render(w, r, nil, http.StatusBadRequest, "Could not find instance name")
 		return
@@ -66,15 +229,16 @@ func (router *Router) getFields(w http.ResponseWriter, r *http.Request) {
 // query language to get the logs from ClickHouse.
 func (router *Router) getLogs(w http.ResponseWriter, r *http.Request) {
 	name := chi.URLParam(r, "name")
+	namespace := r.URL.Query().Get("namespace")
 	query := r.URL.Query().Get("query")
 	order := r.URL.Query().Get("order")
 	orderBy := r.URL.Query().Get("orderBy")
 	timeStart := r.URL.Query().Get("timeStart")
 	timeEnd := r.URL.Query().Get("timeEnd")
 
-	log.WithFields(logrus.Fields{"name": name, "query": query, "order": order, "orderBy": orderBy, "timeStart": timeStart, "timeEnd": timeEnd}).Tracef("getLogs")
+	log.WithFields(logrus.Fields{"name": name, "namespace": namespace, "query": query, "order": order, "orderBy": orderBy, "timeStart": timeStart, "timeEnd": timeEnd}).Tracef("getLogs")
 
-	i := router.getInstance(name)
+	i := router.getInstance(name, namespace)
 	if i == nil {
 		errresponse.Render(w, r, nil, http.StatusBadRequest, "Could not find instance name")
 		return
@@ -132,30 +296,176 @@ func (router *Router) getLogs(w http.ResponseWriter, r *http.Request) {
 	}
 
 	data := struct {
-		Documents []map[string]interface{} `json:"documents"`
-		Fields    []string                 `json:"fields"`
-		Count     int64                    `json:"count"`
-		Took      int64                    `json:"took"`
-		Buckets   []instance.Bucket        `json:"buckets"`
+		Documents    []map[string]interface{} `json:"documents"`
+		Fields       []string                 `json:"fields"`
+		Count        int64                    `json:"count"`
+		Took         int64                    `json:"took"`
+		Buckets      []instance.Bucket        `json:"buckets"`
+		TraceIDField string                   `json:"traceIDField,omitempty"`
 	}{
 		documents,
 		fields,
 		count,
 		took,
 		buckets,
+		i.TraceIDField,
+	}
+
+	render.JSON(w, r, data)
+}
+
+// getErrorRate returns the number of all log lines and of the error log lines for the given query and time range. The
+// log lines are counted in buckets, so that the error rate can be shown as a chart in the React UI. The error log lines
+// can be selected via the errorQuery parameter, if it isn't provided the configured error query of the instance is used.
+func (router *Router) getErrorRate(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	namespace := r.URL.Query().Get("namespace")
+	query := r.URL.Query().Get("query")
+	errorQuery := r.URL.Query().Get("errorQuery")
+	timeStart := r.URL.Query().Get("timeStart")
+	timeEnd := r.URL.Query().Get("timeEnd")
+
+	log.WithFields(logrus.Fields{"name": name, "namespace": namespace, "query": query, "errorQuery": errorQuery, "timeStart": timeStart, "timeEnd": timeEnd}).Tracef("getErrorRate")
+
+	i := router.getInstance(name, namespace)
+	if i == nil {
+		errresponse.Render(w, r, nil, http.StatusBadRequest, "Could not find instance name")
+		return
+	}
+
+	parsedTimeStart, err := strconv.ParseInt(timeStart, 10, 64)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not parse start time")
+		return
+	}
+
+	parsedTimeEnd, err := strconv.ParseInt(timeEnd, 10, 64)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not parse end time")
+		return
+	}
+
+	errorRate, err := i.GetErrorRate(r.Context(), query, errorQuery, parsedTimeStart, parsedTimeEnd)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not get error rate")
+		return
+	}
+
+	log.WithFields(logrus.Fields{"count": errorRate.Count, "errors": errorRate.Errors}).Tracef("getErrorRate")
+	render.JSON(w, r, errorRate)
+}
+
+// getMergedLogs returns the logs for the given query from multiple sources (ClickHouse instances). The logs of all
+// sources are merged by their timestamp into a single timeline. To bound the memory usage, we only fetch the first
+// mergedLogsLimit documents from each source.
+func (router *Router) getMergedLogs(w http.ResponseWriter, r *http.Request) {
+	sources := r.URL.Query()["source"]
+	namespace := r.URL.Query().Get("namespace")
+	query := r.URL.Query().Get("query")
+	order := r.URL.Query().Get("order")
+	timeStart := r.URL.Query().Get("timeStart")
+	timeEnd := r.URL.Query().Get("timeEnd")
+
+	log.WithFields(logrus.Fields{"sources": sources, "namespace": namespace, "query": query, "order": order, "timeStart": timeStart, "timeEnd": timeEnd}).Tracef("getMergedLogs")
+
+	if len(sources) == 0 {
+		errresponse.Render(w, r, nil, http.StatusBadRequest, "At least one source is required")
+		return
+	}
+
+	var instances []*instance.Instance
+	for _, source := range sources {
+		i := router.getInstance(source, namespace)
+		if i == nil {
+			errresponse.Render(w, r, fmt.Errorf("source %s", source), http.StatusBadRequest, "Could not find instance name")
+			return
+		}
+
+		instances = append(instances, i)
+	}
+
+	parsedTimeStart, err := strconv.ParseInt(timeStart, 10, 64)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not parse start time")
+		return
+	}
+
+	parsedTimeEnd, err := strconv.ParseInt(timeEnd, 10, 64)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not parse end time")
+		return
+	}
+
+	results := make([]instance.LogsResult, len(instances))
+	errs := make([]error, len(instances))
+
+	var wg sync.WaitGroup
+	for index, i := range instances {
+		wg.Add(1)
+
+		go func(index int, i *instance.Instance) {
+			defer wg.Done()
+
+			documents, fields, count, took, buckets, err := i.GetLogs(r.Context(), query, order, "timestamp", mergedLogsLimit, parsedTimeStart, parsedTimeEnd)
+			results[index] = instance.LogsResult{Source: i.Name, Documents: documents, Fields: fields, Count: count, Took: took, Buckets: buckets}
+			errs[index] = err
+		}(index, i)
+	}
+
+	wg.Wait()
+
+	for index, err := range errs {
+		if err != nil {
+			errresponse.Render(w, r, fmt.Errorf("source %s: %w", instances[index].Name, err), http.StatusBadRequest, "Could not get logs")
+			return
+		}
+	}
+
+	merged := instance.MergeLogs(results, order == "ascending", mergedLogsLimit)
+
+	data := struct {
+		Documents []map[string]interface{} `json:"documents"`
+		Fields    []string                 `json:"fields"`
+		Count     int64                    `json:"count"`
+		Took      int64                    `json:"took"`
+		Buckets   []instance.Bucket        `json:"buckets"`
+	}{
+		merged.Documents,
+		merged.Fields,
+		merged.Count,
+		merged.Took,
+		merged.Buckets,
 	}
 
 	render.JSON(w, r, data)
 }
 
+// getDecodeError returns a more specific error for the errors returned while decoding a JSON request body. For type
+// errors the returned error contains the name of the field, for syntax errors the offset of the invalid character.
+func getDecodeError(err error) error {
+	var typeError *json.UnmarshalTypeError
+	if errors.As(err, &typeError) {
+		return fmt.Errorf("%s must be of type %s, got %s", typeError.Field, typeError.Type.String(), typeError.Value)
+	}
+
+	var syntaxError *json.SyntaxError
+	if errors.As(err, &syntaxError) {
+		return fmt.Errorf("invalid JSON at offset %d: %s", syntaxError.Offset, syntaxError.Error())
+	}
+
+	return err
+}
+
 // getAggregation returns the columns and rows for the user given aggregation request. The aggregation data must
-// provided in the body of the request and is the run against the specified Clichouse instance.
+// provided in the body of the request and is the run against the specified Clichouse instance. If the request contains
+// the histogram option, the response also contains the buckets for the selected time range.
 func (router *Router) getAggregation(w http.ResponseWriter, r *http.Request) {
 	name := chi.URLParam(r, "name")
+	namespace := r.URL.Query().Get("namespace")
 
-	log.WithFields(logrus.Fields{"name": name}).Tracef("getAggregation")
+	log.WithFields(logrus.Fields{"name": name, "namespace": namespace}).Tracef("getAggregation")
 
-	i := router.getInstance(name)
+	i := router.getInstance(name, namespace)
 	if i == nil {
 		errresponse.Render(w, r, nil, http.StatusBadRequest, "Could not find instance name")
 		return
@@ -165,7 +475,12 @@ func (router *Router) getAggregation(w http.ResponseWriter, r *http.Request) {
 
 	err := json.NewDecoder(r.Body).Decode(&aggregationData)
 	if err != nil {
-		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not decode request body")
+		errresponse.Render(w, r, getDecodeError(err), http.StatusBadRequest, "Could not decode request body")
+		return
+	}
+
+	if err := aggregationData.Validate(); err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid aggregation")
 		return
 	}
 
@@ -195,23 +510,92 @@ func (router *Router) getAggregation(w http.ResponseWriter, r *http.Request) {
 
 	rows, columns, err := i.GetAggregation(r.Context(), aggregationData)
 	if err != nil {
+		if r.Context().Err() != nil {
+			log.WithError(err).Debugf("Aggregation was canceled")
+			return
+		}
+
 		errresponse.Render(w, r, err, http.StatusBadRequest, "Error while running aggregation")
 		return
 	}
 
+	// When the user requested a histogram for the aggregation, we also return the distribution of the matching rows in
+	// the selected time range. The buckets can be rendered with the same chart as it is used for the logs.
+	var buckets []instance.Bucket
+	if aggregationData.Histogram != nil {
+		buckets, err = i.GetAggregationHistogram(r.Context(), aggregationData)
+		if err != nil {
+			errresponse.Render(w, r, err, http.StatusBadRequest, "Error while running aggregation")
+			return
+		}
+	}
+
 	data := struct {
 		Rows    []map[string]interface{} `json:"rows"`
 		Columns []string                 `json:"columns"`
+		Buckets []instance.Bucket        `json:"buckets,omitempty"`
 	}{
 		rows,
 		columns,
+		buckets,
 	}
 
 	render.JSON(w, r, data)
 }
 
-// Register returns a new router which can be used in the router for the kobs rest api.
-func Register(clusters *clusters.Clusters, plugins *plugin.Plugins, config Config) (chi.Router, []*instance.Instance) {
+// testInstance tests the connection to a ClickHouse instance with the configuration from the request body, without adding
+// the instance to the plugin. Since this can be used to send requests to any address, it can only be used by admins,
+// which are only available when authentication is enabled.
+func (router *Router) testInstance(w http.ResponseWriter, r *http.Request) {
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to test the instance")
+		return
+	}
+
+	log.WithFields(logrus.Fields{"user": user.ID}).Tracef("testInstance")
+
+	if !user.IsAdmin {
+		errresponse.Render(w, r, fmt.Errorf("user is not an admin"), http.StatusForbidden, "You are not allowed to test the instance")
+		return
+	}
+
+	var config instance.Config
+	if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not decode request body")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), testTimeout)
+	defer cancel()
+
+	if err := instance.Test(ctx, config); err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Connection test failed")
+		return
+	}
+
+	render.JSON(w, r, nil)
+}
+
+// getPlugin returns the plugin metadata for the ClickHouse instance with the given configuration. The route under which
+// the plugin is mounted is added to the options, so that the frontend uses the correct route for its requests.
+func getPlugin(cfg instance.Config, route string) plugin.Plugin {
+	return plugin.Plugin{
+		Name:        cfg.Name,
+		DisplayName: cfg.DisplayName,
+		Description: cfg.Description,
+		Type:        "clickhouse",
+		Options: map[string]interface{}{
+			"traceIDField": cfg.TraceIDField,
+			"route":        route,
+		},
+	}
+}
+
+// Register returns a new router which can be used in the router for the kobs rest api. The route is the route under
+// which the returned router is mounted. It is passed to the frontend, so that multiple ClickHouse plugin sets can be
+// mounted under different routes.
+func Register(clusters *clusters.Clusters, plugins *plugin.Plugins, config Config, route string) *Router {
 	var instances []*instance.Instance
 
 	for _, cfg := range config {
@@ -221,24 +605,23 @@ func Register(clusters *clusters.Clusters, plugins *plugin.Plugins, config Confi
 		}
 
 		instances = append(instances, instance)
-
-		plugins.Append(plugin.Plugin{
-			Name:        cfg.Name,
-			DisplayName: cfg.DisplayName,
-			Description: cfg.Description,
-			Type:        "clickhouse",
-		})
+		plugins.Append(getPlugin(cfg, route))
 	}
 
-	router := Router{
-		chi.NewRouter(),
-		clusters,
-		instances,
+	router := &Router{
+		Mux:       chi.NewRouter(),
+		clusters:  clusters,
+		plugins:   plugins,
+		route:     route,
+		instances: instances,
 	}
 
 	router.Get("/fields/{name}", router.getFields)
+	router.Get("/logs", router.getMergedLogs)
 	router.Get("/logs/{name}", router.getLogs)
+	router.Get("/errors/{name}", router.getErrorRate)
 	router.Post("/aggregation/{name}", router.getAggregation)
+	router.Post("/test", router.testInstance)
 
-	return router, instances
+	return router
 }
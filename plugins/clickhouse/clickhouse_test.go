@@ -0,0 +1,64 @@
+package clickhouse
+
+import (
+	"testing"
+
+	"github.com/kobsio/kobs/plugins/clickhouse/pkg/instance"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetInstance(t *testing.T) {
+	defaultInstance := &instance.Instance{Name: "default-instance", Default: true}
+	tenantInstance := &instance.Instance{Name: "tenant-instance", Namespaces: []string{"tenant-a", "tenant-b"}}
+
+	router := Router{instances: []*instance.Instance{defaultInstance, tenantInstance}}
+
+	for _, tt := range []struct {
+		name      string
+		namespace string
+		expected  *instance.Instance
+	}{
+		{name: "", namespace: "", expected: defaultInstance},
+		{name: "default", namespace: "tenant-a", expected: tenantInstance},
+		{name: "", namespace: "tenant-b", expected: tenantInstance},
+		{name: "default", namespace: "other", expected: defaultInstance},
+		{name: "default-instance", namespace: "tenant-a", expected: defaultInstance},
+		{name: "unknown", namespace: "tenant-a", expected: nil},
+	} {
+		t.Run(tt.name+"/"+tt.namespace, func(t *testing.T) {
+			require.Equal(t, tt.expected, router.getInstance(tt.name, tt.namespace))
+		})
+	}
+
+	t.Run("no default instance", func(t *testing.T) {
+		firstInstance := &instance.Instance{Name: "first-instance"}
+		router := Router{instances: []*instance.Instance{firstInstance, {Name: "second-instance"}}}
+		require.Equal(t, firstInstance, router.getInstance("default", ""))
+	})
+
+	t.Run("instance named default", func(t *testing.T) {
+		namedInstance := &instance.Instance{Name: "default"}
+		router := Router{instances: []*instance.Instance{defaultInstance, namedInstance}}
+		require.Equal(t, namedInstance, router.getInstance("default", ""))
+	})
+}
+
+func TestValidateInstanceNames(t *testing.T) {
+	instances := []*instance.Instance{{Name: "clickhouse-a"}, {Name: "clickhouse-b"}}
+
+	require.NoError(t, validateInstanceNames(instances, Config{{Name: "clickhouse-b"}, {Name: "clickhouse-a"}}))
+	require.Error(t, validateInstanceNames(instances, Config{{Name: "clickhouse-a"}}))
+	require.Error(t, validateInstanceNames(instances, Config{{Name: "clickhouse-a"}, {Name: "clickhouse-c"}}))
+	require.Error(t, validateInstanceNames(instances, Config{{Name: "clickhouse-a"}, {Name: "clickhouse-a"}}))
+}
+
+func TestPrepareReload(t *testing.T) {
+	instances := []*instance.Instance{{Name: "clickhouse-a"}}
+	router := Router{instances: instances}
+
+	pendingReload, err := router.PrepareReload(Config{{Name: "clickhouse-b"}})
+	require.Error(t, err)
+	require.Nil(t, pendingReload)
+	require.Equal(t, instances, router.getInstances())
+}
@@ -2,19 +2,32 @@ package instance
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"math"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/ClickHouse/clickhouse-go"
 	"github.com/sirupsen/logrus"
 )
 
-// Aggregation is the structure of the data, which is required to run an aggregation.
+// Aggregation is the structure of the data, which is required to run an aggregation. If the histogram field is set, the
+// distribution of the rows matching the query over the selected time range is also returned.
 type Aggregation struct {
-	Query   string             `json:"query"`
-	Chart   string             `json:"chart"`
-	Times   AggregationTimes   `json:"times"`
-	Options AggregationOptions `json:"options"`
+	Query     string                `json:"query"`
+	Chart     string                `json:"chart"`
+	Times     AggregationTimes      `json:"times"`
+	Options   AggregationOptions    `json:"options"`
+	Histogram *AggregationHistogram `json:"histogram,omitempty"`
+}
+
+// AggregationHistogram is the structure, which defines the interval (in seconds) for the histogram of an aggregation.
+// If the interval is 0, it is computed from the selected time range.
+type AggregationHistogram struct {
+	Interval int64 `json:"interval"`
 }
 
 // AggregationOptions is the structure of the options for an aggregation. It contains all the fields, which are required
@@ -43,6 +56,91 @@ type AggregationTimes struct {
 	TimeStart int64 `json:"timeStart"`
 }
 
+// ValidationError is returned by the Validate method of an aggregation. It contains the JSON path of the invalid field
+// and a message, which describes why the field is invalid.
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s %s", e.Field, e.Message)
+}
+
+// aggregationOperations is a list of all operations, which can be used for the size by and vertical axis operation.
+var aggregationOperations = []string{"count", "min", "max", "sum", "avg"}
+
+// Validate checks the aggregation for missing required fields and invalid values. It returns a ValidationError, which
+// names the first invalid field, so that the user knows how to fix the aggregation.
+func (a Aggregation) Validate() error {
+	if a.Times.TimeStart <= 0 {
+		return &ValidationError{"times.timeStart", "is required"}
+	}
+
+	if a.Times.TimeEnd <= a.Times.TimeStart {
+		return &ValidationError{"times.timeEnd", "must be greater than times.timeStart"}
+	}
+
+	if a.Histogram != nil && a.Histogram.Interval < 0 {
+		return &ValidationError{"histogram.interval", "must not be negative"}
+	}
+
+	switch a.Chart {
+	case "":
+		return &ValidationError{"chart", "is required"}
+	case "pie":
+		if a.Options.SliceBy == "" {
+			return &ValidationError{"options.sliceBy", "is required"}
+		}
+
+		if !contains(aggregationOperations, a.Options.SizeByOperation) {
+			return &ValidationError{"options.sizeByOperation", fmt.Sprintf("must be one of %s", strings.Join(aggregationOperations, ", "))}
+		}
+
+		if a.Options.SizeByOperation != "count" && a.Options.SizeByField == "" {
+			return &ValidationError{"options.sizeByField", fmt.Sprintf("is required for the %s operation", a.Options.SizeByOperation)}
+		}
+
+		return nil
+	case "bar", "line", "area":
+		if a.Options.HorizontalAxisOperation == "top" {
+			if a.Chart != "bar" {
+				return &ValidationError{"options.horizontalAxisOperation", fmt.Sprintf("top is only allowed for bar charts, not for %s charts", a.Chart)}
+			}
+
+			if a.Options.HorizontalAxisField == "" {
+				return &ValidationError{"options.horizontalAxisField", "is required for the top operation"}
+			}
+
+			if limit := strings.TrimSpace(a.Options.HorizontalAxisLimit); limit != "" {
+				if _, err := strconv.ParseUint(limit, 10, 64); err != nil {
+					return &ValidationError{"options.horizontalAxisLimit", "must be a positive number"}
+				}
+			}
+		} else if a.Options.HorizontalAxisOperation != "time" {
+			return &ValidationError{"options.horizontalAxisOperation", "must be one of time, top"}
+		}
+
+		if !contains(aggregationOperations, a.Options.VerticalAxisOperation) {
+			return &ValidationError{"options.verticalAxisOperation", fmt.Sprintf("must be one of %s", strings.Join(aggregationOperations, ", "))}
+		}
+
+		if a.Options.VerticalAxisOperation != "count" && a.Options.VerticalAxisField == "" {
+			return &ValidationError{"options.verticalAxisField", fmt.Sprintf("is required for the %s operation", a.Options.VerticalAxisOperation)}
+		}
+
+		for index, filter := range a.Options.BreakDownByFilters {
+			if strings.TrimSpace(filter) == "" {
+				return &ValidationError{fmt.Sprintf("options.breakDownByFilters[%d]", index), "must not be empty"}
+			}
+		}
+
+		return nil
+	default:
+		return &ValidationError{"chart", "must be one of pie, bar, line, area"}
+	}
+}
+
 // generateFieldName generates the field name for an aggregation. For that we are using the user defined field and we
 // are checking if this field is a default field or a materialized column. If this is the case we can directly use the
 // field name. If it is a custom field, we check against the array of the loaded fields to check if it is a string or
@@ -79,7 +177,7 @@ func getOrderBy(order string) string {
 // buildAggregationQuery is our helper function to build the different parts of the SQL statement for the user defined
 // chart and aggregation. The function returns the SELECT, GROUP BY, ORDER BY and LIMIT statement for the SQL query, to
 // get the results of the aggregation.
-func buildAggregationQuery(chart string, options AggregationOptions, materializedColumns []string, customFields Fields, timeStart, timeEnd int64) (string, string, string, string, error) {
+func buildAggregationQuery(chart string, options AggregationOptions, materializedColumns []string, traceIDField string, customFields Fields, timeStart, timeEnd int64) (string, string, string, string, error) {
 	var selectStatement, groupByStatement, orderByStatement, limitByStatement string
 
 	if chart != "pie" && chart != "bar" && chart != "line" && chart != "area" {
@@ -157,7 +255,7 @@ func buildAggregationQuery(chart string, options AggregationOptions, materialize
 
 			var breakDownByFilters []string
 			for _, breakDownByFilter := range options.BreakDownByFilters {
-				f, err := parseLogsQuery(breakDownByFilter, materializedColumns)
+				f, err := parseLogsQuery(breakDownByFilter, materializedColumns, traceIDField)
 				if err != nil {
 					return "", "", "", "", fmt.Errorf("invalid break down filter")
 				}
@@ -206,7 +304,7 @@ func buildAggregationQuery(chart string, options AggregationOptions, materialize
 
 		var breakDownByFilters []string
 		for _, breakDownByFilter := range options.BreakDownByFilters {
-			f, err := parseLogsQuery(breakDownByFilter, materializedColumns)
+			f, err := parseLogsQuery(breakDownByFilter, materializedColumns, traceIDField)
 			if err != nil {
 				return "", "", "", "", fmt.Errorf("invalid break down filter")
 			}
@@ -268,6 +366,37 @@ func buildAggregationQuery(chart string, options AggregationOptions, materialize
 	return "", "", "", "", fmt.Errorf("invalid aggregation")
 }
 
+// watchQuery adds a unique query id to the given context. When the context is canceled before the returned finish
+// function is called, we kill the query with this id on the ClickHouse server. This ensures that long running queries
+// are not orphaned, when the client goes away.
+func (i *Instance) watchQuery(ctx context.Context) (context.Context, func()) {
+	queryID := newQueryID()
+	done := make(chan struct{})
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			killCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			log.WithFields(logrus.Fields{"queryID": queryID}).Debugf("Kill canceled query")
+			if _, err := i.client.ExecContext(killCtx, fmt.Sprintf("KILL QUERY WHERE query_id = '%s' ASYNC", queryID)); err != nil {
+				log.WithError(err).WithFields(logrus.Fields{"queryID": queryID}).Warnf("Could not kill query")
+			}
+		case <-done:
+		}
+	}()
+
+	return clickhouse.WithQueryID(ctx, queryID), func() { close(done) }
+}
+
+// newQueryID returns a random id, which can be used as query id for ClickHouse.
+func newQueryID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
 // GetAggregation returns the data for the given aggregation. To get the data we have to build the aggregation query.
 // Then we can reuse the parseLogsQuery function from getting the logs, to build the WHERE statement. Finally we are
 // running the query and parsing all rows into a map with the column names as keys and the value of each row.
@@ -277,7 +406,7 @@ func (i *Instance) GetAggregation(ctx context.Context, aggregation Aggregation)
 	// Build the SELECT, GROUP BY, ORDER BY and LIMIT statement for the SQL query. When the function returns an error
 	// the user provided an invalid aggregation. If the function doesn't return a ORDER BY or LIMIT statement we can
 	// also omit it in the SQL query.
-	selectStatement, groupByStatement, orderByStatement, limitByStatement, err := buildAggregationQuery(aggregation.Chart, aggregation.Options, i.materializedColumns, i.cachedFields, aggregation.Times.TimeStart, aggregation.Times.TimeEnd)
+	selectStatement, groupByStatement, orderByStatement, limitByStatement, err := buildAggregationQuery(aggregation.Chart, aggregation.Options, i.materializedColumns, i.TraceIDField, i.cachedFields, aggregation.Times.TimeStart, aggregation.Times.TimeEnd)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -294,7 +423,7 @@ func (i *Instance) GetAggregation(ctx context.Context, aggregation Aggregation)
 	// syntax to filter down the aggregation results.
 	conditions := ""
 	if aggregation.Query != "" {
-		parsedQuery, err := parseLogsQuery(aggregation.Query, i.materializedColumns)
+		parsedQuery, err := parseLogsQuery(aggregation.Query, i.materializedColumns, i.TraceIDField)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -307,7 +436,12 @@ func (i *Instance) GetAggregation(ctx context.Context, aggregation Aggregation)
 	query := fmt.Sprintf("SELECT %s FROM %s.logs WHERE timestamp >= FROM_UNIXTIME(%d) AND timestamp <= FROM_UNIXTIME(%d) %s GROUP BY %s %s %s SETTINGS skip_unavailable_shards = 1", selectStatement, i.database, aggregation.Times.TimeStart, aggregation.Times.TimeEnd, conditions, groupByStatement, orderByStatement, limitByStatement)
 	log.WithFields(logrus.Fields{"query": query}).Tracef("aggregation query")
 
-	rows, err := i.client.QueryContext(ctx, query)
+	// The query is canceled, when the context is canceled (e.g. the client disconnects). To ensure that the query is
+	// also stopped on the ClickHouse server, we are watching the query, so that it can be killed.
+	queryCtx, finish := i.watchQuery(ctx)
+	defer finish()
+
+	rows, err := i.client.QueryContext(queryCtx, query)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -355,3 +489,29 @@ func (i *Instance) GetAggregation(ctx context.Context, aggregation Aggregation)
 
 	return result, columns, nil
 }
+
+// GetAggregationHistogram returns the number of rows matching the query of the aggregation for each interval in the
+// selected time range. The returned buckets have the same format as the buckets returned for logs, so that they can be
+// rendered by the same chart component.
+func (i *Instance) GetAggregationHistogram(ctx context.Context, aggregation Aggregation) ([]Bucket, error) {
+	if aggregation.Times.TimeEnd-aggregation.Times.TimeStart <= 0 {
+		return nil, fmt.Errorf("invalid time range")
+	}
+
+	interval := getInterval(aggregation.Times.TimeStart, aggregation.Times.TimeEnd)
+	if aggregation.Histogram != nil && aggregation.Histogram.Interval > 0 {
+		interval = aggregation.Histogram.Interval
+	}
+
+	conditions := ""
+	if aggregation.Query != "" {
+		parsedQuery, err := parseLogsQuery(aggregation.Query, i.materializedColumns, i.TraceIDField)
+		if err != nil {
+			return nil, err
+		}
+
+		conditions = fmt.Sprintf("AND %s", parsedQuery)
+	}
+
+	return i.getBuckets(ctx, conditions, aggregation.Times.TimeStart, aggregation.Times.TimeEnd, interval)
+}
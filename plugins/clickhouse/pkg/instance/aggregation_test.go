@@ -1,7 +1,12 @@
 package instance
 
 import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -40,3 +45,107 @@ func TestGetOrderBy(t *testing.T) {
 		})
 	}
 }
+
+func TestAggregationValidate(t *testing.T) {
+	times := AggregationTimes{TimeStart: 1640000000, TimeEnd: 1640000900}
+
+	for _, tc := range []struct {
+		name        string
+		aggregation Aggregation
+		expectField string
+	}{
+		{name: "valid pie chart", aggregation: Aggregation{Chart: "pie", Times: times, Options: AggregationOptions{SliceBy: "namespace", SizeByOperation: "count"}}},
+		{name: "valid time chart", aggregation: Aggregation{Chart: "line", Times: times, Options: AggregationOptions{HorizontalAxisOperation: "time", VerticalAxisOperation: "avg", VerticalAxisField: "content.duration"}}},
+		{name: "missing chart", aggregation: Aggregation{Times: times}, expectField: "chart"},
+		{name: "invalid chart", aggregation: Aggregation{Chart: "table", Times: times}, expectField: "chart"},
+		{name: "invalid times", aggregation: Aggregation{Chart: "pie", Times: AggregationTimes{TimeStart: 1640000900, TimeEnd: 1640000000}}, expectField: "times.timeEnd"},
+		{name: "missing slice by", aggregation: Aggregation{Chart: "pie", Times: times}, expectField: "options.sliceBy"},
+		{name: "invalid size by operation", aggregation: Aggregation{Chart: "pie", Times: times, Options: AggregationOptions{SliceBy: "namespace", SizeByOperation: "median"}}, expectField: "options.sizeByOperation"},
+		{name: "top for line chart", aggregation: Aggregation{Chart: "line", Times: times, Options: AggregationOptions{HorizontalAxisOperation: "top"}}, expectField: "options.horizontalAxisOperation"},
+		{name: "invalid limit", aggregation: Aggregation{Chart: "bar", Times: times, Options: AggregationOptions{HorizontalAxisOperation: "top", HorizontalAxisField: "namespace", HorizontalAxisLimit: "10; DROP"}}, expectField: "options.horizontalAxisLimit"},
+		{name: "missing vertical axis field", aggregation: Aggregation{Chart: "area", Times: times, Options: AggregationOptions{HorizontalAxisOperation: "time", VerticalAxisOperation: "sum"}}, expectField: "options.verticalAxisField"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.aggregation.Validate()
+			if tc.expectField == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			require.Equal(t, tc.expectField, err.(*ValidationError).Field)
+		})
+	}
+}
+
+// blockingDriver is a database driver, which blocks all queries until the context is canceled. Executed statements are
+// sent to the statements channel, so that we can check if a canceled query is killed. The driver also implements the
+// driver.Connector interface, so that it can be used via sql.OpenDB without registering it globally.
+type blockingDriver struct {
+	statements chan string
+}
+
+func (d *blockingDriver) Open(name string) (driver.Conn, error) {
+	return &blockingConn{statements: d.statements}, nil
+}
+
+func (d *blockingDriver) Connect(ctx context.Context) (driver.Conn, error) {
+	return d.Open("")
+}
+
+func (d *blockingDriver) Driver() driver.Driver {
+	return d
+}
+
+type blockingConn struct {
+	statements chan string
+}
+
+func (c *blockingConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, driver.ErrSkip
+}
+
+func (c *blockingConn) Close() error {
+	return nil
+}
+
+func (c *blockingConn) Begin() (driver.Tx, error) {
+	return nil, driver.ErrSkip
+}
+
+func (c *blockingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (c *blockingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.statements <- query
+	return driver.RowsAffected(0), nil
+}
+
+func TestGetAggregationCanceled(t *testing.T) {
+	statements := make(chan string, 1)
+	client := sql.OpenDB(&blockingDriver{statements: statements})
+	defer client.Close()
+
+	i := &Instance{Name: "clickhouse", database: "logs", client: client}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, _, err := i.GetAggregation(ctx, Aggregation{
+		Chart:   "pie",
+		Times:   AggregationTimes{TimeStart: 1640000000, TimeEnd: 1640000900},
+		Options: AggregationOptions{SliceBy: "namespace", SizeByOperation: "count"},
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	require.Less(t, time.Since(start), time.Second)
+
+	select {
+	case statement := <-statements:
+		require.True(t, strings.HasPrefix(statement, "KILL QUERY WHERE query_id = "))
+	case <-time.After(time.Second):
+		t.Fatal("canceled query was not killed")
+	}
+}
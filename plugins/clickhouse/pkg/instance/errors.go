@@ -0,0 +1,117 @@
+package instance
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// defaultErrorQuery is the query, which is used to select the error log lines, when no error query is configured for
+// an instance and the user didn't provide an error query.
+const defaultErrorQuery = "content.level=~'error'"
+
+// ErrorRate is the structure of the response for an error rate request. It contains the number of all log lines and
+// the number of error log lines for the selected time range and for each bucket within the time range.
+type ErrorRate struct {
+	Count   int64         `json:"count"`
+	Errors  int64         `json:"errors"`
+	Buckets []ErrorBucket `json:"buckets"`
+}
+
+// ErrorBucket contains the number of all log lines and the number of error log lines for a single interval.
+type ErrorBucket struct {
+	Interval int64 `json:"interval"`
+	Count    int64 `json:"count"`
+	Errors   int64 `json:"errors"`
+}
+
+// GetErrorRate returns the number of log lines and error log lines for the given query and time range. The log lines
+// are counted in the same buckets as they are used for the distribution chart of the GetLogs function. The error log
+// lines are selected via the given error query, if it is empty the error query of the instance is used.
+func (i *Instance) GetErrorRate(ctx context.Context, query, errorQuery string, timeStart, timeEnd int64) (*ErrorRate, error) {
+	if timeEnd-timeStart <= 0 {
+		return nil, fmt.Errorf("invalid time range")
+	}
+
+	conditions := ""
+	if query != "" {
+		parsedQuery, err := parseLogsQuery(query, i.materializedColumns, i.TraceIDField)
+		if err != nil {
+			return nil, err
+		}
+
+		conditions = fmt.Sprintf("AND %s", parsedQuery)
+	}
+
+	if errorQuery == "" {
+		errorQuery = i.ErrorQuery
+	}
+
+	parsedErrorQuery, err := parseLogsQuery(errorQuery, i.materializedColumns, i.TraceIDField)
+	if err != nil {
+		return nil, err
+	}
+
+	errorConditions := fmt.Sprintf("%s AND (%s)", conditions, parsedErrorQuery)
+	interval := getInterval(timeStart, timeEnd)
+
+	var buckets, errorBuckets []Bucket
+	var bucketsErr, errorBucketsErr error
+
+	parallelize(
+		func() {
+			buckets, bucketsErr = i.getBuckets(ctx, conditions, timeStart, timeEnd, interval)
+		},
+		func() {
+			errorBuckets, errorBucketsErr = i.getBuckets(ctx, errorConditions, timeStart, timeEnd, interval)
+		},
+	)
+
+	if bucketsErr != nil {
+		return nil, bucketsErr
+	}
+
+	if errorBucketsErr != nil {
+		return nil, errorBucketsErr
+	}
+
+	return mergeErrorBuckets(buckets, errorBuckets), nil
+}
+
+// mergeErrorBuckets merges the buckets for all log lines with the buckets for the error log lines by their interval.
+// Since both queries are using the same time range and interval, the intervals of both slices are normally the same,
+// but we do not rely on this, so that an error bucket without a corresponding bucket is also returned. For such a
+// bucket the number of error log lines is also used as the number of all log lines.
+func mergeErrorBuckets(buckets, errorBuckets []Bucket) *ErrorRate {
+	errorRate := &ErrorRate{Buckets: []ErrorBucket{}}
+	errors := make(map[int64]int64, len(errorBuckets))
+
+	for _, bucket := range errorBuckets {
+		errors[bucket.Interval] = errors[bucket.Interval] + bucket.Count
+		errorRate.Errors = errorRate.Errors + bucket.Count
+	}
+
+	for _, bucket := range buckets {
+		errorRate.Count = errorRate.Count + bucket.Count
+		errorRate.Buckets = append(errorRate.Buckets, ErrorBucket{
+			Interval: bucket.Interval,
+			Count:    bucket.Count,
+			Errors:   errors[bucket.Interval],
+		})
+		delete(errors, bucket.Interval)
+	}
+
+	for _, bucket := range errorBuckets {
+		if count, ok := errors[bucket.Interval]; ok {
+			errorRate.Count = errorRate.Count + count
+			errorRate.Buckets = append(errorRate.Buckets, ErrorBucket{Interval: bucket.Interval, Count: count, Errors: count})
+			delete(errors, bucket.Interval)
+		}
+	}
+
+	sort.Slice(errorRate.Buckets, func(i, j int) bool {
+		return errorRate.Buckets[i].Interval < errorRate.Buckets[j].Interval
+	})
+
+	return errorRate
+}
@@ -0,0 +1,41 @@
+package instance
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeErrorBuckets(t *testing.T) {
+	t.Run("same intervals", func(t *testing.T) {
+		actual := mergeErrorBuckets([]Bucket{{Interval: 0, Count: 10}, {Interval: 60, Count: 5}, {Interval: 120, Count: 0}}, []Bucket{{Interval: 0, Count: 2}, {Interval: 60, Count: 0}, {Interval: 120, Count: 0}})
+		require.Equal(t, &ErrorRate{
+			Count:   15,
+			Errors:  2,
+			Buckets: []ErrorBucket{{Interval: 0, Count: 10, Errors: 2}, {Interval: 60, Count: 5, Errors: 0}, {Interval: 120, Count: 0, Errors: 0}},
+		}, actual)
+	})
+
+	t.Run("missing intervals", func(t *testing.T) {
+		actual := mergeErrorBuckets([]Bucket{{Interval: 60, Count: 5}}, []Bucket{{Interval: 0, Count: 1}, {Interval: 60, Count: 3}})
+		require.Equal(t, &ErrorRate{
+			Count:   6,
+			Errors:  4,
+			Buckets: []ErrorBucket{{Interval: 0, Count: 1, Errors: 1}, {Interval: 60, Count: 5, Errors: 3}},
+		}, actual)
+	})
+
+	t.Run("no buckets", func(t *testing.T) {
+		require.Equal(t, &ErrorRate{Buckets: []ErrorBucket{}}, mergeErrorBuckets(nil, nil))
+	})
+}
+
+func TestGetErrorRate(t *testing.T) {
+	i := &Instance{ErrorQuery: defaultErrorQuery}
+
+	t.Run("invalid time range", func(t *testing.T) {
+		_, err := i.GetErrorRate(context.Background(), "", "", 10, 10)
+		require.Error(t, err)
+	})
+}
@@ -2,13 +2,17 @@ package instance
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"database/sql"
 	"fmt"
+	"net/url"
+	"os"
 	"sort"
 	"strings"
 	"time"
 
-	_ "github.com/ClickHouse/clickhouse-go"
+	"github.com/ClickHouse/clickhouse-go"
 	"github.com/sirupsen/logrus"
 )
 
@@ -18,25 +22,44 @@ var (
 
 // Config is the structure of the configuration for a single ClickHouse instance.
 type Config struct {
-	Name                string   `json:"name"`
-	DisplayName         string   `json:"displayName"`
-	Description         string   `json:"description"`
-	Address             string   `json:"address"`
-	Database            string   `json:"database"`
-	Username            string   `json:"username"`
-	Password            string   `json:"password"`
-	WriteTimeout        string   `json:"writeTimeout"`
-	ReadTimeout         string   `json:"readTimeout"`
-	MaterializedColumns []string `json:"materializedColumns"`
+	Name                string    `json:"name"`
+	DisplayName         string    `json:"displayName"`
+	Default             bool      `json:"default"`
+	Description         string    `json:"description"`
+	Address             string    `json:"address"`
+	Database            string    `json:"database"`
+	Username            string    `json:"username"`
+	Password            string    `json:"password"`
+	WriteTimeout        string    `json:"writeTimeout"`
+	ReadTimeout         string    `json:"readTimeout"`
+	MaterializedColumns []string  `json:"materializedColumns"`
+	Namespaces          []string  `json:"namespaces"`
+	TLS                 TLSConfig `json:"tls"`
+	TraceIDField        string    `json:"traceIDField"`
+	ErrorQuery          string    `json:"errorQuery"`
+}
+
+// TLSConfig is the structure of the TLS configuration for a ClickHouse instance. When TLS is enabled, the connection to
+// ClickHouse is encrypted. The certificate of the server is verified against the system CAs or the CA from the
+// provided file, unless insecureSkipVerify is set.
+type TLSConfig struct {
+	Enabled            bool   `json:"enabled"`
+	CAFile             string `json:"caFile"`
+	InsecureSkipVerify bool   `json:"insecureSkipVerify"`
 }
 
 // Instance represents a single ClickHouse instance, which can be added via the configuration file.
 type Instance struct {
 	Name                string
+	Default             bool
+	Namespaces          []string
+	TraceIDField        string
+	ErrorQuery          string
 	database            string
 	client              *sql.DB
 	materializedColumns []string
 	cachedFields        Fields
+	done                chan struct{}
 }
 
 func (i *Instance) getFields(ctx context.Context) (Fields, error) {
@@ -91,6 +114,8 @@ func (i *Instance) refreshCachedFields() []string {
 
 	for {
 		select {
+		case <-i.done:
+			return nil
 		case <-ticker.C:
 			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
 			defer cancel()
@@ -138,11 +163,64 @@ func (i *Instance) GetFields(filter string, fieldType string) []string {
 	return fields
 }
 
+// getInterval returns the interval in seconds for the buckets of the given time range. By default we are creating 30
+// buckets, but for time ranges with less then 30 seconds we have to create less buckets.
+func getInterval(timeStart, timeEnd int64) int64 {
+	switch seconds := timeEnd - timeStart; {
+	case seconds <= 2:
+		return (timeEnd - timeStart) / 1
+	case seconds <= 10:
+		return (timeEnd - timeStart) / 5
+	case seconds <= 30:
+		return (timeEnd - timeStart) / 10
+	default:
+		return (timeEnd - timeStart) / 30
+	}
+}
+
+// getBuckets returns the number of rows for each interval in the given time range. The conditions are added to the
+// WHERE statement of the SQL query and must start with "AND". Intervals without any rows are also returned, so that
+// the buckets can directly be used to render a chart.
+func (i *Instance) getBuckets(ctx context.Context, conditions string, timeStart, timeEnd, interval int64) ([]Bucket, error) {
+	var buckets []Bucket
+
+	sqlQueryBuckets := fmt.Sprintf(`SELECT toStartOfInterval(timestamp, INTERVAL %d second) AS interval_data , count(*) AS count_data FROM %s.logs WHERE timestamp >= FROM_UNIXTIME(%d) AND timestamp <= FROM_UNIXTIME(%d) %s GROUP BY interval_data ORDER BY interval_data WITH FILL FROM toStartOfInterval(FROM_UNIXTIME(%d), INTERVAL %d second) TO toStartOfInterval(FROM_UNIXTIME(%d), INTERVAL %d second) STEP %d SETTINGS skip_unavailable_shards = 1`, interval, i.database, timeStart, timeEnd, conditions, timeStart, interval, timeEnd, interval, interval)
+	log.WithFields(logrus.Fields{"query": sqlQueryBuckets}).Tracef("sql query buckets")
+	rowsBuckets, err := i.client.QueryContext(ctx, sqlQueryBuckets)
+	if err != nil {
+		return nil, err
+	}
+	defer rowsBuckets.Close()
+
+	for rowsBuckets.Next() {
+		var intervalData time.Time
+		var countData int64
+
+		if err := rowsBuckets.Scan(&intervalData, &countData); err != nil {
+			return nil, err
+		}
+
+		buckets = append(buckets, Bucket{
+			Interval: intervalData.Unix(),
+			Count:    countData,
+		})
+	}
+
+	if err := rowsBuckets.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(buckets, func(i, j int) bool {
+		return buckets[i].Interval < buckets[j].Interval
+	})
+
+	return buckets, nil
+}
+
 // GetLogs parses the given query into the sql syntax, which is then run against the ClickHouse instance. The returned
 // rows are converted into a document schema which can be used by our UI.
 func (i *Instance) GetLogs(ctx context.Context, query, order, orderBy string, limit, timeStart, timeEnd int64) ([]map[string]interface{}, []string, int64, int64, []Bucket, error) {
 	var count int64
-	var buckets []Bucket
 	var documents []map[string]interface{}
 	var timeConditions string
 	var interval int64
@@ -155,7 +233,7 @@ func (i *Instance) GetLogs(ctx context.Context, query, order, orderBy string, li
 	// where statement. These conditions are the added as additional AND to our sql query.
 	conditions := ""
 	if query != "" {
-		parsedQuery, err := parseLogsQuery(query, i.materializedColumns)
+		parsedQuery, err := parseLogsQuery(query, i.materializedColumns, i.TraceIDField)
 		if err != nil {
 			return nil, nil, 0, 0, nil, err
 		}
@@ -163,7 +241,7 @@ func (i *Instance) GetLogs(ctx context.Context, query, order, orderBy string, li
 		conditions = fmt.Sprintf("AND %s", parsedQuery)
 	}
 
-	parsedOrder := parseOrder(order, orderBy, i.materializedColumns)
+	parsedOrder := parseOrder(order, orderBy, i.materializedColumns, i.TraceIDField)
 
 	// We check that the time range if not 0 or lower then 0, because this would mean that the end time is equal to the
 	// start time or before the start time, which results in an error for the following SQL queries.
@@ -171,51 +249,15 @@ func (i *Instance) GetLogs(ctx context.Context, query, order, orderBy string, li
 		return nil, nil, 0, 0, nil, fmt.Errorf("invalid time range")
 	}
 
-	// We have to define the interval for the selected time range. By default we are creating 30 buckets in the
-	// following SQL query, but for time ranges with less then 30 seconds we have to create less buckets.
-	switch seconds := timeEnd - timeStart; {
-	case seconds <= 2:
-		interval = (timeEnd - timeStart) / 1
-	case seconds <= 10:
-		interval = (timeEnd - timeStart) / 5
-	case seconds <= 30:
-		interval = (timeEnd - timeStart) / 10
-	default:
-		interval = (timeEnd - timeStart) / 30
-	}
-
 	// Now we are creating 30 buckets for the selected time range and count the documents in each bucket. This is used
 	// to render the distribution chart, which shows how many documents/rows are available within a bucket.
-	sqlQueryBuckets := fmt.Sprintf(`SELECT toStartOfInterval(timestamp, INTERVAL %d second) AS interval_data , count(*) AS count_data FROM %s.logs WHERE timestamp >= FROM_UNIXTIME(%d) AND timestamp <= FROM_UNIXTIME(%d) %s GROUP BY interval_data ORDER BY interval_data WITH FILL FROM toStartOfInterval(FROM_UNIXTIME(%d), INTERVAL %d second) TO toStartOfInterval(FROM_UNIXTIME(%d), INTERVAL %d second) STEP %d SETTINGS skip_unavailable_shards = 1`, interval, i.database, timeStart, timeEnd, conditions, timeStart, interval, timeEnd, interval, interval)
-	log.WithFields(logrus.Fields{"query": sqlQueryBuckets}).Tracef("sql query buckets")
-	rowsBuckets, err := i.client.QueryContext(ctx, sqlQueryBuckets)
-	if err != nil {
-		return nil, nil, 0, 0, nil, err
-	}
-	defer rowsBuckets.Close()
-
-	for rowsBuckets.Next() {
-		var intervalData time.Time
-		var countData int64
-
-		if err := rowsBuckets.Scan(&intervalData, &countData); err != nil {
-			return nil, nil, 0, 0, nil, err
-		}
+	interval = getInterval(timeStart, timeEnd)
 
-		buckets = append(buckets, Bucket{
-			Interval: intervalData.Unix(),
-			Count:    countData,
-		})
-	}
-
-	if err := rowsBuckets.Err(); err != nil {
+	buckets, err := i.getBuckets(ctx, conditions, timeStart, timeEnd, interval)
+	if err != nil {
 		return nil, nil, 0, 0, nil, err
 	}
 
-	sort.Slice(buckets, func(i, j int) bool {
-		return buckets[i].Interval < buckets[j].Interval
-	})
-
 	// To optimize the query to get the raw logs we are creating a new time condition for the where statement. In that
 	// way we only have to look into the buckets which are containing some documents only have to include the first N
 	// buckets until the limit is reached.
@@ -312,6 +354,9 @@ func (i *Instance) GetLogs(ctx context.Context, query, order, orderBy string, li
 			fields = appendIfMissing(fields, field)
 		}
 
+		if addTraceID(document, i.TraceIDField) {
+			fields = appendIfMissing(fields, "trace_id")
+		}
 		documents = append(documents, document)
 	}
 
@@ -364,6 +409,13 @@ func (i *Instance) GetRawQueryResults(ctx context.Context, query string) ([][]in
 	return result, columns, nil
 }
 
+// Close stops the refresh of the cached fields and closes the connection pool of the instance. Queries which are
+// already running are finished before the connection pool is closed.
+func (i *Instance) Close() error {
+	close(i.done)
+	return i.client.Close()
+}
+
 // New returns a new ClickHouse instance for the given configuration.
 func New(config Config) (*Instance, error) {
 	if config.WriteTimeout == "" {
@@ -374,9 +426,13 @@ func New(config Config) (*Instance, error) {
 		config.ReadTimeout = "30"
 	}
 
-	dns := "tcp://" + config.Address + "?username=" + config.Username + "&password=" + config.Password + "&database=" + config.Database + "&write_timeout=" + config.WriteTimeout + "&read_timeout=" + config.ReadTimeout
+	dsn, err := getDSN(config)
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"name": config.Name}).Errorf("invalid connection configuration")
+		return nil, err
+	}
 
-	client, err := sql.Open("clickhouse", dns)
+	client, err := sql.Open("clickhouse", dsn)
 	if err != nil {
 		log.WithError(err).Errorf("could not initialize database connection")
 		return nil, err
@@ -394,13 +450,115 @@ func New(config Config) (*Instance, error) {
 	// 	return nil, err
 	// }
 
+	if config.ErrorQuery == "" {
+		config.ErrorQuery = defaultErrorQuery
+	}
+
+	if _, err := parseLogsQuery(config.ErrorQuery, config.MaterializedColumns, config.TraceIDField); err != nil {
+		log.WithError(err).WithFields(logrus.Fields{"name": config.Name}).Errorf("invalid error query")
+		return nil, err
+	}
+
 	instance := &Instance{
 		Name:                config.Name,
+		Default:             config.Default,
+		Namespaces:          config.Namespaces,
+		TraceIDField:        config.TraceIDField,
+		ErrorQuery:          config.ErrorQuery,
 		database:            config.Database,
 		client:              client,
 		materializedColumns: config.MaterializedColumns,
+		done:                make(chan struct{}),
 	}
 
 	go instance.refreshCachedFields()
 	return instance, nil
 }
+
+// Test checks if a connection to a ClickHouse instance with the given configuration can be established. In contrast to
+// the New function the connection is verified via a ping and closed afterwards, so that a configuration can be tested
+// without creating an instance. A CA file can not be used, because the file would be read from the file system of kobs
+// and registered as global TLS configuration.
+func Test(ctx context.Context, config Config) error {
+	if config.TLS.CAFile != "" {
+		return fmt.Errorf("caFile can not be used to test an instance")
+	}
+
+	if config.WriteTimeout == "" {
+		config.WriteTimeout = "30"
+	}
+
+	if config.ReadTimeout == "" {
+		config.ReadTimeout = "30"
+	}
+
+	dsn, err := getDSN(config)
+	if err != nil {
+		return err
+	}
+
+	client, err := sql.Open("clickhouse", dsn)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	return client.PingContext(ctx)
+}
+
+// getDSN returns the data source name for the given ClickHouse configuration. The username and password are escaped,
+// so that they can contain special characters. When TLS is enabled and a CA file is provided, a TLS configuration is
+// registered for the instance, which is then referenced in the data source name. When only a password is set, the
+// "default" user of ClickHouse is used.
+func getDSN(config Config) (string, error) {
+	if config.Password != "" && config.Username == "" {
+		config.Username = "default"
+	}
+
+	if !config.TLS.Enabled && (config.TLS.CAFile != "" || config.TLS.InsecureSkipVerify) {
+		return "", fmt.Errorf("tls options are set, but tls is not enabled")
+	}
+
+	if config.TLS.CAFile != "" && config.TLS.InsecureSkipVerify {
+		return "", fmt.Errorf("caFile and insecureSkipVerify can not be used together")
+	}
+
+	query := url.Values{}
+	query.Set("database", config.Database)
+	query.Set("write_timeout", config.WriteTimeout)
+	query.Set("read_timeout", config.ReadTimeout)
+
+	if config.Username != "" {
+		query.Set("username", config.Username)
+		query.Set("password", config.Password)
+	}
+
+	if config.TLS.Enabled {
+		query.Set("secure", "true")
+
+		if config.TLS.InsecureSkipVerify {
+			query.Set("skip_verify", "true")
+		}
+
+		if config.TLS.CAFile != "" {
+			ca, err := os.ReadFile(config.TLS.CAFile)
+			if err != nil {
+				return "", fmt.Errorf("could not read ca file: %w", err)
+			}
+
+			certPool := x509.NewCertPool()
+			if ok := certPool.AppendCertsFromPEM(ca); !ok {
+				return "", fmt.Errorf("could not parse ca file")
+			}
+
+			tlsConfigName := "kobs-" + config.Name
+			if err := clickhouse.RegisterTLSConfig(tlsConfigName, &tls.Config{RootCAs: certPool}); err != nil {
+				return "", err
+			}
+
+			query.Set("tls_config", tlsConfigName)
+		}
+	}
+
+	return "tcp://" + config.Address + "?" + query.Encode(), nil
+}
@@ -0,0 +1,62 @@
+package instance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDSN(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		config      Config
+		expectedDSN string
+		expectError bool
+	}{
+		{
+			name:        "without credentials",
+			config:      Config{Address: "localhost:9000", Database: "logs", WriteTimeout: "30", ReadTimeout: "30"},
+			expectedDSN: "tcp://localhost:9000?database=logs&read_timeout=30&write_timeout=30",
+		},
+		{
+			name:        "with escaped credentials",
+			config:      Config{Address: "localhost:9000", Database: "logs", Username: "admin", Password: "p&ss=word", WriteTimeout: "30", ReadTimeout: "30"},
+			expectedDSN: "tcp://localhost:9000?database=logs&password=p%26ss%3Dword&read_timeout=30&username=admin&write_timeout=30",
+		},
+		{
+			name:        "with tls",
+			config:      Config{Address: "localhost:9440", Database: "logs", WriteTimeout: "30", ReadTimeout: "30", TLS: TLSConfig{Enabled: true, InsecureSkipVerify: true}},
+			expectedDSN: "tcp://localhost:9440?database=logs&read_timeout=30&secure=true&skip_verify=true&write_timeout=30",
+		},
+		{
+			name:        "password without username",
+			config:      Config{Address: "localhost:9000", Database: "logs", Password: "admin", WriteTimeout: "30", ReadTimeout: "30"},
+			expectedDSN: "tcp://localhost:9000?database=logs&password=admin&read_timeout=30&username=default&write_timeout=30",
+		},
+		{
+			name:        "tls options without tls",
+			config:      Config{Address: "localhost:9000", TLS: TLSConfig{InsecureSkipVerify: true}},
+			expectError: true,
+		},
+		{
+			name:        "ca file and insecure skip verify",
+			config:      Config{Address: "localhost:9000", TLS: TLSConfig{Enabled: true, CAFile: "/etc/ssl/ca.crt", InsecureSkipVerify: true}},
+			expectError: true,
+		},
+		{
+			name:        "missing ca file",
+			config:      Config{Address: "localhost:9000", TLS: TLSConfig{Enabled: true, CAFile: "/does/not/exist"}},
+			expectError: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			actualDSN, err := getDSN(tc.config)
+			if tc.expectError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.expectedDSN, actualDSN)
+			}
+		})
+	}
+}
@@ -19,12 +19,38 @@ var (
 	}, []string{"field"})
 )
 
+// addTraceID adds the value of the configured trace id field as "trace_id" to the given document, so that a log line can
+// be linked to the corresponding trace, without assumptions about the schema of the logs. If no field is configured or
+// the document doesn't contain the field, the document isn't changed and false is returned.
+func addTraceID(document map[string]interface{}, traceIDField string) bool {
+	if traceIDField == "" {
+		return false
+	}
+
+	if traceID, ok := document[traceIDField].(string); ok && traceID != "" {
+		document["trace_id"] = traceID
+		return true
+	}
+
+	return false
+}
+
+// getFieldName returns the name of the field, which should be used in a sql query for the given key. Because the
+// "trace_id" field is added to the documents by kobs, it is replaced with the configured trace id field.
+func getFieldName(key, traceIDField string) string {
+	if key == "trace_id" && traceIDField != "" {
+		return traceIDField
+	}
+
+	return key
+}
+
 // parseLogsQuery parses the given query string and return the conditions for the where statement in the sql query. We
 // are providing a very simple query language where the user can use "(", ")", "_not_", "_and_" and "_or_" operators.
 // Then we are splitting the string again for the other operators "=", "!=", ">", ">=", "<", "<=" and "~" which are used
 // to check the value of a field.
 // Once we have build all the conditions we concate all the strings to the final sql statement for the where clause.
-func parseLogsQuery(query string, materializedColumns []string) (string, error) {
+func parseLogsQuery(query string, materializedColumns []string, traceIDField string) (string, error) {
 	var newOpenBrackets []string
 	openBrackets := strings.Split(query, "(")
 	for _, openBracket := range openBrackets {
@@ -40,7 +66,7 @@ func parseLogsQuery(query string, materializedColumns []string) (string, error)
 					var newOrs []string
 					ors := strings.Split(and, "_or_")
 					for _, or := range ors {
-						condition, err := splitOperator(or, materializedColumns)
+						condition, err := splitOperator(or, materializedColumns, traceIDField)
 						if err != nil {
 							return "", err
 						}
@@ -63,54 +89,54 @@ func parseLogsQuery(query string, materializedColumns []string) (string, error)
 // result is a slice with two items we found the operator which was used by the user to check the value of a field. So
 // that we pass the key (first item), value (second item) and the operator to the handleConditionParts to build the
 // where condition.
-func splitOperator(condition string, materializedColumns []string) (string, error) {
+func splitOperator(condition string, materializedColumns []string, traceIDField string) (string, error) {
 	greaterThanOrEqual := strings.Split(condition, ">=")
 	if len(greaterThanOrEqual) == 2 {
-		return handleConditionParts(greaterThanOrEqual[0], greaterThanOrEqual[1], ">=", materializedColumns)
+		return handleConditionParts(greaterThanOrEqual[0], greaterThanOrEqual[1], ">=", materializedColumns, traceIDField)
 	}
 
 	greaterThan := strings.Split(condition, ">")
 	if len(greaterThan) == 2 {
-		return handleConditionParts(greaterThan[0], greaterThan[1], ">", materializedColumns)
+		return handleConditionParts(greaterThan[0], greaterThan[1], ">", materializedColumns, traceIDField)
 	}
 
 	lessThanOrEqual := strings.Split(condition, "<=")
 	if len(lessThanOrEqual) == 2 {
-		return handleConditionParts(lessThanOrEqual[0], lessThanOrEqual[1], "<=", materializedColumns)
+		return handleConditionParts(lessThanOrEqual[0], lessThanOrEqual[1], "<=", materializedColumns, traceIDField)
 	}
 
 	lessThan := strings.Split(condition, "<")
 	if len(lessThan) == 2 {
-		return handleConditionParts(lessThan[0], lessThan[1], "<", materializedColumns)
+		return handleConditionParts(lessThan[0], lessThan[1], "<", materializedColumns, traceIDField)
 	}
 
 	ilike := strings.Split(condition, "=~")
 	if len(ilike) == 2 {
-		return handleConditionParts(ilike[0], ilike[1], "=~", materializedColumns)
+		return handleConditionParts(ilike[0], ilike[1], "=~", materializedColumns, traceIDField)
 	}
 
 	notEqual := strings.Split(condition, "!=")
 	if len(notEqual) == 2 {
-		return handleConditionParts(notEqual[0], notEqual[1], "!=", materializedColumns)
+		return handleConditionParts(notEqual[0], notEqual[1], "!=", materializedColumns, traceIDField)
 	}
 
 	notIlike := strings.Split(condition, "!~")
 	if len(notIlike) == 2 {
-		return handleConditionParts(notIlike[0], notIlike[1], "!~", materializedColumns)
+		return handleConditionParts(notIlike[0], notIlike[1], "!~", materializedColumns, traceIDField)
 	}
 
 	regex := strings.Split(condition, "~")
 	if len(regex) == 2 {
-		return handleConditionParts(regex[0], regex[1], "~", materializedColumns)
+		return handleConditionParts(regex[0], regex[1], "~", materializedColumns, traceIDField)
 	}
 
 	equal := strings.Split(condition, "=")
 	if len(equal) == 2 {
-		return handleConditionParts(equal[0], equal[1], "=", materializedColumns)
+		return handleConditionParts(equal[0], equal[1], "=", materializedColumns, traceIDField)
 	}
 
 	if strings.Contains(condition, "_exists_ ") {
-		return handleExistsCondition(strings.TrimLeft(strings.TrimSpace(condition), "_exists_ "), materializedColumns), nil
+		return handleExistsCondition(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(condition), "_exists_ ")), materializedColumns, traceIDField), nil
 	}
 
 	if strings.TrimSpace(condition) == "" {
@@ -128,8 +154,8 @@ func splitOperator(condition string, materializedColumns []string) (string, erro
 //
 // See: https://gist.github.com/alexey-milovidov/d6ffc9e0bc0bc72dd7bca90e76e3b83b
 // See: https://clickhouse.tech/docs/en/sql-reference/functions/string-search-functions/#matchhaystack-pattern
-func handleConditionParts(key, value, operator string, materializedColumns []string) (string, error) {
-	key = strings.TrimSpace(key)
+func handleConditionParts(key, value, operator string, materializedColumns []string, traceIDField string) (string, error) {
+	key = getFieldName(strings.TrimSpace(key), traceIDField)
 	value = strings.TrimSpace(value)
 
 	// The kobs_clickhouse_fields_total metric can be used to determine how often a field is used. This information can
@@ -185,7 +211,9 @@ func handleConditionParts(key, value, operator string, materializedColumns []str
 	return fmt.Sprintf("fields_number.value[indexOf(fields_number.key, '%s')] %s %s", key, operator, value), nil
 }
 
-func handleExistsCondition(key string, materializedColumns []string) string {
+func handleExistsCondition(key string, materializedColumns []string, traceIDField string) string {
+	key = getFieldName(key, traceIDField)
+
 	if contains(defaultFields, key) || contains(materializedColumns, key) {
 		return fmt.Sprintf("%s IS NOT NULL", key)
 	}
@@ -193,7 +221,7 @@ func handleExistsCondition(key string, materializedColumns []string) string {
 	return fmt.Sprintf("(has(fields_string.key, '%s') = 1 OR has(fields_number.key, '%s') = 1)", key, key)
 }
 
-func parseOrder(order, orderBy string, materializedColumns []string) string {
+func parseOrder(order, orderBy string, materializedColumns []string, traceIDField string) string {
 	if order == "" || orderBy == "" {
 		return "timestamp DESC"
 	}
@@ -204,7 +232,7 @@ func parseOrder(order, orderBy string, materializedColumns []string) string {
 		order = "DESC"
 	}
 
-	orderBy = strings.TrimSpace(orderBy)
+	orderBy = getFieldName(strings.TrimSpace(orderBy), traceIDField)
 	if contains(defaultFields, orderBy) || contains(materializedColumns, orderBy) {
 		return fmt.Sprintf("%s %s", orderBy, order)
 	}
@@ -17,9 +17,11 @@ func TestParseLogsQuery(t *testing.T) {
 		{query: "kubernetes.label_foo = 'bar'", where: "fields_string.value[indexOf(fields_string.key, 'kubernetes.label_foo')] = 'bar'", isInvalid: false},
 		{query: "kubernetes.label_foo_bar =~ '\\%hellow\\%world\\%'", where: "fields_string.value[indexOf(fields_string.key, 'kubernetes.label_foo_bar')] ILIKE '\\%hellow\\%world\\%'", isInvalid: false},
 		{query: "kubernetes.label_foo_bar ~ 'hello.*'", where: "match(fields_string.value[indexOf(fields_string.key, 'kubernetes.label_foo_bar')], 'hello.*')", isInvalid: false},
+		{query: "trace_id = 'abc'", where: "fields_string.value[indexOf(fields_string.key, 'content.trace_id')] = 'abc'", isInvalid: false},
+		{query: "_exists_ trace_id", where: "(has(fields_string.key, 'content.trace_id') = 1 OR has(fields_number.key, 'content.trace_id') = 1)", isInvalid: false},
 	} {
 		t.Run(tc.query, func(t *testing.T) {
-			parsedWhere, err := parseLogsQuery(tc.query, nil)
+			parsedWhere, err := parseLogsQuery(tc.query, nil, "content.trace_id")
 			if tc.isInvalid {
 				require.Error(t, err)
 			} else {
@@ -29,3 +31,29 @@ func TestParseLogsQuery(t *testing.T) {
 		})
 	}
 }
+
+func TestAddTraceID(t *testing.T) {
+	t.Run("field configured and present", func(t *testing.T) {
+		document := map[string]interface{}{"content.trace_id": "abc"}
+		require.True(t, addTraceID(document, "content.trace_id"))
+		require.Equal(t, "abc", document["trace_id"])
+	})
+
+	t.Run("field not configured", func(t *testing.T) {
+		document := map[string]interface{}{"content.trace_id": "abc"}
+		require.False(t, addTraceID(document, ""))
+		require.NotContains(t, document, "trace_id")
+	})
+
+	t.Run("field missing or not a string", func(t *testing.T) {
+		document := map[string]interface{}{"content.trace_id": float64(1)}
+		require.False(t, addTraceID(document, "content.trace_id"))
+		require.NotContains(t, document, "trace_id")
+	})
+}
+
+func TestParseOrder(t *testing.T) {
+	require.Equal(t, "timestamp DESC", parseOrder("", "", nil, "content.trace_id"))
+	require.Equal(t, "namespace ASC", parseOrder("ascending", "namespace", nil, "content.trace_id"))
+	require.Equal(t, "fields_string.value[indexOf(fields_string.key, 'content.trace_id')] DESC, fields_number.value[indexOf(fields_number.key, 'content.trace_id')] DESC", parseOrder("descending", "trace_id", nil, "content.trace_id"))
+}
@@ -0,0 +1,79 @@
+package instance
+
+import (
+	"sort"
+	"time"
+)
+
+// LogsResult is the result of the GetLogs function for a single source. It is used to merge the logs of multiple
+// ClickHouse instances into a single timeline.
+type LogsResult struct {
+	Source    string
+	Documents []map[string]interface{}
+	Fields    []string
+	Count     int64
+	Took      int64
+	Buckets   []Bucket
+}
+
+// MergeLogs merges the results of multiple sources into a single result. The documents of all sources are sorted by
+// their timestamp, before the limit is applied, so that the returned documents are a coherent timeline. Each document
+// gets an additional "source" field with the name of the source it was returned from. The buckets of all sources are
+// merged by their interval, the fields are deduplicated and the took value is the time of the slowest source.
+func MergeLogs(results []LogsResult, ascending bool, limit int64) LogsResult {
+	var merged LogsResult
+	buckets := make(map[int64]int64)
+
+	for _, result := range results {
+		for _, document := range result.Documents {
+			document["source"] = result.Source
+			merged.Documents = append(merged.Documents, document)
+		}
+
+		for _, field := range result.Fields {
+			merged.Fields = appendIfMissing(merged.Fields, field)
+		}
+
+		for _, bucket := range result.Buckets {
+			buckets[bucket.Interval] = buckets[bucket.Interval] + bucket.Count
+		}
+
+		merged.Count = merged.Count + result.Count
+		if result.Took > merged.Took {
+			merged.Took = result.Took
+		}
+	}
+
+	sort.SliceStable(merged.Documents, func(i, j int) bool {
+		if ascending {
+			return getTimestamp(merged.Documents[i]).Before(getTimestamp(merged.Documents[j]))
+		}
+		return getTimestamp(merged.Documents[i]).After(getTimestamp(merged.Documents[j]))
+	})
+
+	if limit > 0 && int64(len(merged.Documents)) > limit {
+		merged.Documents = merged.Documents[:limit]
+	}
+
+	for interval, count := range buckets {
+		merged.Buckets = append(merged.Buckets, Bucket{Interval: interval, Count: count})
+	}
+
+	sort.Slice(merged.Buckets, func(i, j int) bool {
+		return merged.Buckets[i].Interval < merged.Buckets[j].Interval
+	})
+
+	sort.Strings(merged.Fields)
+
+	return merged
+}
+
+// getTimestamp returns the timestamp of the given document. If the document doesn't contain a valid timestamp, the zero
+// time is returned.
+func getTimestamp(document map[string]interface{}) time.Time {
+	if timestamp, ok := document["timestamp"].(time.Time); ok {
+		return timestamp
+	}
+
+	return time.Time{}
+}
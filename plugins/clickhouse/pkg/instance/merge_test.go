@@ -0,0 +1,41 @@
+package instance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeLogs(t *testing.T) {
+	now := time.Now()
+
+	results := []LogsResult{
+		{
+			Source:    "logs-1",
+			Documents: []map[string]interface{}{{"timestamp": now.Add(-1 * time.Second)}, {"timestamp": now.Add(-3 * time.Second)}},
+			Fields:    []string{"content.method"},
+			Count:     2,
+			Took:      10,
+			Buckets:   []Bucket{{Interval: 1, Count: 2}},
+		},
+		{
+			Source:    "logs-2",
+			Documents: []map[string]interface{}{{"timestamp": now}, {"timestamp": now.Add(-2 * time.Second)}},
+			Fields:    []string{"content.method", "content.status"},
+			Count:     2,
+			Took:      20,
+			Buckets:   []Bucket{{Interval: 1, Count: 1}, {Interval: 0, Count: 1}},
+		},
+	}
+
+	merged := MergeLogs(results, false, 3)
+	require.Len(t, merged.Documents, 3)
+	require.Equal(t, "logs-2", merged.Documents[0]["source"])
+	require.Equal(t, "logs-1", merged.Documents[1]["source"])
+	require.Equal(t, "logs-2", merged.Documents[2]["source"])
+	require.Equal(t, []string{"content.method", "content.status"}, merged.Fields)
+	require.Equal(t, int64(4), merged.Count)
+	require.Equal(t, int64(20), merged.Took)
+	require.Equal(t, []Bucket{{Interval: 0, Count: 1}, {Interval: 1, Count: 3}}, merged.Buckets)
+}
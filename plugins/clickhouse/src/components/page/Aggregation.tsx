@@ -9,17 +9,18 @@ import AggregationChart from '../panel/AggregationChart';
 
 interface IAggregationProps {
   name: string;
+  route: string;
   options: IAggregationOptions;
 }
 
-const Aggregation: React.FunctionComponent<IAggregationProps> = ({ name, options }: IAggregationProps) => {
+const Aggregation: React.FunctionComponent<IAggregationProps> = ({ name, route, options }: IAggregationProps) => {
   const history = useHistory();
 
   const { isError, isFetching, isLoading, data, error, refetch } = useQuery<IAggregationData, Error>(
-    ['clickhouse/aggregation', name, options],
+    ['clickhouse/aggregation', name, route, options],
     async () => {
       try {
-        const response = await fetch(`/api/plugins/clickhouse/aggregation/${name}`, {
+        const response = await fetch(`/api/plugins${route}/aggregation/${name}`, {
           body: JSON.stringify(options),
           method: 'post',
         });
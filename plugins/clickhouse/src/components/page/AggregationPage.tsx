@@ -22,12 +22,14 @@ import { getAggregationOptionsFromSearch } from '../../utils/helpers';
 
 interface IAggregationPageProps {
   name: string;
+  route: string;
   displayName: string;
   description: string;
 }
 
 const AggregationPage: React.FunctionComponent<IAggregationPageProps> = ({
   name,
+  route,
   displayName,
   description,
 }: IAggregationPageProps) => {
@@ -86,7 +88,7 @@ const AggregationPage: React.FunctionComponent<IAggregationPageProps> = ({
                 </GridItem>
                 <GridItem sm={12} md={12} lg={9} xl={8} xl2={9}>
                   {options.query && options.chart && options.options ? (
-                    <Aggregation name={name} options={options} />
+                    <Aggregation name={name} route={route} options={options} />
                   ) : null}
                 </GridItem>
               </Grid>
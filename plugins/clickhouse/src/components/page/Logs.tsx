@@ -24,6 +24,7 @@ import LogsFields from './LogsFields';
 
 interface ILogsProps {
   name: string;
+  route: string;
   fields?: string[];
   order: string;
   orderBy: string;
@@ -50,11 +51,11 @@ const Logs: React.FunctionComponent<ILogsProps> = ({
   const history = useHistory();
 
   const { isError, isFetching, isLoading, data, error, refetch } = useQuery<ILogsData, Error>(
-    ['clickhouse/logs', name, query, order, orderBy, times],
+    ['clickhouse/logs', name, route, query, order, orderBy, times],
     async () => {
       try {
         const response = await fetch(
-          `/api/plugins/clickhouse/logs/${name}?query=${encodeURIComponent(
+          `/api/plugins${route}/logs/${name}?query=${encodeURIComponent(
             query,
           )}&order=${order}&orderBy=${encodeURIComponent(orderBy)}&timeStart=${times.timeStart}&timeEnd=${
             times.timeEnd
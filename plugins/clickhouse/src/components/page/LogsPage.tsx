@@ -20,11 +20,17 @@ import { getOptionsFromSearch } from '../../utils/helpers';
 
 interface ILogsPageProps {
   name: string;
+  route: string;
   displayName: string;
   description: string;
 }
 
-const LogsPage: React.FunctionComponent<ILogsPageProps> = ({ name, displayName, description }: ILogsPageProps) => {
+const LogsPage: React.FunctionComponent<ILogsPageProps> = ({
+  name,
+  route,
+  displayName,
+  description,
+}: ILogsPageProps) => {
   const location = useLocation();
   const history = useHistory();
   const [options, setOptions] = useState<IOptions>(getOptionsFromSearch(location.search));
@@ -114,6 +120,7 @@ const LogsPage: React.FunctionComponent<ILogsPageProps> = ({ name, displayName,
               {options.query.length > 0 ? (
                 <Logs
                   name={name}
+                  route={route}
                   fields={options.fields}
                   query={options.query}
                   order={options.order}
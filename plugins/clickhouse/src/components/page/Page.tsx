@@ -4,15 +4,23 @@ import React from 'react';
 import AggregationPage from './AggregationPage';
 import { IPluginPageProps } from '@kobsio/plugin-core';
 import LogsPage from './LogsPage';
+import { getRoute } from '../../utils/helpers';
+
+const Page: React.FunctionComponent<IPluginPageProps> = ({
+  name,
+  displayName,
+  description,
+  options,
+}: IPluginPageProps) => {
+  const route = getRoute(options);
 
-const Page: React.FunctionComponent<IPluginPageProps> = ({ name, displayName, description }: IPluginPageProps) => {
   return (
     <Switch>
       <Route exact={true} path={`/${name}`}>
-        <LogsPage name={name} displayName={displayName} description={description} />
+        <LogsPage name={name} route={route} displayName={displayName} description={description} />
       </Route>
       <Route exact={true} path={`/${name}/aggregation`}>
-        <AggregationPage name={name} displayName={displayName} description={description} />
+        <AggregationPage name={name} route={route} displayName={displayName} description={description} />
       </Route>
     </Switch>
   );
@@ -9,6 +9,7 @@ import { PluginCard } from '@kobsio/plugin-core';
 
 interface IAggregationProps {
   name: string;
+  route: string;
   title: string;
   description?: string;
   options: IAggregationOptions;
@@ -16,15 +17,16 @@ interface IAggregationProps {
 
 const Aggregation: React.FunctionComponent<IAggregationProps> = ({
   name,
+  route,
   title,
   description,
   options,
 }: IAggregationProps) => {
   const { isError, isFetching, isLoading, data, error, refetch } = useQuery<IAggregationData, Error>(
-    ['clickhouse/aggregation', name, options],
+    ['clickhouse/aggregation', name, route, options],
     async () => {
       try {
-        const response = await fetch(`/api/plugins/clickhouse/aggregation/${name}`, {
+        const response = await fetch(`/api/plugins${route}/aggregation/${name}`, {
           body: JSON.stringify(options),
           method: 'post',
         });
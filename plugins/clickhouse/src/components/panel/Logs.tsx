@@ -19,18 +19,26 @@ import LogsDocuments from '../panel/LogsDocuments';
 
 interface ILogsProps {
   name: string;
+  route: string;
   title: string;
   description?: string;
   queries: IQuery[];
   times: IPluginTimes;
 }
 
-const Logs: React.FunctionComponent<ILogsProps> = ({ name, title, description, queries, times }: ILogsProps) => {
+const Logs: React.FunctionComponent<ILogsProps> = ({
+  name,
+  route,
+  title,
+  description,
+  queries,
+  times,
+}: ILogsProps) => {
   const [showSelect, setShowSelect] = useState<boolean>(false);
   const [selectedQuery, setSelectedQuery] = useState<IQuery>(queries[0]);
 
   const { isError, isFetching, isLoading, data, error, refetch } = useQuery<ILogsData, Error>(
-    ['clickhouse/logs', selectedQuery, times],
+    ['clickhouse/logs', name, route, selectedQuery, times],
     async () => {
       try {
         if (!selectedQuery.query) {
@@ -38,7 +46,7 @@ const Logs: React.FunctionComponent<ILogsProps> = ({ name, title, description, q
         }
 
         const response = await fetch(
-          `/api/plugins/clickhouse/logs/${name}?query=${encodeURIComponent(selectedQuery.query)}&order=${
+          `/api/plugins${route}/logs/${name}?query=${encodeURIComponent(selectedQuery.query)}&order=${
             selectedQuery.order || ''
           }&orderBy=${encodeURIComponent(selectedQuery.orderBy || '')}&timeStart=${times.timeStart}&timeEnd=${
             times.timeEnd
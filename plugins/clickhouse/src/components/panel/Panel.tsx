@@ -4,6 +4,7 @@ import { IPluginPanelProps, PluginOptionsMissing } from '@kobsio/plugin-core';
 import Aggregation from './Aggregation';
 import { IPanelOptions } from '../../utils/interfaces';
 import Logs from './Logs';
+import { getRoute } from '../../utils/helpers';
 
 interface IPanelProps extends IPluginPanelProps {
   options?: IPanelOptions;
@@ -14,10 +15,22 @@ export const Panel: React.FunctionComponent<IPanelProps> = ({
   title,
   description,
   times,
+  pluginOptions,
   options,
 }: IPanelProps) => {
+  const route = getRoute(pluginOptions);
+
   if (options && options.type === 'logs' && options.queries && times) {
-    return <Logs name={name} title={title} description={description} queries={options.queries} times={times} />;
+    return (
+      <Logs
+        name={name}
+        route={route}
+        title={title}
+        description={description}
+        queries={options.queries}
+        times={times}
+      />
+    );
   }
 
   if (
@@ -31,6 +44,7 @@ export const Panel: React.FunctionComponent<IPanelProps> = ({
     return (
       <Aggregation
         name={name}
+        route={route}
         title={title}
         description={description}
         options={{ ...options.aggregation, times: times }}
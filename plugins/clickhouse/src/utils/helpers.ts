@@ -1,5 +1,11 @@
 import { IAggregationOptions, IAggregationOptionsAggregation, IOptions } from './interfaces';
-import { getTimeParams } from '@kobsio/plugin-core';
+import { IPluginDataOptions, getTimeParams } from '@kobsio/plugin-core';
+
+// getRoute returns the route of the ClickHouse plugin from the plugin options. The route can be changed via the routes
+// option in the configuration and is different for each ClickHouse plugin set.
+export const getRoute = (options?: IPluginDataOptions): string => {
+  return options && options.route ? options.route : '/clickhouse';
+};
 
 // getOptionsFromSearch is used to get the ClickHouse options from a given search location.
 export const getOptionsFromSearch = (search: string): IOptions => {
@@ -55,7 +55,7 @@ func (router *Router) getAllDashboards(w http.ResponseWriter, r *http.Request) {
 
 	var dashboards []dashboard.DashboardSpec
 
-	for _, cluster := range router.clusters.Clusters {
+	for _, cluster := range router.clusters.GetClusters() {
 		dashboard, err := cluster.GetDashboards(r.Context(), "")
 		if err != nil {
 			errresponse.Render(w, r, err, http.StatusBadRequest, "Could not get dashboards")
@@ -109,9 +109,9 @@ func (router *Router) getDashboards(w http.ResponseWriter, r *http.Request) {
 				Rows:        reference.Inline.Rows,
 			})
 		} else {
-			cluster := router.clusters.GetCluster(reference.Cluster)
-			if cluster == nil {
-				errresponse.Render(w, r, nil, http.StatusBadRequest, "Invalid cluster name")
+			cluster, err := router.clusters.GetCluster(reference.Cluster)
+			if err != nil {
+				errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
 				return
 			}
 
@@ -154,9 +154,9 @@ func (router *Router) getDashboard(w http.ResponseWriter, r *http.Request) {
 
 	log.WithFields(logrus.Fields{"cluster": data.Cluster, "namespace": data.Namespace, "name": data.Name, "placeholders": data.Placeholders}).Tracef("getDashboard")
 
-	cluster := router.clusters.GetCluster(data.Cluster)
-	if cluster == nil {
-		errresponse.Render(w, r, nil, http.StatusBadRequest, "Invalid cluster name")
+	cluster, err := router.clusters.GetCluster(data.Cluster)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
 		return
 	}
 
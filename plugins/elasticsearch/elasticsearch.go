@@ -1,8 +1,10 @@
 package elasticsearch
 
 import (
+	"encoding/json"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/kobsio/kobs/pkg/api/clusters"
 	"github.com/kobsio/kobs/pkg/api/middleware/errresponse"
@@ -31,28 +33,57 @@ type Router struct {
 	instances []*instance.Instance
 }
 
+// getInstance returns the instance with the given name. If no instance has the given name and the name is empty or
+// "default", the default instance is returned.
 func (router *Router) getInstance(name string) *instance.Instance {
-	for _, i := range router.instances {
-		if i.Name == name {
-			return i
-		}
+	index := plugin.GetInstanceIndex(name, len(router.instances), func(i int) (string, bool) {
+		return router.instances[i].Name, router.instances[i].Default
+	})
+	if index == -1 {
+		return nil
 	}
 
-	return nil
+	return router.instances[index]
 }
 
-// getLogs returns the raw documents for a given query from Elasticsearch. The result also contains the distribution of
+// getFields returns all fields from the mapping of the configured index pattern, which are containing the filter term.
+func (router *Router) getFields(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	filter := r.URL.Query().Get("filter")
+	fieldType := r.URL.Query().Get("fieldType")
+
+	log.WithFields(logrus.Fields{"name": name, "filter": filter, "fieldType": fieldType}).Tracef("getFields")
+
+	i := router.getInstance(name)
+	if i == nil {
+		errresponse.Render(w, r, nil, http.StatusBadRequest, "Could not find instance name")
+		return
+	}
+
+	fields, err := i.GetFields(r.Context(), filter, fieldType)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusInternalServerError, "Could not get fields")
+		return
+	}
+
+	log.WithFields(logrus.Fields{"fields": len(fields)}).Tracef("getFields")
+	render.JSON(w, r, fields)
+}
+
+// getLogs returns the documents for a given query from Elasticsearch. The result also contains the distribution of
 // the documents in the given time range. The name of the Elasticsearch instance must be set via the name path
-// parameter, all other values like the query, start and end time are set via query parameters. These
+// parameter, all other values like the query, order, start and end time are set via query parameters. These
 // parameters are then passed to the GetLogs function of the Elasticsearch instance, which returns the documents and
 // buckets.
 func (router *Router) getLogs(w http.ResponseWriter, r *http.Request) {
 	name := chi.URLParam(r, "name")
 	query := r.URL.Query().Get("query")
+	order := r.URL.Query().Get("order")
+	orderBy := r.URL.Query().Get("orderBy")
 	timeStart := r.URL.Query().Get("timeStart")
 	timeEnd := r.URL.Query().Get("timeEnd")
 
-	log.WithFields(logrus.Fields{"name": name, "query": query, "timeStart": timeStart, "timeEnd": timeEnd}).Tracef("getLogs")
+	log.WithFields(logrus.Fields{"name": name, "query": query, "order": order, "orderBy": orderBy, "timeStart": timeStart, "timeEnd": timeEnd}).Tracef("getLogs")
 
 	i := router.getInstance(name)
 	if i == nil {
@@ -72,7 +103,7 @@ func (router *Router) getLogs(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	data, err := i.GetLogs(r.Context(), query, parsedTimeStart, parsedTimeEnd)
+	data, err := i.GetLogs(r.Context(), query, order, orderBy, 1000, parsedTimeStart, parsedTimeEnd)
 	if err != nil {
 		errresponse.Render(w, r, err, http.StatusInternalServerError, "Could not get logs")
 		return
@@ -81,6 +112,67 @@ func (router *Router) getLogs(w http.ResponseWriter, r *http.Request) {
 	render.JSON(w, r, data)
 }
 
+// getAggregation returns the columns and rows for the user given aggregation request. The aggregation data must be
+// provided in the body of the request and uses the same format as for the ClickHouse plugin.
+func (router *Router) getAggregation(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	log.WithFields(logrus.Fields{"name": name}).Tracef("getAggregation")
+
+	i := router.getInstance(name)
+	if i == nil {
+		errresponse.Render(w, r, nil, http.StatusBadRequest, "Could not find instance name")
+		return
+	}
+
+	var aggregationData instance.Aggregation
+
+	err := json.NewDecoder(r.Body).Decode(&aggregationData)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not decode request body")
+		return
+	}
+
+	done := make(chan bool)
+
+	go func() {
+		ticker := time.NewTicker(10 * time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if f, ok := w.(http.Flusher); ok {
+					w.Write([]byte("\n"))
+					f.Flush()
+				}
+			}
+		}
+	}()
+
+	defer func() {
+		done <- true
+	}()
+
+	rows, columns, err := i.GetAggregation(r.Context(), aggregationData)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Error while running aggregation")
+		return
+	}
+
+	data := struct {
+		Rows    []map[string]interface{} `json:"rows"`
+		Columns []string                 `json:"columns"`
+	}{
+		rows,
+		columns,
+	}
+
+	render.JSON(w, r, data)
+}
+
 // Register returns a new router which can be used in the router for the kobs rest api.
 func Register(clusters *clusters.Clusters, plugins *plugin.Plugins, config Config) chi.Router {
 	var instances []*instance.Instance
@@ -107,7 +199,9 @@ func Register(clusters *clusters.Clusters, plugins *plugin.Plugins, config Confi
 		instances,
 	}
 
+	router.Get("/fields/{name}", router.getFields)
 	router.Get("/logs/{name}", router.getLogs)
+	router.Post("/aggregation/{name}", router.getAggregation)
 
 	return router
 }
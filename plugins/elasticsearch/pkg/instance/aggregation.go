@@ -0,0 +1,296 @@
+package instance
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Aggregation is the structure of the data, which is required to run an aggregation. It uses the same format as the
+// aggregations of the ClickHouse plugin.
+type Aggregation struct {
+	Query   string             `json:"query"`
+	Chart   string             `json:"chart"`
+	Times   AggregationTimes   `json:"times"`
+	Options AggregationOptions `json:"options"`
+}
+
+// AggregationOptions is the structure of the options for an aggregation. It contains all the fields, which are required
+// to build the query for the choosen chart type.
+type AggregationOptions struct {
+	SliceBy         string `json:"sliceBy"`
+	SizeByOperation string `json:"sizeByOperation"`
+	SizeByField     string `json:"sizeByField"`
+
+	HorizontalAxisOperation string `json:"horizontalAxisOperation"`
+	HorizontalAxisField     string `json:"horizontalAxisField"`
+	HorizontalAxisOrder     string `json:"horizontalAxisOrder"`
+	HorizontalAxisLimit     string `json:"horizontalAxisLimit"`
+
+	VerticalAxisOperation string `json:"verticalAxisOperation"`
+	VerticalAxisField     string `json:"verticalAxisField"`
+
+	BreakDownBy        string   `json:"breakDownBy"`
+	BreakDownByFields  []string `json:"breakDownByFields"`
+	BreakDownByFilters []string `json:"breakDownByFilters"`
+}
+
+// AggregationTimes is the structure, which defines the time interval for the aggregation.
+type AggregationTimes struct {
+	TimeEnd   int64 `json:"timeEnd"`
+	TimeStart int64 `json:"timeStart"`
+}
+
+// aggregationLevel is a single bucket aggregation in the nested Elasticsearch aggregation. The column is the name of
+// the column in the returned rows, which contains the key of a bucket.
+type aggregationLevel struct {
+	column      string
+	aggregation map[string]interface{}
+}
+
+// isValidOperation checks if the given operation is supported for an aggregation.
+func isValidOperation(operation string) bool {
+	return operation == "count" || operation == "min" || operation == "max" || operation == "sum" || operation == "avg"
+}
+
+// termsLevels returns a terms aggregation for each of the given fields.
+func termsLevels(fields []string, size int) []aggregationLevel {
+	var levels []aggregationLevel
+	for _, field := range fields {
+		levels = append(levels, aggregationLevel{
+			column:      field,
+			aggregation: map[string]interface{}{"terms": map[string]interface{}{"field": field, "size": size}},
+		})
+	}
+
+	return levels
+}
+
+// buildAggregation returns the bucket aggregations, the metric operation and the metric field for the user defined
+// chart. The bucket aggregations are nested into each other, so that we get one row for each combination of the bucket
+// keys.
+func buildAggregation(chart string, options AggregationOptions, timeStart, timeEnd int64) ([]aggregationLevel, string, string, error) {
+	if chart != "pie" && chart != "bar" && chart != "line" && chart != "area" {
+		return nil, "", "", fmt.Errorf("invalid chart type")
+	}
+
+	if len(options.BreakDownByFilters) > 0 {
+		return nil, "", "", fmt.Errorf("break down by filters are not supported")
+	}
+
+	if chart == "pie" {
+		if options.SliceBy == "" {
+			return nil, "", "", fmt.Errorf("slice by field is required")
+		}
+
+		if !isValidOperation(options.SizeByOperation) {
+			return nil, "", "", fmt.Errorf("invalid size by operation")
+		}
+
+		return termsLevels([]string{options.SliceBy}, 100), options.SizeByOperation, options.SizeByField, nil
+	}
+
+	if !isValidOperation(options.VerticalAxisOperation) {
+		return nil, "", "", fmt.Errorf("invalid vertical axis operation")
+	}
+
+	if options.VerticalAxisField == "" && options.VerticalAxisOperation != "count" {
+		return nil, "", "", fmt.Errorf("vertical axis field is required")
+	}
+
+	if chart == "bar" && options.HorizontalAxisOperation == "top" {
+		if options.HorizontalAxisField == "" {
+			return nil, "", "", fmt.Errorf("horizontal axis field is required")
+		}
+
+		limit := 10
+		if options.HorizontalAxisLimit != "" {
+			parsedLimit, err := strconv.Atoi(strings.TrimSpace(options.HorizontalAxisLimit))
+			if err != nil {
+				return nil, "", "", fmt.Errorf("invalid horizontal axis limit")
+			}
+			limit = parsedLimit
+		}
+
+		order := "asc"
+		if options.HorizontalAxisOrder == "descending" {
+			order = "desc"
+		}
+
+		orderBy := "_count"
+		if options.VerticalAxisOperation != "count" {
+			orderBy = "data"
+		}
+
+		levels := []aggregationLevel{{
+			column:      options.HorizontalAxisField,
+			aggregation: map[string]interface{}{"terms": map[string]interface{}{"field": options.HorizontalAxisField, "size": limit, "order": map[string]interface{}{orderBy: order}}},
+		}}
+
+		return append(levels, termsLevels(options.BreakDownByFields, 10)...), options.VerticalAxisOperation, options.VerticalAxisField, nil
+	}
+
+	if (chart == "bar" || chart == "line" || chart == "area") && options.HorizontalAxisOperation == "time" {
+		// Create an interval for the selected start and end time, so that we always return the same amount of data
+		// points, so that our charts are rendered in the same ways for each selected time range.
+		var interval int64
+		switch seconds := timeEnd - timeStart; {
+		case seconds <= 2:
+			interval = (timeEnd - timeStart) / 1
+		case seconds <= 10:
+			interval = (timeEnd - timeStart) / 5
+		case seconds <= 30:
+			interval = (timeEnd - timeStart) / 15
+		case seconds <= 60:
+			interval = (timeEnd - timeStart) / 30
+		case seconds <= 120:
+			interval = (timeEnd - timeStart) / 60
+		default:
+			interval = (timeEnd - timeStart) / 100
+		}
+
+		if interval < 1 {
+			interval = 1
+		}
+
+		levels := []aggregationLevel{{
+			column: "time",
+			aggregation: map[string]interface{}{"date_histogram": map[string]interface{}{
+				"field":           "@timestamp",
+				"fixed_interval":  fmt.Sprintf("%ds", interval),
+				"min_doc_count":   0,
+				"extended_bounds": map[string]interface{}{"min": timeStart * 1000, "max": timeEnd * 1000},
+			}},
+		}}
+
+		return append(levels, termsLevels(options.BreakDownByFields, 10)...), options.VerticalAxisOperation, options.VerticalAxisField, nil
+	}
+
+	return nil, "", "", fmt.Errorf("invalid aggregation")
+}
+
+// nestAggregations nests the given bucket aggregations into each other. The metric aggregation is added to the last
+// bucket aggregation, when the operation isn't "count", because for count we can use the doc_count of the buckets.
+func nestAggregations(levels []aggregationLevel, operation, field string) map[string]interface{} {
+	var aggs map[string]interface{}
+	if operation != "count" {
+		aggs = map[string]interface{}{"data": map[string]interface{}{operation: map[string]interface{}{"field": field}}}
+	}
+
+	for index := len(levels) - 1; index >= 0; index-- {
+		aggregation := make(map[string]interface{})
+		for key, value := range levels[index].aggregation {
+			aggregation[key] = value
+		}
+
+		if aggs != nil {
+			aggregation["aggs"] = aggs
+		}
+
+		aggs = map[string]interface{}{fmt.Sprintf("level%d", index): aggregation}
+	}
+
+	return aggs
+}
+
+// parseAggregations walks through the nested buckets of an aggregation result and returns one row for each combination
+// of bucket keys. The value of the metric is added as "<operation>_data" column, like it is done in the ClickHouse
+// plugin.
+func parseAggregations(aggs map[string]interface{}, levels []aggregationLevel, index int, operation string, row map[string]interface{}) []map[string]interface{} {
+	level, ok := aggs[fmt.Sprintf("level%d", index)].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	buckets, ok := level["buckets"].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	var rows []map[string]interface{}
+
+	for _, b := range buckets {
+		bucket, ok := b.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		newRow := make(map[string]interface{})
+		for key, value := range row {
+			newRow[key] = value
+		}
+
+		if levels[index].column == "time" {
+			if key, ok := bucket["key"].(float64); ok {
+				newRow["time"] = time.Unix(int64(key)/1000, 0).UTC()
+			}
+		} else {
+			newRow[levels[index].column] = bucket["key"]
+		}
+
+		if index < len(levels)-1 {
+			rows = append(rows, parseAggregations(bucket, levels, index+1, operation, newRow)...)
+			continue
+		}
+
+		if operation == "count" {
+			newRow["count_data"] = bucket["doc_count"]
+		} else if data, ok := bucket["data"].(map[string]interface{}); ok && data["value"] != nil {
+			newRow[fmt.Sprintf("%s_data", operation)] = data["value"]
+		}
+
+		rows = append(rows, newRow)
+	}
+
+	return rows
+}
+
+// GetAggregation returns the data for the given aggregation. To get the data we have to build the nested bucket
+// aggregations, which are then run together with the user provided query against Elasticsearch. The result is
+// converted into rows and columns, so that it has the same format as the aggregation results of the ClickHouse plugin.
+func (i *Instance) GetAggregation(ctx context.Context, aggregation Aggregation) ([]map[string]interface{}, []string, error) {
+	log.WithFields(logrus.Fields{"aggregation": fmt.Sprintf("%#v", aggregation)}).Tracef("aggregation data")
+
+	levels, operation, field, err := buildAggregation(aggregation.Chart, aggregation.Options, aggregation.Times.TimeStart, aggregation.Times.TimeEnd)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	queryString, err := i.getQuery(aggregation.Query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	body := map[string]interface{}{
+		"size": 0,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must": []map[string]interface{}{
+					{"range": map[string]interface{}{"@timestamp": map[string]interface{}{"gte": aggregation.Times.TimeStart * 1000, "lte": aggregation.Times.TimeEnd * 1000, "format": "epoch_millis"}}},
+					{"query_string": map[string]interface{}{"query": queryString}},
+				},
+			},
+		},
+		"aggs": nestAggregations(levels, operation, field),
+	}
+
+	var res struct {
+		Aggregations map[string]interface{} `json:"aggregations"`
+	}
+	if err := i.do(ctx, http.MethodPost, i.getURL("_search"), body, &res); err != nil {
+		return nil, nil, err
+	}
+
+	var columns []string
+	for _, level := range levels {
+		columns = append(columns, level.column)
+	}
+	columns = append(columns, fmt.Sprintf("%s_data", operation))
+
+	return parseAggregations(res.Aggregations, levels, 0, operation, nil), columns, nil
+}
@@ -0,0 +1,57 @@
+package instance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildAggregation(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		chart     string
+		options   AggregationOptions
+		columns   []string
+		isInvalid bool
+	}{
+		{name: "invalid chart", chart: "table", isInvalid: true},
+		{name: "pie", chart: "pie", options: AggregationOptions{SliceBy: "namespace", SizeByOperation: "count"}, columns: []string{"namespace"}},
+		{name: "pie without slice by", chart: "pie", options: AggregationOptions{SizeByOperation: "count"}, isInvalid: true},
+		{name: "bar top", chart: "bar", options: AggregationOptions{HorizontalAxisOperation: "top", HorizontalAxisField: "namespace", VerticalAxisOperation: "count", BreakDownByFields: []string{"app"}}, columns: []string{"namespace", "app"}},
+		{name: "line", chart: "line", options: AggregationOptions{HorizontalAxisOperation: "time", VerticalAxisOperation: "avg", VerticalAxisField: "duration"}, columns: []string{"time"}},
+		{name: "line without vertical axis field", chart: "line", options: AggregationOptions{HorizontalAxisOperation: "time", VerticalAxisOperation: "avg"}, isInvalid: true},
+		{name: "break down by filters", chart: "line", options: AggregationOptions{HorizontalAxisOperation: "time", VerticalAxisOperation: "count", BreakDownByFilters: []string{"app='kobs'"}}, isInvalid: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			levels, _, _, err := buildAggregation(tc.chart, tc.options, 0, 3600)
+			if tc.isInvalid {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+
+				var columns []string
+				for _, level := range levels {
+					columns = append(columns, level.column)
+				}
+				require.Equal(t, tc.columns, columns)
+			}
+		})
+	}
+}
+
+func TestParseAggregations(t *testing.T) {
+	levels := []aggregationLevel{{column: "namespace"}, {column: "app"}}
+	aggs := map[string]interface{}{
+		"level0": map[string]interface{}{"buckets": []interface{}{
+			map[string]interface{}{"key": "kobs", "doc_count": float64(3), "level1": map[string]interface{}{"buckets": []interface{}{
+				map[string]interface{}{"key": "hub", "doc_count": float64(2), "data": map[string]interface{}{"value": float64(10)}},
+				map[string]interface{}{"key": "satellite", "doc_count": float64(1), "data": map[string]interface{}{"value": nil}},
+			}}},
+		}},
+	}
+
+	require.Equal(t, []map[string]interface{}{
+		{"namespace": "kobs", "app": "hub", "avg_data": float64(10)},
+		{"namespace": "kobs", "app": "satellite"},
+	}, parseAggregations(aggs, levels, 0, "avg", nil))
+}
@@ -15,90 +15,238 @@ import (
 
 var (
 	log = logrus.WithFields(logrus.Fields{"package": "elasticsearch"})
+
+	numberTypes = []string{"long", "integer", "short", "byte", "double", "float", "half_float", "scaled_float", "unsigned_long"}
 )
 
-// Config is the structure of the configuration for a single Elasticsearch instance.
+// Config is the structure of the configuration for a single Elasticsearch instance. The index can be used to search
+// only in the indices matching the given pattern (e.g. "logs-*"). The query language can be "lucene" (default) to use
+// the Elasticsearch query string syntax or "kobs" to use the same query language as in the ClickHouse plugin.
 type Config struct {
-	Name        string `json:"name"`
-	DisplayName string `json:"displayName"`
-	Description string `json:"description"`
-	Address     string `json:"address"`
-	Username    string `json:"username"`
-	Password    string `json:"password"`
-	Token       string `json:"token"`
+	Name          string `json:"name"`
+	DisplayName   string `json:"displayName"`
+	Default       bool   `json:"default"`
+	Description   string `json:"description"`
+	Address       string `json:"address"`
+	Index         string `json:"index"`
+	QueryLanguage string `json:"queryLanguage"`
+	Username      string `json:"username"`
+	Password      string `json:"password"`
+	Token         string `json:"token"`
 }
 
 // Instance represents a single Elasticsearch instance, which can be added via the configuration file.
 type Instance struct {
-	Name    string
-	address string
-	client  *http.Client
+	Name          string
+	Default       bool
+	address       string
+	index         string
+	queryLanguage string
+	client        *http.Client
 }
 
-// GetLogs returns the raw log documents and the buckets for the distribution of the logs accross the selected time
-// range. We have to pass a query, start and end time to the function.
-func (i *Instance) GetLogs(ctx context.Context, query string, timeStart, timeEnd int64) (*Data, error) {
-	var err error
-	var body []byte
-	var url string
+// getURL returns the url for the given Elasticsearch API endpoint. If an index pattern was configured, the endpoint is
+// called for this index pattern.
+func (i *Instance) getURL(endpoint string) string {
+	if i.index != "" {
+		return fmt.Sprintf("%s/%s/%s", i.address, i.index, endpoint)
+	}
 
-	url = fmt.Sprintf("%s/_search", i.address)
-	body = []byte(fmt.Sprintf(`{"size":1000,"sort":[{"@timestamp":{"order":"desc"}}],"query":{"bool":{"must":[{"range":{"@timestamp":{"gte":"%d","lte":"%d"}}},{"query_string":{"query":"%s"}}]}},"aggs":{"logcount":{"auto_date_histogram":{"field":"@timestamp","buckets":30}}}}`, timeStart*1000, timeEnd*1000, strings.ReplaceAll(query, "\"", "\\\"")))
+	return fmt.Sprintf("%s/%s", i.address, endpoint)
+}
 
-	log.WithFields(logrus.Fields{"query": string(body)}).Debugf("Run Elasticsearch query")
+// getQuery returns the query string for Elasticsearch. When the instance uses the kobs query language, we have to
+// translate the query first. An empty query matches all documents.
+func (i *Instance) getQuery(query string) (string, error) {
+	if strings.TrimSpace(query) == "" {
+		return "*", nil
+	}
 
-	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(body))
+	if i.queryLanguage == "kobs" {
+		return parseLogsQuery(query)
+	}
+
+	return query, nil
+}
+
+// do runs a request against the Elasticsearch API and decodes the response into the given value. When the request
+// fails, the error returned by Elasticsearch is returned.
+func (i *Instance) do(ctx context.Context, method, url string, body interface{}, v interface{}) error {
+	var reqBody bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&reqBody).Encode(body); err != nil {
+			return err
+		}
+	}
+
+	log.WithFields(logrus.Fields{"url": url, "body": reqBody.String()}).Debugf("Run Elasticsearch request")
+
+	req, err := http.NewRequestWithContext(ctx, method, url, &reqBody)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	req.Header.Add("Content-Type", "application/json")
 
 	resp, err := i.client.Do(req)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-		var res Response
+		return json.NewDecoder(resp.Body).Decode(v)
+	}
 
-		err = json.NewDecoder(resp.Body).Decode(&res)
-		if err != nil {
-			return nil, err
-		}
+	var res ResponseError
 
-		var hits int64
-		for _, bucket := range res.Aggregations.LogCount.Buckets {
-			hits = hits + bucket.DocCount
-		}
+	err = json.NewDecoder(resp.Body).Decode(&res)
+	if err != nil {
+		return err
+	}
+
+	log.WithFields(logrus.Fields{"type": res.Error.Type, "reason": res.Error.Reason}).Error("The query returned an error.")
+
+	return fmt.Errorf("%s: %s", res.Error.Type, res.Error.Reason)
+}
 
-		data := &Data{
-			Took:      res.Took,
-			Hits:      hits,
-			Documents: res.Hits.Hits,
-			Buckets:   res.Aggregations.LogCount.Buckets,
+// GetLogs returns the log documents and the buckets for the distribution of the logs accross the selected time range.
+// We have to pass a query, the order, start and end time to the function. When the instance uses the kobs query
+// language, the documents are flattened in the same way as they are returned by the ClickHouse plugin, so that the
+// same UI can be used. Otherwise the raw hits from Elasticsearch are returned.
+func (i *Instance) GetLogs(ctx context.Context, query, order, orderBy string, limit, timeStart, timeEnd int64) (*Data, error) {
+	if timeEnd-timeStart <= 0 {
+		return nil, fmt.Errorf("invalid time range")
+	}
+
+	queryString, err := i.getQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	interval := getInterval(timeStart, timeEnd)
+
+	body := map[string]interface{}{
+		"size":             limit,
+		"track_total_hits": true,
+		"sort":             getSort(order, orderBy),
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must": []map[string]interface{}{
+					{"range": map[string]interface{}{"@timestamp": map[string]interface{}{"gte": timeStart * 1000, "lte": timeEnd * 1000, "format": "epoch_millis"}}},
+					{"query_string": map[string]interface{}{"query": queryString}},
+				},
+			},
+		},
+		"aggs": map[string]interface{}{
+			"logcount": map[string]interface{}{
+				"date_histogram": map[string]interface{}{
+					"field":           "@timestamp",
+					"fixed_interval":  fmt.Sprintf("%ds", interval),
+					"min_doc_count":   0,
+					"extended_bounds": map[string]interface{}{"min": timeStart * 1000, "max": timeEnd * 1000},
+				},
+			},
+		},
+	}
+
+	var res Response
+	if err := i.do(ctx, http.MethodPost, i.getURL("_search"), body, &res); err != nil {
+		return nil, err
+	}
+
+	var hits int64
+	var buckets []Bucket
+	for _, bucket := range res.Aggregations.LogCount.Buckets {
+		hits = hits + bucket.DocCount
+		bucket.Interval = bucket.Key / 1000
+		bucket.Count = bucket.DocCount
+		buckets = append(buckets, bucket)
+	}
+
+	var fields []string
+	var documents []map[string]interface{}
+	for _, hit := range res.Hits.Hits {
+		document := make(map[string]interface{})
+		if source, ok := hit["_source"].(map[string]interface{}); ok {
+			fields = flattenDocument("", source, document, fields)
 		}
 
-		log.WithFields(logrus.Fields{"took": data.Took, "hits": data.Hits, "documents": len(data.Documents), "buckets": len(data.Buckets)}).Debugf("Elasticsearch query results")
+		if i.queryLanguage == "kobs" {
+			document["timestamp"] = document["@timestamp"]
+			documents = append(documents, document)
+		} else {
+			documents = append(documents, hit)
+		}
+	}
 
-		return data, nil
+	data := &Data{
+		Took:      res.Took,
+		Hits:      hits,
+		Count:     res.Hits.Total.Value,
+		Fields:    sortedFields(fields),
+		Documents: documents,
+		Buckets:   buckets,
 	}
 
-	var res ResponseError
+	log.WithFields(logrus.Fields{"took": data.Took, "hits": data.Hits, "documents": len(data.Documents), "buckets": len(data.Buckets)}).Debugf("Elasticsearch query results")
 
-	err = json.NewDecoder(resp.Body).Decode(&res)
-	if err != nil {
+	return data, nil
+}
+
+// GetFields returns all fields from the mapping of the configured index pattern, which are containing the filter term.
+// The fieldType can be "string" or "number" to only return the fields of the given type.
+func (i *Instance) GetFields(ctx context.Context, filter, fieldType string) ([]string, error) {
+	var res map[string]Mapping
+	if err := i.do(ctx, http.MethodGet, i.getURL("_mapping"), nil, &res); err != nil {
 		return nil, err
 	}
 
-	log.WithFields(logrus.Fields{"type": res.Error.Type, "reason": res.Error.Reason}).Error("The query returned an error.")
+	var fields []string
+	for _, mapping := range res {
+		fields = getMappingFields("", mapping.Mappings.Properties, filter, fieldType, fields)
+	}
+
+	return sortedFields(fields), nil
+}
+
+// getMappingFields walks through the properties of a mapping and returns all fields matching the filter and type.
+func getMappingFields(prefix string, properties map[string]Property, filter, fieldType string, fields []string) []string {
+	for name, property := range properties {
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+
+		if property.Properties != nil {
+			fields = getMappingFields(name, property.Properties, filter, fieldType, fields)
+			continue
+		}
+
+		isNumber := false
+		for _, numberType := range numberTypes {
+			if property.Type == numberType {
+				isNumber = true
+			}
+		}
+
+		if (fieldType == "number" && !isNumber) || (fieldType == "string" && isNumber) {
+			continue
+		}
+
+		if strings.Contains(name, filter) {
+			fields = appendIfMissing(fields, name)
+		}
+	}
 
-	return nil, fmt.Errorf("%s: %s", res.Error.Type, res.Error.Reason)
+	return fields
 }
 
 // New returns a new Elasticsearch instance for the given configuration.
 func New(config Config) (*Instance, error) {
+	if config.QueryLanguage != "" && config.QueryLanguage != "lucene" && config.QueryLanguage != "kobs" {
+		return nil, fmt.Errorf("invalid query language %s", config.QueryLanguage)
+	}
+
 	roundTripper := roundtripper.DefaultRoundTripper
 
 	if config.Username != "" && config.Password != "" {
@@ -117,8 +265,11 @@ func New(config Config) (*Instance, error) {
 	}
 
 	return &Instance{
-		Name:    config.Name,
-		address: config.Address,
+		Name:          config.Name,
+		Default:       config.Default,
+		address:       config.Address,
+		index:         config.Index,
+		queryLanguage: config.QueryLanguage,
 		client: &http.Client{
 			Transport: roundTripper,
 		},
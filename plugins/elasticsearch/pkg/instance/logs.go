@@ -0,0 +1,236 @@
+package instance
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// parseLogsQuery parses the given query in the kobs query language and returns the corresponding query in the
+// Elasticsearch query string syntax. The kobs query language is the same as it is used in the ClickHouse plugin, so
+// that the user can use "(", ")", "_not_", "_and_" and "_or_" operators. Then we are splitting the string again for the
+// other operators "=", "!=", ">", ">=", "<", "<=", "=~", "!~" and "~" which are used to check the value of a field.
+func parseLogsQuery(query string) (string, error) {
+	var newOpenBrackets []string
+	openBrackets := strings.Split(query, "(")
+	for _, openBracket := range openBrackets {
+		var newCloseBrackets []string
+		closeBrackets := strings.Split(openBracket, ")")
+		for _, closeBracket := range closeBrackets {
+			var newNots []string
+			nots := strings.Split(closeBracket, "_not_")
+			for _, not := range nots {
+				var newAnds []string
+				ands := strings.Split(not, "_and_")
+				for _, and := range ands {
+					var newOrs []string
+					ors := strings.Split(and, "_or_")
+					for _, or := range ors {
+						condition, err := splitOperator(or)
+						if err != nil {
+							return "", err
+						}
+
+						newOrs = append(newOrs, condition)
+					}
+					newAnds = append(newAnds, strings.Join(newOrs, " OR "))
+				}
+				newNots = append(newNots, strings.Join(newAnds, " AND "))
+			}
+			newCloseBrackets = append(newCloseBrackets, strings.Join(newNots, " NOT "))
+		}
+		newOpenBrackets = append(newOpenBrackets, strings.Join(newCloseBrackets, ")"))
+	}
+
+	return strings.Join(newOpenBrackets, "("), nil
+}
+
+// splitOperator splits the given string by the following operators "=", "!=", ">", ">=", "<", "<=", "=~", "!~" and
+// "~". If the result is a slice with two items we found the operator which was used by the user to check the value of a
+// field. So that we pass the key (first item), value (second item) and the operator to the handleConditionParts to
+// build the condition.
+func splitOperator(condition string) (string, error) {
+	greaterThanOrEqual := strings.Split(condition, ">=")
+	if len(greaterThanOrEqual) == 2 {
+		return handleConditionParts(greaterThanOrEqual[0], greaterThanOrEqual[1], ">=")
+	}
+
+	greaterThan := strings.Split(condition, ">")
+	if len(greaterThan) == 2 {
+		return handleConditionParts(greaterThan[0], greaterThan[1], ">")
+	}
+
+	lessThanOrEqual := strings.Split(condition, "<=")
+	if len(lessThanOrEqual) == 2 {
+		return handleConditionParts(lessThanOrEqual[0], lessThanOrEqual[1], "<=")
+	}
+
+	lessThan := strings.Split(condition, "<")
+	if len(lessThan) == 2 {
+		return handleConditionParts(lessThan[0], lessThan[1], "<")
+	}
+
+	ilike := strings.Split(condition, "=~")
+	if len(ilike) == 2 {
+		return handleConditionParts(ilike[0], ilike[1], "=~")
+	}
+
+	notEqual := strings.Split(condition, "!=")
+	if len(notEqual) == 2 {
+		return handleConditionParts(notEqual[0], notEqual[1], "!=")
+	}
+
+	notIlike := strings.Split(condition, "!~")
+	if len(notIlike) == 2 {
+		return handleConditionParts(notIlike[0], notIlike[1], "!~")
+	}
+
+	regex := strings.Split(condition, "~")
+	if len(regex) == 2 {
+		return handleConditionParts(regex[0], regex[1], "~")
+	}
+
+	equal := strings.Split(condition, "=")
+	if len(equal) == 2 {
+		return handleConditionParts(equal[0], equal[1], "=")
+	}
+
+	if strings.Contains(condition, "_exists_ ") {
+		return fmt.Sprintf("_exists_:%s", strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(condition), "_exists_ "))), nil
+	}
+
+	if strings.TrimSpace(condition) == "" {
+		return "", nil
+	}
+
+	return "", fmt.Errorf("invalid operator: %s", condition)
+}
+
+// handleConditionParts converts the given key, value and operator to it's representation in the Elasticsearch query
+// string syntax. String values are using single quotes in the kobs query language, which must be replaced with double
+// quotes. For the "=~" and "!~" operators the "%" wildcard from the kobs query language is replaced with the "*"
+// wildcard and for the "~" operator the value is used as regular expression.
+func handleConditionParts(key, value, operator string) (string, error) {
+	key = strings.TrimSpace(key)
+	value = strings.TrimSpace(value)
+
+	if key == "" || value == "" {
+		return "", fmt.Errorf("invalid condition: %s %s %s", key, operator, value)
+	}
+
+	unquotedValue := strings.TrimSuffix(strings.TrimPrefix(value, "'"), "'")
+
+	switch operator {
+	case "=":
+		return fmt.Sprintf("%s:%s", key, quoteValue(value)), nil
+	case "!=":
+		return fmt.Sprintf("NOT %s:%s", key, quoteValue(value)), nil
+	case ">", ">=", "<", "<=":
+		return fmt.Sprintf("%s:%s%s", key, operator, quoteValue(value)), nil
+	case "=~":
+		return fmt.Sprintf("%s:%s", key, wildcardValue(unquotedValue)), nil
+	case "!~":
+		return fmt.Sprintf("NOT %s:%s", key, wildcardValue(unquotedValue)), nil
+	case "~":
+		return fmt.Sprintf("%s:/%s/", key, strings.ReplaceAll(unquotedValue, "/", "\\/")), nil
+	}
+
+	return "", fmt.Errorf("invalid operator: %s", operator)
+}
+
+// quoteValue replaces the single quotes of a string value with double quotes. Values without single quotes (e.g.
+// numbers) are returned as they are.
+func quoteValue(value string) string {
+	if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+		return fmt.Sprintf("\"%s\"", strings.ReplaceAll(value[1:len(value)-1], "\"", "\\\""))
+	}
+
+	return value
+}
+
+// wildcardValue converts the value of an "=~" or "!~" condition into a wildcard query. The "%" wildcard is replaced
+// with "*" and spaces are escaped, because a wildcard query can not be quoted.
+func wildcardValue(value string) string {
+	value = strings.ReplaceAll(value, "\\%", "\u0000")
+	value = strings.ReplaceAll(value, "%", "*")
+	value = strings.ReplaceAll(value, "\u0000", "%")
+	return strings.ReplaceAll(value, " ", "\\ ")
+}
+
+// flattenDocument converts the nested source of an Elasticsearch document into a flat map, where the keys of nested
+// objects are joined with a dot (e.g. "kubernetes.namespace"). This is the same format as it is used for the documents
+// returned by the ClickHouse plugin. All keys are also added to the given fields slice.
+func flattenDocument(prefix string, source map[string]interface{}, document map[string]interface{}, fields []string) []string {
+	for key, value := range source {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		if nested, ok := value.(map[string]interface{}); ok {
+			fields = flattenDocument(key, nested, document, fields)
+			continue
+		}
+
+		document[key] = value
+		fields = appendIfMissing(fields, key)
+	}
+
+	return fields
+}
+
+// getSort returns the sort statement for the Elasticsearch query. If the user doesn't provide a custom order, the
+// documents are sorted by the timestamp in descending order.
+func getSort(order, orderBy string) []map[string]interface{} {
+	if order == "" || orderBy == "" {
+		return []map[string]interface{}{{"@timestamp": map[string]interface{}{"order": "desc"}}}
+	}
+
+	if order == "ascending" {
+		order = "asc"
+	} else {
+		order = "desc"
+	}
+
+	return []map[string]interface{}{{strings.TrimSpace(orderBy): map[string]interface{}{"order": order, "unmapped_type": "keyword"}}}
+}
+
+// getInterval returns the interval in seconds for the buckets of the given time range. By default we are creating 30
+// buckets, but for time ranges with less then 30 seconds we have to create less buckets. This is the same logic as it
+// is used in the ClickHouse plugin.
+func getInterval(timeStart, timeEnd int64) int64 {
+	var interval int64
+
+	switch seconds := timeEnd - timeStart; {
+	case seconds <= 2:
+		interval = (timeEnd - timeStart) / 1
+	case seconds <= 10:
+		interval = (timeEnd - timeStart) / 5
+	case seconds <= 30:
+		interval = (timeEnd - timeStart) / 10
+	default:
+		interval = (timeEnd - timeStart) / 30
+	}
+
+	if interval < 1 {
+		interval = 1
+	}
+
+	return interval
+}
+
+// appendIfMissing appends a value to a slice, when this values doesn't exist in the slice already.
+func appendIfMissing(items []string, item string) []string {
+	for _, ele := range items {
+		if ele == item {
+			return items
+		}
+	}
+
+	return append(items, item)
+}
+
+// sortedFields returns the given fields sorted alphabetically.
+func sortedFields(fields []string) []string {
+	sort.Strings(fields)
+	return fields
+}
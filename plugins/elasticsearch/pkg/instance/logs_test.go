@@ -0,0 +1,41 @@
+package instance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLogsQuery(t *testing.T) {
+	for _, tc := range []struct {
+		query     string
+		result    string
+		isInvalid bool
+	}{
+		{query: "cluster = 'foo' _and_ namespace = 'bar'", result: "cluster:\"foo\" AND namespace:\"bar\"", isInvalid: false},
+		{query: "cluster = 'foo' _and_ (namespace='hello' _or_ namespace='world')", result: "cluster:\"foo\" AND (namespace:\"hello\" OR namespace:\"world\")", isInvalid: false},
+		{query: "content.response_code >= 500 _and_ content.method != 'GET'", result: "content.response_code:>=500 AND NOT content.method:\"GET\"", isInvalid: false},
+		{query: "content.path =~ '%/api/%'", result: "content.path:*/api/*", isInvalid: false},
+		{query: "content.path ~ 'hello.*'", result: "content.path:/hello.*/", isInvalid: false},
+		{query: "_exists_ content.method", result: "_exists_:content.method", isInvalid: false},
+		{query: "cluster 'foo'", isInvalid: true},
+	} {
+		t.Run(tc.query, func(t *testing.T) {
+			result, err := parseLogsQuery(tc.query)
+			if tc.isInvalid {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.result, result)
+			}
+		})
+	}
+}
+
+func TestFlattenDocument(t *testing.T) {
+	document := make(map[string]interface{})
+	fields := flattenDocument("", map[string]interface{}{"log": "hello world", "kubernetes": map[string]interface{}{"namespace": "kobs", "labels": map[string]interface{}{"app": "kobs"}}}, document, nil)
+
+	require.Equal(t, map[string]interface{}{"log": "hello world", "kubernetes.namespace": "kobs", "kubernetes.labels.app": "kobs"}, document)
+	require.Equal(t, []string{"kubernetes.labels.app", "kubernetes.namespace", "log"}, sortedFields(fields))
+}
@@ -25,11 +25,28 @@ type Response struct {
 	} `json:"aggregations"`
 }
 
-// Bucket is the structure of a bucket returned by the Elasticsearch API.
+// Bucket is the structure of a bucket returned by the Elasticsearch API. The interval (in seconds) and count fields are
+// set by kobs, so that the buckets have the same format as the buckets returned by the ClickHouse plugin.
 type Bucket struct {
 	KeyAsString string `json:"key_as_string"`
 	Key         int64  `json:"key"`
 	DocCount    int64  `json:"doc_count"`
+	Interval    int64  `json:"interval"`
+	Count       int64  `json:"count"`
+}
+
+// Mapping is the structure of the mapping for a single index returned by the Elasticsearch API.
+type Mapping struct {
+	Mappings struct {
+		Properties map[string]Property `json:"properties"`
+	} `json:"mappings"`
+}
+
+// Property is a single field in the mapping of an index. Objects are containing the nested properties instead of a
+// type.
+type Property struct {
+	Type       string              `json:"type"`
+	Properties map[string]Property `json:"properties"`
 }
 
 // ResponseError is the structure of failed Elasticsearch API call.
@@ -50,10 +67,13 @@ type ResponseError struct {
 }
 
 // Data is the transformed Response result, which is passed to the React UI. It contains only the important fields, like
-// the time a request took, the number of hits, the documents and the buckets.
+// the time a request took, the number of hits, the documents and the buckets. The count and fields are using the same
+// format as the ClickHouse plugin.
 type Data struct {
 	Took      int64                    `json:"took"`
 	Hits      int64                    `json:"hits"`
+	Count     int64                    `json:"count"`
+	Fields    []string                 `json:"fields"`
 	Documents []map[string]interface{} `json:"documents"`
 	Buckets   []Bucket                 `json:"buckets"`
 }
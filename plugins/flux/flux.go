@@ -38,9 +38,9 @@ func (router *Router) sync(w http.ResponseWriter, r *http.Request) {
 
 	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "name": name, "resource": resource}).Tracef("sync")
 
-	cluster := router.clusters.GetCluster(clusterName)
-	if cluster == nil {
-		errresponse.Render(w, r, nil, http.StatusBadRequest, "Invalid cluster name")
+	cluster, err := router.clusters.GetCluster(clusterName)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
 		return
 	}
 
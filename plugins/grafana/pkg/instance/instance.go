@@ -20,6 +20,7 @@ var (
 type Config struct {
 	Name            string `json:"name"`
 	DisplayName     string `json:"displayName"`
+	Default         bool   `json:"default"`
 	Description     string `json:"description"`
 	InternalAddress string `json:"internalAddress"`
 	PublicAddress   string `json:"publicAddress"`
@@ -31,6 +32,7 @@ type Config struct {
 // Instance represents a single Grafana instance, which can be added via the configuration file.
 type Instance struct {
 	Name    string
+	Default bool
 	address string
 	client  *http.Client
 }
@@ -124,6 +126,7 @@ func New(config Config) (*Instance, error) {
 
 	return &Instance{
 		Name:    config.Name,
+		Default: config.Default,
 		address: config.InternalAddress,
 		client: &http.Client{
 			Transport: roundTripper,
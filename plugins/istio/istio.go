@@ -7,7 +7,6 @@ import (
 	"github.com/kobsio/kobs/pkg/api/clusters"
 	"github.com/kobsio/kobs/pkg/api/middleware/errresponse"
 	"github.com/kobsio/kobs/pkg/api/plugins/plugin"
-	clickhouseInstance "github.com/kobsio/kobs/plugins/clickhouse/pkg/instance"
 	"github.com/kobsio/kobs/plugins/istio/pkg/instance"
 	prometheusInstance "github.com/kobsio/kobs/plugins/prometheus/pkg/instance"
 
@@ -35,14 +34,17 @@ type Router struct {
 	instances []*instance.Instance
 }
 
+// getInstance returns the instance with the given name. If no instance has the given name and the name is empty or
+// "default", the default instance is returned.
 func (router *Router) getInstance(name string) *instance.Instance {
-	for _, i := range router.instances {
-		if i.Name == name {
-			return i
-		}
+	index := plugin.GetInstanceIndex(name, len(router.instances), func(i int) (string, bool) {
+		return router.instances[i].Name, router.instances[i].Default
+	})
+	if index == -1 {
+		return nil
 	}
 
-	return nil
+	return router.instances[index]
 }
 
 func (router *Router) getNamespaces(w http.ResponseWriter, r *http.Request) {
@@ -370,11 +372,11 @@ func (router *Router) getTopDetails(w http.ResponseWriter, r *http.Request) {
 }
 
 // Register returns a new router which can be used in the router for the kobs rest api.
-func Register(clusters *clusters.Clusters, plugins *plugin.Plugins, config Config, prometheusInstances []*prometheusInstance.Instance, clickhouseInstances []*clickhouseInstance.Instance) chi.Router {
+func Register(clusters *clusters.Clusters, plugins *plugin.Plugins, config Config, prometheusInstances []*prometheusInstance.Instance, getClickhouseInstance instance.ClickhouseInstanceGetter) chi.Router {
 	var instances []*instance.Instance
 
 	for _, cfg := range config {
-		instance, err := instance.New(cfg, prometheusInstances, clickhouseInstances)
+		instance, err := instance.New(cfg, prometheusInstances, getClickhouseInstance)
 		if err != nil {
 			log.WithError(err).WithFields(logrus.Fields{"name": cfg.Name}).Fatalf("Could not create Istio instance")
 		}
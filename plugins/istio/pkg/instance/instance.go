@@ -19,6 +19,7 @@ var (
 type Config struct {
 	Name        string           `json:"name"`
 	DisplayName string           `json:"displayName"`
+	Default     bool             `json:"default"`
 	Description string           `json:"description"`
 	Prometheus  ConfigPrometheus `json:"prometheus"`
 	Clickhouse  ConfigClickhouse `json:"clickhouse"`
@@ -38,11 +39,32 @@ type ConfigClickhouse struct {
 	Name    string `json:"name"`
 }
 
-// Instance represents a single Jaeger instance, which can be added via the configuration file.
+// ClickhouseInstanceGetter returns the ClickHouse instance with the given name or nil, when the instance doesn't exist.
+type ClickhouseInstanceGetter func(name string) *clickhouseInstance.Instance
+
+// Instance represents a single Jaeger instance, which can be added via the configuration file. The ClickHouse instance
+// is not stored in the Istio instance, but retrieved via the getClickhouseInstance function for each request, so that
+// the Istio plugin also uses the ClickHouse instances after they were reloaded.
 type Instance struct {
-	Name       string
-	prometheus *prometheusInstance.Instance
-	clickhouse *clickhouseInstance.Instance
+	Name                  string
+	Default               bool
+	prometheus            *prometheusInstance.Instance
+	clickhouseName        string
+	getClickhouseInstance ClickhouseInstanceGetter
+}
+
+// getClickhouse returns the current ClickHouse instance, which is used by the Istio instance.
+func (i *Instance) getClickhouse() (*clickhouseInstance.Instance, error) {
+	if i.getClickhouseInstance == nil {
+		return nil, fmt.Errorf("Clickhouse integration is not enabled")
+	}
+
+	clickhouse := i.getClickhouseInstance(i.clickhouseName)
+	if clickhouse == nil {
+		return nil, fmt.Errorf("Clickhouse instance \"%s\" was not found", i.clickhouseName)
+	}
+
+	return clickhouse, nil
 }
 
 // GetNamespaces returns a list of namespaces, which can be selected to get the applications from.
@@ -313,7 +335,12 @@ func (i *Instance) Tap(ctx context.Context, namespace, application, filterUpstre
 		filters = filters + fmt.Sprintf(" _and_ content.path~'%s'", filterPath)
 	}
 
-	logs, _, _, _, _, err := i.clickhouse.GetLogs(ctx, fmt.Sprintf("namespace='%s' _and_ app='%s' _and_ container_name='istio-proxy' %s", namespace, application, filters), "", "", 100, timeStart, timeEnd)
+	clickhouse, err := i.getClickhouse()
+	if err != nil {
+		return nil, err
+	}
+
+	logs, _, _, _, _, err := clickhouse.GetLogs(ctx, fmt.Sprintf("namespace='%s' _and_ app='%s' _and_ container_name='istio-proxy' %s", namespace, application, filters), "", "", 100, timeStart, timeEnd)
 	if err != nil {
 		return nil, err
 	}
@@ -334,7 +361,12 @@ func (i *Instance) Top(ctx context.Context, namespace, application, filterUpstre
 		filters = filters + fmt.Sprintf(" AND match(fields_string.value[indexOf(fields_string.key, 'content.path')], '%s')", filterPath)
 	}
 
-	rows, _, err := i.clickhouse.GetRawQueryResults(ctx, fmt.Sprintf(`SELECT
+	clickhouse, err := i.getClickhouse()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, _, err := clickhouse.GetRawQueryResults(ctx, fmt.Sprintf(`SELECT
     fields_string.value[indexOf(fields_string.key, 'content.upstream_cluster')] as upstream,
     fields_string.value[indexOf(fields_string.key, 'content.method')] as method,
     path(fields_string.value[indexOf(fields_string.key, 'content.path')]) as path,
@@ -375,7 +407,12 @@ func (i *Instance) TopDetails(ctx context.Context, namespace, application, upstr
 		filters = filters + fmt.Sprintf(" AND path(fields_string.value[indexOf(fields_string.key, 'content.path')]) = '%s'", path)
 	}
 
-	rows, _, err := i.clickhouse.GetRawQueryResults(ctx, fmt.Sprintf(`SELECT
+	clickhouse, err := i.getClickhouse()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, _, err := clickhouse.GetRawQueryResults(ctx, fmt.Sprintf(`SELECT
     toStartOfInterval(timestamp, INTERVAL %d second) AS interval_data,
     count(*) AS count_data,
     countIf(fields_number.value[indexOf(fields_number.key, 'content.response_code')] < 500) / count_data * 100 as sr_data,
@@ -396,9 +433,8 @@ SETTINGS skip_unavailable_shards = 1`, interval, timeStart, timeEnd, filters, ti
 }
 
 // New returns a new Elasticsearch instance for the given configuration.
-func New(config Config, prometheusInstances []*prometheusInstance.Instance, clickhouseInstances []*clickhouseInstance.Instance) (*Instance, error) {
+func New(config Config, prometheusInstances []*prometheusInstance.Instance, getClickhouseInstance ClickhouseInstanceGetter) (*Instance, error) {
 	var prometheusInstance *prometheusInstance.Instance
-	var clickhouseInstance *clickhouseInstance.Instance
 
 	if config.Prometheus.Enabled {
 		for _, instance := range prometheusInstances {
@@ -413,20 +449,16 @@ func New(config Config, prometheusInstances []*prometheusInstance.Instance, clic
 	}
 
 	if config.Clickhouse.Enabled {
-		for _, instance := range clickhouseInstances {
-			if instance.Name == config.Clickhouse.Name {
-				clickhouseInstance = instance
-			}
-		}
-
-		if clickhouseInstance == nil {
+		if getClickhouseInstance(config.Clickhouse.Name) == nil {
 			return nil, fmt.Errorf("Clickhouse instance \"%s\" was not found", config.Clickhouse.Name)
 		}
 	}
 
 	return &Instance{
-		Name:       config.Name,
-		prometheus: prometheusInstance,
-		clickhouse: clickhouseInstance,
+		Name:                  config.Name,
+		Default:               config.Default,
+		prometheus:            prometheusInstance,
+		clickhouseName:        config.Clickhouse.Name,
+		getClickhouseInstance: getClickhouseInstance,
 	}, nil
 }
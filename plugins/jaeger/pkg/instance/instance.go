@@ -19,6 +19,7 @@ var (
 type Config struct {
 	Name        string `json:"name"`
 	DisplayName string `json:"displayName"`
+	Default     bool   `json:"default"`
 	Description string `json:"description"`
 	Address     string `json:"address"`
 	Username    string `json:"username"`
@@ -37,6 +38,7 @@ type ResponseError struct {
 // Instance represents a single Jaeger instance, which can be added via the configuration file.
 type Instance struct {
 	Name    string
+	Default bool
 	address string
 	client  *http.Client
 }
@@ -118,6 +120,7 @@ func New(config Config) (*Instance, error) {
 
 	return &Instance{
 		Name:    config.Name,
+		Default: config.Default,
 		address: config.Address,
 		client: &http.Client{
 			Transport: roundTripper,
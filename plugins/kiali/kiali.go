@@ -31,14 +31,17 @@ type Router struct {
 	instances []*instance.Instance
 }
 
+// getInstance returns the instance with the given name. If no instance has the given name and the name is empty or
+// "default", the default instance is returned.
 func (router *Router) getInstance(name string) *instance.Instance {
-	for _, i := range router.instances {
-		if i.Name == name {
-			return i
-		}
+	index := plugin.GetInstanceIndex(name, len(router.instances), func(i int) (string, bool) {
+		return router.instances[i].Name, router.instances[i].Default
+	})
+	if index == -1 {
+		return nil
 	}
 
-	return nil
+	return router.instances[index]
 }
 
 func (router *Router) getNamespaces(w http.ResponseWriter, r *http.Request) {
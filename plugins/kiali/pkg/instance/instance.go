@@ -24,6 +24,7 @@ var (
 type Config struct {
 	Name        string  `json:"name"`
 	DisplayName string  `json:"displayName"`
+	Default     bool    `json:"default"`
 	Description string  `json:"description"`
 	Address     string  `json:"address"`
 	Username    string  `json:"username"`
@@ -87,6 +88,7 @@ type ResponseError struct {
 // Instance represents a single Kiali instance, which can be added via the configuration file.
 type Instance struct {
 	Name    string
+	Default bool
 	address string
 	client  *http.Client
 	traffic Traffic
@@ -296,6 +298,7 @@ func New(config Config) (*Instance, error) {
 
 	return &Instance{
 		Name:    config.Name,
+		Default: config.Default,
 		address: config.Address,
 		client: &http.Client{
 			Transport: roundTripper,
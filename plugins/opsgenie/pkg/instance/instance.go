@@ -20,6 +20,7 @@ var (
 type Config struct {
 	Name        string  `json:"name"`
 	DisplayName string  `json:"displayName"`
+	Default     bool    `json:"default"`
 	Description string  `json:"description"`
 	APIKey      string  `json:"apiKey"`
 	APIUrl      string  `json:"apiUrl"`
@@ -37,6 +38,7 @@ type Actions struct {
 // Instance represents a single Jaeger instance, which can be added via the configuration file.
 type Instance struct {
 	Name           string
+	Default        bool
 	Actions        Actions
 	alertClient    *alert.Client
 	incidentClient *incident.Client
@@ -213,6 +215,7 @@ func New(config Config) (*Instance, error) {
 
 	return &Instance{
 		Name:           config.Name,
+		Default:        config.Default,
 		Actions:        config.Actions,
 		alertClient:    alertClient,
 		incidentClient: incidentClient,
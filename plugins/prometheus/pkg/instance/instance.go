@@ -24,6 +24,7 @@ var (
 type Config struct {
 	Name        string `json:"name"`
 	DisplayName string `json:"displayName"`
+	Default     bool   `json:"default"`
 	Description string `json:"description"`
 	Address     string `json:"address"`
 	Username    string `json:"username"`
@@ -34,6 +35,7 @@ type Config struct {
 // Instance represents a single Prometheus instance, which can be added via the configuration file.
 type Instance struct {
 	Name                 string
+	Default              bool
 	labelValues          model.LabelValues
 	labelValuesLastFetch time.Time
 	v1api                v1.API
@@ -257,6 +259,28 @@ func (i *Instance) GetTableData(ctx context.Context, queries []Query, timeEnd in
 	return rows, nil
 }
 
+// HasResults runs the given query at the current time and returns true, when the query returned at least one sample.
+// This can be used to evaluate expressions, which are written like the expression of a Prometheus alerting rule.
+func (i *Instance) HasResults(ctx context.Context, query string) (bool, error) {
+	log.WithFields(logrus.Fields{"query": query}).Tracef("Query results")
+
+	result, _, err := i.v1api.Query(ctx, query, time.Now())
+	if err != nil {
+		return false, err
+	}
+
+	switch value := result.(type) {
+	case model.Vector:
+		return len(value) > 0, nil
+	case model.Matrix:
+		return len(value) > 0, nil
+	case *model.Scalar:
+		return value.Value != 0, nil
+	default:
+		return false, fmt.Errorf("unsupported result type %s", result.Type())
+	}
+}
+
 // GetLabelValues returns all label values for a configured Prometheus instance. These labels are used to show the user
 // a list of suggestions for his entered query. The returned label values from the Prometheus API are cached for one
 // hour.
@@ -315,7 +339,20 @@ func New(config Config) (*Instance, error) {
 	}
 
 	return &Instance{
-		Name:  config.Name,
-		v1api: v1.NewAPI(client),
+		Name:    config.Name,
+		Default: config.Default,
+		v1api:   v1.NewAPI(client),
 	}, nil
 }
+
+// Test checks if the Prometheus instance with the given configuration is reachable, by running a simple query against
+// the instance. It can be used to test a configuration without adding the instance.
+func Test(ctx context.Context, config Config) error {
+	i, err := New(config)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = i.v1api.Query(ctx, "1", time.Now())
+	return err
+}
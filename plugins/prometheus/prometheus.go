@@ -1,10 +1,14 @@
 package prometheus
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/kobsio/kobs/pkg/api/clusters"
+	authContext "github.com/kobsio/kobs/pkg/api/middleware/auth/context"
 	"github.com/kobsio/kobs/pkg/api/middleware/errresponse"
 	"github.com/kobsio/kobs/pkg/api/plugins/plugin"
 	"github.com/kobsio/kobs/plugins/prometheus/pkg/instance"
@@ -17,6 +21,9 @@ import (
 // Route is the route under which the plugin should be registered in our router for the rest api.
 const Route = "/prometheus"
 
+// testTimeout is the timeout for testing the connection to a Prometheus instance.
+const testTimeout = 10 * time.Second
+
 var (
 	log = logrus.WithFields(logrus.Fields{"package": "prometheus"})
 )
@@ -50,14 +57,17 @@ type getMetricsRequest struct {
 	TimeEnd    int64            `json:"timeEnd"`
 }
 
+// getInstance returns the instance with the given name. If no instance has the given name and the name is empty or
+// "default", the default instance is returned.
 func (router *Router) getInstance(name string) *instance.Instance {
-	for _, i := range router.instances {
-		if i.Name == name {
-			return i
-		}
+	index := plugin.GetInstanceIndex(name, len(router.instances), func(i int) (string, bool) {
+		return router.instances[i].Name, router.instances[i].Default
+	})
+	if index == -1 {
+		return nil
 	}
 
-	return nil
+	return router.instances[index]
 }
 
 // getVariable returns a list of variable values for a given label and query. The query and label are provided in the
@@ -181,6 +191,40 @@ func (router *Router) getLabels(w http.ResponseWriter, r *http.Request) {
 	render.JSON(w, r, labelValues)
 }
 
+// testInstance tests the connection to a Prometheus instance with the configuration from the request body, without adding
+// the instance to the plugin. Since this can be used to send requests to any address, it can only be used by admins,
+// which are only available when authentication is enabled.
+func (router *Router) testInstance(w http.ResponseWriter, r *http.Request) {
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to test the instance")
+		return
+	}
+
+	log.WithFields(logrus.Fields{"user": user.ID}).Tracef("testInstance")
+
+	if !user.IsAdmin {
+		errresponse.Render(w, r, fmt.Errorf("user is not an admin"), http.StatusForbidden, "You are not allowed to test the instance")
+		return
+	}
+
+	var config instance.Config
+	if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not decode request body")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), testTimeout)
+	defer cancel()
+
+	if err := instance.Test(ctx, config); err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Connection test failed")
+		return
+	}
+
+	render.JSON(w, r, nil)
+}
+
 // Register returns a new router which can be used in the router for the kobs rest api.
 func Register(clusters *clusters.Clusters, plugins *plugin.Plugins, config Config) (chi.Router, []*instance.Instance) {
 	var instances []*instance.Instance
@@ -211,6 +255,7 @@ func Register(clusters *clusters.Clusters, plugins *plugin.Plugins, config Confi
 	router.Post("/metrics/{name}", router.getMetrics)
 	router.Post("/table/{name}", router.getTable)
 	router.Get("/labels/{name}", router.getLabels)
+	router.Post("/test", router.testInstance)
 
 	return router, instances
 }
@@ -1,14 +1,24 @@
 package resources
 
 import (
+	"compress/gzip"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"path/filepath"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/kobsio/kobs/pkg/api/clusters"
+	clusterPkg "github.com/kobsio/kobs/pkg/api/clusters/cluster"
 	"github.com/kobsio/kobs/pkg/api/clusters/cluster/terminal"
 	authContext "github.com/kobsio/kobs/pkg/api/middleware/auth/context"
 	"github.com/kobsio/kobs/pkg/api/middleware/errresponse"
@@ -19,36 +29,191 @@ import (
 	"github.com/gorilla/websocket"
 	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 // Route is the route under which the plugin should be registered in our router for the rest api.
 const Route = "/resources"
 
+// StreamingRoutes are the routes of the plugin, which are streaming their response to the client via Server-Sent Events
+// or as file download. The configured timeout for the plugin is not applied to these routes.
+var StreamingRoutes = []string{"/resources/events", "/resources/stream", "/rollout", "/events/watch", "/logs/download"}
+
+const (
+	// searchConcurrency is the maximum number of clusters, which are searched in parallel by the searchResources
+	// function.
+	searchConcurrency = 5
+	// searchDefaultLimit and searchMaxLimit are the default and the maximum number of results, which are returned by
+	// the searchResources function.
+	searchDefaultLimit = 100
+	searchMaxLimit     = 1000
+	// defaultMaxMessageSize is the maximum size of a WebSocket message for the log stream, when no size was
+	// configured. Longer log lines are split across multiple messages.
+	defaultMaxMessageSize = 64 * 1024
+	// defaultMaxLogStreams is the maximum number of Pods, for which the logs are streamed at the same time, when the
+	// logs of a workload are requested and no limit was configured.
+	defaultMaxLogStreams = 10
+	// defaultMaxEventsWatches is the maximum number of watches (clusters times namespaces), which can be used for the
+	// merged events feed, when no limit was configured.
+	defaultMaxEventsWatches = 20
+	// namespacesConcurrency is the maximum number of namespaces, for which the resources are retrieved in parallel by
+	// the streamResources function.
+	namespacesConcurrency = 10
+	// namespacesCacheDuration is the duration for which the list of namespaces is cached, when the streamResources
+	// function must get the resources for all namespaces of a cluster.
+	namespacesCacheDuration = 5 * time.Minute
+)
+
 var (
 	log        = logrus.WithFields(logrus.Fields{"package": "resources"})
 	pingPeriod = 30 * time.Second
+
+	// listParams is the list of query parameters, which are passed through to the Kubernetes API server by the
+	// getResources function.
+	listParams = []string{"labelSelector", "fieldSelector", "limit", "continue", "resourceVersion", "resourceVersionMatch", "timeoutSeconds"}
 )
 
 // Resources is the structure for the getResources api call. It contains the cluster, namespace and the json
-// representation of the retunred list object from the Kuberntes API.
+// representation of the retunred list object from the Kuberntes API. The warnings field contains all warnings, which
+// were returned by the Kubernetes API (e.g. when a deprecated API version is used).
+// The ages field contains the creation timestamp and the age of each returned object at the same index as the object
+// in the items of the list. We do not add the age to the objects, because the returned objects must not be modified.
 type Resources struct {
 	Cluster   string                 `json:"cluster"`
 	Namespace string                 `json:"namespace"`
 	Resources map[string]interface{} `json:"resources"`
+	Ages      []clusterPkg.Age       `json:"ages,omitempty"`
+	Warnings  []string               `json:"warnings,omitempty"`
+}
+
+// SearchResult is the structure for a single result of the searchResources api call. It contains a reference to the
+// object, which name contains the search term.
+type SearchResult struct {
+	Cluster   string `json:"cluster"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// SearchResults is the structure, which is returned by the searchResources api call. It contains the results and the
+// clusters, which could not be searched, so that the user knows when the results are incomplete.
+type SearchResults struct {
+	Results []SearchResult `json:"results"`
+	Failed  []SearchError  `json:"failed,omitempty"`
+}
+
+// SearchError is the structure for a cluster, which could not be searched by the searchResources api call.
+type SearchError struct {
+	Cluster string `json:"cluster"`
+	Error   string `json:"error"`
+}
+
+// StreamSummary is the structure of the last event of the streamResources api call. It contains the number of
+// namespaces, for which the resources were retrieved successfully, the namespaces which failed and the duration in
+// milliseconds.
+type StreamSummary struct {
+	Cluster   string   `json:"cluster"`
+	Total     int      `json:"total"`
+	Succeeded int      `json:"succeeded"`
+	Failed    []string `json:"failed"`
+	Duration  int64    `json:"duration"`
+}
+
+// StreamError is the structure of an error event of the streamResources api call, when the resources for a single
+// namespace could not be retrieved.
+type StreamError struct {
+	Cluster   string `json:"cluster"`
+	Namespace string `json:"namespace"`
+	Error     string `json:"error"`
 }
 
 // Config is the structure of the configuration for the resources plugin. It only contains one filed to forbid access to
 // the provided resources.
 type Config struct {
 	Forbidden           []string                    `json:"forbidden"`
+	Mutable             []string                    `json:"mutable"`
 	WebSocket           WebSocket                   `json:"webSocket"`
 	EphemeralContainers []corev1.EphemeralContainer `json:"ephemeralContainers"`
+	Events              Events                      `json:"events"`
+	MaxResponseSize     MaxResponseSize             `json:"maxResponseSize"`
+}
+
+// MaxResponseSize is the structure for the configuration of the maximum size of the lists of resources in bytes, which
+// are returned by the Kubernetes API servers. The default value is used for all routes of the plugin, which are not
+// configured via the routes map (e.g. "/resources" or "/resources/stream"). A value of 0 disables the limit.
+type MaxResponseSize struct {
+	Default int64            `json:"default"`
+	Routes  map[string]int64 `json:"routes"`
+}
+
+// responseTooLargeMessage is the message, which is returned when a list of resources exceeds the configured maximum
+// response size. It tells the user how the number of returned resources can be reduced.
+const responseTooLargeMessage = "Response is too large, use a label selector, a field selector or the limit parameter to reduce the number of returned resources"
+
+// get returns the maximum response size for the given route of the plugin.
+func (c MaxResponseSize) get(route string) int64 {
+	if maxSize, ok := c.Routes[route]; ok {
+		return maxSize
+	}
+
+	return c.Default
+}
+
+// Events is the structure for the configuration of the merged events feed of multiple clusters.
+type Events struct {
+	MaxWatches int `json:"maxWatches"`
 }
 
 // WebSocket is the structure for the WebSocket configuration for terminal for Pods.
 type WebSocket struct {
 	Address         string `json:"address"`
 	AllowAllOrigins bool   `json:"allowAllOrigins"`
+	MaxMessageSize  int    `json:"maxMessageSize"`
+	MaxLogStreams   int    `json:"maxLogStreams"`
+	IdleTimeout     int    `json:"idleTimeout"`
+}
+
+// sseWriter implements the ResourceEventWriter interface for Server-Sent Events. Each event is written as "data:" line
+// and flushed directly to the client. The mutex is required, because the heartbeat comments are written from another
+// goroutine.
+type sseWriter struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+	mutex   sync.Mutex
+}
+
+// WriteJSON writes the given value as JSON encoded "data:" field of a Server-Sent Event.
+func (s *sseWriter) WriteJSON(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return s.write(fmt.Sprintf("data: %s\n\n", data))
+}
+
+// writeEvent writes the given value as JSON encoded "data:" field of a Server-Sent Event with the given event type.
+func (s *sseWriter) writeEvent(event string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return s.write(fmt.Sprintf("event: %s\ndata: %s\n\n", event, data))
+}
+
+// write writes the given message to the client and flushes the response.
+func (s *sseWriter) write(msg string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, err := s.w.Write([]byte(msg)); err != nil {
+		return err
+	}
+
+	s.flusher.Flush()
+	return nil
 }
 
 // Router implements the router for the resources plugin, which can be registered in the router for our rest api.
@@ -70,8 +235,62 @@ func (router *Router) isForbidden(resource string) bool {
 	return false
 }
 
+// isMutable checks if the requested resource can be created, patched or deleted via kobs. When no list of mutable
+// resources was provided, all resources can be mutated, so that existing setups are working as before.
+func (router *Router) isMutable(resource string) bool {
+	if len(router.config.Mutable) == 0 {
+		return true
+	}
+
+	for _, r := range router.config.Mutable {
+		if resource == r {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getPatchType returns the Kubernetes patch type for the user provided patch type. The patch type can be "json" for a
+// JSON patch, "merge" for a JSON merge patch or "strategic" for a strategic merge patch. When no patch type is provided
+// we use a JSON patch, so that existing clients are working as before.
+func getPatchType(patchType string) (types.PatchType, error) {
+	switch patchType {
+	case "", "json":
+		return types.JSONPatchType, nil
+	case "merge":
+		return types.MergePatchType, nil
+	case "strategic":
+		return types.StrategicMergePatchType, nil
+	default:
+		return "", fmt.Errorf("invalid patch type %s", patchType)
+	}
+}
+
+// getListParams returns the query parameters for the request against the Kubernetes API server. Next to the parameter
+// defined via the paramName and param query parameters, all parameters from the listParams slice are passed through.
+func getListParams(r *http.Request) url.Values {
+	params := url.Values{}
+
+	if paramName := r.URL.Query().Get("paramName"); paramName != "" {
+		params.Set(paramName, r.URL.Query().Get("param"))
+	}
+
+	for _, listParam := range listParams {
+		if values, ok := r.URL.Query()[listParam]; ok {
+			params[listParam] = values
+		}
+	}
+
+	return params
+}
+
 // getResources returns a list of resources for the given clusters and namespaces. The result can limited by the
-// paramName and param query parameter.
+// paramName and param query parameter and by the list options from the listParams slice (e.g. "limit" and "continue").
+// The items can be sorted by a jsonPath via the sortBy parameter, the sortOrder parameter can be used to sort the items
+// in ascending ("asc", default) or descending ("desc") order. When the sortBy parameter is set, the complete lists are
+// retrieved from all clusters and namespaces and the merged items are sorted, before the limit and continue parameters
+// are applied by kobs. See the sortAndPaginateResources function for the format of the returned lists.
 func (router *Router) getResources(w http.ResponseWriter, r *http.Request) {
 	user, err := authContext.GetUser(r.Context())
 	if err != nil {
@@ -79,24 +298,42 @@ func (router *Router) getResources(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	clusterNames := r.URL.Query()["cluster"]
+	clusterNames := router.clusters.GetClusterNames(r.URL.Query()["cluster"])
 	namespaces := r.URL.Query()["namespace"]
 	name := r.URL.Query().Get("name")
 	resource := r.URL.Query().Get("resource")
 	path := r.URL.Query().Get("path")
 	paramName := r.URL.Query().Get("paramName")
 	param := r.URL.Query().Get("param")
+	params := getListParams(r)
+	sortBy := r.URL.Query().Get("sortBy")
+	sortOrder := r.URL.Query().Get("sortOrder")
+
+	log.WithFields(logrus.Fields{"clusters": clusterNames, "namespaces": namespaces, "name": name, "resource": resource, "path": path, "paramName": paramName, "param": param, "params": params.Encode(), "sortBy": sortBy, "sortOrder": sortOrder}).Tracef("getResources")
+
+	if sortOrder != "" && sortOrder != "asc" && sortOrder != "desc" {
+		errresponse.Render(w, r, nil, http.StatusBadRequest, "Sort order must be \"asc\" or \"desc\"")
+		return
+	}
 
-	log.WithFields(logrus.Fields{"clusters": clusterNames, "namespaces": namespaces, "name": name, "resource": resource, "path": path, "paramName": paramName, "param": param}).Tracef("getResources")
+	// The Kubernetes API returns the items of a paginated list ordered by their key, so that sorting a single page would
+	// lead to an inconsistent order across pages. Therefore we retrieve the complete lists when the sortBy parameter is
+	// set and apply the limit and continue parameters after the items were sorted.
+	limit := params.Get("limit")
+	continueToken := params.Get("continue")
+	if sortBy != "" {
+		params.Del("limit")
+		params.Del("continue")
+	}
 
 	var resources []Resources
 
 	// Loop through all the given cluster names and get for each provided name the cluster interface. After that we
 	// check if the resource was provided via the forbidden resources list.
 	for _, clusterName := range clusterNames {
-		cluster := router.clusters.GetCluster(clusterName)
-		if cluster == nil {
-			errresponse.Render(w, r, nil, http.StatusBadRequest, "Invalid cluster name")
+		cluster, err := router.clusters.GetCluster(clusterName)
+		if err != nil {
+			errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
 			return
 		}
 
@@ -114,7 +351,18 @@ func (router *Router) getResources(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 
-			list, err := cluster.GetResources(r.Context(), "", name, path, resource, paramName, param)
+			release, err := router.clusters.Acquire(r.Context(), clusterName)
+			if err != nil {
+				errresponse.Render(w, r, err, http.StatusTooManyRequests, "Could not get resources")
+				return
+			}
+
+			list, warnings, err := cluster.GetResourcesWithMaxSize(r.Context(), "", name, path, resource, params, router.config.MaxResponseSize.get("/resources"))
+			release()
+			if errors.Is(err, clusterPkg.ErrResponseTooLarge) {
+				errresponse.Render(w, r, err, http.StatusRequestEntityTooLarge, responseTooLargeMessage)
+				return
+			}
 			if err != nil {
 				errresponse.Render(w, r, err, http.StatusBadRequest, "Could not get resources")
 				return
@@ -131,6 +379,8 @@ func (router *Router) getResources(w http.ResponseWriter, r *http.Request) {
 				Cluster:   clusterName,
 				Namespace: "",
 				Resources: tmpResources,
+				Ages:      clusterPkg.GetAges(tmpResources, time.Now()),
+				Warnings:  warnings,
 			})
 		} else {
 			for _, namespace := range namespaces {
@@ -139,7 +389,18 @@ func (router *Router) getResources(w http.ResponseWriter, r *http.Request) {
 					return
 				}
 
-				list, err := cluster.GetResources(r.Context(), namespace, name, path, resource, paramName, param)
+				release, err := router.clusters.Acquire(r.Context(), clusterName)
+				if err != nil {
+					errresponse.Render(w, r, err, http.StatusTooManyRequests, "Could not get resources")
+					return
+				}
+
+				list, warnings, err := cluster.GetResourcesWithMaxSize(r.Context(), namespace, name, path, resource, params, router.config.MaxResponseSize.get("/resources"))
+				release()
+				if errors.Is(err, clusterPkg.ErrResponseTooLarge) {
+					errresponse.Render(w, r, err, http.StatusRequestEntityTooLarge, responseTooLargeMessage)
+					return
+				}
 				if err != nil {
 					errresponse.Render(w, r, err, http.StatusBadRequest, "Could not get resources")
 					return
@@ -156,287 +417,2770 @@ func (router *Router) getResources(w http.ResponseWriter, r *http.Request) {
 					Cluster:   clusterName,
 					Namespace: namespace,
 					Resources: tmpResources,
+					Ages:      clusterPkg.GetAges(tmpResources, time.Now()),
+					Warnings:  warnings,
 				})
 			}
 		}
 	}
 
+	if sortBy != "" {
+		resources, err = sortAndPaginateResources(resources, sortBy, sortOrder == "desc", limit, continueToken)
+		if err != nil {
+			errresponse.Render(w, r, err, http.StatusBadRequest, "Could not sort resources")
+			return
+		}
+	}
+
 	log.WithFields(logrus.Fields{"count": len(resources)}).Tracef("getResources")
 	render.JSON(w, r, resources)
 }
 
-// deleteResource handles the deletion of a resource. The resource can be identified by the given cluster, namespace,
-// name, resource and path.
-// When the user sets the "force" parameter to "true" we will set a body on the delete request, where we set the
-// "gracePeriodSeconds" to 0. This will cause the same behaviour as "kubectl delete --force --grace-period 0".
-func (router *Router) deleteResource(w http.ResponseWriter, r *http.Request) {
+// sortAndPaginateResources sorts the merged items of all given resources by the given jsonPath and returns the page
+// for the given limit and continue token. To keep the format of the getResources api call, the sorted items are
+// returned as consecutive lists, where each list contains the items of one cluster and namespace. The items of all
+// returned lists must be concatenated to get the sorted items. If there are more items, the continue token for the
+// next page is set in the metadata of the last list.
+//
+// The continue token is the offset of the next page, so that it can only be used for requests with the same
+// parameters.
+func sortAndPaginateResources(resources []Resources, sortBy string, descending bool, limit, continueToken string) ([]Resources, error) {
+	lists := make([]map[string]interface{}, 0, len(resources))
+	for _, r := range resources {
+		lists = append(lists, r.Resources)
+	}
+
+	sortedItems, err := clusterPkg.SortItems(lists, sortBy, descending)
+	if err != nil {
+		return nil, err
+	}
+
+	// If none of the resources is a list (e.g. when a single resource was requested), there is nothing to sort.
+	if len(sortedItems) == 0 {
+		return resources, nil
+	}
+
+	offset := 0
+	if continueToken != "" {
+		offset, err = strconv.Atoi(continueToken)
+		if err != nil || offset < 0 || offset > len(sortedItems) {
+			return nil, fmt.Errorf("invalid continue token")
+		}
+	}
+
+	end := len(sortedItems)
+	if limit != "" {
+		parsedLimit, err := strconv.Atoi(limit)
+		if err != nil || parsedLimit <= 0 {
+			return nil, fmt.Errorf("invalid limit")
+		}
+
+		if offset+parsedLimit < end {
+			end = offset + parsedLimit
+		}
+	}
+
+	var sortedResources []Resources
+	lastList := -1
+
+	for _, sortedItem := range sortedItems[offset:end] {
+		if sortedItem.List != lastList {
+			source := resources[sortedItem.List]
+			list := make(map[string]interface{}, len(source.Resources))
+			for key, value := range source.Resources {
+				list[key] = value
+			}
+			list["items"] = []interface{}{}
+
+			sortedResources = append(sortedResources, Resources{
+				Cluster:   source.Cluster,
+				Namespace: source.Namespace,
+				Resources: list,
+				Warnings:  source.Warnings,
+			})
+			lastList = sortedItem.List
+		}
+
+		last := &sortedResources[len(sortedResources)-1]
+		last.Resources["items"] = append(last.Resources["items"].([]interface{}), sortedItem.Item)
+	}
+
+	for i := range sortedResources {
+		sortedResources[i].Ages = clusterPkg.GetAges(sortedResources[i].Resources, time.Now())
+	}
+
+	if end < len(sortedItems) && len(sortedResources) > 0 {
+		last := sortedResources[len(sortedResources)-1].Resources
+		metadata := map[string]interface{}{}
+		if sourceMetadata, ok := last["metadata"].(map[string]interface{}); ok {
+			for key, value := range sourceMetadata {
+				metadata[key] = value
+			}
+		}
+		metadata["continue"] = strconv.Itoa(end)
+		last["metadata"] = metadata
+	}
+
+	return sortedResources, nil
+}
+
+// searchResources searches all clusters for resources, which name contains the provided search term. The resources are
+// retrieved for all namespaces of a cluster and then filtered by the permissions of the user, so that a user only gets
+// references to resources the user is allowed to view. The clusters are searched in parallel, but at most searchConcurrency
+// clusters at the same time. The number of returned results can be set via the limit parameter. Only the metadata of the
+// resources is retrieved and the maximum response size of the "/resources/search" route is respected. Clusters, which
+// could not be searched, are returned in the failed field of the response.
+func (router *Router) searchResources(w http.ResponseWriter, r *http.Request) {
 	user, err := authContext.GetUser(r.Context())
 	if err != nil {
 		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
 		return
 	}
 
+	term := r.URL.Query().Get("term")
+	resource := r.URL.Query().Get("resource")
+	path := r.URL.Query().Get("path")
+	limit := r.URL.Query().Get("limit")
+
+	log.WithFields(logrus.Fields{"term": term, "resource": resource, "path": path, "limit": limit}).Tracef("searchResources")
+
+	if term == "" {
+		errresponse.Render(w, r, nil, http.StatusBadRequest, "Search term is required")
+		return
+	}
+
+	if router.isForbidden(resource) {
+		errresponse.Render(w, r, nil, http.StatusForbidden, fmt.Sprintf("Access for resource %s is forbidding", resource))
+		return
+	}
+
+	parsedLimit := searchDefaultLimit
+	if limit != "" {
+		parsedLimit, err = strconv.Atoi(limit)
+		if err != nil || parsedLimit <= 0 {
+			errresponse.Render(w, r, err, http.StatusBadRequest, "Could not parse limit parameter")
+			return
+		}
+
+		if parsedLimit > searchMaxLimit {
+			parsedLimit = searchMaxLimit
+		}
+	}
+
+	var results []SearchResult
+	var failed []SearchError
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, searchConcurrency)
+
+	addFailed := func(clusterName string, err error) {
+		log.WithError(err).WithFields(logrus.Fields{"cluster": clusterName}).Warnf("Could not search resources")
+
+		mutex.Lock()
+		failed = append(failed, SearchError{Cluster: clusterName, Error: err.Error()})
+		mutex.Unlock()
+	}
+
+	for _, cluster := range router.clusters.GetClusters() {
+		wg.Add(1)
+
+		go func(cluster *clusterPkg.Cluster) {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			clusterName := cluster.GetName()
+
+			release, err := router.clusters.Acquire(r.Context(), clusterName)
+			if err != nil {
+				addFailed(clusterName, err)
+				return
+			}
+
+			// We only need the name and namespace of the resources, so that we only get the metadata of the resources,
+			// to keep the response of the Kubernetes API server as small as possible.
+			list, _, err := cluster.GetResourcesMetadata(r.Context(), "", path, resource, router.config.MaxResponseSize.get("/resources/search"))
+			release()
+			if errors.Is(err, clusterPkg.ErrResponseTooLarge) {
+				addFailed(clusterName, fmt.Errorf("%s: %w", responseTooLargeMessage, err))
+				return
+			}
+			if err != nil {
+				addFailed(clusterName, err)
+				return
+			}
+
+			var objects metav1.PartialObjectMetadataList
+			if err := json.Unmarshal(list, &objects); err != nil {
+				addFailed(clusterName, err)
+				return
+			}
+
+			var clusterResults []SearchResult
+			for _, object := range objects.Items {
+				if !strings.Contains(object.Name, term) {
+					continue
+				}
+
+				namespace := object.Namespace
+				if namespace == "" {
+					namespace = "*"
+				}
+
+				if !user.HasResourceAccess(clusterName, namespace, resource) {
+					continue
+				}
+
+				clusterResults = append(clusterResults, SearchResult{
+					Cluster:   clusterName,
+					Namespace: object.Namespace,
+					Name:      object.Name,
+				})
+			}
+
+			mutex.Lock()
+			results = append(results, clusterResults...)
+			mutex.Unlock()
+		}(cluster)
+	}
+
+	wg.Wait()
+
+	// Sort the results, so that the order of the results doesn't depend on the order in which the clusters responded
+	// and then cap the results at the provided limit.
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Cluster != results[j].Cluster {
+			return results[i].Cluster < results[j].Cluster
+		}
+		if results[i].Namespace != results[j].Namespace {
+			return results[i].Namespace < results[j].Namespace
+		}
+		return results[i].Name < results[j].Name
+	})
+
+	if len(results) > parsedLimit {
+		results = results[:parsedLimit]
+	}
+
+	sort.Slice(failed, func(i, j int) bool {
+		return failed[i].Cluster < failed[j].Cluster
+	})
+
+	log.WithFields(logrus.Fields{"count": len(results), "failed": len(failed)}).Tracef("searchResources")
+	render.JSON(w, r, SearchResults{Results: results, Failed: failed})
+}
+
+// watchResources returns the resources for the given cluster, namespace, path and resource via a WebSocket connection.
+// The first message contains the complete list of resources, all following messages are the changes for the resources,
+// so that the resources table can be updated without polling the getResources endpoint.
+func (router *Router) watchResources(w http.ResponseWriter, r *http.Request) {
 	clusterName := r.URL.Query().Get("cluster")
 	namespace := r.URL.Query().Get("namespace")
-	name := r.URL.Query().Get("name")
 	resource := r.URL.Query().Get("resource")
 	path := r.URL.Query().Get("path")
-	force := r.URL.Query().Get("force")
+	paramName := r.URL.Query().Get("paramName")
+	param := r.URL.Query().Get("param")
 
-	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "name": name, "resource": resource, "path": path}).Tracef("deleteResource")
+	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "resource": resource, "path": path, "paramName": paramName, "param": param}).Tracef("watchResources")
 
-	if !user.HasResourceAccess(clusterName, namespace, resource) {
-		errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: %s, resource: %s", clusterName, namespace, resource), http.StatusForbidden, "You are not authorized to access the resource")
+	var upgrader = websocket.Upgrader{}
+
+	if router.config.WebSocket.AllowAllOrigins {
+		upgrader.CheckOrigin = func(r *http.Request) bool { return true }
+	}
+
+	c, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.WithError(err).Errorf("Could not upgrade connection")
+		return
+	}
+	defer c.Close()
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	// The context of the request isn't canceled when the client closes the WebSocket connection, so that we have to
+	// read from the connection to detect the close of the connection and to stop the watch. Otherwise the watch would
+	// only be stopped with the next event, which can not be written to the connection.
+	k := newKeepAlive(c, "watch", router.config.WebSocket.IdleTimeout)
+	defer k.close()
+
+	go k.read(cancel)
+	go k.ping(ctx)
+
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		c.WriteMessage(websocket.TextMessage, []byte("You are not authorized to access the resource"))
+		return
+	}
+
+	ns := namespace
+	if ns == "" {
+		ns = "*"
+	}
+
+	if !user.HasResourceAccess(clusterName, ns, resource) {
+		c.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf("You are not authorized to access the resource: cluster: %s, namespace: %s, resource: %s", clusterName, ns, resource)))
+		return
+	}
+
+	if router.isForbidden(resource) {
+		c.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf("Access for resource %s is forbidding", resource)))
+		return
+	}
+
+	cluster, err := router.clusters.GetCluster(clusterName)
+	if err != nil {
+		c.WriteMessage(websocket.TextMessage, []byte("Invalid cluster name"))
+		return
+	}
+
+	err = cluster.WatchResources(ctx, c, namespace, path, resource, paramName, param)
+	if err != nil && ctx.Err() == nil {
+		c.WriteMessage(websocket.TextMessage, []byte("Could not watch resources: "+err.Error()))
+		return
+	}
+
+	log.Tracef("Resources watch was closed")
+}
+
+// watchPodStatus sends the compact status of all Pods in the given namespace via a WebSocket connection. Compared to
+// the watchResources function, only the phase, the number of ready containers and the restarts of a Pod are sent, so
+// that the payload stays small for namespaces with a lot of Pods. The watch is stopped, when the client closes the
+// connection.
+func (router *Router) watchPodStatus(w http.ResponseWriter, r *http.Request) {
+	clusterName := r.URL.Query().Get("cluster")
+	namespace := r.URL.Query().Get("namespace")
+
+	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace}).Tracef("watchPodStatus")
+
+	var upgrader = websocket.Upgrader{}
+
+	if router.config.WebSocket.AllowAllOrigins {
+		upgrader.CheckOrigin = func(r *http.Request) bool { return true }
+	}
+
+	c, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.WithError(err).Errorf("Could not upgrade connection")
+		return
+	}
+	defer c.Close()
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	// The context of the request isn't canceled when the client closes the WebSocket connection, so that we have to
+	// read from the connection to detect the close of the connection and to stop the watch.
+	k := newKeepAlive(c, "watch", router.config.WebSocket.IdleTimeout)
+	defer k.close()
+
+	go k.read(cancel)
+	go k.ping(ctx)
+
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		c.WriteMessage(websocket.TextMessage, []byte("You are not authorized to access the resource"))
+		return
+	}
+
+	ns := namespace
+	if ns == "" {
+		ns = "*"
+	}
+
+	if !user.HasResourceAccess(clusterName, ns, "pods") {
+		c.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf("You are not authorized to access the resource: cluster: %s, namespace: %s, resource: pods", clusterName, ns)))
+		return
+	}
+
+	if router.isForbidden("pods") {
+		c.WriteMessage(websocket.TextMessage, []byte("Access for resource pods is forbidding"))
+		return
+	}
+
+	cluster, err := router.clusters.GetCluster(clusterName)
+	if err != nil {
+		c.WriteMessage(websocket.TextMessage, []byte("Invalid cluster name"))
+		return
+	}
+
+	err = cluster.WatchPodStatus(ctx, c, namespace)
+	if err != nil && ctx.Err() == nil {
+		c.WriteMessage(websocket.TextMessage, []byte("Could not watch pods: "+err.Error()))
+		return
+	}
+
+	log.Tracef("Pod status watch was closed")
+}
+
+// getChildren returns all objects of the given resources, which are owned by the object with the given uid. The
+// resources are provided via the path and resource parameters, where the n-th path belongs to the n-th resource (e.g.
+// "path=/apis/apps/v1&resource=replicasets&path=/api/v1&resource=pods"). This can be used to build a tree of an
+// object and its children.
+func (router *Router) getChildren(w http.ResponseWriter, r *http.Request) {
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
+		return
+	}
+
+	clusterName := r.URL.Query().Get("cluster")
+	namespace := r.URL.Query().Get("namespace")
+	uid := r.URL.Query().Get("uid")
+	paths := r.URL.Query()["path"]
+	resources := r.URL.Query()["resource"]
+
+	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "uid": uid, "paths": paths, "resources": resources}).Tracef("getChildren")
+
+	if uid == "" {
+		errresponse.Render(w, r, nil, http.StatusBadRequest, "The uid parameter is required")
+		return
+	}
+
+	if len(resources) == 0 || len(paths) != len(resources) {
+		errresponse.Render(w, r, nil, http.StatusBadRequest, "The number of path and resource parameters must be equal")
+		return
+	}
+
+	var childResources []clusterPkg.ChildResource
+	for i, resource := range resources {
+		if !user.HasResourceAccess(clusterName, namespace, resource) {
+			errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: %s, resource: %s", clusterName, namespace, resource), http.StatusForbidden, "You are not authorized to access the resource")
+			return
+		}
+
+		if router.isForbidden(resource) {
+			errresponse.Render(w, r, nil, http.StatusForbidden, fmt.Sprintf("Access for resource %s is forbidding", resource))
+			return
+		}
+
+		childResources = append(childResources, clusterPkg.ChildResource{Path: paths[i], Resource: resource})
+	}
+
+	cluster, err := router.clusters.GetCluster(clusterName)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
+		return
+	}
+
+	children, err := cluster.GetChildren(r.Context(), namespace, uid, childResources)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not get children")
+		return
+	}
+
+	log.WithFields(logrus.Fields{"count": len(children)}).Tracef("getChildren")
+	render.JSON(w, r, children)
+}
+
+// watchResourcesSSE works like the watchResources function, but instead of a WebSocket connection it uses Server-Sent
+// Events to send the list of resources and all changes to the client. This is easier to consume for read-only views and
+// works better with proxies. We are sending a retry hint at the beginning of the stream and a heartbeat comment every
+// ping period, so that the connection isn't closed by a load balancer.
+func (router *Router) watchResourcesSSE(w http.ResponseWriter, r *http.Request) {
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
+		return
+	}
+
+	clusterName := r.URL.Query().Get("cluster")
+	namespace := r.URL.Query().Get("namespace")
+	resource := r.URL.Query().Get("resource")
+	path := r.URL.Query().Get("path")
+	paramName := r.URL.Query().Get("paramName")
+	param := r.URL.Query().Get("param")
+
+	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "resource": resource, "path": path, "paramName": paramName, "param": param}).Tracef("watchResourcesSSE")
+
+	ns := namespace
+	if ns == "" {
+		ns = "*"
+	}
+
+	if !user.HasResourceAccess(clusterName, ns, resource) {
+		errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: %s, resource: %s", clusterName, ns, resource), http.StatusForbidden, "You are not authorized to access the resource")
+		return
+	}
+
+	if router.isForbidden(resource) {
+		errresponse.Render(w, r, nil, http.StatusForbidden, fmt.Sprintf("Access for resource %s is forbidding", resource))
+		return
+	}
+
+	cluster, err := router.clusters.GetCluster(clusterName)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		errresponse.Render(w, r, nil, http.StatusInternalServerError, "Streaming is not supported")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	writer := &sseWriter{w: w, flusher: flusher}
+	if err := writer.write("retry: 5000\n\n"); err != nil {
+		return
+	}
+
+	done := make(chan bool)
+	defer close(done)
+
+	go func() {
+		ticker := time.NewTicker(pingPeriod)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := writer.write(": heartbeat\n\n"); err != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	err = cluster.WatchResources(r.Context(), writer, namespace, path, resource, paramName, param)
+	if err != nil && r.Context().Err() == nil {
+		writer.write(fmt.Sprintf("event: error\ndata: %s\n\n", strconv.Quote(err.Error())))
+		return
+	}
+
+	log.Tracef("Resources watch was closed")
+}
+
+// watchRollout streams the rollout status of a Deployment, StatefulSet or DaemonSet via Server-Sent Events. Each change
+// of the workload is sent as event with the number of updated, ready and available replicas. The stream is closed by
+// the server when the rollout is done.
+func (router *Router) watchRollout(w http.ResponseWriter, r *http.Request) {
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
+		return
+	}
+
+	clusterName := r.URL.Query().Get("cluster")
+	namespace := r.URL.Query().Get("namespace")
+	name := r.URL.Query().Get("name")
+	resource := r.URL.Query().Get("resource")
+
+	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "name": name, "resource": resource}).Tracef("watchRollout")
+
+	if !user.HasResourceAccess(clusterName, namespace, resource) {
+		errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: %s, resource: %s", clusterName, namespace, resource), http.StatusForbidden, "You are not authorized to access the resource")
+		return
+	}
+
+	if router.isForbidden(resource) {
+		errresponse.Render(w, r, nil, http.StatusForbidden, fmt.Sprintf("Access for resource %s is forbidding", resource))
+		return
+	}
+
+	cluster, err := router.clusters.GetCluster(clusterName)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		errresponse.Render(w, r, nil, http.StatusInternalServerError, "Streaming is not supported")
+		return
+	}
+
+	statusCh, err := cluster.WaitForRollout(r.Context(), namespace, name, resource)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not get rollout status")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	writer := &sseWriter{w: w, flusher: flusher}
+
+	done := make(chan bool)
+	defer close(done)
+
+	go func() {
+		ticker := time.NewTicker(pingPeriod)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := writer.write(": heartbeat\n\n"); err != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	for status := range statusCh {
+		if err := writer.WriteJSON(status); err != nil {
+			return
+		}
+	}
+
+	log.Tracef("Rollout watch was closed")
+}
+
+// watchEvents streams the Kubernetes events of multiple clusters and namespaces as one feed via Server-Sent Events.
+// Each event is tagged with the name of its cluster. If the cluster parameter is not set, the events of all clusters
+// are watched. If the namespace parameter is not set, the events of all namespaces are watched. The number of watches
+// (clusters times namespaces) is limited by the events.maxWatches option.
+func (router *Router) watchEvents(w http.ResponseWriter, r *http.Request) {
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
+		return
+	}
+
+	clusterNames := r.URL.Query()["cluster"]
+	namespaces := r.URL.Query()["namespace"]
+
+	log.WithFields(logrus.Fields{"clusters": clusterNames, "namespaces": namespaces}).Tracef("watchEvents")
+
+	if router.isForbidden("events") {
+		errresponse.Render(w, r, nil, http.StatusForbidden, "Access for resource events is forbidding")
+		return
+	}
+
+	if len(clusterNames) == 0 {
+		for _, cluster := range router.clusters.GetClusters() {
+			clusterNames = append(clusterNames, cluster.GetName())
+		}
+	}
+
+	if len(namespaces) == 0 {
+		namespaces = []string{""}
+	}
+
+	var watches []clusters.EventsWatch
+	for _, clusterName := range clusterNames {
+		for _, namespace := range namespaces {
+			ns := namespace
+			if ns == "" {
+				ns = "*"
+			}
+
+			if !user.HasResourceAccess(clusterName, ns, "events") {
+				errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: %s, resource: events", clusterName, ns), http.StatusForbidden, "You are not authorized to access the resource")
+				return
+			}
+
+			watches = append(watches, clusters.EventsWatch{Cluster: clusterName, Namespace: namespace})
+		}
+	}
+
+	if len(watches) > router.config.Events.MaxWatches {
+		errresponse.Render(w, r, nil, http.StatusBadRequest, fmt.Sprintf("Too many clusters and namespaces, only %d watches are allowed", router.config.Events.MaxWatches))
+		return
+	}
+
+	for _, watch := range watches {
+		if _, err := router.clusters.GetCluster(watch.Cluster); err != nil {
+			errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
+			return
+		}
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		errresponse.Render(w, r, nil, http.StatusInternalServerError, "Streaming is not supported")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	writer := &sseWriter{w: w, flusher: flusher}
+	if err := writer.write("retry: 5000\n\n"); err != nil {
+		return
+	}
+
+	done := make(chan bool)
+	defer close(done)
+
+	go func() {
+		ticker := time.NewTicker(pingPeriod)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := writer.write(": heartbeat\n\n"); err != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	err = router.clusters.WatchEvents(r.Context(), writer, watches, router.config.Events.MaxWatches)
+	if err != nil && r.Context().Err() == nil {
+		writer.write(fmt.Sprintf("event: error\ndata: %s\n\n", strconv.Quote(err.Error())))
+		return
+	}
+
+	log.Tracef("Events watch was closed")
+}
+
+// streamResources streams the resources for all namespaces of a cluster via Server-Sent Events. Instead of returning
+// the resources for all namespaces at once, the resources are retrieved per namespace with at most
+// namespacesConcurrency requests in parallel and each namespace is sent to the client as soon as it is completed. When
+// the resources for a namespace could not be retrieved, an "error" event is sent. The last event is a "summary" event,
+// which contains the number of succeeded and failed namespaces. If the namespace parameter is not set, the resources
+// are streamed for all namespaces of the cluster, the user has access to. Because each namespace is sent on its own, the
+// sortBy and sortOrder parameters are applied to the items of each namespace.
+func (router *Router) streamResources(w http.ResponseWriter, r *http.Request) {
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
+		return
+	}
+
+	clusterName := r.URL.Query().Get("cluster")
+	namespaces := r.URL.Query()["namespace"]
+	resource := r.URL.Query().Get("resource")
+	path := r.URL.Query().Get("path")
+	paramName := r.URL.Query().Get("paramName")
+	param := r.URL.Query().Get("param")
+	sortBy := r.URL.Query().Get("sortBy")
+	sortOrder := r.URL.Query().Get("sortOrder")
+
+	log.WithFields(logrus.Fields{"cluster": clusterName, "namespaces": namespaces, "resource": resource, "path": path, "paramName": paramName, "param": param, "sortBy": sortBy, "sortOrder": sortOrder}).Tracef("streamResources")
+
+	if sortOrder != "" && sortOrder != "asc" && sortOrder != "desc" {
+		errresponse.Render(w, r, nil, http.StatusBadRequest, "Sort order must be \"asc\" or \"desc\"")
+		return
+	}
+
+	if router.isForbidden(resource) {
+		errresponse.Render(w, r, nil, http.StatusForbidden, fmt.Sprintf("Access for resource %s is forbidding", resource))
+		return
+	}
+
+	cluster, err := router.clusters.GetCluster(clusterName)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
+		return
+	}
+
+	if namespaces == nil {
+		namespaces, err = cluster.GetNamespaces(r.Context(), namespacesCacheDuration)
+		if err != nil {
+			errresponse.Render(w, r, err, http.StatusBadRequest, "Could not get namespaces")
+			return
+		}
+	}
+
+	// Filter the namespaces by the permissions of the user. If the user explicitly requested a namespace, which the
+	// user is not allowed to access, we return an error. For all namespaces of the cluster we just skip the forbidden
+	// namespaces.
+	var allowedNamespaces []string
+	for _, namespace := range namespaces {
+		if !user.HasResourceAccess(clusterName, namespace, resource) {
+			if r.URL.Query()["namespace"] != nil {
+				errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: %s, resource: %s", clusterName, namespace, resource), http.StatusForbidden, "You are not authorized to access the resource")
+				return
+			}
+
+			continue
+		}
+
+		allowedNamespaces = append(allowedNamespaces, namespace)
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		errresponse.Render(w, r, nil, http.StatusInternalServerError, "Streaming is not supported")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	writer := &sseWriter{w: w, flusher: flusher}
+
+	start := time.Now()
+	summary := StreamSummary{Cluster: clusterName, Total: len(allowedNamespaces), Failed: []string{}}
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, namespacesConcurrency)
+
+	for _, namespace := range allowedNamespaces {
+		wg.Add(1)
+
+		go func(namespace string) {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			if r.Context().Err() != nil {
+				return
+			}
+
+			resources, err := router.getNamespaceResources(r, cluster, clusterName, namespace, path, resource, paramName, param, sortBy, sortOrder == "desc")
+
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			if err != nil {
+				log.WithError(err).WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace}).Warnf("Could not get resources")
+				summary.Failed = append(summary.Failed, namespace)
+				writer.writeEvent("error", StreamError{Cluster: clusterName, Namespace: namespace, Error: err.Error()})
+				return
+			}
+
+			summary.Succeeded = summary.Succeeded + 1
+			writer.WriteJSON(resources)
+		}(namespace)
+	}
+
+	wg.Wait()
+
+	if r.Context().Err() != nil {
+		log.Tracef("Resources stream was closed")
+		return
+	}
+
+	sort.Strings(summary.Failed)
+	summary.Duration = time.Since(start).Milliseconds()
+	writer.writeEvent("summary", summary)
+
+	log.WithFields(logrus.Fields{"total": summary.Total, "succeeded": summary.Succeeded, "failed": len(summary.Failed)}).Tracef("streamResources")
+}
+
+// getNamespaceResources returns the resources for a single namespace. It is used by the streamResources function to
+// get the resources for each namespace and respects the limit of concurrent requests for the cluster and the maximum
+// response size of the "/resources/stream" route. If sortBy is set, the items are sorted by the given jsonPath.
+func (router *Router) getNamespaceResources(r *http.Request, cluster *clusterPkg.Cluster, clusterName, namespace, path, resource, paramName, param, sortBy string, descending bool) (*Resources, error) {
+	release, err := router.clusters.Acquire(r.Context(), clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	if paramName != "" {
+		params.Set(paramName, param)
+	}
+
+	list, warnings, err := cluster.GetResourcesWithMaxSize(r.Context(), namespace, "", path, resource, params, router.config.MaxResponseSize.get("/resources/stream"))
+	release()
+	if errors.Is(err, clusterPkg.ErrResponseTooLarge) {
+		return nil, fmt.Errorf("%s: %w", responseTooLargeMessage, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var tmpResources map[string]interface{}
+	if err := json.Unmarshal(list, &tmpResources); err != nil {
+		return nil, err
+	}
+
+	if sortBy != "" {
+		if err := clusterPkg.SortResources(tmpResources, sortBy, descending); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Resources{
+		Cluster:   clusterName,
+		Namespace: namespace,
+		Resources: tmpResources,
+		Ages:      clusterPkg.GetAges(tmpResources, time.Now()),
+		Warnings:  warnings,
+	}, nil
+}
+
+// deleteResource handles the deletion of a resource. The resource can be identified by the given cluster, namespace,
+// name, resource and path.
+// When the user sets the "force" parameter to "true" we will set a body on the delete request, where we set the
+// "gracePeriodSeconds" to 0. This will cause the same behaviour as "kubectl delete --force --grace-period 0".
+func (router *Router) deleteResource(w http.ResponseWriter, r *http.Request) {
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
+		return
+	}
+
+	clusterName := r.URL.Query().Get("cluster")
+	namespace := r.URL.Query().Get("namespace")
+	name := r.URL.Query().Get("name")
+	resource := r.URL.Query().Get("resource")
+	path := r.URL.Query().Get("path")
+	force := r.URL.Query().Get("force")
+
+	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "name": name, "resource": resource, "path": path}).Tracef("deleteResource")
+
+	if !user.HasResourceAccess(clusterName, namespace, resource) {
+		errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: %s, resource: %s", clusterName, namespace, resource), http.StatusForbidden, "You are not authorized to access the resource")
+		return
+	}
+
+	cluster, err := router.clusters.GetCluster(clusterName)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
+		return
+	}
+
+	if router.isForbidden(resource) {
+		errresponse.Render(w, r, nil, http.StatusForbidden, fmt.Sprintf("Access for resource %s is forbidding", resource))
+		return
+	}
+
+	if !router.isMutable(resource) {
+		errresponse.Render(w, r, nil, http.StatusForbidden, fmt.Sprintf("Resource %s can not be modified", resource))
+		return
+	}
+
+	parsedForce, err := strconv.ParseBool(force)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not parse force parameter")
+		return
+	}
+
+	var body []byte
+	if parsedForce {
+		body = []byte(`{"gracePeriodSeconds": 0}`)
+	}
+
+	err = cluster.DeleteResource(r.Context(), namespace, name, path, resource, body)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not delete resource")
+		return
+	}
+
+	render.JSON(w, r, nil)
+}
+
+// patchResource hadnles patch operations for resources. The resource can be identified by the given cluster,
+// namespace, name, resource and path. The patch operation must be provided in the request body. The optional patchType
+// parameter can be used to send a JSON merge patch ("merge") or a strategic merge patch ("strategic") instead of a JSON
+// patch.
+func (router *Router) patchResource(w http.ResponseWriter, r *http.Request) {
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
+		return
+	}
+
+	clusterName := r.URL.Query().Get("cluster")
+	namespace := r.URL.Query().Get("namespace")
+	name := r.URL.Query().Get("name")
+	resource := r.URL.Query().Get("resource")
+	path := r.URL.Query().Get("path")
+	patchType := r.URL.Query().Get("patchType")
+
+	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "name": name, "resource": resource, "path": path, "patchType": patchType}).Tracef("patchResource")
+
+	parsedPatchType, err := getPatchType(patchType)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not parse patch type parameter")
+		return
+	}
+
+	if !user.HasResourceAccess(clusterName, namespace, resource) {
+		errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: %s, resource: %s", clusterName, namespace, resource), http.StatusForbidden, "You are not authorized to access the resource")
+		return
+	}
+
+	cluster, err := router.clusters.GetCluster(clusterName)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
+		return
+	}
+
+	if router.isForbidden(resource) {
+		errresponse.Render(w, r, nil, http.StatusForbidden, fmt.Sprintf("Access for resource %s is forbidding", resource))
+		return
+	}
+
+	if !router.isMutable(resource) {
+		errresponse.Render(w, r, nil, http.StatusForbidden, fmt.Sprintf("Resource %s can not be modified", resource))
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not decode request body")
+		return
+	}
+
+	err = cluster.PatchResource(r.Context(), namespace, name, path, resource, parsedPatchType, body)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not patch resource")
+		return
+	}
+
+	render.JSON(w, r, nil)
+}
+
+// updateLabels adds, changes or removes the labels of a resource. The resource is identified by the cluster, namespace,
+// name, path and resource query parameters. The labels must be provided as map in the request body, where a label with
+// a null value is removed from the resource.
+func (router *Router) updateLabels(w http.ResponseWriter, r *http.Request) {
+	router.updateMetadata(w, r, "labels")
+}
+
+// updateAnnotations adds, changes or removes the annotations of a resource. It works in the same way as the
+// updateLabels function.
+func (router *Router) updateAnnotations(w http.ResponseWriter, r *http.Request) {
+	router.updateMetadata(w, r, "annotations")
+}
+
+// updateMetadata implements the shared logic for the updateLabels and updateAnnotations functions. Since changing the
+// metadata is a patch of the resource, the same checks as in the patchResource function are applied: The user must
+// have access to the resource and the resource must not be forbidden and must be mutable.
+func (router *Router) updateMetadata(w http.ResponseWriter, r *http.Request, field string) {
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
+		return
+	}
+
+	clusterName := r.URL.Query().Get("cluster")
+	namespace := r.URL.Query().Get("namespace")
+	name := r.URL.Query().Get("name")
+	resource := r.URL.Query().Get("resource")
+	path := r.URL.Query().Get("path")
+
+	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "name": name, "resource": resource, "path": path, "field": field}).Tracef("updateMetadata")
+
+	if !user.HasResourceAccess(clusterName, namespace, resource) {
+		errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: %s, resource: %s", clusterName, namespace, resource), http.StatusForbidden, "You are not authorized to access the resource")
+		return
+	}
+
+	if router.isForbidden(resource) {
+		errresponse.Render(w, r, nil, http.StatusForbidden, fmt.Sprintf("Access for resource %s is forbidding", resource))
+		return
+	}
+
+	if !router.isMutable(resource) {
+		errresponse.Render(w, r, nil, http.StatusForbidden, fmt.Sprintf("Resource %s can not be modified", resource))
+		return
+	}
+
+	cluster, err := router.clusters.GetCluster(clusterName)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
+		return
+	}
+
+	var values map[string]*string
+	if err := json.NewDecoder(r.Body).Decode(&values); err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not decode request body")
+		return
+	}
+
+	if field == "labels" {
+		err = cluster.UpdateLabels(r.Context(), namespace, name, path, resource, values)
+	} else {
+		err = cluster.UpdateAnnotations(r.Context(), namespace, name, path, resource, values)
+	}
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, fmt.Sprintf("Could not update %s", field))
+		return
+	}
+
+	render.JSON(w, r, nil)
+}
+
+// patchResourcesRequest is the structure of the request body for the patchResources function. It contains the list
+// of resources, which should be patched and the patch, which should be applied to all resources.
+type patchResourcesRequest struct {
+	Items []clusterPkg.ResourceRef `json:"items"`
+	Patch json.RawMessage          `json:"patch"`
+}
+
+// patchResourcesResult is the result of the patchResources function for a single resource. When the resource couldn't
+// be patched the error field contains the error message. The conflict field is set, when the patch failed because of a
+// conflict, so that the user can retry the patch for this resource.
+type patchResourcesResult struct {
+	clusterPkg.ResourceRef
+	Error    string `json:"error,omitempty"`
+	Conflict bool   `json:"conflict,omitempty"`
+}
+
+// patchResources applies the same patch to multiple resources in a cluster, e.g. to add an annotation to a list of
+// Pods. The user must have access to all resources. The result contains the outcome for each resource, so that a
+// failing resource doesn't affect the other resources.
+func (router *Router) patchResources(w http.ResponseWriter, r *http.Request) {
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
+		return
+	}
+
+	clusterName := r.URL.Query().Get("cluster")
+	patchType := r.URL.Query().Get("patchType")
+
+	log.WithFields(logrus.Fields{"cluster": clusterName, "patchType": patchType}).Tracef("patchResources")
+
+	parsedPatchType, err := getPatchType(patchType)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not parse patch type parameter")
+		return
+	}
+
+	var data patchResourcesRequest
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not decode request body")
+		return
+	}
+
+	if len(data.Items) == 0 || len(data.Patch) == 0 {
+		errresponse.Render(w, r, nil, http.StatusBadRequest, "Items and patch are required")
+		return
+	}
+
+	for _, item := range data.Items {
+		if !user.HasResourceAccess(clusterName, item.Namespace, item.Resource) {
+			errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: %s, resource: %s", clusterName, item.Namespace, item.Resource), http.StatusForbidden, "You are not authorized to access the resource")
+			return
+		}
+
+		if router.isForbidden(item.Resource) {
+			errresponse.Render(w, r, nil, http.StatusForbidden, fmt.Sprintf("Access for resource %s is forbidding", item.Resource))
+			return
+		}
+
+		if !router.isMutable(item.Resource) {
+			errresponse.Render(w, r, nil, http.StatusForbidden, fmt.Sprintf("Resource %s can not be modified", item.Resource))
+			return
+		}
+	}
+
+	cluster, err := router.clusters.GetCluster(clusterName)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
+		return
+	}
+
+	errs := cluster.PatchResources(r.Context(), data.Items, parsedPatchType, data.Patch)
+
+	results := make([]patchResourcesResult, 0, len(data.Items))
+	for i, item := range data.Items {
+		result := patchResourcesResult{ResourceRef: item}
+		if errs[i] != nil {
+			result.Error = errs[i].Error()
+			result.Conflict = apierrors.IsConflict(errs[i])
+		}
+
+		results = append(results, result)
+	}
+
+	log.WithFields(logrus.Fields{"count": len(results)}).Tracef("patchResources")
+	render.JSON(w, r, results)
+}
+
+// diffResource returns the changes between the live resource and the manifest provided in the request body. The
+// resource can be identified by the given cluster, namespace, name, resource and path. This can be used to show a user
+// all changes before they are applied. When the applied parameter is set, the changes between the last applied manifest
+// and the live resource are returned instead, which contains all fields set by the server.
+func (router *Router) diffResource(w http.ResponseWriter, r *http.Request) {
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
+		return
+	}
+
+	clusterName := r.URL.Query().Get("cluster")
+	namespace := r.URL.Query().Get("namespace")
+	name := r.URL.Query().Get("name")
+	resource := r.URL.Query().Get("resource")
+	path := r.URL.Query().Get("path")
+	applied := r.URL.Query().Get("applied")
+
+	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "name": name, "resource": resource, "path": path, "applied": applied}).Tracef("diffResource")
+
+	if !user.HasResourceAccess(clusterName, namespace, resource) {
+		errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: %s, resource: %s", clusterName, namespace, resource), http.StatusForbidden, "You are not authorized to access the resource")
+		return
+	}
+
+	cluster, err := router.clusters.GetCluster(clusterName)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
+		return
+	}
+
+	if router.isForbidden(resource) {
+		errresponse.Render(w, r, nil, http.StatusForbidden, fmt.Sprintf("Access for resource %s is forbidding", resource))
+		return
+	}
+
+	// If the applied parameter is set to true, we do not compare the live object with the submitted manifest. Instead
+	// we compare the last applied manifest with the live object, to show which fields were defaulted by the server.
+	if applied == "true" {
+		changes, err := cluster.DiffAppliedResource(r.Context(), namespace, name, path, resource)
+		if err != nil {
+			errresponse.Render(w, r, err, http.StatusBadRequest, "Could not get diff")
+			return
+		}
+
+		log.WithFields(logrus.Fields{"count": len(changes)}).Tracef("diffResource")
+		render.JSON(w, r, changes)
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not decode request body")
+		return
+	}
+
+	changes, err := cluster.DiffResource(r.Context(), namespace, name, path, resource, body)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not get diff")
+		return
+	}
+
+	log.WithFields(logrus.Fields{"count": len(changes)}).Tracef("diffResource")
+	render.JSON(w, r, changes)
+}
+
+// validateResource validates the manifest from the request body against the OpenAPI schema of the cluster. The manifest
+// is not send to the Kubernetes API, so that this can be used to validate a manifest on each edit of the user. The
+// response contains a list of field-level errors, which is empty when the manifest is valid.
+func (router *Router) validateResource(w http.ResponseWriter, r *http.Request) {
+	clusterName := r.URL.Query().Get("cluster")
+
+	log.WithFields(logrus.Fields{"cluster": clusterName}).Tracef("validateResource")
+
+	_, err := authContext.GetUser(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
+		return
+	}
+
+	cluster, err := router.clusters.GetCluster(clusterName)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not decode request body")
+		return
+	}
+
+	validationErrors, err := cluster.ValidateManifest(body)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not validate manifest")
+		return
+	}
+
+	if validationErrors == nil {
+		validationErrors = []clusterPkg.ValidationError{}
+	}
+
+	log.WithFields(logrus.Fields{"count": len(validationErrors)}).Tracef("validateResource")
+	render.JSON(w, r, validationErrors)
+}
+
+// createResource hadnles patch operations for resources. The resource can be identified by the given cluster,
+// namespace, name, resource and path. The resource must be provided in the request body.
+func (router *Router) createResource(w http.ResponseWriter, r *http.Request) {
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
+		return
+	}
+
+	clusterName := r.URL.Query().Get("cluster")
+	namespace := r.URL.Query().Get("namespace")
+	name := r.URL.Query().Get("name")
+	resource := r.URL.Query().Get("resource")
+	subResource := r.URL.Query().Get("subResource")
+	path := r.URL.Query().Get("path")
+
+	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "name": name, "path": path, "resource": resource, "subResource": subResource}).Tracef("createResource")
+
+	if !user.HasResourceAccess(clusterName, namespace, resource) {
+		errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: %s, resource: %s", clusterName, namespace, resource), http.StatusForbidden, "You are not authorized to access the resource")
+		return
+	}
+
+	cluster, err := router.clusters.GetCluster(clusterName)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
+		return
+	}
+
+	if router.isForbidden(resource) {
+		errresponse.Render(w, r, nil, http.StatusForbidden, fmt.Sprintf("Access for resource %s is forbidding", resource))
+		return
+	}
+
+	if !router.isMutable(resource) {
+		errresponse.Render(w, r, nil, http.StatusForbidden, fmt.Sprintf("Resource %s can not be modified", resource))
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not decode request body")
+		return
+	}
+
+	err = cluster.CreateResource(r.Context(), namespace, name, path, resource, subResource, body)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not create resource")
+		return
+	}
+
+	render.JSON(w, r, nil)
+}
+
+// cordonNode marks a node as unschedulable or schedulable. The node is identified by the cluster and name parameter. If
+// the unschedulable parameter is "false" the node is uncordoned, otherwise the node is cordoned.
+func (router *Router) cordonNode(w http.ResponseWriter, r *http.Request) {
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
+		return
+	}
+
+	clusterName := r.URL.Query().Get("cluster")
+	name := r.URL.Query().Get("name")
+	unschedulable := r.URL.Query().Get("unschedulable")
+
+	log.WithFields(logrus.Fields{"cluster": clusterName, "name": name, "unschedulable": unschedulable}).Tracef("cordonNode")
+
+	if !user.HasResourceAccess(clusterName, "*", "nodes") {
+		errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: *, resource: nodes", clusterName), http.StatusForbidden, "You are not authorized to access the resource")
+		return
+	}
+
+	if router.isForbidden("nodes") {
+		errresponse.Render(w, r, nil, http.StatusForbidden, "Access for resource nodes is forbidding")
+		return
+	}
+
+	if !router.isMutable("nodes") {
+		errresponse.Render(w, r, nil, http.StatusForbidden, "Resource nodes can not be modified")
+		return
+	}
+
+	cluster, err := router.clusters.GetCluster(clusterName)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
+		return
+	}
+
+	if unschedulable == "false" {
+		err = cluster.UncordonNode(r.Context(), name)
+	} else {
+		err = cluster.CordonNode(r.Context(), name)
+	}
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not cordon node")
+		return
+	}
+
+	render.JSON(w, r, nil)
+}
+
+// getDeploymentRevisions returns the rollout history of a Deployment. The optional limit parameter can be used to only
+// return the latest N revisions.
+func (router *Router) getDeploymentRevisions(w http.ResponseWriter, r *http.Request) {
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
+		return
+	}
+
+	clusterName := r.URL.Query().Get("cluster")
+	namespace := r.URL.Query().Get("namespace")
+	name := r.URL.Query().Get("name")
+	limit := r.URL.Query().Get("limit")
+
+	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "name": name, "limit": limit}).Tracef("getDeploymentRevisions")
+
+	for _, resource := range []string{"deployments", "replicasets"} {
+		if !user.HasResourceAccess(clusterName, namespace, resource) {
+			errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: %s, resource: %s", clusterName, namespace, resource), http.StatusForbidden, "You are not authorized to access the resource")
+			return
+		}
+
+		if router.isForbidden(resource) {
+			errresponse.Render(w, r, nil, http.StatusForbidden, fmt.Sprintf("Access for resource %s is forbidding", resource))
+			return
+		}
+	}
+
+	cluster, err := router.clusters.GetCluster(clusterName)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
+		return
+	}
+
+	var parsedLimit int
+	if limit != "" {
+		parsedLimit, err = strconv.Atoi(limit)
+		if err != nil {
+			errresponse.Render(w, r, err, http.StatusBadRequest, "Could not parse limit parameter")
+			return
+		}
+	}
+
+	revisions, err := cluster.GetDeploymentRevisions(r.Context(), namespace, name, parsedLimit)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not get revisions")
+		return
+	}
+
+	log.WithFields(logrus.Fields{"count": len(revisions)}).Tracef("getDeploymentRevisions")
+	render.JSON(w, r, revisions)
+}
+
+// diffDeploymentRevisions returns the changes between the Pod templates of two revisions of a Deployment. The from and
+// to parameters are the revision numbers, which should be compared.
+func (router *Router) diffDeploymentRevisions(w http.ResponseWriter, r *http.Request) {
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
+		return
+	}
+
+	clusterName := r.URL.Query().Get("cluster")
+	namespace := r.URL.Query().Get("namespace")
+	name := r.URL.Query().Get("name")
+	from := r.URL.Query().Get("from")
+	to := r.URL.Query().Get("to")
+
+	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "name": name, "from": from, "to": to}).Tracef("diffDeploymentRevisions")
+
+	for _, resource := range []string{"deployments", "replicasets"} {
+		if !user.HasResourceAccess(clusterName, namespace, resource) {
+			errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: %s, resource: %s", clusterName, namespace, resource), http.StatusForbidden, "You are not authorized to access the resource")
+			return
+		}
+
+		if router.isForbidden(resource) {
+			errresponse.Render(w, r, nil, http.StatusForbidden, fmt.Sprintf("Access for resource %s is forbidding", resource))
+			return
+		}
+	}
+
+	cluster, err := router.clusters.GetCluster(clusterName)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
+		return
+	}
+
+	parsedFrom, err := strconv.ParseInt(from, 10, 64)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not parse from parameter")
+		return
+	}
+
+	parsedTo, err := strconv.ParseInt(to, 10, 64)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not parse to parameter")
+		return
+	}
+
+	changes, err := cluster.DiffDeploymentRevisions(r.Context(), namespace, name, parsedFrom, parsedTo)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not get diff")
+		return
+	}
+
+	log.WithFields(logrus.Fields{"count": len(changes)}).Tracef("diffDeploymentRevisions")
+	render.JSON(w, r, changes)
+}
+
+// rollbackDeployment rolls back a Deployment to the given revision. If the revision parameter is empty, the Deployment
+// is rolled back to the previous revision.
+func (router *Router) rollbackDeployment(w http.ResponseWriter, r *http.Request) {
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
+		return
+	}
+
+	clusterName := r.URL.Query().Get("cluster")
+	namespace := r.URL.Query().Get("namespace")
+	name := r.URL.Query().Get("name")
+	revision := r.URL.Query().Get("revision")
+
+	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "name": name, "revision": revision}).Tracef("rollbackDeployment")
+
+	for _, resource := range []string{"deployments", "replicasets"} {
+		if !user.HasResourceAccess(clusterName, namespace, resource) {
+			errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: %s, resource: %s", clusterName, namespace, resource), http.StatusForbidden, "You are not authorized to access the resource")
+			return
+		}
+
+		if router.isForbidden(resource) {
+			errresponse.Render(w, r, nil, http.StatusForbidden, fmt.Sprintf("Access for resource %s is forbidding", resource))
+			return
+		}
+	}
+
+	if !router.isMutable("deployments") {
+		errresponse.Render(w, r, nil, http.StatusForbidden, "Resource deployments can not be modified")
+		return
+	}
+
+	cluster, err := router.clusters.GetCluster(clusterName)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
+		return
+	}
+
+	var parsedRevision int64
+	if revision != "" {
+		parsedRevision, err = strconv.ParseInt(revision, 10, 64)
+		if err != nil {
+			errresponse.Render(w, r, err, http.StatusBadRequest, "Could not parse revision parameter")
+			return
+		}
+	}
+
+	err = cluster.RollbackDeployment(r.Context(), namespace, name, parsedRevision)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not rollback deployment")
+		return
+	}
+
+	render.JSON(w, r, nil)
+}
+
+// drainNode cordons the node and evicts all Pods from the node. The grace period for the Pods can be set via the
+// gracePeriodSeconds parameter, if it isn't provided the grace period of the Pod is used. The timeout parameter
+// defines how long we retry evictions, which are blocked by a PodDisruptionBudget. The result contains the status for
+// each Pod, which was running on the node.
+func (router *Router) drainNode(w http.ResponseWriter, r *http.Request) {
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
+		return
+	}
+
+	clusterName := r.URL.Query().Get("cluster")
+	name := r.URL.Query().Get("name")
+	gracePeriodSeconds := r.URL.Query().Get("gracePeriodSeconds")
+	timeout := r.URL.Query().Get("timeout")
+
+	log.WithFields(logrus.Fields{"cluster": clusterName, "name": name, "gracePeriodSeconds": gracePeriodSeconds, "timeout": timeout}).Tracef("drainNode")
+
+	if !user.HasResourceAccess(clusterName, "*", "nodes") || !user.HasResourceAccess(clusterName, "*", "pods") {
+		errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: *, resource: nodes, pods", clusterName), http.StatusForbidden, "You are not authorized to access the resource")
+		return
+	}
+
+	if router.isForbidden("nodes") || router.isForbidden("pods") {
+		errresponse.Render(w, r, nil, http.StatusForbidden, "Access for resource nodes or pods is forbidding")
+		return
+	}
+
+	if !router.isMutable("nodes") || !router.isMutable("pods") {
+		errresponse.Render(w, r, nil, http.StatusForbidden, "Resource nodes or pods can not be modified")
+		return
+	}
+
+	cluster, err := router.clusters.GetCluster(clusterName)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
+		return
+	}
+
+	parsedGracePeriodSeconds := int64(-1)
+	if gracePeriodSeconds != "" {
+		parsedGracePeriodSeconds, err = strconv.ParseInt(gracePeriodSeconds, 10, 64)
+		if err != nil {
+			errresponse.Render(w, r, err, http.StatusBadRequest, "Could not parse grace period parameter")
+			return
+		}
+	}
+
+	parsedTimeout := 5 * time.Minute
+	if timeout != "" {
+		parsedTimeout, err = time.ParseDuration(timeout)
+		if err != nil {
+			errresponse.Render(w, r, err, http.StatusBadRequest, "Could not parse timeout parameter")
+			return
+		}
+	}
+
+	results, err := cluster.DrainNode(r.Context(), name, parsedGracePeriodSeconds, parsedTimeout)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not drain node")
+		return
+	}
+
+	log.WithFields(logrus.Fields{"count": len(results)}).Tracef("drainNode")
+	render.JSON(w, r, results)
+}
+
+// getContainerName returns the container, for which the logs should be returned. When the containerIndex parameter
+// is set, the container is selected by its name first and by its index second, so that the logs can also be retrieved
+// when the name of a container is ambiguous. The function must only be called after the access of the user to the Pod
+// was checked and the returned error must not be passed to the user, because it contains details about the Pod.
+func getContainerName(r *http.Request, cluster *clusterPkg.Cluster, namespace, name, container string) (string, error) {
+	containerIndex := r.URL.Query().Get("containerIndex")
+	if containerIndex == "" {
+		return container, nil
+	}
+
+	parsedContainerIndex, err := strconv.Atoi(containerIndex)
+	if err != nil {
+		return "", fmt.Errorf("could not parse containerIndex parameter: %w", err)
+	}
+
+	return cluster.GetContainerName(r.Context(), namespace, name, container, parsedContainerIndex)
+}
+
+// getPreviousLogs returns the last lines of the logs of the previous instance of a container together with the reason,
+// exit code and finish time of its termination. By default the last 100 lines are returned, which can be changed via
+// the tail parameter. If the container wasn't terminated before, a 404 error is returned.
+func (router *Router) getPreviousLogs(w http.ResponseWriter, r *http.Request) {
+	clusterName := r.URL.Query().Get("cluster")
+	namespace := r.URL.Query().Get("namespace")
+	name := r.URL.Query().Get("name")
+	container := r.URL.Query().Get("container")
+	tail := r.URL.Query().Get("tail")
+
+	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "name": name, "container": container, "tail": tail}).Tracef("getPreviousLogs")
+
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
+		return
+	}
+
+	if !user.HasResourceAccess(clusterName, namespace, "pods") {
+		errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: %s, resource: pods", clusterName, namespace), http.StatusForbidden, "You are not authorized to access the resource")
+		return
+	}
+
+	if router.isForbidden("pods") {
+		errresponse.Render(w, r, nil, http.StatusForbidden, "Access for resource pods is forbidding")
+		return
+	}
+
+	parsedTail := int64(100)
+	if tail != "" {
+		parsedTail, err = strconv.ParseInt(tail, 10, 64)
+		if err != nil {
+			errresponse.Render(w, r, err, http.StatusBadRequest, "Could not parse tail parameter")
+			return
+		}
+	}
+
+	cluster, err := router.clusters.GetCluster(clusterName)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
+		return
+	}
+
+	container, err = getContainerName(r, cluster, namespace, name, container)
+	if err != nil {
+		log.WithError(err).Warnf("Invalid container")
+		errresponse.Render(w, r, nil, http.StatusBadRequest, "Invalid container")
+		return
+	}
+
+	previousLogs, err := cluster.GetPreviousLogs(r.Context(), namespace, name, container, parsedTail)
+	if err != nil {
+		if errors.Is(err, clusterPkg.ErrNoPreviousContainer) {
+			errresponse.Render(w, r, err, http.StatusNotFound, "Container has no previous instance")
+			return
+		}
+
+		errresponse.Render(w, r, err, http.StatusBadGateway, "Could not get previous logs")
+		return
+	}
+
+	log.WithFields(logrus.Fields{"reason": previousLogs.Reason, "exitCode": previousLogs.ExitCode}).Tracef("getPreviousLogs")
+	render.JSON(w, r, previousLogs)
+}
+
+// getLogs returns the logs for the container of a pod in a cluster and namespace. A user can also set the time since
+// when the logs should be returned. The lineTerminator parameter can be used to select how the lines are joined ("lf",
+// "crlf" or "lfcr"). By default the lines are joined by "\n\r" as it is required by the terminal in the frontend.
+func (router *Router) getLogs(w http.ResponseWriter, r *http.Request) {
+	clusterName := r.URL.Query().Get("cluster")
+	namespace := r.URL.Query().Get("namespace")
+	name := r.URL.Query().Get("name")
+	container := r.URL.Query().Get("container")
+	regexes := r.URL.Query()["regex"]
+	since := r.URL.Query().Get("since")
+	tail := r.URL.Query().Get("tail")
+	previous := r.URL.Query().Get("previous")
+	follow := r.URL.Query().Get("follow")
+	lineTerminator := r.URL.Query().Get("lineTerminator")
+
+	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "name": name, "container": container, "regexes": regexes, "since": since, "previous": previous, "follow": follow, "lineTerminator": lineTerminator}).Tracef("getLogs")
+
+	cluster, err := router.clusters.GetCluster(clusterName)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
+		return
+	}
+
+	parsedSince, err := strconv.ParseInt(since, 10, 64)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not parse since parameter")
+		return
+	}
+
+	parsedTail, err := strconv.ParseInt(tail, 10, 64)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not parse tail parameter")
+		return
+	}
+
+	parsedPrevious, err := strconv.ParseBool(previous)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not parse previous parameter")
+		return
+	}
+
+	parsedFollow, err := strconv.ParseBool(follow)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not parse follow parameter")
+		return
+	}
+
+	// If the parsedFollow parameter was set to true, we stream the logs via an WebSocket connection instead of
+	// returning a json response.
+	if parsedFollow {
+		var upgrader = websocket.Upgrader{}
+
+		if router.config.WebSocket.AllowAllOrigins {
+			upgrader.CheckOrigin = func(r *http.Request) bool { return true }
+		}
+
+		c, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			log.WithError(err).Errorf("Could not upgrade connection")
+			return
+		}
+		defer c.Close()
+
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+
+		// The context of the request isn't canceled when the client closes the WebSocket connection, so that we have
+		// to read from the connection to detect the close of the connection and to stop the log stream.
+		k := newKeepAlive(c, "logs", router.config.WebSocket.IdleTimeout)
+		defer k.close()
+
+		go k.read(cancel)
+		go k.ping(ctx)
+
+		user, err := authContext.GetUser(r.Context())
+		if err != nil {
+			c.WriteMessage(websocket.TextMessage, []byte("You are not authorized to access the resource"))
+			return
+		}
+
+		if !user.HasResourceAccess(clusterName, namespace, "pods") {
+			c.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf("You are not authorized to access the resource: cluster: %s, namespace: %s, resource: pods", clusterName, namespace)))
+			return
+		}
+
+		container, err = getContainerName(r, cluster, namespace, name, container)
+		if err != nil {
+			log.WithError(err).Warnf("Invalid container")
+			c.WriteMessage(websocket.TextMessage, []byte("Invalid container"))
+			return
+		}
+
+		err = cluster.StreamLogs(ctx, c, namespace, name, container, parsedSince, parsedTail, parsedFollow, router.config.WebSocket.MaxMessageSize)
+		if err != nil {
+			if errors.Is(err, clusterPkg.ErrStreamReconnect) || ctx.Err() != nil {
+				return
+			}
+
+			c.WriteMessage(websocket.TextMessage, []byte("Could not stream logs: "+err.Error()))
+			return
+		}
+
+		log.Tracef("Logs stream was closed")
+		return
+	}
+
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
+		return
+	}
+
+	if !user.HasResourceAccess(clusterName, namespace, "pods") {
+		errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: %s, resource: pods", clusterName, namespace), http.StatusForbidden, "You are not authorized to access the resource")
+		return
+	}
+
+	container, err = getContainerName(r, cluster, namespace, name, container)
+	if err != nil {
+		log.WithError(err).Warnf("Invalid container")
+		errresponse.Render(w, r, nil, http.StatusBadRequest, "Invalid container")
+		return
+	}
+
+	parsedLineTerminator, err := clusterPkg.GetLineTerminator(lineTerminator)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not parse lineTerminator parameter")
+		return
+	}
+
+	logs, err := cluster.GetLogs(r.Context(), namespace, name, container, regexes, parsedSince, parsedTail, parsedPrevious, parsedLineTerminator)
+	if err != nil {
+		errresponse.Render(w, r, err, getLogsErrorStatus(err), "Could not get logs")
+		return
+	}
+
+	log.WithFields(logrus.Fields{"count": len(logs)}).Tracef("getLogs")
+	render.JSON(w, r, struct {
+		Logs string `json:"logs"`
+	}{logs})
+}
+
+// getLogsErrorStatus returns the status code for an error returned by the GetLogs and GetLogsReader functions. An
+// invalid regular expression is an error of the client, all other errors are caused by the Kubernetes API.
+func getLogsErrorStatus(err error) int {
+	if errors.Is(err, clusterPkg.ErrInvalidRegex) {
+		return http.StatusBadRequest
+	}
+
+	return http.StatusBadGateway
+}
+
+// downloadLogs returns the logs for the container of a pod as gzip compressed file. The logs are compressed while they
+// are streamed from the Kubernetes API, so that we do not have to keep large logs in memory. The logs can be filtered
+// via the same regex parameters as they are used in the getLogs function.
+// The body is always compressed via gzip, but we are not setting the "Content-Encoding" header, because the compressed
+// file should be saved as it is and should not be decompressed by the browser or a proxy.
+func (router *Router) downloadLogs(w http.ResponseWriter, r *http.Request) {
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
+		return
+	}
+
+	clusterName := r.URL.Query().Get("cluster")
+	namespace := r.URL.Query().Get("namespace")
+	name := r.URL.Query().Get("name")
+	container := r.URL.Query().Get("container")
+	regexes := r.URL.Query()["regex"]
+	since := r.URL.Query().Get("since")
+	tail := r.URL.Query().Get("tail")
+	previous := r.URL.Query().Get("previous")
+
+	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "name": name, "container": container, "regexes": regexes, "since": since, "tail": tail, "previous": previous}).Tracef("downloadLogs")
+
+	if !user.HasResourceAccess(clusterName, namespace, "pods") {
+		errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: %s, resource: pods", clusterName, namespace), http.StatusForbidden, "You are not authorized to access the resource")
+		return
+	}
+
+	if router.isForbidden("pods") {
+		errresponse.Render(w, r, nil, http.StatusForbidden, "Access for resource pods is forbidding")
+		return
+	}
+
+	cluster, err := router.clusters.GetCluster(clusterName)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
+		return
+	}
+
+	container, err = getContainerName(r, cluster, namespace, name, container)
+	if err != nil {
+		log.WithError(err).Warnf("Invalid container")
+		errresponse.Render(w, r, nil, http.StatusBadRequest, "Invalid container")
+		return
+	}
+
+	parsedSince, err := strconv.ParseInt(since, 10, 64)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not parse since parameter")
+		return
+	}
+
+	parsedTail, err := strconv.ParseInt(tail, 10, 64)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not parse tail parameter")
+		return
+	}
+
+	parsedPrevious, err := strconv.ParseBool(previous)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not parse previous parameter")
+		return
+	}
+
+	logs, err := cluster.GetLogsReader(r.Context(), namespace, name, container, regexes, parsedSince, parsedTail, parsedPrevious)
+	if err != nil {
+		errresponse.Render(w, r, err, getLogsErrorStatus(err), "Could not get logs")
+		return
+	}
+	defer logs.Close()
+
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s-%s.log.gz\"", name, container))
+	w.WriteHeader(http.StatusOK)
+
+	gw := gzip.NewWriter(w)
+	defer gw.Close()
+
+	if _, err := io.Copy(gw, logs); err != nil {
+		log.WithError(err).Errorf("Could not write logs")
+	}
+}
+
+// getJobLogs streams the logs of all Pods of a Job via a WebSocket connection. The Pods of the Job are tracked, so that
+// the logs of newly created Pods are streamed automatically. Each line is prefixed with the name of the Pod.
+func (router *Router) getJobLogs(w http.ResponseWriter, r *http.Request) {
+	clusterName := r.URL.Query().Get("cluster")
+	namespace := r.URL.Query().Get("namespace")
+	name := r.URL.Query().Get("name")
+	container := r.URL.Query().Get("container")
+	since := r.URL.Query().Get("since")
+
+	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "name": name, "container": container, "since": since}).Tracef("getJobLogs")
+
+	cluster, err := router.clusters.GetCluster(clusterName)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
+		return
+	}
+
+	parsedSince, err := strconv.ParseInt(since, 10, 64)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not parse since parameter")
+		return
+	}
+
+	var upgrader = websocket.Upgrader{}
+
+	if router.config.WebSocket.AllowAllOrigins {
+		upgrader.CheckOrigin = func(r *http.Request) bool { return true }
+	}
+
+	c, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.WithError(err).Errorf("Could not upgrade connection")
+		return
+	}
+	defer c.Close()
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	// The context of the request isn't canceled when the WebSocket connection is closed, so that we have to read from
+	// the connection to detect the close of the connection. Otherwise we would poll the Job until it is finished.
+	k := newKeepAlive(c, "logs", router.config.WebSocket.IdleTimeout)
+	defer k.close()
+
+	go k.read(cancel)
+	go k.ping(ctx)
+
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		c.WriteMessage(websocket.TextMessage, []byte("You are not authorized to access the resource"))
+		return
+	}
+
+	if !user.HasResourceAccess(clusterName, namespace, "jobs") || !user.HasResourceAccess(clusterName, namespace, "pods") {
+		c.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf("You are not authorized to access the resource: cluster: %s, namespace: %s, resource: jobs, pods", clusterName, namespace)))
+		return
+	}
+
+	if router.isForbidden("jobs") || router.isForbidden("pods") {
+		c.WriteMessage(websocket.TextMessage, []byte("Access for resource jobs or pods is forbidding"))
+		return
+	}
+
+	err = cluster.StreamJobLogs(ctx, c, namespace, name, container, parsedSince, router.config.WebSocket.MaxMessageSize)
+	if err != nil {
+		c.WriteMessage(websocket.TextMessage, []byte("Could not stream logs: "+err.Error()))
+		return
+	}
+
+	log.Tracef("Job logs stream was closed")
+}
+
+// getWorkloadLogs streams the logs of all Pods of a workload (e.g. a Deployment or Service) via a WebSocket connection.
+// The workload is defined via the resource and name parameter. The number of Pods, for which the logs are streamed at
+// the same time, is limited by the maxLogStreams option of the WebSocket configuration.
+func (router *Router) getWorkloadLogs(w http.ResponseWriter, r *http.Request) {
+	clusterName := r.URL.Query().Get("cluster")
+	namespace := r.URL.Query().Get("namespace")
+	resource := r.URL.Query().Get("resource")
+	name := r.URL.Query().Get("name")
+	container := r.URL.Query().Get("container")
+	since := r.URL.Query().Get("since")
+
+	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "resource": resource, "name": name, "container": container, "since": since}).Tracef("getWorkloadLogs")
+
+	cluster, err := router.clusters.GetCluster(clusterName)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
+		return
+	}
+
+	parsedSince, err := strconv.ParseInt(since, 10, 64)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not parse since parameter")
+		return
+	}
+
+	var upgrader = websocket.Upgrader{}
+
+	if router.config.WebSocket.AllowAllOrigins {
+		upgrader.CheckOrigin = func(r *http.Request) bool { return true }
+	}
+
+	c, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.WithError(err).Errorf("Could not upgrade connection")
+		return
+	}
+	defer c.Close()
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	// In contrast to the logs of a Job, the logs of a workload are streamed until the user closes the connection. Since
+	// the context of the request isn't canceled when the WebSocket connection is closed, we have to read from the
+	// connection to detect the close of the connection.
+	k := newKeepAlive(c, "logs", router.config.WebSocket.IdleTimeout)
+	defer k.close()
+
+	go k.read(cancel)
+	go k.ping(ctx)
+
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		c.WriteMessage(websocket.TextMessage, []byte("You are not authorized to access the resource"))
+		return
+	}
+
+	if !user.HasResourceAccess(clusterName, namespace, resource) || !user.HasResourceAccess(clusterName, namespace, "pods") {
+		c.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf("You are not authorized to access the resource: cluster: %s, namespace: %s, resource: %s, pods", clusterName, namespace, resource)))
+		return
+	}
+
+	if router.isForbidden(resource) || router.isForbidden("pods") {
+		c.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf("Access for resource %s is forbidding", resource)))
+		return
+	}
+
+	err = cluster.StreamWorkloadLogs(ctx, c, namespace, resource, name, container, parsedSince, router.config.WebSocket.MaxMessageSize, router.config.WebSocket.MaxLogStreams)
+	if err != nil {
+		c.WriteMessage(websocket.TextMessage, []byte("Could not stream logs: "+err.Error()))
+		return
+	}
+
+	log.Tracef("Workload logs stream was closed")
+}
+
+// getImages returns the images of all containers of a Pod. Next to the image from the spec, we also return the
+// resolved image id and digest of each container.
+func (router *Router) getImages(w http.ResponseWriter, r *http.Request) {
+	clusterName := r.URL.Query().Get("cluster")
+	namespace := r.URL.Query().Get("namespace")
+	name := r.URL.Query().Get("name")
+
+	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "name": name}).Tracef("getImages")
+
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
+		return
+	}
+
+	if !user.HasResourceAccess(clusterName, namespace, "pods") {
+		errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: %s, resource: pods", clusterName, namespace), http.StatusForbidden, "You are not authorized to access the resource")
+		return
+	}
+
+	if router.isForbidden("pods") {
+		errresponse.Render(w, r, nil, http.StatusForbidden, "Access for resource pods is forbidding")
+		return
+	}
+
+	cluster, err := router.clusters.GetCluster(clusterName)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
+		return
+	}
+
+	images, err := cluster.GetPodImages(r.Context(), namespace, name)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not get images")
+		return
+	}
+
+	log.WithFields(logrus.Fields{"count": len(images)}).Tracef("getImages")
+	render.JSON(w, r, images)
+}
+
+// getContainerStatus returns the status of all containers of a Pod, including the restart count and the reason and exit
+// code of the last termination. This can be used to show why a container is crashlooping.
+func (router *Router) getContainerStatus(w http.ResponseWriter, r *http.Request) {
+	clusterName := r.URL.Query().Get("cluster")
+	namespace := r.URL.Query().Get("namespace")
+	name := r.URL.Query().Get("name")
+
+	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "name": name}).Tracef("getContainerStatus")
+
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
+		return
+	}
+
+	if !user.HasResourceAccess(clusterName, namespace, "pods") {
+		errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: %s, resource: pods", clusterName, namespace), http.StatusForbidden, "You are not authorized to access the resource")
+		return
+	}
+
+	if router.isForbidden("pods") {
+		errresponse.Render(w, r, nil, http.StatusForbidden, "Access for resource pods is forbidding")
+		return
+	}
+
+	cluster, err := router.clusters.GetCluster(clusterName)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
+		return
+	}
+
+	statuses, err := cluster.GetPodContainerStatus(r.Context(), namespace, name)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not get container status")
+		return
+	}
+
+	log.WithFields(logrus.Fields{"count": len(statuses)}).Tracef("getContainerStatus")
+	render.JSON(w, r, statuses)
+}
+
+// getPodProbes returns the liveness, readiness and startup probes of all containers of a Pod, together with the
+// conditions of the Pod and the latest events for failed probes. This can be used to show why a container isn't ready.
+func (router *Router) getPodProbes(w http.ResponseWriter, r *http.Request) {
+	clusterName := r.URL.Query().Get("cluster")
+	namespace := r.URL.Query().Get("namespace")
+	name := r.URL.Query().Get("name")
+
+	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "name": name}).Tracef("getPodProbes")
+
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
+		return
+	}
+
+	for _, resource := range []string{"pods", "events"} {
+		if !user.HasResourceAccess(clusterName, namespace, resource) {
+			errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: %s, resource: %s", clusterName, namespace, resource), http.StatusForbidden, "You are not authorized to access the resource")
+			return
+		}
+
+		if router.isForbidden(resource) {
+			errresponse.Render(w, r, nil, http.StatusForbidden, fmt.Sprintf("Access for resource %s is forbidding", resource))
+			return
+		}
+	}
+
+	cluster, err := router.clusters.GetCluster(clusterName)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
+		return
+	}
+
+	probes, err := cluster.GetPodProbes(r.Context(), namespace, name)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not get probes")
+		return
+	}
+
+	log.WithFields(logrus.Fields{"count": len(probes.Containers)}).Tracef("getPodProbes")
+	render.JSON(w, r, probes)
+}
+
+// getPodSecurity returns the security settings of a Pod and the effective security context of all containers of the
+// Pod, together with a list of risky settings.
+func (router *Router) getPodSecurity(w http.ResponseWriter, r *http.Request) {
+	clusterName := r.URL.Query().Get("cluster")
+	namespace := r.URL.Query().Get("namespace")
+	name := r.URL.Query().Get("name")
+
+	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "name": name}).Tracef("getPodSecurity")
+
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
+		return
+	}
+
+	if !user.HasResourceAccess(clusterName, namespace, "pods") {
+		errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: %s, resource: pods", clusterName, namespace), http.StatusForbidden, "You are not authorized to access the resource")
+		return
+	}
+
+	if router.isForbidden("pods") {
+		errresponse.Render(w, r, nil, http.StatusForbidden, "Access for resource pods is forbidding")
+		return
+	}
+
+	cluster, err := router.clusters.GetCluster(clusterName)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
+		return
+	}
+
+	security, err := cluster.GetPodSecurity(r.Context(), namespace, name)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not get security context")
+		return
+	}
+
+	log.WithFields(logrus.Fields{"count": len(security.Containers)}).Tracef("getPodSecurity")
+	render.JSON(w, r, security)
+}
+
+// getHPA returns the status of the HorizontalPodAutoscaler, which targets the workload with the given kind and name.
+// The kind parameter is optional. If no HorizontalPodAutoscaler targets the workload, a 404 error is returned.
+func (router *Router) getHPA(w http.ResponseWriter, r *http.Request) {
+	clusterName := r.URL.Query().Get("cluster")
+	namespace := r.URL.Query().Get("namespace")
+	kind := r.URL.Query().Get("kind")
+	name := r.URL.Query().Get("name")
+
+	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "kind": kind, "name": name}).Tracef("getHPA")
+
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
+		return
+	}
+
+	for _, resource := range []string{"horizontalpodautoscalers", "events"} {
+		if !user.HasResourceAccess(clusterName, namespace, resource) {
+			errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: %s, resource: %s", clusterName, namespace, resource), http.StatusForbidden, "You are not authorized to access the resource")
+			return
+		}
+
+		if router.isForbidden(resource) {
+			errresponse.Render(w, r, nil, http.StatusForbidden, fmt.Sprintf("Access for resource %s is forbidding", resource))
+			return
+		}
+	}
+
+	cluster, err := router.clusters.GetCluster(clusterName)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
+		return
+	}
+
+	hpa, err := cluster.GetHPA(r.Context(), namespace, kind, name)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not get HorizontalPodAutoscaler")
+		return
+	}
+
+	if hpa == nil {
+		errresponse.Render(w, r, nil, http.StatusNotFound, "No HorizontalPodAutoscaler found for the workload")
+		return
+	}
+
+	log.WithFields(logrus.Fields{"hpa": hpa.Name}).Tracef("getHPA")
+	render.JSON(w, r, hpa)
+}
+
+// getRestarts returns the sum of all container restarts in a namespace and the containers with the most restarts. The
+// number of returned containers can be set via the limit parameter, which defaults to 10.
+func (router *Router) getRestarts(w http.ResponseWriter, r *http.Request) {
+	clusterName := r.URL.Query().Get("cluster")
+	namespace := r.URL.Query().Get("namespace")
+	limit := r.URL.Query().Get("limit")
+
+	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "limit": limit}).Tracef("getRestarts")
+
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
+		return
+	}
+
+	if !user.HasResourceAccess(clusterName, namespace, "pods") {
+		errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: %s, resource: pods", clusterName, namespace), http.StatusForbidden, "You are not authorized to access the resource")
+		return
+	}
+
+	if router.isForbidden("pods") {
+		errresponse.Render(w, r, nil, http.StatusForbidden, "Access for resource pods is forbidding")
+		return
+	}
+
+	cluster, err := router.clusters.GetCluster(clusterName)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
+		return
+	}
+
+	parsedLimit := 10
+	if limit != "" {
+		parsedLimit, err = strconv.Atoi(limit)
+		if err != nil {
+			errresponse.Render(w, r, err, http.StatusBadRequest, "Could not parse limit parameter")
+			return
+		}
+	}
+
+	restarts, err := cluster.GetRestarts(r.Context(), namespace, parsedLimit)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not get restarts")
+		return
+	}
+
+	log.WithFields(logrus.Fields{"total": restarts.Total, "count": len(restarts.Containers)}).Tracef("getRestarts")
+	render.JSON(w, r, restarts)
+}
+
+// getPodScheduling returns the node, zone and region of all Pods in a namespace, which are matching the optional label
+// selector, together with a summary of the scheduling constraints of each Pod. Because the node labels are used to
+// get the zone and region, the user must also have access to nodes.
+func (router *Router) getPodScheduling(w http.ResponseWriter, r *http.Request) {
+	clusterName := r.URL.Query().Get("cluster")
+	namespace := r.URL.Query().Get("namespace")
+	labelSelector := r.URL.Query().Get("labelSelector")
+
+	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "labelSelector": labelSelector}).Tracef("getPodScheduling")
+
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
 		return
 	}
 
-	cluster := router.clusters.GetCluster(clusterName)
-	if cluster == nil {
-		errresponse.Render(w, r, nil, http.StatusBadRequest, "Invalid cluster name")
+	if !user.HasResourceAccess(clusterName, namespace, "pods") {
+		errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: %s, resource: pods", clusterName, namespace), http.StatusForbidden, "You are not authorized to access the resource")
 		return
 	}
 
-	if router.isForbidden(resource) {
-		errresponse.Render(w, r, nil, http.StatusForbidden, fmt.Sprintf("Access for resource %s is forbidding", resource))
+	if !user.HasResourceAccess(clusterName, "*", "nodes") {
+		errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: *, resource: nodes", clusterName), http.StatusForbidden, "You are not authorized to access the resource")
 		return
 	}
 
-	parsedForce, err := strconv.ParseBool(force)
-	if err != nil {
-		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not parse force parameter")
+	if router.isForbidden("pods") || router.isForbidden("nodes") {
+		errresponse.Render(w, r, nil, http.StatusForbidden, "Access for resource pods or nodes is forbidding")
 		return
 	}
 
-	var body []byte
-	if parsedForce {
-		body = []byte(`{"gracePeriodSeconds": 0}`)
+	cluster, err := router.clusters.GetCluster(clusterName)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
+		return
 	}
 
-	err = cluster.DeleteResource(r.Context(), namespace, name, path, resource, body)
+	schedulings, err := cluster.GetPodScheduling(r.Context(), namespace, labelSelector)
 	if err != nil {
-		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not delete resource")
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not get scheduling information")
 		return
 	}
 
-	render.JSON(w, r, nil)
+	log.WithFields(logrus.Fields{"count": len(schedulings)}).Tracef("getPodScheduling")
+	render.JSON(w, r, schedulings)
 }
 
-// patchResource hadnles patch operations for resources. The resource can be identified by the given cluster,
-// namespace, name, resource and path. The patch operation must be provided in the request body.
-func (router *Router) patchResource(w http.ResponseWriter, r *http.Request) {
+// getIngresses returns all Ingresses of a namespace with their hosts and routes. The optional filter parameter can be
+// used to only return Ingresses, where a host or a backend Service contains the filter. When the resolveBackends
+// parameter is true, we also check if the backend Services exist and how many ready endpoints they have, for that the
+// user must also have access to the Services and Endpoints.
+func (router *Router) getIngresses(w http.ResponseWriter, r *http.Request) {
+	clusterName := r.URL.Query().Get("cluster")
+	namespace := r.URL.Query().Get("namespace")
+	filter := r.URL.Query().Get("filter")
+	resolveBackends := r.URL.Query().Get("resolveBackends")
+
+	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "filter": filter, "resolveBackends": resolveBackends}).Tracef("getIngresses")
+
 	user, err := authContext.GetUser(r.Context())
 	if err != nil {
 		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
 		return
 	}
 
-	clusterName := r.URL.Query().Get("cluster")
-	namespace := r.URL.Query().Get("namespace")
-	name := r.URL.Query().Get("name")
-	resource := r.URL.Query().Get("resource")
-	path := r.URL.Query().Get("path")
-
-	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "name": name, "resource": resource, "path": path}).Tracef("patchResource")
-
-	if !user.HasResourceAccess(clusterName, namespace, resource) {
-		errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: %s, resource: %s", clusterName, namespace, resource), http.StatusForbidden, "You are not authorized to access the resource")
-		return
+	parsedResolveBackends := false
+	if resolveBackends != "" {
+		parsedResolveBackends, err = strconv.ParseBool(resolveBackends)
+		if err != nil {
+			errresponse.Render(w, r, err, http.StatusBadRequest, "Could not parse resolveBackends parameter")
+			return
+		}
 	}
 
-	cluster := router.clusters.GetCluster(clusterName)
-	if cluster == nil {
-		errresponse.Render(w, r, nil, http.StatusBadRequest, "Invalid cluster name")
-		return
+	resources := []string{"ingresses"}
+	if parsedResolveBackends {
+		resources = append(resources, "services", "endpoints")
 	}
 
-	if router.isForbidden(resource) {
-		errresponse.Render(w, r, nil, http.StatusForbidden, fmt.Sprintf("Access for resource %s is forbidding", resource))
-		return
+	for _, resource := range resources {
+		if !user.HasResourceAccess(clusterName, namespace, resource) {
+			errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: %s, resource: %s", clusterName, namespace, resource), http.StatusForbidden, "You are not authorized to access the resource")
+			return
+		}
+
+		if router.isForbidden(resource) {
+			errresponse.Render(w, r, nil, http.StatusForbidden, fmt.Sprintf("Access for resource %s is forbidding", resource))
+			return
+		}
 	}
 
-	body, err := ioutil.ReadAll(r.Body)
+	cluster, err := router.clusters.GetCluster(clusterName)
 	if err != nil {
-		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not decode request body")
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
 		return
 	}
 
-	err = cluster.PatchResource(r.Context(), namespace, name, path, resource, body)
+	ingresses, err := cluster.GetIngresses(r.Context(), namespace, parsedResolveBackends)
 	if err != nil {
-		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not patch resource")
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not get ingresses")
 		return
 	}
 
-	render.JSON(w, r, nil)
+	ingresses = clusterPkg.FilterIngresses(ingresses, filter)
+
+	log.WithFields(logrus.Fields{"count": len(ingresses)}).Tracef("getIngresses")
+	render.JSON(w, r, ingresses)
 }
 
-// createResource hadnles patch operations for resources. The resource can be identified by the given cluster,
-// namespace, name, resource and path. The resource must be provided in the request body.
-func (router *Router) createResource(w http.ResponseWriter, r *http.Request) {
+// getHelmRelease returns a Helm release with the resources which are managed by the release. The driver parameter must
+// be "secret" (default) or "configmap", depending on the storage driver used by Helm. Since Helm stores the release in
+// a Secret or ConfigMap, the user must have access to these resources. If the version parameter is not set, the latest
+// version of the release is returned.
+func (router *Router) getHelmRelease(w http.ResponseWriter, r *http.Request) {
+	clusterName := r.URL.Query().Get("cluster")
+	namespace := r.URL.Query().Get("namespace")
+	name := r.URL.Query().Get("name")
+	driver := r.URL.Query().Get("driver")
+	version := r.URL.Query().Get("version")
+
+	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "name": name, "driver": driver, "version": version}).Tracef("getHelmRelease")
+
 	user, err := authContext.GetUser(r.Context())
 	if err != nil {
 		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
 		return
 	}
 
-	clusterName := r.URL.Query().Get("cluster")
-	namespace := r.URL.Query().Get("namespace")
-	name := r.URL.Query().Get("name")
-	resource := r.URL.Query().Get("resource")
-	subResource := r.URL.Query().Get("subResource")
-	path := r.URL.Query().Get("path")
-
-	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "name": name, "path": path, "resource": resource, "subResource": subResource}).Tracef("createResource")
+	resource := "secrets"
+	if driver == "configmap" {
+		resource = "configmaps"
+	}
 
 	if !user.HasResourceAccess(clusterName, namespace, resource) {
 		errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: %s, resource: %s", clusterName, namespace, resource), http.StatusForbidden, "You are not authorized to access the resource")
 		return
 	}
 
-	cluster := router.clusters.GetCluster(clusterName)
-	if cluster == nil {
-		errresponse.Render(w, r, nil, http.StatusBadRequest, "Invalid cluster name")
-		return
-	}
-
 	if router.isForbidden(resource) {
 		errresponse.Render(w, r, nil, http.StatusForbidden, fmt.Sprintf("Access for resource %s is forbidding", resource))
 		return
 	}
 
-	body, err := ioutil.ReadAll(r.Body)
+	cluster, err := router.clusters.GetCluster(clusterName)
 	if err != nil {
-		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not decode request body")
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
 		return
 	}
 
-	err = cluster.CreateResource(r.Context(), namespace, name, path, resource, subResource, body)
+	var parsedVersion int64
+	if version != "" {
+		parsedVersion, err = strconv.ParseInt(version, 10, 64)
+		if err != nil {
+			errresponse.Render(w, r, err, http.StatusBadRequest, "Could not parse version parameter")
+			return
+		}
+	}
+
+	release, err := cluster.GetHelmRelease(r.Context(), namespace, name, driver, parsedVersion)
 	if err != nil {
-		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not create resource")
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not get Helm release")
 		return
 	}
 
-	render.JSON(w, r, nil)
+	log.WithFields(logrus.Fields{"version": release.Version, "resources": len(release.Resources)}).Tracef("getHelmRelease")
+	render.JSON(w, r, release)
 }
 
-// getLogs returns the logs for the container of a pod in a cluster and namespace. A user can also set the time since
-// when the logs should be returned.
-func (router *Router) getLogs(w http.ResponseWriter, r *http.Request) {
+// getNetworkPolicies returns all NetworkPolicies, which are selecting the given Pod, together with their ingress and
+// egress rules. This can be used to see why the traffic to or from a Pod is blocked.
+func (router *Router) getNetworkPolicies(w http.ResponseWriter, r *http.Request) {
 	clusterName := r.URL.Query().Get("cluster")
 	namespace := r.URL.Query().Get("namespace")
 	name := r.URL.Query().Get("name")
-	container := r.URL.Query().Get("container")
-	regex := r.URL.Query().Get("regex")
-	since := r.URL.Query().Get("since")
-	tail := r.URL.Query().Get("tail")
-	previous := r.URL.Query().Get("previous")
-	follow := r.URL.Query().Get("follow")
 
-	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "name": name, "container": container, "regex": regex, "since": since, "previous": previous, "follow": follow}).Tracef("getLogs")
+	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "name": name}).Tracef("getNetworkPolicies")
 
-	cluster := router.clusters.GetCluster(clusterName)
-	if cluster == nil {
-		errresponse.Render(w, r, nil, http.StatusBadRequest, "Invalid cluster name")
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
 		return
 	}
 
-	parsedSince, err := strconv.ParseInt(since, 10, 64)
-	if err != nil {
-		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not parse since parameter")
-		return
+	for _, resource := range []string{"pods", "networkpolicies"} {
+		if !user.HasResourceAccess(clusterName, namespace, resource) {
+			errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: %s, resource: %s", clusterName, namespace, resource), http.StatusForbidden, "You are not authorized to access the resource")
+			return
+		}
+
+		if router.isForbidden(resource) {
+			errresponse.Render(w, r, nil, http.StatusForbidden, fmt.Sprintf("Access for resource %s is forbidding", resource))
+			return
+		}
 	}
 
-	parsedTail, err := strconv.ParseInt(tail, 10, 64)
+	cluster, err := router.clusters.GetCluster(clusterName)
 	if err != nil {
-		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not parse tail parameter")
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
 		return
 	}
 
-	parsedPrevious, err := strconv.ParseBool(previous)
+	networkPolicies, err := cluster.GetNetworkPoliciesForPod(r.Context(), namespace, name)
 	if err != nil {
-		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not parse previous parameter")
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not get network policies")
 		return
 	}
 
-	parsedFollow, err := strconv.ParseBool(follow)
+	log.WithFields(logrus.Fields{"count": len(networkPolicies)}).Tracef("getNetworkPolicies")
+	render.JSON(w, r, networkPolicies)
+}
+
+// getPodPermissions returns the ServiceAccount of the given Pod, all RoleBindings and ClusterRoleBindings which are
+// referencing the ServiceAccount and a normalized summary of the granted permissions. Since the ClusterRoleBindings and
+// ClusterRoles are cluster scoped the user must have access to them in all namespaces.
+func (router *Router) getPodPermissions(w http.ResponseWriter, r *http.Request) {
+	clusterName := r.URL.Query().Get("cluster")
+	namespace := r.URL.Query().Get("namespace")
+	name := r.URL.Query().Get("name")
+
+	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "name": name}).Tracef("getPodPermissions")
+
+	user, err := authContext.GetUser(r.Context())
 	if err != nil {
-		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not parse follow parameter")
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
 		return
 	}
 
-	// If the parsedFollow parameter was set to true, we stream the logs via an WebSocket connection instead of
-	// returning a json response.
-	if parsedFollow {
-		var upgrader = websocket.Upgrader{}
+	for _, resource := range []string{"pods", "rolebindings", "roles"} {
+		if !user.HasResourceAccess(clusterName, namespace, resource) {
+			errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: %s, resource: %s", clusterName, namespace, resource), http.StatusForbidden, "You are not authorized to access the resource")
+			return
+		}
 
-		if router.config.WebSocket.AllowAllOrigins {
-			upgrader.CheckOrigin = func(r *http.Request) bool { return true }
+		if router.isForbidden(resource) {
+			errresponse.Render(w, r, nil, http.StatusForbidden, fmt.Sprintf("Access for resource %s is forbidding", resource))
+			return
 		}
+	}
 
-		c, err := upgrader.Upgrade(w, r, nil)
-		if err != nil {
-			log.WithError(err).Errorf("Could not upgrade connection")
+	for _, resource := range []string{"clusterrolebindings", "clusterroles"} {
+		if !user.HasResourceAccess(clusterName, "*", resource) {
+			errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: *, resource: %s", clusterName, resource), http.StatusForbidden, "You are not authorized to access the resource")
 			return
 		}
-		defer c.Close()
 
-		c.SetPongHandler(func(string) error { return nil })
+		if router.isForbidden(resource) {
+			errresponse.Render(w, r, nil, http.StatusForbidden, fmt.Sprintf("Access for resource %s is forbidding", resource))
+			return
+		}
+	}
 
-		go func() {
-			ticker := time.NewTicker(pingPeriod)
-			defer ticker.Stop()
+	cluster, err := router.clusters.GetCluster(clusterName)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
+		return
+	}
 
-			for {
-				select {
-				case <-ticker.C:
-					if err := c.WriteMessage(websocket.PingMessage, nil); err != nil {
-						return
-					}
-				}
-			}
-		}()
+	permissions, err := cluster.GetPodPermissions(r.Context(), namespace, name)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not get permissions")
+		return
+	}
 
-		user, err := authContext.GetUser(r.Context())
+	log.WithFields(logrus.Fields{"serviceAccount": permissions.ServiceAccount, "bindings": len(permissions.Bindings), "rules": len(permissions.Rules)}).Tracef("getPodPermissions")
+	render.JSON(w, r, permissions)
+}
+
+// getContainerEnv returns the resolved environment of all containers of a Pod. The user must have access to the Pod
+// and the ConfigMaps in the namespace. The values of Secrets are masked, unless the user sets the resolveSecrets
+// parameter to true. In this case the user must also have access to the Secrets and Secrets must not be forbidden via
+// the plugin configuration. Each request which resolves the values of Secrets is logged.
+func (router *Router) getContainerEnv(w http.ResponseWriter, r *http.Request) {
+	clusterName := r.URL.Query().Get("cluster")
+	namespace := r.URL.Query().Get("namespace")
+	name := r.URL.Query().Get("name")
+	resolveSecrets := r.URL.Query().Get("resolveSecrets")
+
+	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "name": name, "resolveSecrets": resolveSecrets}).Tracef("getContainerEnv")
+
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
+		return
+	}
+
+	parsedResolveSecrets := false
+	if resolveSecrets != "" {
+		parsedResolveSecrets, err = strconv.ParseBool(resolveSecrets)
 		if err != nil {
-			c.WriteMessage(websocket.TextMessage, []byte("You are not authorized to access the resource"))
+			errresponse.Render(w, r, err, http.StatusBadRequest, "Could not parse resolveSecrets parameter")
 			return
 		}
+	}
 
-		if !user.HasResourceAccess(clusterName, namespace, "pods") {
-			c.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf("You are not authorized to access the resource: cluster: %s, namespace: %s, resource: pods", clusterName, namespace)))
+	resources := []string{"pods", "configmaps"}
+	if parsedResolveSecrets {
+		resources = append(resources, "secrets")
+	}
+
+	for _, resource := range resources {
+		if !user.HasResourceAccess(clusterName, namespace, resource) {
+			errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: %s, resource: %s", clusterName, namespace, resource), http.StatusForbidden, "You are not authorized to access the resource")
 			return
 		}
 
-		err = cluster.StreamLogs(r.Context(), c, namespace, name, container, parsedSince, parsedTail, parsedFollow)
-		if err != nil {
-			c.WriteMessage(websocket.TextMessage, []byte("Could not stream logs: "+err.Error()))
+		if router.isForbidden(resource) {
+			errresponse.Render(w, r, nil, http.StatusForbidden, fmt.Sprintf("Access for resource %s is forbidding", resource))
 			return
 		}
+	}
 
-		log.Tracef("Logs stream was closed")
+	cluster, err := router.clusters.GetCluster(clusterName)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
+		return
+	}
+
+	env, err := cluster.GetContainerEnv(r.Context(), namespace, name, parsedResolveSecrets)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not get container environment")
 		return
 	}
 
+	if parsedResolveSecrets {
+		log.WithFields(logrus.Fields{"user": user.ID, "cluster": clusterName, "namespace": namespace, "name": name}).Infof("Secrets were resolved for the container environment")
+	}
+
+	log.WithFields(logrus.Fields{"count": len(env)}).Tracef("getContainerEnv")
+	render.JSON(w, r, env)
+}
+
+// getLanguage returns the language of a ConfigMap key based on the file extension of the key. The language can be used
+// in the frontend to highlight the value of the key. If we do not know the extension an empty string is returned.
+func getLanguage(key string) string {
+	switch filepath.Ext(key) {
+	case ".yaml", ".yml":
+		return "yaml"
+	case ".json":
+		return "json"
+	case ".toml":
+		return "toml"
+	case ".xml":
+		return "xml"
+	case ".ini", ".conf", ".cfg":
+		return "ini"
+	case ".properties":
+		return "properties"
+	case ".sh":
+		return "shell"
+	case ".lua":
+		return "lua"
+	default:
+		return ""
+	}
+}
+
+// getConfigMap returns the data and binary data of a ConfigMap. Next to the data we also return a language hint for
+// each key, which is based on the file extension of the key and can be used for syntax highlighting in the frontend.
+func (router *Router) getConfigMap(w http.ResponseWriter, r *http.Request) {
+	clusterName := r.URL.Query().Get("cluster")
+	namespace := r.URL.Query().Get("namespace")
+	name := r.URL.Query().Get("name")
+
+	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "name": name}).Tracef("getConfigMap")
+
 	user, err := authContext.GetUser(r.Context())
 	if err != nil {
 		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
 		return
 	}
 
-	if !user.HasResourceAccess(clusterName, namespace, "pods") {
-		errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: %s, resource: pods", clusterName, namespace), http.StatusForbidden, "You are not authorized to access the resource")
+	if !user.HasResourceAccess(clusterName, namespace, "configmaps") {
+		errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: %s, resource: configmaps", clusterName, namespace), http.StatusForbidden, "You are not authorized to access the resource")
+		return
+	}
+
+	if router.isForbidden("configmaps") {
+		errresponse.Render(w, r, nil, http.StatusForbidden, "Access for resource configmaps is forbidding")
 		return
 	}
 
-	logs, err := cluster.GetLogs(r.Context(), namespace, name, container, regex, parsedSince, parsedTail, parsedPrevious)
+	cluster, err := router.clusters.GetCluster(clusterName)
 	if err != nil {
-		errresponse.Render(w, r, err, http.StatusBadGateway, "Could not get logs")
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
 		return
 	}
 
-	log.WithFields(logrus.Fields{"count": len(logs)}).Tracef("getLogs")
+	data, binaryData, err := cluster.GetConfigMap(r.Context(), namespace, name)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not get ConfigMap")
+		return
+	}
+
+	languages := make(map[string]string)
+	for key := range data {
+		if language := getLanguage(key); language != "" {
+			languages[key] = language
+		}
+	}
+
 	render.JSON(w, r, struct {
-		Logs string `json:"logs"`
-	}{logs})
+		Data       map[string]string `json:"data"`
+		BinaryData map[string][]byte `json:"binaryData"`
+		Languages  map[string]string `json:"languages"`
+	}{data, binaryData, languages})
 }
 
 // getTerminal starts a new terminal session for a container in a pod. The user must provide the cluster, namespace, pod
@@ -464,21 +3208,16 @@ func (router *Router) getTerminal(w http.ResponseWriter, r *http.Request) {
 	}
 	defer c.Close()
 
-	c.SetPongHandler(func(string) error { return nil })
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
 
-	go func() {
-		ticker := time.NewTicker(pingPeriod)
-		defer ticker.Stop()
+	// The messages of the connection are read by the terminal session, so that the pong messages are also handled there.
+	// When the client doesn't answer the ping messages anymore, the reads of the session fail and the process in the
+	// container is terminated.
+	k := newKeepAlive(c, "terminal", router.config.WebSocket.IdleTimeout)
+	defer k.close()
 
-		for {
-			select {
-			case <-ticker.C:
-				if err := c.WriteMessage(websocket.PingMessage, nil); err != nil {
-					return
-				}
-			}
-		}
-	}()
+	go k.ping(ctx)
 
 	user, err := authContext.GetUser(r.Context())
 	if err != nil {
@@ -501,8 +3240,8 @@ func (router *Router) getTerminal(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	cluster := router.clusters.GetCluster(clusterName)
-	if cluster == nil {
+	cluster, err := router.clusters.GetCluster(clusterName)
+	if err != nil {
 		log.WithError(err).Errorf("Invalid cluster name")
 		msg, _ := json.Marshal(terminal.Message{
 			Op:   "stdout",
@@ -537,19 +3276,60 @@ func (router *Router) getFile(w http.ResponseWriter, r *http.Request) {
 
 	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "name": name, "container": container, "srcPath": srcPath}).Tracef("getFile")
 
-	cluster := router.clusters.GetCluster(clusterName)
-	if cluster == nil {
-		errresponse.Render(w, r, nil, http.StatusBadRequest, "Invalid cluster name")
+	cluster, err := router.clusters.GetCluster(clusterName)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
 		return
 	}
 
-	err := cluster.CopyFileFromPod(w, namespace, name, container, srcPath)
+	err = cluster.CopyFileFromPod(w, namespace, name, container, srcPath)
 	if err != nil {
 		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not copy file")
 		return
 	}
 }
 
+// getRawFile returns the raw content of a single file from the given container. In contrast to the getFile function the
+// file isn't returned as tar archive, so that it can be displayed inline in the frontend (e.g. an image or a config
+// file). If the given path isn't a regular file, we return a bad request error.
+func (router *Router) getRawFile(w http.ResponseWriter, r *http.Request) {
+	clusterName := r.URL.Query().Get("cluster")
+	namespace := r.URL.Query().Get("namespace")
+	name := r.URL.Query().Get("name")
+	container := r.URL.Query().Get("container")
+	srcPath := r.URL.Query().Get("srcPath")
+
+	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "name": name, "container": container, "srcPath": srcPath}).Tracef("getRawFile")
+
+	user, err := authContext.GetUser(r.Context())
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusUnauthorized, "You are not authorized to access the resource")
+		return
+	}
+
+	if !user.HasResourceAccess(clusterName, namespace, "pods") {
+		errresponse.Render(w, r, fmt.Errorf("cluster: %s, namespace: %s, resource: pods", clusterName, namespace), http.StatusForbidden, "You are not authorized to access the resource")
+		return
+	}
+
+	if router.isForbidden("pods") {
+		errresponse.Render(w, r, nil, http.StatusForbidden, "Access for resource pods is forbidding")
+		return
+	}
+
+	cluster, err := router.clusters.GetCluster(clusterName)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
+		return
+	}
+
+	err = cluster.GetRawFileFromPod(w, namespace, name, container, srcPath)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Could not get file")
+		return
+	}
+}
+
 // postFile allows a user to upload a file to a given container. For that the file must be sent as form data, so that it
 // can be created in the destination (destPath).
 func (router *Router) postFile(w http.ResponseWriter, r *http.Request) {
@@ -568,9 +3348,9 @@ func (router *Router) postFile(w http.ResponseWriter, r *http.Request) {
 	}
 	defer f.Close()
 
-	cluster := router.clusters.GetCluster(clusterName)
-	if cluster == nil {
-		errresponse.Render(w, r, nil, http.StatusBadRequest, "Invalid cluster name")
+	cluster, err := router.clusters.GetCluster(clusterName)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
 		return
 	}
 
@@ -592,6 +3372,24 @@ func Register(clusters *clusters.Clusters, plugins *plugin.Plugins, config Confi
 	options["webSocketAddress"] = config.WebSocket.Address
 	options["ephemeralContainers"] = config.EphemeralContainers
 
+	if config.WebSocket.MaxMessageSize == 0 {
+		config.WebSocket.MaxMessageSize = defaultMaxMessageSize
+	}
+
+	if config.WebSocket.MaxLogStreams <= 0 {
+		config.WebSocket.MaxLogStreams = defaultMaxLogStreams
+	}
+
+	// The idle timeout must be larger than the ping period, otherwise connections would be closed before the client
+	// had the chance to answer our first ping message.
+	if time.Duration(config.WebSocket.IdleTimeout)*time.Second <= pingPeriod {
+		config.WebSocket.IdleTimeout = defaultIdleTimeout
+	}
+
+	if config.Events.MaxWatches <= 0 {
+		config.Events.MaxWatches = defaultMaxEventsWatches
+	}
+
 	plugins.Append(plugin.Plugin{
 		Name:        "resources",
 		DisplayName: "Resources",
@@ -607,12 +3405,48 @@ func Register(clusters *clusters.Clusters, plugins *plugin.Plugins, config Confi
 	}
 
 	router.Get("/resources", router.getResources)
+	router.Get("/resources/search", router.searchResources)
+	router.Get("/resources/children", router.getChildren)
+	router.HandleFunc("/resources/watch", router.watchResources)
+	router.Get("/resources/events", router.watchResourcesSSE)
+	router.Get("/resources/stream", router.streamResources)
+	router.HandleFunc("/pods/status", router.watchPodStatus)
+	router.Get("/rollout", router.watchRollout)
+	router.Get("/events/watch", router.watchEvents)
 	router.Delete("/resources", router.deleteResource)
 	router.Put("/resources", router.patchResource)
+	router.Put("/resources/bulk", router.patchResources)
+	router.Put("/resources/labels", router.updateLabels)
+	router.Put("/resources/annotations", router.updateAnnotations)
 	router.Post("/resources", router.createResource)
+	router.Post("/resources/diff", router.diffResource)
+	router.Post("/resources/validate", router.validateResource)
 	router.Get("/logs", router.getLogs)
+	router.Get("/logs/download", router.downloadLogs)
+	router.Get("/logs/previous", router.getPreviousLogs)
+	router.HandleFunc("/logs/job", router.getJobLogs)
+	router.HandleFunc("/logs/workload", router.getWorkloadLogs)
+	router.Put("/nodes/cordon", router.cordonNode)
+	router.Post("/nodes/drain", router.drainNode)
+	router.Get("/deployments/revisions", router.getDeploymentRevisions)
+	router.Get("/deployments/revisions/diff", router.diffDeploymentRevisions)
+	router.Post("/deployments/rollback", router.rollbackDeployment)
+	router.Get("/images", router.getImages)
+	router.Get("/containerstatus", router.getContainerStatus)
+	router.Get("/probes", router.getPodProbes)
+	router.Get("/security", router.getPodSecurity)
+	router.Get("/restarts", router.getRestarts)
+	router.Get("/scheduling", router.getPodScheduling)
+	router.Get("/hpa", router.getHPA)
+	router.Get("/networkpolicies", router.getNetworkPolicies)
+	router.Get("/ingresses", router.getIngresses)
+	router.Get("/helm/release", router.getHelmRelease)
+	router.Get("/serviceaccount", router.getPodPermissions)
+	router.Get("/containerenv", router.getContainerEnv)
+	router.Get("/configmap", router.getConfigMap)
 	router.HandleFunc("/terminal", router.getTerminal)
 	router.Get("/file", router.getFile)
+	router.Get("/file/raw", router.getRawFile)
 	router.Post("/file", router.postFile)
 
 	return router
@@ -0,0 +1,83 @@
+package resources
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	clusterPkg "github.com/kobsio/kobs/pkg/api/clusters/cluster"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSortAndPaginateResources(t *testing.T) {
+	list := func(names ...string) map[string]interface{} {
+		var items []interface{}
+		for _, name := range names {
+			items = append(items, map[string]interface{}{"metadata": map[string]interface{}{"name": name}})
+		}
+		return map[string]interface{}{"kind": "PodList", "metadata": map[string]interface{}{"resourceVersion": "1"}, "items": items}
+	}
+
+	getNames := func(resources []Resources) []string {
+		var names []string
+		for _, r := range resources {
+			for _, item := range r.Resources["items"].([]interface{}) {
+				names = append(names, r.Namespace+"/"+item.(map[string]interface{})["metadata"].(map[string]interface{})["name"].(string))
+			}
+		}
+		return names
+	}
+
+	resources := []Resources{
+		{Cluster: "dev-de1", Namespace: "kube-system", Resources: list("b", "e")},
+		{Cluster: "dev-de1", Namespace: "default", Resources: list("d", "a", "c")},
+	}
+
+	t.Run("without limit", func(t *testing.T) {
+		sortedResources, err := sortAndPaginateResources(resources, ".metadata.name", false, "", "")
+		require.NoError(t, err)
+		require.Equal(t, []string{"default/a", "kube-system/b", "default/c", "default/d", "kube-system/e"}, getNames(sortedResources))
+		require.Len(t, sortedResources, 4)
+		require.Nil(t, sortedResources[3].Resources["metadata"].(map[string]interface{})["continue"])
+	})
+
+	t.Run("with limit", func(t *testing.T) {
+		firstPage, err := sortAndPaginateResources(resources, ".metadata.name", true, "2", "")
+		require.NoError(t, err)
+		require.Equal(t, []string{"kube-system/e", "default/d"}, getNames(firstPage))
+		require.Equal(t, "2", firstPage[1].Resources["metadata"].(map[string]interface{})["continue"])
+
+		secondPage, err := sortAndPaginateResources(resources, ".metadata.name", true, "2", "2")
+		require.NoError(t, err)
+		require.Equal(t, []string{"default/c", "kube-system/b"}, getNames(secondPage))
+
+		lastPage, err := sortAndPaginateResources(resources, ".metadata.name", true, "2", "4")
+		require.NoError(t, err)
+		require.Equal(t, []string{"default/a"}, getNames(lastPage))
+		require.Nil(t, lastPage[0].Resources["metadata"].(map[string]interface{})["continue"])
+	})
+
+	t.Run("original resources are not modified", func(t *testing.T) {
+		require.Equal(t, []string{"kube-system/b", "kube-system/e", "default/d", "default/a", "default/c"}, getNames(resources))
+		require.Nil(t, resources[1].Resources["metadata"].(map[string]interface{})["continue"])
+	})
+
+	t.Run("invalid continue token", func(t *testing.T) {
+		_, err := sortAndPaginateResources(resources, ".metadata.name", false, "2", "foo")
+		require.Error(t, err)
+	})
+}
+
+func TestGetLogsErrorStatus(t *testing.T) {
+	require.Equal(t, http.StatusBadRequest, getLogsErrorStatus(fmt.Errorf("%w: \"(\"", clusterPkg.ErrInvalidRegex)))
+	require.Equal(t, http.StatusBadGateway, getLogsErrorStatus(fmt.Errorf("connection refused")))
+}
+
+func TestMaxResponseSizeGet(t *testing.T) {
+	maxResponseSize := MaxResponseSize{Default: 1024, Routes: map[string]int64{"/resources/stream": 0}}
+
+	require.Equal(t, int64(1024), maxResponseSize.get("/resources"))
+	require.Equal(t, int64(0), maxResponseSize.get("/resources/stream"))
+	require.Equal(t, int64(0), MaxResponseSize{}.get("/resources"))
+}
@@ -0,0 +1,113 @@
+package resources
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultIdleTimeout is the time after which a WebSocket connection is closed, when the client didn't answer our
+	// ping messages and no idle timeout was configured.
+	defaultIdleTimeout = 90
+)
+
+var (
+	reapedWebSocketsMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "kobs",
+		Name:      "reaped_websockets_total",
+		Help:      "Number of WebSocket connections, which were closed because the client didn't answer the ping messages.",
+	}, []string{"type"})
+)
+
+// keepAlive is used to close idle WebSocket connections, e.g. from a browser tab which was closed without closing the
+// connection or when the network connection to the client was lost. We are sending a ping message every ping period
+// and the client must answer with a pong message within the idle timeout. Each pong message extends the read deadline
+// of the connection, so that long running connections like followed log streams or a terminal without any input are
+// kept open as long as the client is alive. When the deadline is exceeded, all reads from the connection fail and the
+// handler of the connection returns.
+type keepAlive struct {
+	conn           *websocket.Conn
+	connectionType string
+	idleTimeout    time.Duration
+	pingPeriod     time.Duration
+	deadline       int64
+}
+
+// newKeepAlive sets the read deadline for the given connection and extends it with every pong message. The connection
+// type is used as label for the metric of the reaped connections (e.g. "logs" or "terminal") and the idle timeout must
+// be provided in seconds.
+func newKeepAlive(conn *websocket.Conn, connectionType string, idleTimeout int) *keepAlive {
+	k := &keepAlive{
+		conn:           conn,
+		connectionType: connectionType,
+		idleTimeout:    time.Duration(idleTimeout) * time.Second,
+		pingPeriod:     pingPeriod,
+	}
+
+	k.extendDeadline()
+	conn.SetPongHandler(func(string) error {
+		return k.extendDeadline()
+	})
+
+	return k
+}
+
+// extendDeadline sets the read deadline of the connection to the current time plus the idle timeout.
+func (k *keepAlive) extendDeadline() error {
+	deadline := time.Now().Add(k.idleTimeout)
+	atomic.StoreInt64(&k.deadline, deadline.UnixNano())
+	return k.conn.SetReadDeadline(deadline)
+}
+
+// read reads all messages from the connection, because the pong messages are only handled while reading from the
+// connection and because the context of the request isn't canceled when the client closes the connection. When the
+// connection is closed or the deadline is exceeded the given cancel function is called, so that the handler can stop
+// the watch or log stream. It must not be used, when the handler reads the messages by itself (e.g. for the terminal).
+func (k *keepAlive) read(cancel context.CancelFunc) {
+	defer cancel()
+
+	for {
+		if _, _, err := k.conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+// ping sends a ping message every ping period until the given context is canceled. The ping messages are sent via the
+// WriteControl method, because it can be called concurrently with the writes of the handler.
+func (k *keepAlive) ping(ctx context.Context) {
+	ticker := time.NewTicker(k.pingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := k.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(k.pingPeriod)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// close must be called when the handler of the connection returns. If the read deadline was exceeded at this point,
+// the connection was closed because the client didn't answer our ping messages and we increase the metric for the
+// reaped connections.
+func (k *keepAlive) close() {
+	if k.expired(time.Now()) {
+		reapedWebSocketsMetric.WithLabelValues(k.connectionType).Inc()
+		log.WithFields(logrus.Fields{"type": k.connectionType, "idleTimeout": k.idleTimeout}).Debugf("Idle WebSocket connection was closed")
+	}
+}
+
+// expired returns true, when the read deadline of the connection is before the given time.
+func (k *keepAlive) expired(now time.Time) bool {
+	return now.UnixNano() > atomic.LoadInt64(&k.deadline)
+}
@@ -0,0 +1,85 @@
+package resources
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeepAlive(t *testing.T) {
+	defaultPingPeriod := pingPeriod
+	pingPeriod = 100 * time.Millisecond
+	defer func() { pingPeriod = defaultPingPeriod }()
+
+	expired := make(chan bool, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upgrader := websocket.Upgrader{}
+		c, err := upgrader.Upgrade(w, r, nil)
+		require.NoError(t, err)
+		defer c.Close()
+
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+
+		k := newKeepAlive(c, "test", 1)
+		go k.ping(ctx)
+		k.read(cancel)
+
+		expired <- k.expired(time.Now())
+	}))
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	t.Run("idle client", func(t *testing.T) {
+		// The client never reads from the connection, so that the ping messages are not answered and the connection is
+		// closed after the idle timeout.
+		c, _, err := websocket.DefaultDialer.Dial(url, nil)
+		require.NoError(t, err)
+		defer c.Close()
+
+		select {
+		case e := <-expired:
+			require.True(t, e)
+		case <-time.After(5 * time.Second):
+			t.Fatal("connection was not closed")
+		}
+	})
+
+	t.Run("alive client", func(t *testing.T) {
+		// The client answers the ping messages while reading, so that the connection is kept open longer than the idle
+		// timeout until the client closes it.
+		c, _, err := websocket.DefaultDialer.Dial(url, nil)
+		require.NoError(t, err)
+
+		go func() {
+			for {
+				if _, _, err := c.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+
+		select {
+		case <-expired:
+			t.Fatal("connection was closed")
+		case <-time.After(1500 * time.Millisecond):
+		}
+
+		c.Close()
+
+		select {
+		case e := <-expired:
+			require.False(t, e)
+		case <-time.After(5 * time.Second):
+			t.Fatal("connection was not closed")
+		}
+	})
+}
@@ -1,10 +1,13 @@
 package rss
 
 import (
+	"context"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/kobsio/kobs/pkg/api/clusters"
+	"github.com/kobsio/kobs/pkg/api/middleware/roundtripper"
 	"github.com/kobsio/kobs/pkg/api/plugins/plugin"
 	"github.com/kobsio/kobs/plugins/rss/pkg/feed"
 
@@ -21,14 +24,33 @@ var (
 	log = logrus.WithFields(logrus.Fields{"package": "rss"})
 )
 
-// Config is the structure of the configuration for the rss plugin.
-type Config struct{}
+// Config is the structure of the configuration for the rss plugin. The concurrency is the maximum number of feeds,
+// which are fetched at the same time across all requests. The timeout is the maximum duration of a single request to
+// get the feeds.
+type Config struct {
+	Concurrency int    `json:"concurrency"`
+	Timeout     string `json:"timeout"`
+}
 
 // Router implements the router for the resources plugin, which can be registered in the router for our rest api.
 type Router struct {
 	*chi.Mux
 	clusters *clusters.Clusters
 	config   Config
+	slots    chan struct{}
+	timeout  time.Duration
+}
+
+// acquire waits for a free slot to fetch a feed and returns a function to release the slot again. The slots are shared
+// between all requests, so that fetches which exceed the configured concurrency are queued. If the context is canceled
+// before a slot is free, the error of the context is returned.
+func (router *Router) acquire(ctx context.Context) (func(), error) {
+	select {
+	case router.slots <- struct{}{}:
+		return func() { <-router.slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
 }
 
 // getFeed returns a feed with the retrieved items from the given links.
@@ -36,23 +58,39 @@ func (router *Router) getFeed(w http.ResponseWriter, r *http.Request) {
 	urls := r.URL.Query()["url"]
 	sortBy := r.URL.Query().Get("sortBy")
 
+	// All feeds must be fetched within the configured timeout. Feeds which are not fetched within the timeout, because
+	// they are still waiting for a free slot or the upstream provider is to slow, are skipped.
+	ctx, cancel := context.WithTimeout(r.Context(), router.timeout)
+	defer cancel()
+
 	var feeds []*gofeed.Feed
+	var mutex sync.Mutex
 	var wg sync.WaitGroup
 	wg.Add(len(urls))
 
 	for _, url := range urls {
 		go func(url string) {
+			defer wg.Done()
+
+			release, err := router.acquire(ctx)
+			if err != nil {
+				log.WithError(err).WithFields(logrus.Fields{"url": url}).Error("Timeout while waiting to get feed")
+				return
+			}
+			defer release()
+
 			fp := gofeed.NewParser()
-			feed, err := fp.ParseURL(url)
+			fp.Client = roundtripper.DefaultClient
+			feed, err := fp.ParseURLWithContext(url, ctx)
 			if err != nil {
-				log.WithError(err).Error("Error while getting feed")
+				log.WithError(err).WithFields(logrus.Fields{"url": url}).Error("Error while getting feed")
 			}
 
 			if feed != nil {
+				mutex.Lock()
 				feeds = append(feeds, feed)
+				mutex.Unlock()
 			}
-
-			wg.Done()
 		}(url)
 	}
 
@@ -74,10 +112,24 @@ func Register(clusters *clusters.Clusters, plugins *plugin.Plugins, config Confi
 		Type:        "rss",
 	})
 
+	// By default we fetch 10 feeds at the same time and a request to get the feeds can take up to 30 seconds. Both
+	// values can be overwritten via the configuration.
+	concurrency := 10
+	if config.Concurrency > 0 {
+		concurrency = config.Concurrency
+	}
+
+	timeout := 30 * time.Second
+	if parsedTimeout, err := time.ParseDuration(config.Timeout); err == nil && parsedTimeout > 0 {
+		timeout = parsedTimeout
+	}
+
 	router := Router{
-		chi.NewRouter(),
-		clusters,
-		config,
+		Mux:      chi.NewRouter(),
+		clusters: clusters,
+		config:   config,
+		slots:    make(chan struct{}, concurrency),
+		timeout:  timeout,
 	}
 
 	router.Get("/feed", router.getFeed)
@@ -21,6 +21,7 @@ var (
 type Config struct {
 	Name        string   `json:"name"`
 	DisplayName string   `json:"displayName"`
+	Default     bool     `json:"default"`
 	Description string   `json:"description"`
 	Address     string   `json:"address"`
 	Username    string   `json:"username"`
@@ -31,6 +32,7 @@ type Config struct {
 // Instance represents a single Jaeger instance, which can be added via the configuration file.
 type Instance struct {
 	Name       string
+	Default    bool
 	address    string
 	client     *http.Client
 	metricKeys []string
@@ -120,6 +122,7 @@ func New(config Config) (*Instance, error) {
 
 	return &Instance{
 		Name:    config.Name,
+		Default: config.Default,
 		address: config.Address,
 		client: &http.Client{
 			Transport: roundTripper,
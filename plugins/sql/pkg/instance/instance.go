@@ -20,6 +20,7 @@ var (
 type Config struct {
 	Name        string `json:"name"`
 	DisplayName string `json:"displayName"`
+	Default     bool   `json:"default"`
 	Description string `json:"description"`
 	Driver      string `json:"driver"`
 	Connection  string `json:"connection"`
@@ -27,8 +28,9 @@ type Config struct {
 
 // Instance represents a single SQL database instance, which can be added via the configuration file.
 type Instance struct {
-	Name   string
-	client *sql.DB
+	Name    string
+	Default bool
+	client  *sql.DB
 }
 
 // GetQueryResults returns all rows for the user provided SQL query.
@@ -95,7 +97,8 @@ func New(config Config) (*Instance, error) {
 	}
 
 	return &Instance{
-		Name:   config.Name,
-		client: client,
+		Name:    config.Name,
+		Default: config.Default,
+		client:  client,
 	}, nil
 }
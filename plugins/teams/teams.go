@@ -37,7 +37,7 @@ func (router *Router) getTeams(w http.ResponseWriter, r *http.Request) {
 
 	var teams []team.TeamSpec
 
-	for _, cluster := range router.clusters.Clusters {
+	for _, cluster := range router.clusters.GetClusters() {
 		team, err := cluster.GetTeams(r.Context(), "")
 		if err != nil {
 			errresponse.Render(w, r, err, http.StatusBadRequest, "Could not get teams")
@@ -61,9 +61,9 @@ func (router *Router) getTeam(w http.ResponseWriter, r *http.Request) {
 
 	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "name": name}).Tracef("getTeam")
 
-	cluster := router.clusters.GetCluster(clusterName)
-	if cluster == nil {
-		errresponse.Render(w, r, nil, http.StatusBadRequest, "Invalid cluster name")
+	cluster, err := router.clusters.GetCluster(clusterName)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
 		return
 	}
 
@@ -63,7 +63,7 @@ func (router *Router) getUsers(w http.ResponseWriter, r *http.Request) {
 
 	var users []user.UserSpec
 
-	for _, cluster := range router.clusters.Clusters {
+	for _, cluster := range router.clusters.GetClusters() {
 		user, err := cluster.GetUsers(r.Context(), "")
 		if err != nil {
 			errresponse.Render(w, r, err, http.StatusBadRequest, "Could not get users")
@@ -87,9 +87,9 @@ func (router *Router) getUser(w http.ResponseWriter, r *http.Request) {
 
 	log.WithFields(logrus.Fields{"cluster": clusterName, "namespace": namespace, "name": name}).Tracef("getUser")
 
-	cluster := router.clusters.GetCluster(clusterName)
-	if cluster == nil {
-		errresponse.Render(w, r, nil, http.StatusBadRequest, "Invalid cluster name")
+	cluster, err := router.clusters.GetCluster(clusterName)
+	if err != nil {
+		errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
 		return
 	}
 
@@ -128,9 +128,9 @@ func (router *Router) getTeams(w http.ResponseWriter, r *http.Request) {
 			c = team.Namespace
 		}
 
-		cluster := router.clusters.GetCluster(c)
-		if cluster == nil {
-			errresponse.Render(w, r, nil, http.StatusBadRequest, "Invalid cluster name")
+		cluster, err := router.clusters.GetCluster(c)
+		if err != nil {
+			errresponse.Render(w, r, err, http.StatusBadRequest, "Invalid cluster name")
 			return
 		}
 
@@ -157,7 +157,7 @@ func (router *Router) getTeam(w http.ResponseWriter, r *http.Request) {
 	var users []user.UserSpec
 	var filteredUsers []user.UserSpec
 
-	for _, cluster := range router.clusters.Clusters {
+	for _, cluster := range router.clusters.GetClusters() {
 		user, err := cluster.GetUsers(r.Context(), "")
 		if err != nil {
 			errresponse.Render(w, r, err, http.StatusBadRequest, "Could not get users")